@@ -38,6 +38,7 @@ import (
 	"github.com/trankhanh040147/prepf/internal/permission"
 	"github.com/trankhanh040147/prepf/internal/session"
 	"github.com/trankhanh040147/prepf/internal/stringext"
+	"github.com/trankhanh040147/prepf/internal/usage"
 )
 
 const defaultSessionName = "Untitled Session"
@@ -62,12 +63,30 @@ type SessionAgentCall struct {
 	TopK             *int64
 	FrequencyPenalty *float64
 	PresencePenalty  *float64
+	// UseSmallModel runs the call against the small model, e.g. when the
+	// usage budget has been exceeded and the configured action is to
+	// downgrade.
+	UseSmallModel bool
+	// MaxSteps stops the run after this many model steps. Zero means no
+	// limit.
+	MaxSteps int
+	// SessionPrompt holds instructions specific to the session, such as
+	// active skills and topics due for practice, and is appended to the
+	// system prompt.
+	SessionPrompt string
+	// Timing describes how the user composed the prompt. It is stored with
+	// the message and shown to the model.
+	Timing *message.AnswerTiming
+	// Workspace confines the shell commands and file tools of the run to a
+	// directory, such as the scratch copy of a debugging exercise.
+	Workspace string
 }
 
 type SessionAgent interface {
 	Run(context.Context, SessionAgentCall) (*fantasy.AgentResult, error)
 	SetModels(large Model, small Model)
 	SetTools(tools []fantasy.AgentTool)
+	SetSystemPrompt(systemPrompt string)
 	Cancel(sessionID string)
 	CancelAll()
 	IsSessionBusy(sessionID string) bool
@@ -77,6 +96,7 @@ type SessionAgent interface {
 	ClearQueue(sessionID string)
 	Summarize(context.Context, string, fantasy.ProviderOptions) error
 	Model() Model
+	SmallModel() Model
 }
 
 type Model struct {
@@ -89,11 +109,13 @@ type sessionAgent struct {
 	largeModel           Model
 	smallModel           Model
 	systemPromptPrefix   string
+	systemPromptMu       *sync.RWMutex
 	systemPrompt         string
 	isSubAgent           bool
 	tools                []fantasy.AgentTool
 	sessions             session.Service
 	messages             message.Service
+	usage                usage.Service
 	disableAutoSummarize bool
 	isYolo               bool
 
@@ -112,6 +134,7 @@ type SessionAgentOptions struct {
 	Sessions             session.Service
 	Messages             message.Service
 	Tools                []fantasy.AgentTool
+	Usage                usage.Service
 }
 
 func NewSessionAgent(
@@ -121,10 +144,12 @@ func NewSessionAgent(
 		largeModel:           opts.LargeModel,
 		smallModel:           opts.SmallModel,
 		systemPromptPrefix:   opts.SystemPromptPrefix,
+		systemPromptMu:       &sync.RWMutex{},
 		systemPrompt:         opts.SystemPrompt,
 		isSubAgent:           opts.IsSubAgent,
 		sessions:             opts.Sessions,
 		messages:             opts.Messages,
+		usage:                opts.Usage,
 		disableAutoSummarize: opts.DisableAutoSummarize,
 		tools:                opts.Tools,
 		isYolo:               opts.IsYolo,
@@ -157,9 +182,20 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 		a.tools[len(a.tools)-1].SetProviderOptions(a.getCacheControlOptions())
 	}
 
+	model := a.largeModel
+	if call.UseSmallModel {
+		model = a.smallModel
+	}
+
+	a.systemPromptMu.RLock()
+	systemPrompt := a.systemPrompt
+	a.systemPromptMu.RUnlock()
+	if call.SessionPrompt != "" {
+		systemPrompt += "\n\n" + call.SessionPrompt
+	}
 	agent := fantasy.NewAgent(
-		a.largeModel.Model,
-		fantasy.WithSystemPrompt(a.systemPrompt),
+		model.Model,
+		fantasy.WithSystemPrompt(systemPrompt),
 		fantasy.WithTools(a.tools...),
 	)
 
@@ -179,7 +215,7 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 	if len(msgs) == 0 {
 		titleCtx := ctx // Copy to avoid race with ctx reassignment below.
 		wg.Go(func() {
-			a.generateTitle(titleCtx, call.SessionID, call.Prompt)
+			a.generateTitle(titleCtx, currentSession, call.Prompt)
 		})
 	}
 
@@ -191,6 +227,9 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 
 	// Add the session to the context.
 	ctx = context.WithValue(ctx, tools.SessionIDContextKey, call.SessionID)
+	if call.Workspace != "" {
+		ctx = context.WithValue(ctx, tools.WorkspaceContextKey, call.Workspace)
+	}
 
 	genCtx, cancel := context.WithCancel(ctx)
 	a.activeRequests.Set(call.SessionID, cancel)
@@ -206,7 +245,7 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 	var currentAssistant *message.Message
 	var shouldSummarize bool
 	result, err := agent.Stream(genCtx, fantasy.AgentStreamCall{
-		Prompt:           message.PromptWithTextAttachments(call.Prompt, call.Attachments),
+		Prompt:           message.PromptWithTiming(message.PromptWithTextAttachments(call.Prompt, call.Attachments), call.Timing),
 		Files:            files,
 		Messages:         history,
 		ProviderOptions:  call.ProviderOptions,
@@ -258,15 +297,15 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 			assistantMsg, err = a.messages.Create(callContext, call.SessionID, message.CreateMessageParams{
 				Role:     message.Assistant,
 				Parts:    []message.ContentPart{},
-				Model:    a.largeModel.ModelCfg.Model,
-				Provider: a.largeModel.ModelCfg.Provider,
+				Model:    model.ModelCfg.Model,
+				Provider: model.ModelCfg.Provider,
 			})
 			if err != nil {
 				return callContext, prepared, err
 			}
 			callContext = context.WithValue(callContext, tools.MessageIDContextKey, assistantMsg.ID)
-			callContext = context.WithValue(callContext, tools.SupportsImagesContextKey, a.largeModel.CatwalkCfg.SupportsImages)
-			callContext = context.WithValue(callContext, tools.ModelNameContextKey, a.largeModel.CatwalkCfg.Name)
+			callContext = context.WithValue(callContext, tools.SupportsImagesContextKey, model.CatwalkCfg.SupportsImages)
+			callContext = context.WithValue(callContext, tools.ModelNameContextKey, model.CatwalkCfg.Name)
 			currentAssistant = &assistantMsg
 			return callContext, prepared, err
 		},
@@ -360,7 +399,7 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 				sessionLock.Unlock()
 				return getSessionErr
 			}
-			a.updateSessionUsage(a.largeModel, &updatedSession, stepResult.Usage, a.openrouterCost(stepResult.ProviderMetadata))
+			a.updateSessionUsage(genCtx, model, &updatedSession, stepResult.Usage, a.openrouterCost(stepResult.ProviderMetadata))
 			_, sessionErr := a.sessions.Save(genCtx, updatedSession)
 			sessionLock.Unlock()
 			if sessionErr != nil {
@@ -370,7 +409,7 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 		},
 		StopWhen: []fantasy.StopCondition{
 			func(_ []fantasy.StepResult) bool {
-				cw := int64(a.largeModel.CatwalkCfg.ContextWindow)
+				cw := int64(model.CatwalkCfg.ContextWindow)
 				tokens := currentSession.CompletionTokens + currentSession.PromptTokens
 				remaining := cw - tokens
 				var threshold int64
@@ -385,6 +424,9 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 				}
 				return false
 			},
+			func(steps []fantasy.StepResult) bool {
+				return call.MaxSteps > 0 && len(steps) >= call.MaxSteps
+			},
 		},
 	})
 
@@ -621,7 +663,7 @@ func (a *sessionAgent) Summarize(ctx context.Context, sessionID string, opts fan
 		}
 	}
 
-	a.updateSessionUsage(a.largeModel, &currentSession, resp.TotalUsage, openrouterCost)
+	a.updateSessionUsage(genCtx, a.largeModel, &currentSession, resp.TotalUsage, openrouterCost)
 
 	// Just in case, get just the last usage info.
 	usage := resp.Response.Usage
@@ -653,6 +695,9 @@ func (a *sessionAgent) createUserMessage(ctx context.Context, call SessionAgentC
 		attachmentParts = append(attachmentParts, message.BinaryContent{Path: attachment.FilePath, MIMEType: attachment.MimeType, Data: attachment.Content})
 	}
 	parts = append(parts, attachmentParts...)
+	if call.Timing != nil {
+		parts = append(parts, *call.Timing)
+	}
 	msg, err := a.messages.Create(ctx, call.SessionID, message.CreateMessageParams{
 		Role:  message.User,
 		Parts: parts,
@@ -724,10 +769,11 @@ func (a *sessionAgent) getSessionMessages(ctx context.Context, session session.S
 }
 
 // generateTitle generates a session titled based on the initial prompt.
-func (a *sessionAgent) generateTitle(ctx context.Context, sessionID string, userPrompt string) {
+func (a *sessionAgent) generateTitle(ctx context.Context, sess session.Session, userPrompt string) {
 	if userPrompt == "" {
 		return
 	}
+	sessionID := sess.ID
 
 	var maxOutputTokens int64 = 40
 	if a.smallModel.CatwalkCfg.CanReason {
@@ -832,6 +878,7 @@ func (a *sessionAgent) generateTitle(ctx context.Context, sessionID string, user
 
 	promptTokens := resp.TotalUsage.InputTokens + resp.TotalUsage.CacheCreationTokens
 	completionTokens := resp.TotalUsage.OutputTokens + resp.TotalUsage.CacheReadTokens
+	a.recordUsage(ctx, *model, sess, promptTokens, completionTokens, cost)
 
 	// Atomically update only title and usage fields to avoid overriding other
 	// concurrent session updates.
@@ -855,7 +902,7 @@ func (a *sessionAgent) openrouterCost(metadata fantasy.ProviderMetadata) *float6
 	return &opts.Usage.Cost
 }
 
-func (a *sessionAgent) updateSessionUsage(model Model, session *session.Session, usage fantasy.Usage, overrideCost *float64) {
+func (a *sessionAgent) updateSessionUsage(ctx context.Context, model Model, session *session.Session, usage fantasy.Usage, overrideCost *float64) {
 	modelConfig := model.CatwalkCfg
 	cost := modelConfig.CostPer1MInCached/1e6*float64(usage.CacheCreationTokens) +
 		modelConfig.CostPer1MOutCached/1e6*float64(usage.CacheReadTokens) +
@@ -865,13 +912,32 @@ func (a *sessionAgent) updateSessionUsage(model Model, session *session.Session,
 	a.eventTokensUsed(session.ID, model, usage, cost)
 
 	if overrideCost != nil {
-		session.Cost += *overrideCost
-	} else {
-		session.Cost += cost
+		cost = *overrideCost
 	}
+	session.Cost += cost
 
 	session.CompletionTokens = usage.OutputTokens + usage.CacheReadTokens
 	session.PromptTokens = usage.InputTokens + usage.CacheCreationTokens
+	a.recordUsage(ctx, model, *session, session.PromptTokens, session.CompletionTokens, cost)
+}
+
+// recordUsage adds an entry to the usage ledger used for budget enforcement.
+func (a *sessionAgent) recordUsage(ctx context.Context, model Model, session session.Session, promptTokens, completionTokens int64, cost float64) {
+	if a.usage == nil {
+		return
+	}
+	err := a.usage.Record(ctx, usage.Record{
+		SessionID:        session.ID,
+		Mode:             session.Mode,
+		Provider:         model.ModelCfg.Provider,
+		Model:            model.ModelCfg.Model,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		Cost:             cost,
+	})
+	if err != nil {
+		slog.Error("failed to record usage", "error", err)
+	}
 }
 
 func (a *sessionAgent) Cancel(sessionID string) {
@@ -964,10 +1030,20 @@ func (a *sessionAgent) SetTools(tools []fantasy.AgentTool) {
 	a.tools = tools
 }
 
+func (a *sessionAgent) SetSystemPrompt(systemPrompt string) {
+	a.systemPromptMu.Lock()
+	defer a.systemPromptMu.Unlock()
+	a.systemPrompt = systemPrompt
+}
+
 func (a *sessionAgent) Model() Model {
 	return a.largeModel
 }
 
+func (a *sessionAgent) SmallModel() Model {
+	return a.smallModel
+}
+
 func (a *sessionAgent) promptPrefix() string {
 	return a.systemPromptPrefix
 }
@@ -0,0 +1,195 @@
+package agent
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/pubsub"
+	"github.com/trankhanh040147/prepf/internal/session"
+	"github.com/trankhanh040147/prepf/internal/taxonomy"
+	"github.com/trankhanh040147/prepf/internal/usage"
+)
+
+//go:embed templates/classify.md
+var classifyPrompt []byte
+
+// maxClassifyDepth bounds the paths the model may invent when no node of
+// the taxonomy fits.
+const maxClassifyDepth = 4
+
+// ClassifyQuestions files graded drill questions under a node of the topic
+// taxonomy with the small model: first the ones left unclassified, e.g. from
+// before the taxonomy existed or a run that ended before they were filed,
+// then each one as it is graded. It runs until ctx is done.
+func (c *coordinator) ClassifyQuestions(ctx context.Context) {
+	// Classification calls aren't part of recorded sessions, so keep them
+	// out of replays.
+	if c.replay != nil {
+		return
+	}
+	events := c.drills.Subscribe(ctx)
+	pending, err := c.drills.Unclassified(ctx)
+	if err != nil {
+		slog.Error("Failed to list unclassified drill questions", "error", err)
+	}
+	for _, q := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		c.classify(ctx, q)
+	}
+	for event := range events {
+		q := event.Payload
+		graded := q.Status == drill.StatusAnswered || q.Status == drill.StatusGaveUp
+		if event.Type == pubsub.UpdatedEvent && graded && q.Node == "" {
+			c.classify(ctx, q)
+		}
+	}
+}
+
+func (c *coordinator) classify(ctx context.Context, q drill.Question) {
+	// Answers graded locally, such as quiz answers, are recorded under a run
+	// of their page rather than a chat session, so only the daily and
+	// monthly budgets apply to them.
+	sess, err := c.sessions.Get(ctx, q.SessionID)
+	if err != nil {
+		sess = session.Session{}
+	}
+	if _, err := c.checkBudget(ctx, sess); err != nil {
+		slog.Info("Not classifying drill question", "question", q.ID, "error", err)
+		return
+	}
+
+	tx, errs := taxonomy.Load(c.cfg)
+	for _, err := range errs {
+		slog.Warn("Failed to load taxonomy file", "error", err)
+	}
+
+	c.promptMu.Lock()
+	model := c.currentAgent.SmallModel()
+	c.promptMu.Unlock()
+	resp, err := c.callModel(ctx, model, string(classifyPrompt)+"\n /no_think", classifyRequest(tx, q), 60)
+	if err != nil {
+		slog.Error("Failed to classify drill question", "question", q.ID, "error", err)
+		return
+	}
+
+	answer := thinkTagRegex.ReplaceAllString(resp.Response.Content.Text(), "")
+	path := classifiedPath(tx, answer)
+	if path == "" {
+		slog.Warn("Unusable drill question classification", "question", q.ID, "answer", answer)
+		return
+	}
+	if _, err := c.drills.SetNode(ctx, q.ID, path); err != nil {
+		slog.Error("Failed to file drill question", "question", q.ID, "error", err)
+		return
+	}
+	slog.Debug("Classified drill question", "question", q.ID, "node", path)
+	c.recordCallUsage(ctx, q.SessionID, model, resp.TotalUsage)
+}
+
+// callModel makes a single tool-less call outside of the session's
+// conversation. maxOutputTokens is raised to the model's default for
+// reasoning models, which need room to think.
+func (c *coordinator) callModel(ctx context.Context, model Model, systemPrompt, prompt string, maxOutputTokens int64) (*fantasy.AgentResult, error) {
+	if model.CatwalkCfg.CanReason {
+		maxOutputTokens = model.CatwalkCfg.DefaultMaxTokens
+	}
+	var systemPromptPrefix string
+	if providerCfg, ok := c.cfg.Providers.Get(model.ModelCfg.Provider); ok {
+		systemPromptPrefix = providerCfg.SystemPromptPrefix
+	}
+
+	agent := fantasy.NewAgent(model.Model,
+		fantasy.WithSystemPrompt(systemPrompt),
+		fantasy.WithMaxOutputTokens(maxOutputTokens),
+	)
+	resp, err := agent.Stream(ctx, fantasy.AgentStreamCall{
+		Prompt: prompt,
+		PrepareStep: func(callCtx context.Context, opts fantasy.PrepareStepFunctionOptions) (_ context.Context, prepared fantasy.PrepareStepResult, err error) {
+			prepared.Messages = opts.Messages
+			if systemPromptPrefix != "" {
+				prepared.Messages = append([]fantasy.Message{
+					fantasy.NewSystemMessage(systemPromptPrefix),
+				}, prepared.Messages...)
+			}
+			return callCtx, prepared, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, errors.New("no response")
+	}
+	return resp, nil
+}
+
+// recordCallUsage records the cost of a call made with callModel against
+// the session.
+func (c *coordinator) recordCallUsage(ctx context.Context, sessionID string, model Model, u fantasy.Usage) {
+	// Calls for answers graded outside of chat sessions still count towards
+	// the budget, without a mode.
+	var mode string
+	if sess, err := c.sessions.Get(ctx, sessionID); err == nil {
+		mode = sess.Mode
+	}
+	modelConfig := model.CatwalkCfg
+	cost := modelConfig.CostPer1MInCached/1e6*float64(u.CacheCreationTokens) +
+		modelConfig.CostPer1MOutCached/1e6*float64(u.CacheReadTokens) +
+		modelConfig.CostPer1MIn/1e6*float64(u.InputTokens) +
+		modelConfig.CostPer1MOut/1e6*float64(u.OutputTokens)
+	err := c.usage.Record(ctx, usage.Record{
+		SessionID:        sessionID,
+		Mode:             mode,
+		Provider:         model.ModelCfg.Provider,
+		Model:            model.ModelCfg.Model,
+		PromptTokens:     u.InputTokens + u.CacheCreationTokens,
+		CompletionTokens: u.OutputTokens + u.CacheReadTokens,
+		Cost:             cost,
+	})
+	if err != nil {
+		slog.Error("failed to record usage", "error", err)
+	}
+}
+
+func classifyRequest(tx *taxonomy.Taxonomy, q drill.Question) string {
+	var b strings.Builder
+	b.WriteString("<taxonomy>\n")
+	b.WriteString(strings.Join(tx.Paths(), "\n"))
+	b.WriteString("\n</taxonomy>\n\n")
+	if q.Topic != "" {
+		fmt.Fprintf(&b, "Topic: %s\n", q.Topic)
+	}
+	fmt.Fprintf(&b, "Question: %s\n", q.Text)
+	if q.Answer != "" {
+		answer := q.Answer
+		if len(answer) > 500 {
+			answer = answer[:500] + "..."
+		}
+		fmt.Fprintf(&b, "Model answer: %s\n", answer)
+	}
+	return b.String()
+}
+
+// classifiedPath turns the model's reply into the path to file a question
+// under: a node of the taxonomy, spelled as in the taxonomy, or a new path
+// if it isn't too deep. It returns an empty string for unusable replies.
+func classifiedPath(tx *taxonomy.Taxonomy, answer string) string {
+	answer, _, _ = strings.Cut(strings.TrimSpace(answer), "\n")
+	answer = strings.Trim(answer, "\"'`. ")
+	if node, ok := tx.Find(answer); ok {
+		return node.Path
+	}
+	names := taxonomy.Split(answer)
+	if len(names) == 0 || len(names) > maxClassifyDepth {
+		return ""
+	}
+	return strings.Join(names, taxonomy.Separator)
+}
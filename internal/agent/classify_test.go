@@ -0,0 +1,21 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/taxonomy"
+)
+
+func TestClassifiedPath(t *testing.T) {
+	t.Parallel()
+
+	tx := taxonomy.New()
+	tx.Add("Networking > TCP > Congestion Control")
+
+	require.Equal(t, "Networking > TCP > Congestion Control", classifiedPath(tx, "networking > tcp > congestion control"))
+	require.Equal(t, "Networking > TCP", classifiedPath(tx, "  \"Networking > TCP\".\nBecause..."))
+	require.Equal(t, "Networking > QUIC", classifiedPath(tx, "Networking>QUIC"))
+	require.Empty(t, classifiedPath(tx, "A > B > C > D > E"))
+	require.Empty(t, classifiedPath(tx, ""))
+}
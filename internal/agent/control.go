@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Interview control actions sent through slash commands. The mode prompts
+// describe how the model should react to each of them.
+const (
+	ControlSkip   = "skip"
+	ControlHint   = "hint"
+	ControlHarder = "harder"
+	ControlEasier = "easier"
+	ControlReport = "report"
+	ControlGiveUp = "giveup"
+	ControlCheck  = "check"
+)
+
+// Control is a structured instruction from the user to the interviewer, as
+// opposed to an answer. It is sent as the text of a user message so it is
+// stored and replayed like any other message.
+type Control struct {
+	Action string
+	Args   string
+}
+
+var controlPattern = regexp.MustCompile(`(?s)^<control action="([a-z_]+)"(?:/>|>(.*)</control>)$`)
+
+// String renders the control in the tag format the mode prompts expect.
+func (c Control) String() string {
+	if c.Args == "" {
+		return fmt.Sprintf(`<control action=%q/>`, c.Action)
+	}
+	return fmt.Sprintf(`<control action=%q>%s</control>`, c.Action, html.EscapeString(c.Args))
+}
+
+// ParseControl reports whether text is a control message and decodes it.
+func ParseControl(text string) (Control, bool) {
+	m := controlPattern.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return Control{}, false
+	}
+	return Control{Action: m[1], Args: html.UnescapeString(m[2])}, true
+}
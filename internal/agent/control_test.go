@@ -0,0 +1,25 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestControlRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []Control{
+		{Action: ControlSkip},
+		{Action: ControlHint, Args: "about <T> & generics\nplease"},
+	} {
+		got, ok := ParseControl(c.String())
+		require.True(t, ok)
+		require.Equal(t, c, got)
+	}
+
+	require.Equal(t, `<control action="skip"/>`, Control{Action: ControlSkip}.String())
+
+	_, ok := ParseControl("I'd use a <control action=\"skip\"/> here")
+	require.False(t, ok)
+}
@@ -14,6 +14,9 @@ import (
 	"os"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"charm.land/fantasy"
 	"github.com/charmbracelet/catwalk/pkg/catwalk"
@@ -22,13 +25,24 @@ import (
 	"github.com/trankhanh040147/prepf/internal/agent/tools"
 	"github.com/trankhanh040147/prepf/internal/config"
 	"github.com/trankhanh040147/prepf/internal/csync"
+	"github.com/trankhanh040147/prepf/internal/debugging"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/flashcard"
 	"github.com/trankhanh040147/prepf/internal/history"
 	"github.com/trankhanh040147/prepf/internal/log"
 	"github.com/trankhanh040147/prepf/internal/lsp"
 	"github.com/trankhanh040147/prepf/internal/message"
 	"github.com/trankhanh040147/prepf/internal/oauth/copilot"
+	"github.com/trankhanh040147/prepf/internal/pack"
 	"github.com/trankhanh040147/prepf/internal/permission"
+	"github.com/trankhanh040147/prepf/internal/quiz"
+	"github.com/trankhanh040147/prepf/internal/replay"
+	"github.com/trankhanh040147/prepf/internal/review"
+	"github.com/trankhanh040147/prepf/internal/rubric"
 	"github.com/trankhanh040147/prepf/internal/session"
+	"github.com/trankhanh040147/prepf/internal/skills"
+	"github.com/trankhanh040147/prepf/internal/sqlgym"
+	"github.com/trankhanh040147/prepf/internal/usage"
 	"golang.org/x/sync/errgroup"
 
 	"charm.land/fantasy/providers/anthropic"
@@ -45,7 +59,7 @@ import (
 type Coordinator interface {
 	// INFO: (kujtim) this is not used yet we will use this when we have multiple agents
 	// SetMainAgent(string)
-	Run(ctx context.Context, sessionID, prompt string, attachments ...message.Attachment) (*fantasy.AgentResult, error)
+	Run(ctx context.Context, sessionID, prompt string, opts RunOptions, attachments ...message.Attachment) (*fantasy.AgentResult, error)
 	Cancel(sessionID string)
 	CancelAll()
 	IsSessionBusy(sessionID string) bool
@@ -56,6 +70,14 @@ type Coordinator interface {
 	Summarize(context.Context, string) error
 	Model() Model
 	UpdateModels(ctx context.Context) error
+	// ReloadPrompts marks system prompts as outdated, e.g. after a context
+	// file or skill changed. Each agent rebuilds its prompt before its next
+	// run that starts while it is idle.
+	ReloadPrompts()
+	// ClassifyQuestions files graded drill questions under the topic
+	// taxonomy until ctx is done. Interactive sessions run it in the
+	// background.
+	ClassifyQuestions(ctx context.Context)
 }
 
 type coordinator struct {
@@ -64,11 +86,27 @@ type coordinator struct {
 	messages    message.Service
 	permissions permission.Service
 	history     history.Service
+	usage       usage.Service
+	drills      drill.Service
+	flashcards  flashcard.Service
+	debugging   debugging.Service
+	packs       pack.Service
 	lspClients  *csync.Map[string, *lsp.Client]
+	replay      *replay.Transport
 
 	currentAgent SessionAgent
 	agents       map[string]SessionAgent
 
+	// promptGen is bumped on every reload request. promptGens records the
+	// generation each agent's system prompt was built at and configGen the
+	// one the prompt paths were last reloaded at. promptMu guards both, the
+	// mode agents and the models of the current agent against concurrent
+	// runs.
+	promptGen  atomic.Int64
+	promptMu   sync.Mutex
+	promptGens map[string]int64
+	configGen  int64
+
 	readyWg errgroup.Group
 }
 
@@ -79,6 +117,11 @@ func NewCoordinator(
 	messages message.Service,
 	permissions permission.Service,
 	history history.Service,
+	usage usage.Service,
+	drills drill.Service,
+	flashcards flashcard.Service,
+	debugging debugging.Service,
+	packs pack.Service,
 	lspClients *csync.Map[string, *lsp.Client],
 ) (Coordinator, error) {
 	c := &coordinator{
@@ -87,8 +130,27 @@ func NewCoordinator(
 		messages:    messages,
 		permissions: permissions,
 		history:     history,
+		usage:       usage,
+		drills:      drills,
+		flashcards:  flashcards,
+		debugging:   debugging,
+		packs:       packs,
 		lspClients:  lspClients,
 		agents:      make(map[string]SessionAgent),
+		promptGens:  make(map[string]int64),
+	}
+
+	if replayCfg := cfg.Options.Replay; replayCfg != nil && replayCfg.Mode != "" {
+		var next http.RoundTripper = http.DefaultTransport
+		if cfg.Options.Debug {
+			next = &log.HTTPRoundTripLogger{Transport: next}
+		}
+		transport, err := replay.New(replay.Mode(replayCfg.Mode), replayCfg.Cassette, next)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("Provider traffic replay enabled", "mode", replayCfg.Mode, "cassette", replayCfg.Cassette)
+		c.replay = transport
 	}
 
 	agentCfg, ok := cfg.Agents[config.AgentCoder]
@@ -111,7 +173,20 @@ func NewCoordinator(
 	return c, nil
 }
 
+// RunOptions tunes a single [Coordinator.Run].
+type RunOptions struct {
+	// MaxTurns stops the run after that many model turns. Zero or less
+	// means no limit.
+	MaxTurns int
+	// Timing is how the user composed the prompt. It is stored with the
+	// user message and passed to the model.
+	Timing *message.AnswerTiming
+}
+
 func (c *coordinator) getPromptForMode(mode string) (*prompt.Prompt, error) {
+	if tmpl, ok := modeTemplate(c.cfg, mode); ok {
+		return prompt.NewPrompt(mode, tmpl, prompt.WithWorkingDir(c.cfg.WorkingDir()))
+	}
 	var p *prompt.Prompt
 	var err error
 	switch mode {
@@ -119,17 +194,38 @@ func (c *coordinator) getPromptForMode(mode string) (*prompt.Prompt, error) {
 		p, err = mockPrompt(prompt.WithWorkingDir(c.cfg.WorkingDir()))
 	case "gym":
 		p, err = gymPrompt(prompt.WithWorkingDir(c.cfg.WorkingDir()))
+	case "debug":
+		p, err = debugPrompt(prompt.WithWorkingDir(c.cfg.WorkingDir()))
 	default:
 		p, err = coderPrompt(prompt.WithWorkingDir(c.cfg.WorkingDir()))
 	}
 	return p, err
 }
 
+// debugTools are the tools the debugging agent may use. It runs the
+// candidate's commands and reads the workspace, but the fix has to be
+// theirs, so it can't edit files, and the tools that could reach outside
+// the workspace, where the exercise notes are, are left out.
+var debugTools = []string{
+	tools.BashToolName,
+	tools.JobOutputToolName,
+	tools.JobKillToolName,
+	tools.ViewToolName,
+	tools.LSToolName,
+	tools.GrepToolName,
+	tools.GlobToolName,
+	tools.DrillQuestionToolName,
+	tools.DrillGradeToolName,
+	tools.TodosToolName,
+}
+
 func (c *coordinator) ensureAgentForMode(ctx context.Context, mode string) error {
 	if mode == "" {
 		mode = "coder"
 	}
 	agentKey := "coder:" + mode
+	c.promptMu.Lock()
+	defer c.promptMu.Unlock()
 	if _, exists := c.agents[agentKey]; exists {
 		return nil
 	}
@@ -138,6 +234,13 @@ func (c *coordinator) ensureAgentForMode(ctx context.Context, mode string) error
 	if !ok {
 		return errors.New("coder agent not configured")
 	}
+	if mode == "debug" {
+		agentCfg.AllowedTools = slices.DeleteFunc(slices.Clone(agentCfg.AllowedTools), func(tool string) bool {
+			return !slices.Contains(debugTools, tool)
+		})
+		// NO MCPs: they aren't confined to the workspace.
+		agentCfg.AllowedMCP = map[string][]string{}
+	}
 
 	p, err := c.getPromptForMode(mode)
 	if err != nil {
@@ -149,6 +252,7 @@ func (c *coordinator) ensureAgentForMode(ctx context.Context, mode string) error
 		return err
 	}
 	c.agents[agentKey] = agent
+	c.promptGens[agentKey] = c.configGen
 	return nil
 }
 
@@ -157,14 +261,221 @@ func (c *coordinator) getAgentForMode(mode string) SessionAgent {
 		return c.currentAgent
 	}
 	agentKey := "coder:" + mode
+	c.promptMu.Lock()
+	defer c.promptMu.Unlock()
 	if agent, exists := c.agents[agentKey]; exists {
 		return agent
 	}
 	return c.currentAgent
 }
 
+// sessionPrompt collects the instructions specific to the session.
+func (c *coordinator) sessionPrompt(ctx context.Context, sess session.Session) string {
+	var parts []string
+	if skillsPrompt := c.activeSkillsPrompt(sess); skillsPrompt != "" {
+		parts = append(parts, skillsPrompt)
+	}
+	if p, round, ok := c.sessionPack(ctx, sess); ok {
+		parts = append(parts, p.Prompt(round))
+	}
+	if sess.Mode == "gym" {
+		if practicePrompt := c.duePracticePrompt(ctx); practicePrompt != "" {
+			parts = append(parts, practicePrompt)
+		}
+	}
+	if sess.Mode == "debug" {
+		if exercisePrompt := c.debugExercisePrompt(ctx, sess); exercisePrompt != "" {
+			parts = append(parts, exercisePrompt)
+		}
+	}
+	available, _ := rubric.Discover(rubric.Dirs(c.cfg))
+	if r, ok := c.pickRubric(ctx, sess, available); ok {
+		parts = append(parts, "Grade every answer in this session against this rubric, giving drill_grade the points of the level the answer reached on each dimension:\n"+r.Prompt())
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// pickRubric picks the rubric the session's answers are graded against,
+// weighted as the session's interview pack round says.
+func (c *coordinator) pickRubric(ctx context.Context, sess session.Session, available []*rubric.Rubric) (*rubric.Rubric, bool) {
+	r, ok := rubric.Pick(available, c.rubricNames(ctx, sess)...)
+	if !ok {
+		return nil, false
+	}
+	if p, round, ok := c.sessionPack(ctx, sess); ok {
+		r = p.Weigh(r, round)
+	}
+	return r, true
+}
+
+// rubricNames lists the rubrics the session's answers may be graded
+// against, from the most specific to the least: the debugging exercise's,
+// the interview pack round's, those of the active skills, the one
+// configured for the mode and the one named after the mode.
+func (c *coordinator) rubricNames(ctx context.Context, sess session.Session) []string {
+	var names []string
+	if sess.Mode == "debug" {
+		if run, err := c.debugging.ForSession(ctx, sess.ID); err == nil {
+			available, _ := debugging.Exercises.Discover(debugging.Exercises.Dirs(c.cfg))
+			if e, ok := debugging.Exercises.Find(available, run.Exercise); ok {
+				names = append(names, e.Rubric)
+			}
+		}
+	}
+	if p, round, ok := c.sessionPack(ctx, sess); ok {
+		names = append(names, p.RubricName(round))
+	}
+	if len(sess.Skills) > 0 {
+		available, _ := prompt.DiscoverSkills(*c.cfg)
+		for _, activation := range sess.Skills {
+			if skill, ok := skills.Find(available, activation.Name); ok {
+				names = append(names, skill.Metadata["rubric"])
+			}
+		}
+	}
+	return append(names, c.cfg.Options.Rubrics[sess.Mode], sess.Mode)
+}
+
+// sessionRubric implements tools.RubricFunc.
+func (c *coordinator) sessionRubric(ctx context.Context, sessionID, name string) (*rubric.Rubric, error) {
+	available, _ := rubric.Discover(rubric.Dirs(c.cfg))
+	if name != "" {
+		r, ok := rubric.Find(available, name)
+		if !ok {
+			names := make([]string, len(available))
+			for i, r := range available {
+				names[i] = r.Name
+			}
+			return nil, fmt.Errorf("there is no rubric named %q; use one of %s", name, strings.Join(names, ", "))
+		}
+		return r, nil
+	}
+	sess, err := c.sessions.Get(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	r, _ := c.pickRubric(ctx, sess, available)
+	return r, nil
+}
+
+// answerTiming returns the timing stored with the last user message of the
+// session, which is the one being answered while tools run.
+func (c *coordinator) answerTiming(ctx context.Context, sessionID string) *message.AnswerTiming {
+	msgs, err := c.messages.List(ctx, sessionID)
+	if err != nil {
+		slog.Warn("Failed to load messages for answer timing", "session", sessionID, "error", err)
+		return nil
+	}
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == message.User {
+			return msgs[i].Timing()
+		}
+	}
+	return nil
+}
+
+// sessionPack returns the interview pack the session practices a round of,
+// and the round's number.
+func (c *coordinator) sessionPack(ctx context.Context, sess session.Session) (*pack.Pack, int, bool) {
+	sel, err := c.packs.ForSession(ctx, sess.ID)
+	if err != nil {
+		if !errors.Is(err, pack.ErrNoPack) {
+			slog.Warn("Failed to load interview pack", "session", sess.ID, "error", err)
+		}
+		return nil, 0, false
+	}
+	available, _ := pack.DiscoverConfigured(c.cfg)
+	p, ok := pack.Find(available, sel.Pack)
+	if !ok {
+		slog.Warn("Interview pack not found", "session", sess.ID, "pack", sel.Pack)
+		return nil, 0, false
+	}
+	return p, sel.Round, true
+}
+
+// debugExercisePrompt describes the session's debugging exercise, including
+// the notes on the bug that only the interviewer gets to see.
+func (c *coordinator) debugExercisePrompt(ctx context.Context, sess session.Session) string {
+	run, err := c.debugging.ForSession(ctx, sess.ID)
+	if err != nil {
+		if !errors.Is(err, debugging.ErrNoRun) {
+			slog.Warn("Failed to load debugging exercise", "session", sess.ID, "error", err)
+		}
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("<debug_exercise>\n")
+	fmt.Fprintf(&sb, "Workspace: %s\nCheck command: %s\nStatus: %s after %d check(s)\n", run.Workspace, run.CheckCommand, run.Status, run.Checks)
+	if run.Status == debugging.StatusFixed {
+		sb.WriteString("The workspace was removed once the check passed; discuss the fix instead of running commands.\n")
+	}
+	available, _ := debugging.Exercises.Discover(debugging.Exercises.Dirs(c.cfg))
+	if e, ok := debugging.Exercises.Find(available, run.Exercise); ok {
+		fmt.Fprintf(&sb, "\nBug report:\n%s\n", strings.TrimSpace(e.Description))
+		if e.Notes != "" {
+			fmt.Fprintf(&sb, "\nInterviewer notes, never reveal them:\n%s\n", strings.TrimSpace(e.Notes))
+		}
+	}
+	sb.WriteString("</debug_exercise>")
+	return sb.String()
+}
+
+// sessionWorkspace returns the directory the session's shell commands and
+// file tools are confined to, or "" if they aren't. A debugging session without its
+// workspace is an error rather than a session that runs commands anywhere.
+func (c *coordinator) sessionWorkspace(ctx context.Context, sess session.Session) (string, error) {
+	if sess.Mode != "debug" {
+		return "", nil
+	}
+	run, err := c.debugging.ForSession(ctx, sess.ID)
+	if err != nil {
+		return "", fmt.Errorf("loading debugging workspace: %w", err)
+	}
+	return run.Workspace, nil
+}
+
+// duePracticePrompt lists the topics the user gave up on that are due for
+// re-practice.
+func (c *coordinator) duePracticePrompt(ctx context.Context) string {
+	due, err := c.drills.Due(ctx, time.Now())
+	if err != nil {
+		slog.Warn("Failed to list topics due for practice", "error", err)
+		return ""
+	}
+	var topics []string
+	for _, q := range due {
+		if q.Topic != "" && !slices.Contains(topics, q.Topic) {
+			topics = append(topics, q.Topic)
+		}
+	}
+	if len(topics) == 0 {
+		return ""
+	}
+	return "<due_for_practice>\nThe user gave up on these topics before. Work new questions on them into the session:\n- " +
+		strings.Join(topics, "\n- ") + "\n</due_for_practice>"
+}
+
+// activeSkillsPrompt renders the instructions of the skills activated for
+// the session. Skills that no longer exist are skipped.
+func (c *coordinator) activeSkillsPrompt(sess session.Session) string {
+	if len(sess.Skills) == 0 {
+		return ""
+	}
+	available, _ := prompt.DiscoverSkills(*c.cfg)
+	active := make([]*skills.Skill, 0, len(sess.Skills))
+	for _, activation := range sess.Skills {
+		skill, ok := skills.Find(available, activation.Name)
+		if !ok {
+			slog.Warn("Active skill not found", "session_id", sess.ID, "skill", activation.Name)
+			continue
+		}
+		active = append(active, skill)
+	}
+	return skills.ToActiveXML(active)
+}
+
 // Run implements Coordinator.
-func (c *coordinator) Run(ctx context.Context, sessionID string, prompt string, attachments ...message.Attachment) (*fantasy.AgentResult, error) {
+func (c *coordinator) Run(ctx context.Context, sessionID string, prompt string, opts RunOptions, attachments ...message.Attachment) (*fantasy.AgentResult, error) {
 	if err := c.readyWg.Wait(); err != nil {
 		return nil, err
 	}
@@ -178,8 +489,22 @@ func (c *coordinator) Run(ctx context.Context, sessionID string, prompt string,
 		return nil, err
 	}
 
+	downgrade, err := c.checkBudget(ctx, sess)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := c.sessionWorkspace(ctx, sess)
+	if err != nil {
+		return nil, err
+	}
+
 	agent := c.getAgentForMode(sess.Mode)
+	c.refreshSystemPrompt(ctx, sess.Mode, agent)
 	model := agent.Model()
+	if downgrade {
+		model = agent.SmallModel()
+	}
 	maxTokens := model.CatwalkCfg.DefaultMaxTokens
 	if model.ModelCfg.MaxTokens != 0 {
 		maxTokens = model.ModelCfg.MaxTokens
@@ -222,6 +547,11 @@ func (c *coordinator) Run(ctx context.Context, sessionID string, prompt string,
 			TopK:             topK,
 			FrequencyPenalty: freqPenalty,
 			PresencePenalty:  presPenalty,
+			UseSmallModel:    downgrade,
+			MaxSteps:         max(opts.MaxTurns, 0),
+			SessionPrompt:    c.sessionPrompt(ctx, sess),
+			Timing:           opts.Timing,
+			Workspace:        workspace,
 		})
 	}
 	result, originalErr := run()
@@ -248,6 +578,34 @@ func (c *coordinator) Run(ctx context.Context, sessionID string, prompt string,
 	return result, originalErr
 }
 
+// checkBudget enforces the configured usage budget before a prompt is sent.
+// It returns true when the request should fall back to the small model.
+func (c *coordinator) checkBudget(ctx context.Context, sess session.Session) (bool, error) {
+	budget := c.cfg.Options.Budget
+	if c.usage == nil || budget == nil {
+		return false, nil
+	}
+
+	exceeded, err := c.usage.Check(ctx, *budget, sess.ID, sess.Cost)
+	if err != nil {
+		slog.Error("Failed to check usage budget", "error", err)
+		return false, nil
+	}
+	if exceeded == nil {
+		return false, nil
+	}
+
+	if budget.Action() == config.BudgetActionDowngrade {
+		agent := c.getAgentForMode(sess.Mode)
+		large, small := agent.Model().ModelCfg, agent.SmallModel().ModelCfg
+		if large.Provider != small.Provider || large.Model != small.Model {
+			slog.Info("Usage budget exceeded, downgrading to small model", "period", exceeded.Period, "model", small.Model)
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("%w: %s", ErrBudgetExceeded, exceeded)
+}
+
 func getProviderOptions(model Model, providerCfg config.ProviderConfig) fantasy.ProviderOptions {
 	options := fantasy.ProviderOptions{}
 
@@ -401,6 +759,7 @@ func (c *coordinator) buildAgent(ctx context.Context, prompt *prompt.Prompt, age
 		c.sessions,
 		c.messages,
 		nil,
+		c.usage,
 	})
 	c.readyWg.Go(func() error {
 		tools, err := c.buildTools(ctx, agent)
@@ -445,15 +804,21 @@ func (c *coordinator) buildTools(ctx context.Context, agent config.Agent) ([]fan
 		tools.NewJobOutputTool(),
 		tools.NewJobKillTool(),
 		tools.NewDownloadTool(c.permissions, c.cfg.WorkingDir(), nil),
+		tools.NewDrillQuestionTool(c.drills, c.sessionRubric),
+		tools.NewDrillGradeTool(c.drills, c.sessionRubric, c.judgeAnswer, c.answerTiming),
 		tools.NewEditTool(c.lspClients, c.permissions, c.history, c.cfg.WorkingDir()),
 		tools.NewMultiEditTool(c.lspClients, c.permissions, c.history, c.cfg.WorkingDir()),
 		tools.NewFetchTool(c.permissions, c.cfg.WorkingDir(), nil),
+		tools.NewFlashcardsTool(c.flashcards),
 		tools.NewGlobTool(c.cfg.WorkingDir()),
 		tools.NewGrepTool(c.cfg.WorkingDir()),
 		tools.NewLsTool(c.permissions, c.cfg.WorkingDir(), c.cfg.Tools.Ls),
+		tools.NewQuizTool(quiz.Quizzes.Dirs(c.cfg)[0]),
+		tools.NewReviewExerciseTool(review.Exercises.Dirs(c.cfg)[0]),
 		tools.NewSourcegraphTool(nil),
+		tools.NewSQLExerciseTool(sqlgym.Exercises.Dirs(c.cfg)[0]),
 		tools.NewTodosTool(c.sessions),
-		tools.NewViewTool(c.lspClients, c.permissions, c.cfg.WorkingDir(), c.cfg.Options.SkillsPaths...),
+		tools.NewViewTool(c.lspClients, c.permissions, c.cfg.WorkingDir(), c.cfg.SkillsPaths()...),
 		tools.NewWriteTool(c.lspClients, c.permissions, c.history, c.cfg.WorkingDir()),
 	)
 
@@ -600,8 +965,7 @@ func (c *coordinator) buildAnthropicProvider(baseURL, apiKey string, headers map
 		opts = append(opts, anthropic.WithBaseURL(baseURL))
 	}
 
-	if c.cfg.Options.Debug {
-		httpClient := log.NewHTTPClient()
+	if httpClient := c.httpClient(); httpClient != nil {
 		opts = append(opts, anthropic.WithHTTPClient(httpClient))
 	}
 	return anthropic.New(opts...)
@@ -612,8 +976,7 @@ func (c *coordinator) buildOpenaiProvider(baseURL, apiKey string, headers map[st
 		openai.WithAPIKey(apiKey),
 		openai.WithUseResponsesAPI(),
 	}
-	if c.cfg.Options.Debug {
-		httpClient := log.NewHTTPClient()
+	if httpClient := c.httpClient(); httpClient != nil {
 		opts = append(opts, openai.WithHTTPClient(httpClient))
 	}
 	if len(headers) > 0 {
@@ -629,8 +992,7 @@ func (c *coordinator) buildOpenrouterProvider(_, apiKey string, headers map[stri
 	opts := []openrouter.Option{
 		openrouter.WithAPIKey(apiKey),
 	}
-	if c.cfg.Options.Debug {
-		httpClient := log.NewHTTPClient()
+	if httpClient := c.httpClient(); httpClient != nil {
 		opts = append(opts, openrouter.WithHTTPClient(httpClient))
 	}
 	if len(headers) > 0 {
@@ -650,8 +1012,8 @@ func (c *coordinator) buildOpenaiCompatProvider(baseURL, apiKey string, headers
 	if providerID == string(catwalk.InferenceProviderCopilot) {
 		opts = append(opts, openaicompat.WithUseResponsesAPI())
 		httpClient = copilot.NewClient(isSubAgent, c.cfg.Options.Debug)
-	} else if c.cfg.Options.Debug {
-		httpClient = log.NewHTTPClient()
+	} else {
+		httpClient = c.httpClient()
 	}
 	if httpClient != nil {
 		opts = append(opts, openaicompat.WithHTTPClient(httpClient))
@@ -674,8 +1036,7 @@ func (c *coordinator) buildAzureProvider(baseURL, apiKey string, headers map[str
 		azure.WithAPIKey(apiKey),
 		azure.WithUseResponsesAPI(),
 	}
-	if c.cfg.Options.Debug {
-		httpClient := log.NewHTTPClient()
+	if httpClient := c.httpClient(); httpClient != nil {
 		opts = append(opts, azure.WithHTTPClient(httpClient))
 	}
 	if options == nil {
@@ -693,8 +1054,7 @@ func (c *coordinator) buildAzureProvider(baseURL, apiKey string, headers map[str
 
 func (c *coordinator) buildBedrockProvider(headers map[string]string) (fantasy.Provider, error) {
 	var opts []bedrock.Option
-	if c.cfg.Options.Debug {
-		httpClient := log.NewHTTPClient()
+	if httpClient := c.httpClient(); httpClient != nil {
 		opts = append(opts, bedrock.WithHTTPClient(httpClient))
 	}
 	if len(headers) > 0 {
@@ -712,8 +1072,7 @@ func (c *coordinator) buildGoogleProvider(baseURL, apiKey string, headers map[st
 		google.WithBaseURL(baseURL),
 		google.WithGeminiAPIKey(apiKey),
 	}
-	if c.cfg.Options.Debug {
-		httpClient := log.NewHTTPClient()
+	if httpClient := c.httpClient(); httpClient != nil {
 		opts = append(opts, google.WithHTTPClient(httpClient))
 	}
 	if len(headers) > 0 {
@@ -724,8 +1083,7 @@ func (c *coordinator) buildGoogleProvider(baseURL, apiKey string, headers map[st
 
 func (c *coordinator) buildGoogleVertexProvider(headers map[string]string, options map[string]string) (fantasy.Provider, error) {
 	opts := []google.Option{}
-	if c.cfg.Options.Debug {
-		httpClient := log.NewHTTPClient()
+	if httpClient := c.httpClient(); httpClient != nil {
 		opts = append(opts, google.WithHTTPClient(httpClient))
 	}
 	if len(headers) > 0 {
@@ -745,13 +1103,24 @@ func (c *coordinator) buildHyperProvider(baseURL, apiKey string) (fantasy.Provid
 		hyper.WithBaseURL(baseURL),
 		hyper.WithAPIKey(apiKey),
 	}
-	if c.cfg.Options.Debug {
-		httpClient := log.NewHTTPClient()
+	if httpClient := c.httpClient(); httpClient != nil {
 		opts = append(opts, hyper.WithHTTPClient(httpClient))
 	}
 	return hyper.New(opts...)
 }
 
+// httpClient returns the HTTP client providers should use, or nil to use the
+// provider's default client.
+func (c *coordinator) httpClient() *http.Client {
+	if c.replay != nil {
+		return &http.Client{Transport: c.replay}
+	}
+	if c.cfg.Options.Debug {
+		return log.NewHTTPClient()
+	}
+	return nil
+}
+
 func (c *coordinator) isAnthropicThinking(model config.SelectedModel) bool {
 	if model.Think {
 		return true
@@ -860,7 +1229,9 @@ func (c *coordinator) UpdateModels(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	c.promptMu.Lock()
 	c.currentAgent.SetModels(large, small)
+	c.promptMu.Unlock()
 
 	agentCfg, ok := c.cfg.Agents[config.AgentCoder]
 	if !ok {
@@ -875,6 +1246,49 @@ func (c *coordinator) UpdateModels(ctx context.Context) error {
 	return nil
 }
 
+// ReloadPrompts implements Coordinator.
+func (c *coordinator) ReloadPrompts() {
+	c.promptGen.Add(1)
+}
+
+// refreshSystemPrompt rebuilds the agent's system prompt if a reload was
+// requested since it was built. Busy agents keep their prompt until their
+// next idle run so a response in progress never changes prompts midway.
+func (c *coordinator) refreshSystemPrompt(ctx context.Context, mode string, agent SessionAgent) {
+	key := config.AgentCoder
+	if mode != "" {
+		key = "coder:" + mode
+	}
+	c.promptMu.Lock()
+	defer c.promptMu.Unlock()
+	gen := c.promptGen.Load()
+	if c.promptGens[key] == gen || agent.IsBusy() {
+		return
+	}
+
+	if c.configGen != gen {
+		if err := c.cfg.ReloadPromptPaths(); err != nil {
+			slog.Warn("Failed to reload prompt paths from config", "error", err)
+		}
+		c.configGen = gen
+	}
+
+	p, err := c.getPromptForMode(mode)
+	if err != nil {
+		slog.Warn("Failed to reload system prompt", "mode", mode, "error", err)
+		return
+	}
+	model := agent.Model()
+	systemPrompt, err := p.Build(ctx, model.Model.Provider(), model.Model.Model(), *c.cfg)
+	if err != nil {
+		slog.Warn("Failed to reload system prompt", "mode", mode, "error", err)
+		return
+	}
+	agent.SetSystemPrompt(systemPrompt)
+	c.promptGens[key] = gen
+	slog.Info("Reloaded system prompt", "mode", mode)
+}
+
 func (c *coordinator) QueuedPrompts(sessionID string) int {
 	return c.currentAgent.QueuedPrompts(sessionID)
 }
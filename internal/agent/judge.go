@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/judge"
+	"github.com/trankhanh040147/prepf/internal/message"
+	"github.com/trankhanh040147/prepf/internal/rubric"
+)
+
+//go:embed templates/judge.md
+var judgePrompt []byte
+
+// judgeAnswer grades the answer to the open question with the independent
+// grader calls configured in options.judging, against the rubric if not
+// nil. It returns nil if judging is off or the session is over its usage
+// budget, leaving the interviewer's grade to stand. Like classification,
+// judging is skipped when replaying provider traffic.
+func (c *coordinator) judgeAnswer(ctx context.Context, q drill.Question, r *rubric.Rubric) (*judge.Verdict, error) {
+	judging := c.cfg.Options.Judging
+	if !judging.Enabled() || c.replay != nil {
+		return nil, nil
+	}
+	sess, err := c.sessions.Get(ctx, q.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	downgrade, err := c.checkBudget(ctx, sess)
+	if err != nil {
+		slog.Info("Not judging drill answer", "question", q.ID, "error", err)
+		return nil, nil
+	}
+	answer, err := c.candidateAnswer(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	models, err := c.judgeModels(ctx, judging.Models, downgrade)
+	if err != nil {
+		return nil, err
+	}
+
+	request := judgeRequest(q, answer, r)
+	samples := judge.Run(ctx, judging.Samples, judging.Limit(), func(ctx context.Context, i int) drill.Sample {
+		model := models[i%len(models)]
+		sample := drill.Sample{Model: model.ModelCfg.Provider + "/" + model.ModelCfg.Model}
+		resp, err := c.callModel(ctx, model, string(judgePrompt), request, 1024)
+		if err != nil {
+			slog.Warn("Grader call failed", "question", q.ID, "model", sample.Model, "error", err)
+			sample.Err = err.Error()
+			return sample
+		}
+		c.recordCallUsage(ctx, q.SessionID, model, resp.TotalUsage)
+		judge.Parse(&sample, resp.Response.Content.Text(), r)
+		return sample
+	})
+	verdict := judge.Aggregate(samples, r, judging.AgreementTolerance(), judging.AgreementThreshold())
+	slog.Debug("Judged drill answer", "question", q.ID, "score", verdict.Score, "graded", verdict.Graded, "agreement", verdict.Agreement)
+	return &verdict, nil
+}
+
+// candidateAnswer returns what the user wrote since the question was asked.
+// Control messages, such as the hints /hint asks for, aren't part of it.
+func (c *coordinator) candidateAnswer(ctx context.Context, q drill.Question) (string, error) {
+	msgs, err := c.messages.List(ctx, q.SessionID)
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+	for _, msg := range msgs {
+		if msg.Role != message.User || msg.CreatedAt < q.CreatedAt {
+			continue
+		}
+		text := strings.TrimSpace(msg.Content().Text)
+		if _, ok := ParseControl(text); ok || text == "" {
+			continue
+		}
+		parts = append(parts, text)
+	}
+	if len(parts) == 0 {
+		return "", errors.New("no answer to judge")
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// judgeModels builds the models grader calls take turns on, the large
+// model if none are configured. When the usage budget downgrades the
+// session, they all run on the small model.
+func (c *coordinator) judgeModels(ctx context.Context, selected []config.SelectedModel, downgrade bool) ([]Model, error) {
+	c.promptMu.Lock()
+	large, small := c.currentAgent.Model(), c.currentAgent.SmallModel()
+	c.promptMu.Unlock()
+	if downgrade {
+		return []Model{small}, nil
+	}
+	if len(selected) == 0 {
+		return []Model{large}, nil
+	}
+	models := make([]Model, 0, len(selected))
+	for _, s := range selected {
+		model, err := c.buildModel(ctx, s)
+		if err != nil {
+			return nil, fmt.Errorf("grader model %s/%s: %w", s.Provider, s.Model, err)
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+// buildModel builds a model outside of the agent's large and small ones.
+func (c *coordinator) buildModel(ctx context.Context, selected config.SelectedModel) (Model, error) {
+	providerCfg, ok := c.cfg.Providers.Get(selected.Provider)
+	if !ok {
+		return Model{}, errors.New("provider not configured")
+	}
+	catwalkModel := c.cfg.GetModel(selected.Provider, selected.Model)
+	if catwalkModel == nil {
+		return Model{}, errors.New("model not found in provider config")
+	}
+	provider, err := c.buildProvider(providerCfg, selected, true)
+	if err != nil {
+		return Model{}, err
+	}
+	languageModel, err := provider.LanguageModel(ctx, selected.Model)
+	if err != nil {
+		return Model{}, err
+	}
+	return Model{
+		Model:      languageModel,
+		CatwalkCfg: *catwalkModel,
+		ModelCfg:   selected,
+	}, nil
+}
+
+func judgeRequest(q drill.Question, answer string, r *rubric.Rubric) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<question>\n%s\n</question>\n\n", q.Text)
+	if q.Answer != "" {
+		fmt.Fprintf(&b, "<reference_answer>\n%s\n</reference_answer>\n\n", q.Answer)
+	}
+	fmt.Fprintf(&b, "<candidate_answer>\n%s\n</candidate_answer>\n\n", answer)
+	if r == nil {
+		b.WriteString(`Reply with {"score": <0-100>, "rationale": "<one line>"}.`)
+		return b.String()
+	}
+	schema, _ := json.Marshal(r.Schema())
+	fmt.Fprintf(&b, "%s\n\n", r.Prompt())
+	fmt.Fprintf(&b, `Reply with {"dimensions": <grades>, "rationale": "<one line>"}, where the grades match this JSON schema: %s`, schema)
+	return b.String()
+}
@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"github.com/trankhanh040147/prepf/internal/config"
-	"github.com/trankhanh040147/prepf/internal/home"
 	"github.com/trankhanh040147/prepf/internal/shell"
 	"github.com/trankhanh040147/prepf/internal/skills"
 )
@@ -135,17 +134,18 @@ func processContextPath(p string, cfg config.Config) []ContextFile {
 	return contexts
 }
 
-// expandPath expands ~ and environment variables in file paths
-func expandPath(path string, cfg config.Config) string {
-	path = home.Long(path)
-	// Handle environment variable expansion using the same pattern as config
-	if strings.HasPrefix(path, "$") {
-		if expanded, err := cfg.Resolver().ResolveValue(path); err == nil {
-			path = expanded
-		}
+// DiscoverSkills loads the skills in the configured skills paths, along with
+// the skill files that are invalid.
+func DiscoverSkills(cfg config.Config) ([]*skills.Skill, []skills.Problem) {
+	skillsPaths := cfg.SkillsPaths()
+	if len(skillsPaths) == 0 {
+		return nil, nil
 	}
-
-	return path
+	expandedPaths := make([]string, 0, len(skillsPaths))
+	for _, pth := range skillsPaths {
+		expandedPaths = append(expandedPaths, cfg.ExpandPath(pth))
+	}
+	return skills.DiscoverWithProblems(expandedPaths)
 }
 
 func (p *Prompt) promptData(ctx context.Context, provider, model string, cfg config.Config) (PromptDat, error) {
@@ -154,8 +154,8 @@ func (p *Prompt) promptData(ctx context.Context, provider, model string, cfg con
 
 	files := map[string][]ContextFile{}
 
-	for _, pth := range cfg.Options.ContextPaths {
-		expanded := expandPath(pth, cfg)
+	for _, pth := range cfg.ContextPaths() {
+		expanded := cfg.ExpandPath(pth)
 		pathKey := strings.ToLower(expanded)
 		if _, ok := files[pathKey]; ok {
 			continue
@@ -166,14 +166,8 @@ func (p *Prompt) promptData(ctx context.Context, provider, model string, cfg con
 
 	// Discover and load skills metadata.
 	var availSkillXML string
-	if len(cfg.Options.SkillsPaths) > 0 {
-		expandedPaths := make([]string, 0, len(cfg.Options.SkillsPaths))
-		for _, pth := range cfg.Options.SkillsPaths {
-			expandedPaths = append(expandedPaths, expandPath(pth, cfg))
-		}
-		if discoveredSkills := skills.Discover(expandedPaths); len(discoveredSkills) > 0 {
-			availSkillXML = skills.ToPromptXML(discoveredSkills)
-		}
+	if discoveredSkills, _ := DiscoverSkills(cfg); len(discoveredSkills) > 0 {
+		availSkillXML = skills.ToPromptXML(discoveredSkills)
 	}
 
 	isGit := isGitRepo(cfg.WorkingDir())
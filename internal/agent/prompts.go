@@ -3,6 +3,8 @@ package agent
 import (
 	"context"
 	_ "embed"
+	"os"
+	"path/filepath"
 
 	"github.com/trankhanh040147/prepf/internal/agent/prompt"
 	"github.com/trankhanh040147/prepf/internal/config"
@@ -23,6 +25,9 @@ var mockPromptTmpl []byte
 //go:embed templates/gym.md.tpl
 var gymPromptTmpl []byte
 
+//go:embed templates/debug.md.tpl
+var debugPromptTmpl []byte
+
 func coderPrompt(opts ...prompt.Option) (*prompt.Prompt, error) {
 	systemPrompt, err := prompt.NewPrompt("coder", string(coderPromptTmpl), opts...)
 	if err != nil {
@@ -62,3 +67,34 @@ func gymPrompt(opts ...prompt.Option) (*prompt.Prompt, error) {
 	}
 	return systemPrompt, nil
 }
+
+func debugPrompt(opts ...prompt.Option) (*prompt.Prompt, error) {
+	systemPrompt, err := prompt.NewPrompt("debug", string(debugPromptTmpl), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return systemPrompt, nil
+}
+
+// ModeTemplateDirs returns the directories mode templates are loaded from,
+// highest priority first. A file named <mode>.md.tpl in one of them
+// overrides the built-in template for that mode, or defines a new mode.
+func ModeTemplateDirs(cfg *config.Config) []string {
+	return []string{
+		filepath.Join(cfg.Options.DataDirectory, "modes"),
+		filepath.Join(filepath.Dir(config.GlobalConfig()), "modes"),
+	}
+}
+
+func modeTemplate(cfg *config.Config, mode string) (string, bool) {
+	if mode == "" || filepath.Base(mode) != mode {
+		return "", false
+	}
+	for _, dir := range ModeTemplateDirs(cfg) {
+		content, err := os.ReadFile(filepath.Join(dir, mode+".md.tpl"))
+		if err == nil {
+			return string(content), true
+		}
+	}
+	return "", false
+}
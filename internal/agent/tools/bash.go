@@ -197,6 +197,25 @@ func NewBashTool(permissions permission.Service, workingDir string, attribution
 
 			// Determine working directory
 			execWorkingDir := cmp.Or(params.WorkingDir, workingDir)
+			// In a confined session, such as a debugging exercise, commands
+			// run from the workspace and may not leave it.
+			workspace := GetWorkspaceFromContext(ctx)
+			if workspace != "" {
+				execWorkingDir = workspace
+				if filepath.IsAbs(params.WorkingDir) {
+					execWorkingDir = filepath.Clean(params.WorkingDir)
+				} else if params.WorkingDir != "" {
+					execWorkingDir = filepath.Join(workspace, params.WorkingDir)
+				}
+				if !shell.Within(workspace, execWorkingDir) {
+					return fantasy.NewTextErrorResponse(fmt.Sprintf("working_dir must be inside the workspace %s", workspace)), nil
+				}
+			}
+			shellOpts := &shell.Options{
+				WorkingDir: execWorkingDir,
+				BlockFuncs: blockFuncs(),
+				Root:       workspace,
+			}
 
 			isSafeReadOnly := false
 			cmdLower := strings.ToLower(params.Command)
@@ -237,7 +256,7 @@ func NewBashTool(permissions permission.Service, workingDir string, attribution
 				bgManager := shell.GetBackgroundShellManager()
 				bgManager.Cleanup()
 				// Use background context so it continues after tool returns
-				bgShell, err := bgManager.Start(context.Background(), execWorkingDir, blockFuncs(), params.Command, params.Description)
+				bgShell, err := bgManager.StartWithOptions(context.Background(), shellOpts, params.Command, params.Description)
 				if err != nil {
 					return fantasy.ToolResponse{}, fmt.Errorf("error starting background shell: %w", err)
 				}
@@ -292,7 +311,7 @@ func NewBashTool(permissions permission.Service, workingDir string, attribution
 			// Start with detached context so it can survive if moved to background
 			bgManager := shell.GetBackgroundShellManager()
 			bgManager.Cleanup()
-			bgShell, err := bgManager.Start(context.Background(), execWorkingDir, blockFuncs(), params.Command, params.Description)
+			bgShell, err := bgManager.StartWithOptions(context.Background(), shellOpts, params.Command, params.Description)
 			if err != nil {
 				return fantasy.ToolResponse{}, fmt.Errorf("error starting shell: %w", err)
 			}
@@ -0,0 +1,243 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/judge"
+	"github.com/trankhanh040147/prepf/internal/message"
+	"github.com/trankhanh040147/prepf/internal/rubric"
+)
+
+//go:embed drill_question.md
+var drillQuestionDescription []byte
+
+//go:embed drill_grade.md
+var drillGradeDescription []byte
+
+const (
+	DrillQuestionToolName = "drill_question"
+	DrillGradeToolName    = "drill_grade"
+)
+
+type DrillQuestionParams struct {
+	Topic           string `json:"topic" description:"Short name of the topic the question practices, e.g. 'Go channels'"`
+	Question        string `json:"question" description:"The question exactly as it will be asked"`
+	Nudge           string `json:"nudge" description:"Hint level 1: points at the relevant concept"`
+	Approach        string `json:"approach" description:"Hint level 2: the strategy to take"`
+	PartialSolution string `json:"partial_solution" description:"Hint level 3: most of the answer, without the final step"`
+	Answer          string `json:"answer" description:"The complete model answer"`
+	Rubric          string `json:"rubric,omitempty" description:"Name of the rubric to grade the answer against, only when the question calls for another one than the session's"`
+}
+
+type DrillGradeParams struct {
+	Score      *int                    `json:"score,omitempty" description:"Score from 0 to 100 for the answer before hint penalties, only when the session has no rubric"`
+	Dimensions []rubric.DimensionScore `json:"dimensions,omitempty" description:"The level the answer reached on each dimension of the rubric, all of them"`
+	Feedback   string                  `json:"feedback,omitempty" description:"One line summary of what was right or missing"`
+}
+
+type DrillResponseMetadata struct {
+	Topic      string                  `json:"topic"`
+	HintsUsed  int                     `json:"hints_used"`
+	RawScore   int                     `json:"raw_score,omitempty"`
+	Score      int                     `json:"score,omitempty"`
+	Rubric     string                  `json:"rubric,omitempty"`
+	Dimensions []rubric.DimensionScore `json:"dimensions,omitempty"`
+	Graders    int                     `json:"graders,omitempty"`
+	Agreement  *float64                `json:"agreement,omitempty"`
+	Flagged    bool                    `json:"flagged,omitempty"`
+}
+
+// RubricFunc returns the rubric answers in the session are graded against:
+// the named one, or the session's if name is empty. It returns nil if the
+// session has no rubric and an error if the named one doesn't exist.
+type RubricFunc func(ctx context.Context, sessionID, name string) (*rubric.Rubric, error)
+
+// JudgeFunc grades the answer to the open question with independent grader
+// calls, against the rubric if not nil. It returns nil if judging is off.
+type JudgeFunc func(ctx context.Context, q drill.Question, r *rubric.Rubric) (*judge.Verdict, error)
+
+// TimingFunc returns how the user composed the message being answered in the
+// session, or nil if it wasn't recorded.
+type TimingFunc func(ctx context.Context, sessionID string) *message.AnswerTiming
+
+func NewDrillQuestionTool(drills drill.Service, rubrics RubricFunc) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		DrillQuestionToolName,
+		string(drillQuestionDescription),
+		func(ctx context.Context, params DrillQuestionParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			sessionID := GetSessionFromContext(ctx)
+			if sessionID == "" {
+				return fantasy.ToolResponse{}, fmt.Errorf("session ID is required for recording questions")
+			}
+			if params.Question == "" || params.Answer == "" {
+				return fantasy.NewTextErrorResponse("question and answer are required"), nil
+			}
+
+			r, err := rubrics(ctx, sessionID, params.Rubric)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			question := drill.Question{
+				SessionID: sessionID,
+				Topic:     params.Topic,
+				Text:      params.Question,
+				Hints:     []string{params.Nudge, params.Approach, params.PartialSolution},
+				Answer:    params.Answer,
+			}
+			if r != nil {
+				question.Rubric = r.Name
+				question.RubricVersion = r.Version
+			}
+			q, err := drills.Ask(ctx, question)
+			if err != nil {
+				return fantasy.ToolResponse{}, fmt.Errorf("failed to record question: %w", err)
+			}
+
+			response := "Question recorded. Ask it now without revealing the hints or the answer."
+			if r != nil && params.Rubric != "" {
+				response += "\n\nGrade the answer against this rubric instead of the session's:\n" + r.Prompt()
+			}
+			return fantasy.WithResponseMetadata(fantasy.NewTextResponse(response), DrillResponseMetadata{
+				Topic: q.Topic,
+			}), nil
+		})
+}
+
+func NewDrillGradeTool(drills drill.Service, rubrics RubricFunc, judgeAnswer JudgeFunc, answerTiming TimingFunc) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		DrillGradeToolName,
+		string(drillGradeDescription),
+		func(ctx context.Context, params DrillGradeParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			sessionID := GetSessionFromContext(ctx)
+			if sessionID == "" {
+				return fantasy.ToolResponse{}, fmt.Errorf("session ID is required for grading answers")
+			}
+
+			open, err := drills.Current(ctx, sessionID)
+			switch {
+			case errors.Is(err, drill.ErrNoQuestion):
+				return fantasy.NewTextErrorResponse("there is no open question to grade; record questions with drill_question first"), nil
+			case err != nil:
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			// Questions recorded for the user, such as the daily challenge,
+			// are graded against the session's rubric.
+			r, err := rubrics(ctx, sessionID, open.Rubric)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+
+			if r != nil {
+				if err := r.Check(params.Dimensions); err != nil {
+					return fantasy.NewTextErrorResponse(reaskRubric(r, err)), nil
+				}
+			} else if params.Score == nil {
+				return fantasy.NewTextErrorResponse("the session has no rubric, so a score is required; nothing was recorded. Call drill_grade again with a score from 0 to 100"), nil
+			}
+
+			// The interviewer's grade only stands if no grader call gave a
+			// usable one.
+			var score int
+			if params.Score != nil {
+				score = *params.Score
+			}
+			dimensions := params.Dimensions
+			verdict, err := judgeAnswer(ctx, open, r)
+			if err != nil {
+				slog.Error("Failed to judge drill answer", "question", open.ID, "error", err)
+			}
+			if verdict != nil && verdict.Graded > 0 {
+				score, dimensions = verdict.Score, verdict.Dimensions
+			}
+
+			timing := answerTiming(ctx, sessionID)
+			var q drill.Question
+			if r == nil {
+				q, err = drills.Grade(ctx, sessionID, score, timing)
+			} else {
+				q, err = drills.GradeRubric(ctx, sessionID, r, dimensions, timing)
+			}
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			if verdict != nil {
+				if q, err = drills.Judge(ctx, q.ID, verdict.Samples, verdict.Agreement, verdict.Flagged); err != nil {
+					return fantasy.NewTextErrorResponse(err.Error()), nil
+				}
+			}
+
+			response := fmt.Sprintf("Answer graded %d/100", *q.Score)
+			if q.HintsUsed > drill.HintNone {
+				response += fmt.Sprintf(" (%d/100 before the penalty for %d hint(s))", *q.RawScore, q.HintsUsed)
+			}
+			if r != nil {
+				response += fmt.Sprintf(" against the %s rubric, version %d: %s", r.Name, r.Version, dimensionSummary(r, q.Dimensions))
+			}
+			response += "."
+			if verdict != nil {
+				response += judgeSummary(verdict)
+			}
+			return fantasy.WithResponseMetadata(fantasy.NewTextResponse(response), DrillResponseMetadata{
+				Topic:      q.Topic,
+				HintsUsed:  int(q.HintsUsed),
+				RawScore:   *q.RawScore,
+				Score:      *q.Score,
+				Rubric:     q.Rubric,
+				Dimensions: q.Dimensions,
+				Graders:    verdictGraders(verdict),
+				Agreement:  q.Agreement,
+				Flagged:    q.Flagged,
+			}), nil
+		})
+}
+
+// reaskRubric explains what is wrong with the grades and the shape they
+// must take, so the model calls drill_grade again with valid ones.
+func reaskRubric(r *rubric.Rubric, err error) string {
+	schema, _ := json.MarshalIndent(r.Schema(), "", "  ")
+	return fmt.Sprintf(
+		"The grades don't match the %s rubric:\n- %s\n\nNothing was recorded. Call drill_grade again with `dimensions` matching this JSON schema:\n%s",
+		r.Name, strings.ReplaceAll(err.Error(), "\n", "\n- "), schema,
+	)
+}
+
+// judgeSummary tells the interviewer how the grade was judged, so it
+// reports the judged grade rather than its own.
+func judgeSummary(v *judge.Verdict) string {
+	if v.Graded == 0 {
+		return fmt.Sprintf(" None of the %d grader calls gave a usable grade, so your grade was kept and flagged for review.", len(v.Samples))
+	}
+	summary := fmt.Sprintf(" This is the median of %d independent grader calls, %.0f%% of which agreed with it; report it instead of your own grade.", v.Graded, 100*v.Agreement)
+	switch {
+	case v.Flagged && v.Graded < 2:
+		summary += " The grade is flagged for review as too few grader calls gave a usable grade; tell the user it is uncertain."
+	case v.Flagged:
+		summary += " The grade is flagged for review as the graders disagreed; tell the user it is uncertain."
+	}
+	return summary
+}
+
+func verdictGraders(v *judge.Verdict) int {
+	if v == nil {
+		return 0
+	}
+	return v.Graded
+}
+
+// dimensionSummary lists the points earned on each dimension.
+func dimensionSummary(r *rubric.Rubric, scores []rubric.DimensionScore) string {
+	parts := make([]string, 0, len(scores))
+	for _, s := range scores {
+		d, _ := r.Dimension(s.Dimension)
+		parts = append(parts, fmt.Sprintf("%s %d/%d", s.Dimension, s.Points, d.MaxPoints()))
+	}
+	return strings.Join(parts, ", ")
+}
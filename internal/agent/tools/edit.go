@@ -154,7 +154,7 @@ func createNewFile(edit editContext, filePath, content string, call fantasy.Tool
 	}
 
 	// Add the new content to the file history
-	_, err = edit.files.CreateVersion(edit.ctx, sessionID, filePath, content)
+	_, err = edit.files.CreateToolCallVersion(edit.ctx, sessionID, call.ID, filePath, content)
 	if err != nil {
 		// Log error but don't fail the operation
 		slog.Error("Error creating file history version", "error", err)
@@ -288,7 +288,7 @@ func deleteContent(edit editContext, filePath, oldString string, replaceAll bool
 		}
 	}
 	// Store the new version
-	_, err = edit.files.CreateVersion(edit.ctx, sessionID, filePath, "")
+	_, err = edit.files.CreateToolCallVersion(edit.ctx, sessionID, call.ID, filePath, "")
 	if err != nil {
 		slog.Error("Error creating file history version", "error", err)
 	}
@@ -423,7 +423,7 @@ func replaceContent(edit editContext, filePath, oldString, newString string, rep
 		}
 	}
 	// Store the new version
-	_, err = edit.files.CreateVersion(edit.ctx, sessionID, filePath, newContent)
+	_, err = edit.files.CreateToolCallVersion(edit.ctx, sessionID, call.ID, filePath, newContent)
 	if err != nil {
 		slog.Error("Error creating file history version", "error", err)
 	}
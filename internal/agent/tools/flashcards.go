@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"charm.land/fantasy"
+	"github.com/trankhanh040147/prepf/internal/flashcard"
+)
+
+//go:embed flashcards.md
+var flashcardsDescription []byte
+
+const FlashcardsToolName = "flashcards"
+
+// maxFlashcards caps the cards saved per call so a long roast doesn't flood
+// the review queue.
+const maxFlashcards = 5
+
+type FlashcardParams struct {
+	Front string `json:"front" description:"A short question testing one fact"`
+	Back  string `json:"back" description:"The shortest complete answer"`
+}
+
+type FlashcardsParams struct {
+	Topic string            `json:"topic" description:"Short name of the topic the cards cover, e.g. 'Go maps'"`
+	Cards []FlashcardParams `json:"cards" description:"The cards to save, at most 5"`
+}
+
+type FlashcardsResponseMetadata struct {
+	Topic  string   `json:"topic"`
+	Fronts []string `json:"fronts"`
+}
+
+func NewFlashcardsTool(cards flashcard.Service) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		FlashcardsToolName,
+		string(flashcardsDescription),
+		func(ctx context.Context, params FlashcardsParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			sessionID := GetSessionFromContext(ctx)
+			if sessionID == "" {
+				return fantasy.ToolResponse{}, fmt.Errorf("session ID is required for saving flashcards")
+			}
+			if len(params.Cards) == 0 {
+				return fantasy.NewTextErrorResponse("at least one card is required"), nil
+			}
+			if len(params.Cards) > maxFlashcards {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("at most %d cards can be saved at once", maxFlashcards)), nil
+			}
+
+			messageID := GetMessageFromContext(ctx)
+			toCreate := make([]flashcard.Card, len(params.Cards))
+			for i, c := range params.Cards {
+				toCreate[i] = flashcard.Card{
+					SessionID: sessionID,
+					MessageID: messageID,
+					Topic:     params.Topic,
+					Front:     c.Front,
+					Back:      c.Back,
+				}
+			}
+			created, err := cards.Create(ctx, toCreate...)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+
+			meta := FlashcardsResponseMetadata{Topic: params.Topic}
+			for _, c := range created {
+				meta.Fronts = append(meta.Fronts, c.Front)
+			}
+			response := fmt.Sprintf("Saved %d flashcard(s) for review.", len(created))
+			return fantasy.WithResponseMetadata(fantasy.NewTextResponse(response), meta), nil
+		})
+}
@@ -39,7 +39,10 @@ func NewGlobTool(workingDir string) fantasy.AgentTool {
 				return fantasy.NewTextErrorResponse("pattern is required"), nil
 			}
 
-			searchPath := params.Path
+			workingDir, searchPath, err := confine(ctx, workingDir, params.Path)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
 			if searchPath == "" {
 				searchPath = workingDir
 			}
@@ -127,7 +127,10 @@ func NewGrepTool(workingDir string) fantasy.AgentTool {
 				searchPattern = escapeRegexPattern(params.Pattern)
 			}
 
-			searchPath := params.Path
+			workingDir, searchPath, err := confine(ctx, workingDir, params.Path)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
 			if searchPath == "" {
 				searchPath = workingDir
 			}
@@ -28,7 +28,7 @@ import (
 var (
 	sessions = csync.NewMap[string, *mcp.ClientSession]()
 	states   = csync.NewMap[string, ClientInfo]()
-	broker   = pubsub.NewBroker[Event]()
+	broker   = pubsub.NewBroker(pubsub.WithName[Event]("mcp"))
 )
 
 // State represents the current state of an MCP client
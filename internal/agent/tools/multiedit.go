@@ -202,7 +202,7 @@ func processMultiEditWithCreation(edit editContext, params MultiEditParams, call
 		return fantasy.ToolResponse{}, fmt.Errorf("error creating file history: %w", err)
 	}
 
-	_, err = edit.files.CreateVersion(edit.ctx, sessionID, params.FilePath, currentContent)
+	_, err = edit.files.CreateToolCallVersion(edit.ctx, sessionID, call.ID, params.FilePath, currentContent)
 	if err != nil {
 		slog.Error("Error creating file history version", "error", err)
 	}
@@ -358,7 +358,7 @@ func processMultiEditExistingFile(edit editContext, params MultiEditParams, call
 	}
 
 	// Store the new version
-	_, err = edit.files.CreateVersion(edit.ctx, sessionID, params.FilePath, currentContent)
+	_, err = edit.files.CreateToolCallVersion(edit.ctx, sessionID, call.ID, params.FilePath, currentContent)
 	if err != nil {
 		slog.Error("Error creating file history version", "error", err)
 	}
@@ -53,6 +53,10 @@ func (m *mockHistoryService) CreateVersion(ctx context.Context, sessionID, path,
 	return history.File{}, nil
 }
 
+func (m *mockHistoryService) CreateToolCallVersion(ctx context.Context, sessionID, toolCallID, path, content string) (history.File, error) {
+	return history.File{}, nil
+}
+
 func (m *mockHistoryService) GetByPathAndSession(ctx context.Context, path, sessionID string) (history.File, error) {
 	return history.File{Path: path, Content: ""}, nil
 }
@@ -77,6 +81,18 @@ func (m *mockHistoryService) DeleteSessionFiles(ctx context.Context, sessionID s
 	return nil
 }
 
+func (m *mockHistoryService) PlanRevert(ctx context.Context, sessionID string, toolCallIDs []string) ([]history.Change, error) {
+	return nil, nil
+}
+
+func (m *mockHistoryService) PlanRestore(ctx context.Context, sessionID, path string, version int64) (history.Change, error) {
+	return history.Change{}, nil
+}
+
+func (m *mockHistoryService) Revert(ctx context.Context, sessionID string, changes []history.Change, force bool) error {
+	return nil
+}
+
 func TestApplyEditToContentPartialSuccess(t *testing.T) {
 	t.Parallel()
 
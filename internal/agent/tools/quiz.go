@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"charm.land/fantasy"
+	"github.com/trankhanh040147/prepf/internal/quiz"
+)
+
+//go:embed quiz.md
+var quizDescription []byte
+
+const QuizToolName = "quiz"
+
+type QuizQuestionParams struct {
+	Type        string   `json:"type" description:"One of single, multi, true_false or short"`
+	Prompt      string   `json:"prompt" description:"The question"`
+	Choices     []string `json:"choices,omitempty" description:"The options of single and multi questions"`
+	Answer      []string `json:"answer,omitempty" description:"The correct choices, true or false, or the accepted short answers"`
+	Pattern     string   `json:"pattern,omitempty" description:"Regular expression a short answer may match instead"`
+	Explanation string   `json:"explanation,omitempty" description:"Why the answer is correct, shown after answering"`
+}
+
+type QuizParams struct {
+	Title     string               `json:"title" description:"Title of the quiz, also used for its file name"`
+	Topic     string               `json:"topic" description:"Short name of the topic the quiz covers, e.g. 'Go channels'"`
+	Questions []QuizQuestionParams `json:"questions" description:"The questions, in order"`
+}
+
+type QuizResponseMetadata struct {
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Questions int    `json:"questions"`
+}
+
+// NewQuizTool returns the tool that saves generated quizzes to dir.
+func NewQuizTool(dir string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		QuizToolName,
+		string(quizDescription),
+		func(ctx context.Context, params QuizParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			q := &quiz.Quiz{
+				Title: params.Title,
+				Topic: params.Topic,
+			}
+			for _, question := range params.Questions {
+				q.Questions = append(q.Questions, quiz.Question{
+					Type:        quiz.Type(question.Type),
+					Prompt:      question.Prompt,
+					Choices:     question.Choices,
+					Answer:      question.Answer,
+					Pattern:     question.Pattern,
+					Explanation: question.Explanation,
+				})
+			}
+			path, err := quiz.Quizzes.Save(dir, q.Title, q)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+
+			meta := QuizResponseMetadata{
+				Name:      q.Name,
+				Path:      path,
+				Questions: len(q.Questions),
+			}
+			response := fmt.Sprintf("Saved quiz %q with %d question(s). The user can start it with /quiz %s.", q.Name, len(q.Questions), q.Name)
+			return fantasy.WithResponseMetadata(fantasy.NewTextResponse(response), meta), nil
+		})
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"charm.land/fantasy"
+	"github.com/trankhanh040147/prepf/internal/review"
+)
+
+//go:embed review_exercise.md
+var reviewExerciseDescription []byte
+
+const ReviewExerciseToolName = "review_exercise"
+
+type ReviewIssueParams struct {
+	Line        int    `json:"line" description:"1-based line of the after file where the issue is"`
+	EndLine     int    `json:"end_line,omitempty" description:"Last line of an issue spanning several lines"`
+	Kind        string `json:"kind" description:"One of bug, race, security, naming, performance or design"`
+	Description string `json:"description" description:"One sentence describing the issue"`
+}
+
+type ReviewExerciseParams struct {
+	Title       string              `json:"title" description:"Title of the change, also used for the exercise name"`
+	Topic       string              `json:"topic,omitempty" description:"Short name of the topic the exercise covers, e.g. 'Go concurrency'"`
+	Description string              `json:"description,omitempty" description:"The pull request description the author would write"`
+	File        string              `json:"file" description:"Path of the changed file, e.g. 'cache/cache.go'"`
+	Before      string              `json:"before" description:"The file before the change, empty for a new file"`
+	After       string              `json:"after" description:"The file after the change"`
+	Issues      []ReviewIssueParams `json:"issues" description:"The issues seeded in the change"`
+}
+
+type ReviewExerciseResponseMetadata struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Issues int    `json:"issues"`
+}
+
+// NewReviewExerciseTool returns the tool that saves generated code review
+// exercises to dir.
+func NewReviewExerciseTool(dir string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		ReviewExerciseToolName,
+		string(reviewExerciseDescription),
+		func(ctx context.Context, params ReviewExerciseParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			e := &review.Exercise{
+				Title:       params.Title,
+				Topic:       params.Topic,
+				Description: params.Description,
+				File:        params.File,
+				Before:      params.Before,
+				After:       params.After,
+			}
+			for _, issue := range params.Issues {
+				e.Issues = append(e.Issues, review.Issue{
+					Line:        issue.Line,
+					EndLine:     issue.EndLine,
+					Kind:        review.Kind(issue.Kind),
+					Description: issue.Description,
+				})
+			}
+			path, err := review.Exercises.Save(dir, e.Title, e)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+
+			meta := ReviewExerciseResponseMetadata{
+				Name:   e.Name,
+				Path:   path,
+				Issues: len(e.Issues),
+			}
+			response := fmt.Sprintf("Saved review exercise %q with %d seeded issue(s). The user can start it with /review %s.", e.Name, len(e.Issues), e.Name)
+			return fantasy.WithResponseMetadata(fantasy.NewTextResponse(response), meta), nil
+		})
+}
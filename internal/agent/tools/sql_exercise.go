@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"charm.land/fantasy"
+	"github.com/trankhanh040147/prepf/internal/sqlgym"
+)
+
+//go:embed sql_exercise.md
+var sqlExerciseDescription []byte
+
+const SQLExerciseToolName = "sql_exercise"
+
+type SQLTaskParams struct {
+	Prompt    string `json:"prompt" description:"The question the user answers with a query"`
+	Reference string `json:"reference" description:"A SQLite query returning the expected result"`
+	Ordered   bool   `json:"ordered,omitempty" description:"Whether the row order is part of the answer, for tasks asking for sorted output"`
+}
+
+type SQLExerciseParams struct {
+	Title    string          `json:"title" description:"Title of the exercise, also used for its name"`
+	Topic    string          `json:"topic,omitempty" description:"Short name of the topic the exercise covers, e.g. 'SQL joins'"`
+	Schema   string          `json:"schema" description:"SQLite CREATE TABLE and CREATE INDEX statements"`
+	Fixtures string          `json:"fixtures,omitempty" description:"SQLite INSERT statements filling the tables"`
+	Tasks    []SQLTaskParams `json:"tasks" description:"The tasks, from easiest to hardest"`
+}
+
+type SQLExerciseResponseMetadata struct {
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	Tasks int    `json:"tasks"`
+}
+
+// NewSQLExerciseTool returns the tool that saves generated SQL exercises to
+// dir.
+func NewSQLExerciseTool(dir string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		SQLExerciseToolName,
+		string(sqlExerciseDescription),
+		func(ctx context.Context, params SQLExerciseParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			e := &sqlgym.Exercise{
+				Title:    params.Title,
+				Topic:    params.Topic,
+				Schema:   params.Schema,
+				Fixtures: params.Fixtures,
+			}
+			for _, task := range params.Tasks {
+				e.Tasks = append(e.Tasks, sqlgym.Task{
+					Prompt:    task.Prompt,
+					Reference: task.Reference,
+					Ordered:   task.Ordered,
+				})
+			}
+			// Run the reference queries first so a broken exercise is fixed
+			// here rather than found by the user.
+			if err := e.Verify(ctx); err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			path, err := sqlgym.Exercises.Save(dir, e.Title, e)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+
+			meta := SQLExerciseResponseMetadata{
+				Name:  e.Name,
+				Path:  path,
+				Tasks: len(e.Tasks),
+			}
+			response := fmt.Sprintf("Saved SQL exercise %q with %d task(s). The user can start it with /sql %s.", e.Name, len(e.Tasks), e.Name)
+			return fantasy.WithResponseMetadata(fantasy.NewTextResponse(response), meta), nil
+		})
+}
@@ -2,6 +2,11 @@ package tools
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/trankhanh040147/prepf/internal/filepathext"
+	"github.com/trankhanh040147/prepf/internal/fsext"
+	"github.com/trankhanh040147/prepf/internal/shell"
 )
 
 type (
@@ -9,6 +14,7 @@ type (
 	messageIDContextKey string
 	supportsImagesKey   string
 	modelNameKey        string
+	workspaceKey        string
 )
 
 const (
@@ -20,6 +26,9 @@ const (
 	SupportsImagesContextKey supportsImagesKey = "supports_images"
 	// ModelNameContextKey is the key for the model name in the context.
 	ModelNameContextKey modelNameKey = "model_name"
+	// WorkspaceContextKey is the key for the directory shell commands and
+	// file tools are confined to in the context.
+	WorkspaceContextKey workspaceKey = "workspace"
 )
 
 // GetSessionFromContext retrieves the session ID from the context.
@@ -72,3 +81,29 @@ func GetModelNameFromContext(ctx context.Context) string {
 	}
 	return s
 }
+
+// GetWorkspaceFromContext retrieves the directory shell commands and file
+// tools are confined to from the context, or "" if they aren't confined.
+func GetWorkspaceFromContext(ctx context.Context) string {
+	workspace, _ := ctx.Value(WorkspaceContextKey).(string)
+	return workspace
+}
+
+// confine resolves path against the workspace when the session is confined
+// to one, such as in a debugging exercise, and refuses paths outside of it.
+// It returns the directory to resolve paths against and the path to use;
+// outside a workspace they are workingDir and path unchanged.
+func confine(ctx context.Context, workingDir, path string) (string, string, error) {
+	workspace := GetWorkspaceFromContext(ctx)
+	if workspace == "" {
+		return workingDir, path, nil
+	}
+	if expanded, err := fsext.Expand(path); err == nil {
+		path = expanded
+	}
+	resolved := filepathext.SmartJoin(workspace, path)
+	if !shell.Within(workspace, resolved) {
+		return "", "", fmt.Errorf("path must be inside the workspace %s: %s", workspace, path)
+	}
+	return workspace, resolved, nil
+}
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfine(t *testing.T) {
+	t.Parallel()
+
+	workingDir := t.TempDir()
+	workspace := t.TempDir()
+
+	// Outside a workspace paths are left alone.
+	dir, path, err := confine(t.Context(), workingDir, "/etc/passwd")
+	require.NoError(t, err)
+	require.Equal(t, workingDir, dir)
+	require.Equal(t, "/etc/passwd", path)
+
+	ctx := context.WithValue(t.Context(), WorkspaceContextKey, workspace)
+	dir, path, err = confine(ctx, workingDir, "pkg/page.go")
+	require.NoError(t, err)
+	require.Equal(t, workspace, dir)
+	require.Equal(t, filepath.Join(workspace, "pkg", "page.go"), path)
+
+	_, path, err = confine(ctx, workingDir, "")
+	require.NoError(t, err)
+	require.Equal(t, workspace, path)
+
+	for _, outside := range []string{"/etc/passwd", "../secret", workingDir, "~/notes.md"} {
+		_, _, err = confine(ctx, workingDir, outside)
+		require.ErrorContains(t, err, "must be inside the workspace", outside)
+	}
+}
@@ -62,6 +62,11 @@ func NewViewTool(lspClients *csync.Map[string, *lsp.Client], permissions permiss
 			if params.FilePath == "" {
 				return fantasy.NewTextErrorResponse("file_path is required"), nil
 			}
+			workingDir, confined, err := confine(ctx, workingDir, params.FilePath)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			params.FilePath = confined
 
 			// Handle relative paths
 			filePath := filepathext.SmartJoin(workingDir, params.FilePath)
@@ -152,7 +152,7 @@ func NewWriteTool(lspClients *csync.Map[string, *lsp.Client], permissions permis
 				}
 			}
 			// Store the new version
-			_, err = files.CreateVersion(ctx, sessionID, filePath, params.Content)
+			_, err = files.CreateToolCallVersion(ctx, sessionID, call.ID, filePath, params.Content)
 			if err != nil {
 				slog.Error("Error creating file history version", "error", err)
 			}
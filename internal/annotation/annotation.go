@@ -0,0 +1,209 @@
+// Package annotation stores reviewers' comments on the messages of a
+// transcript, so peers and mentors can go over each other's mock
+// interviews.
+package annotation
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"os/user"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/pubsub"
+)
+
+// Range is a part of a message's text, as byte offsets.
+type Range struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Annotation is a comment on a message.
+type Annotation struct {
+	ID        string `json:"id"`
+	SessionID string `json:"session_id"`
+	MessageID string `json:"message_id"`
+	Author    string `json:"author"`
+	Body      string `json:"body"`
+	// Range is the part of the message text the comment is about, or nil
+	// if it is about the whole message.
+	Range     *Range `json:"range,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// Quote returns the part of the message text the annotation is about, or
+// an empty string if it is about the whole message or the range no longer
+// fits the text.
+func (a Annotation) Quote(text string) string {
+	if a.Range == nil || a.Range.Start < 0 || a.Range.End > len(text) || a.Range.Start >= a.Range.End {
+		return ""
+	}
+	return text[a.Range.Start:a.Range.End]
+}
+
+// FindRange locates quote in text. Runs of whitespace match any whitespace,
+// so text copied from the wrapped transcript is still found.
+func FindRange(text, quote string) (Range, bool) {
+	quote = strings.TrimSpace(quote)
+	if quote == "" {
+		return Range{}, false
+	}
+	if i := strings.Index(text, quote); i >= 0 {
+		return Range{Start: i, End: i + len(quote)}, true
+	}
+	words := strings.Fields(quote)
+	for i, w := range words {
+		words[i] = regexp.QuoteMeta(w)
+	}
+	loc := regexp.MustCompile(strings.Join(words, `\s+`)).FindStringIndex(text)
+	if loc == nil {
+		return Range{}, false
+	}
+	return Range{Start: loc[0], End: loc[1]}, true
+}
+
+// Author returns the name annotations and reviews are signed with: the
+// configured one or else the system user's.
+func Author(cfg *config.Config) string {
+	if cfg.Options.Author != "" {
+		return cfg.Options.Author
+	}
+	if u, err := user.Current(); err == nil {
+		return cmp.Or(u.Name, u.Username)
+	}
+	return "anonymous"
+}
+
+type Service interface {
+	pubsub.Subscriber[Annotation]
+	// Create stores a new annotation. It needs a message, an author and a
+	// body.
+	Create(ctx context.Context, a Annotation) (Annotation, error)
+	Update(ctx context.Context, id, body string) (Annotation, error)
+	Delete(ctx context.Context, id string) error
+	// List returns the annotations of a session, oldest first.
+	List(ctx context.Context, sessionID string) ([]Annotation, error)
+}
+
+type service struct {
+	*pubsub.Broker[Annotation]
+	q   db.Querier
+	now func() time.Time
+}
+
+func NewService(q db.Querier) Service {
+	return &service{
+		Broker: pubsub.NewBroker(pubsub.WithName[Annotation]("annotations")),
+		q:      q,
+		now:    time.Now,
+	}
+}
+
+func (s *service) Create(ctx context.Context, a Annotation) (Annotation, error) {
+	a.Body = strings.TrimSpace(a.Body)
+	a.Author = strings.TrimSpace(a.Author)
+	switch {
+	case a.SessionID == "" || a.MessageID == "":
+		return Annotation{}, errors.New("annotation needs a message")
+	case a.Author == "":
+		return Annotation{}, errors.New("annotation needs an author")
+	case a.Body == "":
+		return Annotation{}, errors.New("annotation is empty")
+	case a.Range != nil && (a.Range.Start < 0 || a.Range.Start >= a.Range.End):
+		return Annotation{}, errors.New("annotation range is empty")
+	}
+	now := s.now().Unix()
+	start, end := ToDBRange(a.Range)
+	row, err := s.q.CreateAnnotation(ctx, db.CreateAnnotationParams{
+		ID:         uuid.New().String(),
+		SessionID:  a.SessionID,
+		MessageID:  a.MessageID,
+		Author:     a.Author,
+		Body:       a.Body,
+		RangeStart: start,
+		RangeEnd:   end,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		return Annotation{}, err
+	}
+	a = FromDB(row)
+	s.Publish(pubsub.CreatedEvent, a)
+	return a, nil
+}
+
+func (s *service) Update(ctx context.Context, id, body string) (Annotation, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return Annotation{}, errors.New("annotation is empty")
+	}
+	row, err := s.q.UpdateAnnotation(ctx, db.UpdateAnnotationParams{
+		ID:        id,
+		Body:      body,
+		UpdatedAt: s.now().Unix(),
+	})
+	if err != nil {
+		return Annotation{}, err
+	}
+	a := FromDB(row)
+	s.Publish(pubsub.UpdatedEvent, a)
+	return a, nil
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	row, err := s.q.GetAnnotation(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.q.DeleteAnnotation(ctx, id); err != nil {
+		return err
+	}
+	s.Publish(pubsub.DeletedEvent, FromDB(row))
+	return nil
+}
+
+func (s *service) List(ctx context.Context, sessionID string) ([]Annotation, error) {
+	rows, err := s.q.ListAnnotationsBySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	annotations := make([]Annotation, len(rows))
+	for i, row := range rows {
+		annotations[i] = FromDB(row)
+	}
+	return annotations, nil
+}
+
+// FromDB converts a stored annotation.
+func FromDB(row db.Annotation) Annotation {
+	a := Annotation{
+		ID:        row.ID,
+		SessionID: row.SessionID,
+		MessageID: row.MessageID,
+		Author:    row.Author,
+		Body:      row.Body,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	if row.RangeStart.Valid && row.RangeEnd.Valid {
+		a.Range = &Range{Start: int(row.RangeStart.Int64), End: int(row.RangeEnd.Int64)}
+	}
+	return a
+}
+
+// ToDBRange converts a range to the columns it is stored in.
+func ToDBRange(r *Range) (start, end sql.NullInt64) {
+	if r == nil {
+		return start, end
+	}
+	return sql.NullInt64{Int64: int64(r.Start), Valid: true}, sql.NullInt64{Int64: int64(r.End), Valid: true}
+}
@@ -0,0 +1,84 @@
+package annotation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/message"
+	"github.com/trankhanh040147/prepf/internal/session"
+)
+
+func TestFindRange(t *testing.T) {
+	t.Parallel()
+
+	text := "A buffered channel blocks\nonly when it is full."
+	r, ok := FindRange(text, "buffered channel")
+	require.True(t, ok)
+	require.Equal(t, Range{Start: 2, End: 18}, r)
+
+	r, ok = FindRange(text, "  blocks only   when ")
+	require.True(t, ok)
+	require.Equal(t, "blocks\nonly when", Annotation{Range: &r}.Quote(text))
+
+	_, ok = FindRange(text, "unbuffered")
+	require.False(t, ok)
+	_, ok = FindRange(text, " ")
+	require.False(t, ok)
+
+	require.Empty(t, Annotation{Range: &Range{Start: 40, End: 80}}.Quote(text))
+	require.Empty(t, Annotation{}.Quote(text))
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	q := db.New(conn)
+	sess, err := session.NewService(q).Create(t.Context(), "Mock")
+	require.NoError(t, err)
+	msg, err := message.NewService(q).Create(t.Context(), sess.ID, message.CreateMessageParams{
+		Role:  message.User,
+		Parts: []message.ContentPart{message.TextContent{Text: "Channels are queues."}},
+	})
+	require.NoError(t, err)
+
+	svc := NewService(q).(*service)
+	clock := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time {
+		clock = clock.Add(time.Minute)
+		return clock
+	}
+	_, err = svc.Create(t.Context(), Annotation{SessionID: sess.ID, MessageID: msg.ID, Author: "Alice", Body: "  "})
+	require.Error(t, err)
+
+	a, err := svc.Create(t.Context(), Annotation{
+		SessionID: sess.ID,
+		MessageID: msg.ID,
+		Author:    "Alice",
+		Body:      "Mention that unbuffered ones synchronize.",
+		Range:     &Range{Start: 0, End: 8},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Channels", a.Quote(msg.Content().Text))
+	_, err = svc.Create(t.Context(), Annotation{SessionID: sess.ID, MessageID: msg.ID, Author: "Bob", Body: "Good start."})
+	require.NoError(t, err)
+
+	a, err = svc.Update(t.Context(), a.ID, "Say that unbuffered channels synchronize.")
+	require.NoError(t, err)
+
+	annotations, err := svc.List(t.Context(), sess.ID)
+	require.NoError(t, err)
+	require.Len(t, annotations, 2)
+	require.Equal(t, a, annotations[0])
+	require.Nil(t, annotations[1].Range)
+
+	require.NoError(t, svc.Delete(t.Context(), a.ID))
+	annotations, err = svc.List(t.Context(), sess.ID)
+	require.NoError(t, err)
+	require.Len(t, annotations, 1)
+	require.Equal(t, "Bob", annotations[0].Author)
+}
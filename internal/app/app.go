@@ -3,6 +3,7 @@
 package app
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"errors"
@@ -10,6 +11,8 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -19,22 +22,31 @@ import (
 	"charm.land/lipgloss/v2"
 	"github.com/trankhanh040147/prepf/internal/agent"
 	"github.com/trankhanh040147/prepf/internal/agent/tools/mcp"
+	"github.com/trankhanh040147/prepf/internal/annotation"
 	"github.com/trankhanh040147/prepf/internal/config"
 	"github.com/trankhanh040147/prepf/internal/csync"
+	"github.com/trankhanh040147/prepf/internal/daily"
 	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/debugging"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/flashcard"
 	"github.com/trankhanh040147/prepf/internal/format"
 	"github.com/trankhanh040147/prepf/internal/history"
 	"github.com/trankhanh040147/prepf/internal/log"
 	"github.com/trankhanh040147/prepf/internal/lsp"
 	"github.com/trankhanh040147/prepf/internal/message"
+	"github.com/trankhanh040147/prepf/internal/pack"
 	"github.com/trankhanh040147/prepf/internal/permission"
+	"github.com/trankhanh040147/prepf/internal/plan"
 	"github.com/trankhanh040147/prepf/internal/pubsub"
 	"github.com/trankhanh040147/prepf/internal/session"
 	"github.com/trankhanh040147/prepf/internal/shell"
 	"github.com/trankhanh040147/prepf/internal/tui/components/anim"
 	"github.com/trankhanh040147/prepf/internal/tui/styles"
 	"github.com/trankhanh040147/prepf/internal/update"
+	"github.com/trankhanh040147/prepf/internal/usage"
 	"github.com/trankhanh040147/prepf/internal/version"
+	"github.com/trankhanh040147/prepf/internal/watcher"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/exp/charmtone"
 	"github.com/charmbracelet/x/term"
@@ -45,6 +57,17 @@ type App struct {
 	Messages    message.Service
 	History     history.Service
 	Permissions permission.Service
+	Usage       usage.Service
+	Drills      drill.Service
+	Flashcards  flashcard.Service
+	Debugging   debugging.Service
+	Daily       daily.Service
+	Annotations annotation.Service
+	Plan        plan.Service
+	Packs       pack.Service
+	// Watcher reports changes to context files, skills, mode templates,
+	// custom commands and config files while the TUI is running.
+	Watcher *watcher.Watcher
 
 	AgentCoordinator agent.Coordinator
 
@@ -68,6 +91,11 @@ func New(ctx context.Context, conn *sql.DB, cfg *config.Config) (*App, error) {
 	sessions := session.NewService(q)
 	messages := message.NewService(q)
 	files := history.NewService(q, conn)
+	drills := drill.NewService(q, conn)
+	var dailyOpts config.Daily
+	if cfg.Options.Daily != nil {
+		dailyOpts = *cfg.Options.Daily
+	}
 	skipPermissionsRequests := cfg.Permissions != nil && cfg.Permissions.SkipRequests
 	var allowedTools []string
 	if cfg.Permissions != nil && cfg.Permissions.AllowedTools != nil {
@@ -79,6 +107,15 @@ func New(ctx context.Context, conn *sql.DB, cfg *config.Config) (*App, error) {
 		Messages:    messages,
 		History:     files,
 		Permissions: permission.NewPermissionService(cfg.WorkingDir(), skipPermissionsRequests, allowedTools),
+		Usage:       usage.NewService(q),
+		Drills:      drills,
+		Flashcards:  flashcard.NewService(q),
+		Debugging:   debugging.NewService(q, drills),
+		Daily:       daily.NewService(q, drills, dailyOpts),
+		Annotations: annotation.NewService(q),
+		Plan:        plan.NewService(q, drills, cfg),
+		Packs:       pack.NewService(q),
+		Watcher:     watcher.New(),
 		LSPClients:  csync.NewMap[string, *lsp.Client](),
 
 		globalCtx: ctx,
@@ -92,6 +129,10 @@ func New(ctx context.Context, conn *sql.DB, cfg *config.Config) (*App, error) {
 
 	app.setupEvents()
 
+	// Debugging workspaces live outside the database, so they are removed
+	// along with their session.
+	go app.removeDebugWorkspaces(ctx)
+
 	// Initialize LSP clients in the background.
 	app.initLSPClients(ctx)
 
@@ -122,11 +163,86 @@ func (app *App) Config() *config.Config {
 	return app.config
 }
 
+// NonInteractiveOptions configures [App.RunNonInteractive].
+type NonInteractiveOptions struct {
+	// Quiet hides the spinner.
+	Quiet bool
+	// OutputFormat selects plain text or JSON output. Defaults to text.
+	OutputFormat OutputFormat
+	// Mode is the session mode, e.g. "mock" or "gym". Modes without a
+	// dedicated prompt use the default one. When continuing a session it
+	// must match the session's mode.
+	Mode string
+	// SessionID continues the given session instead of creating a new one.
+	SessionID string
+	// Continue continues the most recently updated session.
+	Continue bool
+	// Attachments are sent along with the prompt.
+	Attachments []message.Attachment
+	// MaxTurns stops the agent after this many model turns. Zero means no
+	// limit.
+	MaxTurns int
+}
+
+// nonInteractiveSession returns the session to run the prompt in, creating a
+// new one unless opts asks to continue an existing session.
+func (app *App) nonInteractiveSession(ctx context.Context, prompt string, opts NonInteractiveOptions) (session.Session, error) {
+	var (
+		sess session.Session
+		err  error
+	)
+	switch {
+	case opts.SessionID != "":
+		sess, err = app.Sessions.Get(ctx, opts.SessionID)
+		if err != nil {
+			return session.Session{}, fmt.Errorf("failed to find session %q: %w", opts.SessionID, err)
+		}
+	case opts.Continue:
+		sessions, err := app.Sessions.List(ctx)
+		if err != nil {
+			return session.Session{}, fmt.Errorf("failed to list sessions: %w", err)
+		}
+		if len(sessions) == 0 {
+			return session.Session{}, errors.New("no session to continue")
+		}
+		sess = sessions[0]
+	default:
+		const maxPromptLengthForTitle = 100
+		const titlePrefix = "Non-interactive: "
+		var titleSuffix string
+
+		if len(prompt) > maxPromptLengthForTitle {
+			titleSuffix = prompt[:maxPromptLengthForTitle] + "..."
+		} else {
+			titleSuffix = prompt
+		}
+		title := titlePrefix + titleSuffix
+
+		sess, err = app.Sessions.CreateWithMode(ctx, title, opts.Mode)
+		if err != nil {
+			return session.Session{}, fmt.Errorf("failed to create session for non-interactive mode: %w", err)
+		}
+		slog.Info("Created session for non-interactive run", "session_id", sess.ID, "mode", sess.Mode)
+		return sess, nil
+	}
+
+	if opts.Mode != "" && opts.Mode != sess.Mode {
+		return session.Session{}, fmt.Errorf("session %s is in %q mode, not %q", sess.ID, cmp.Or(sess.Mode, "default"), opts.Mode)
+	}
+	slog.Info("Continuing session for non-interactive run", "session_id", sess.ID, "mode", sess.Mode)
+	return sess, nil
+}
+
 // RunNonInteractive runs the application in non-interactive mode with the
 // given prompt, printing to stdout.
-func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt string, quiet bool) error {
+func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt string, opts NonInteractiveOptions) error {
 	slog.Info("Running in non-interactive mode")
 
+	outputFormat := cmp.Or(opts.OutputFormat, OutputFormatText)
+	jsonOutput := outputFormat != OutputFormatText
+	// The spinner would corrupt machine-readable output.
+	quiet := opts.Quiet || jsonOutput
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -175,27 +291,41 @@ func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt
 	}
 	defer stopSpinner()
 
-	const maxPromptLengthForTitle = 100
-	const titlePrefix = "Non-interactive: "
-	var titleSuffix string
-
-	if len(prompt) > maxPromptLengthForTitle {
-		titleSuffix = prompt[:maxPromptLengthForTitle] + "..."
-	} else {
-		titleSuffix = prompt
-	}
-	title := titlePrefix + titleSuffix
-
-	sess, err := app.Sessions.Create(ctx, title)
+	sess, err := app.nonInteractiveSession(ctx, prompt, opts)
 	if err != nil {
-		return fmt.Errorf("failed to create session for non-interactive mode: %w", err)
+		return err
 	}
-	slog.Info("Created session for non-interactive run", "session_id", sess.ID)
 
 	// Automatically approve all permission requests for this non-interactive
 	// session.
 	app.Permissions.AutoApproveSession(sess.ID)
 
+	// Subscribe before starting the agent so no events are missed.
+	messageEvents := app.Messages.Subscribe(ctx)
+	messageReadBytes := make(map[string]int)
+
+	var (
+		tracker       *runTracker
+		sessionEvents <-chan pubsub.Event[session.Session]
+		lastUsage     RunUsage
+	)
+	if jsonOutput {
+		sessionEvents = app.Sessions.Subscribe(ctx)
+		tracker = newRunTracker(sess.ID, func(ev RunEvent) {
+			if outputFormat == OutputFormatStreamJSON {
+				_ = writeJSONLine(output, ev)
+			}
+		})
+		tracker.emit(RunEvent{Type: RunEventSession, SessionID: sess.ID})
+		// A continued session already has messages; only this run's are
+		// reported, even when catching up from the database at the end.
+		earlier, err := app.Messages.List(ctx, sess.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list session messages: %w", err)
+		}
+		tracker.ignore(earlier)
+	}
+
 	type response struct {
 		result *fantasy.AgentResult
 		err    error
@@ -203,7 +333,7 @@ func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt
 	done := make(chan response, 1)
 
 	go func(ctx context.Context, sessionID, prompt string) {
-		result, err := app.AgentCoordinator.Run(ctx, sess.ID, prompt)
+		result, err := app.AgentCoordinator.Run(ctx, sess.ID, prompt, agent.RunOptions{MaxTurns: opts.MaxTurns}, opts.Attachments...)
 		if err != nil {
 			done <- response{
 				err: fmt.Errorf("failed to start agent processing stream: %w", err),
@@ -214,9 +344,6 @@ func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt
 		}
 	}(ctx, sess.ID, prompt)
 
-	messageEvents := app.Messages.Subscribe(ctx)
-	messageReadBytes := make(map[string]int)
-
 	defer func() {
 		if stderrTTY {
 			_, _ = fmt.Fprintf(os.Stderr, ansi.ResetProgressBar)
@@ -224,7 +351,9 @@ func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt
 
 		// Always print a newline at the end. If output is a TTY this will
 		// prevent the prompt from overwriting the last line of output.
-		_, _ = fmt.Fprintln(output)
+		if !jsonOutput {
+			_, _ = fmt.Fprintln(output)
+		}
 	}()
 
 	for {
@@ -237,6 +366,9 @@ func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt
 		select {
 		case result := <-done:
 			stopSpinner()
+			if tracker != nil {
+				return app.finishJSONRun(ctx, output, outputFormat, tracker, result.err)
+			}
 			if result.err != nil {
 				if errors.Is(result.err, context.Canceled) || errors.Is(result.err, agent.ErrRequestCancelled) {
 					slog.Info("Non-interactive: agent processing cancelled", "session_id", sess.ID)
@@ -246,7 +378,21 @@ func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt
 			}
 			return nil
 
+		case event := <-sessionEvents:
+			if event.Payload.ID != sess.ID {
+				continue
+			}
+			usage := usageFromSession(event.Payload)
+			if usage != lastUsage && outputFormat == OutputFormatStreamJSON {
+				lastUsage = usage
+				tracker.emit(RunEvent{Type: RunEventUsage, SessionID: sess.ID, Usage: &usage})
+			}
+
 		case event := <-messageEvents:
+			if tracker != nil {
+				tracker.handle(event.Payload)
+				continue
+			}
 			msg := event.Payload
 			if msg.SessionID == sess.ID && msg.Role == message.Assistant && len(msg.Parts) > 0 {
 				stopSpinner()
@@ -276,6 +422,44 @@ func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt
 	}
 }
 
+// finishJSONRun writes the final result of a run in one of the JSON output
+// formats.
+func (app *App) finishJSONRun(ctx context.Context, output io.Writer, outputFormat OutputFormat, tracker *runTracker, runErr error) error {
+	// The run may finish before every message update has been delivered, so
+	// catch up from the database. The run context may already be canceled.
+	ctx = context.WithoutCancel(ctx)
+	if msgs, err := app.Messages.List(ctx, tracker.sessionID); err == nil {
+		for _, msg := range msgs {
+			tracker.handle(msg)
+		}
+	}
+	var usage RunUsage
+	if sess, err := app.Sessions.Get(ctx, tracker.sessionID); err == nil {
+		usage = usageFromSession(sess)
+	}
+
+	canceled := errors.Is(runErr, context.Canceled) || errors.Is(runErr, agent.ErrRequestCancelled)
+	if runErr != nil && outputFormat == OutputFormatStreamJSON {
+		tracker.emit(RunEvent{Type: RunEventError, SessionID: tracker.sessionID, Error: runErr.Error()})
+	}
+
+	result := tracker.result(usage, runErr)
+	var err error
+	if outputFormat == OutputFormatStreamJSON {
+		err = writeJSONLine(output, RunEvent{Type: RunEventResult, SessionID: tracker.sessionID, Result: &result})
+	} else {
+		err = writeJSONLine(output, result)
+	}
+	if err != nil {
+		return err
+	}
+
+	if runErr != nil && !canceled {
+		return fmt.Errorf("agent processing failed: %w", runErr)
+	}
+	return nil
+}
+
 func (app *App) UpdateAgentModel(ctx context.Context) error {
 	if app.AgentCoordinator == nil {
 		return fmt.Errorf("agent configuration is missing")
@@ -291,6 +475,9 @@ func (app *App) setupEvents() {
 	setupSubscriber(ctx, app.serviceEventsWG, "permissions", app.Permissions.Subscribe, app.events)
 	setupSubscriber(ctx, app.serviceEventsWG, "permissions-notifications", app.Permissions.SubscribeNotifications, app.events)
 	setupSubscriber(ctx, app.serviceEventsWG, "history", app.History.Subscribe, app.events)
+	setupSubscriber(ctx, app.serviceEventsWG, "annotations", app.Annotations.Subscribe, app.events)
+	setupSubscriber(ctx, app.serviceEventsWG, "usage", app.Usage.Subscribe, app.events)
+	setupSubscriber(ctx, app.serviceEventsWG, "watcher", app.Watcher.Subscribe, app.events)
 	setupSubscriber(ctx, app.serviceEventsWG, "mcp", mcp.SubscribeEvents, app.events)
 	setupSubscriber(ctx, app.serviceEventsWG, "lsp", SubscribeLSPEvents, app.events)
 	cleanupFunc := func() error {
@@ -317,11 +504,12 @@ func setupSubscriber[T any](
 					slog.Debug("subscription channel closed", "name", name)
 					return
 				}
+				// Wait for the UI however long it takes: while we wait the
+				// broker buffers events and applies the subscription's
+				// policy, which knows better what can be dropped.
 				var msg tea.Msg = event
 				select {
 				case outputCh <- msg:
-				case <-time.After(2 * time.Second):
-					slog.Warn("message dropped due to slow consumer", "name", name)
 				case <-ctx.Done():
 					slog.Debug("subscription cancelled", "name", name)
 					return
@@ -347,6 +535,11 @@ func (app *App) InitCoderAgent(ctx context.Context) error {
 		app.Messages,
 		app.Permissions,
 		app.History,
+		app.Usage,
+		app.Drills,
+		app.Flashcards,
+		app.Debugging,
+		app.Packs,
 		app.LSPClients,
 	)
 	if err != nil {
@@ -373,6 +566,11 @@ func (app *App) Subscribe(program *tea.Program) {
 	})
 	defer app.tuiWG.Done()
 
+	app.watchFiles(tuiCtx)
+	if app.AgentCoordinator != nil {
+		go app.AgentCoordinator.ClassifyQuestions(tuiCtx)
+	}
+
 	for {
 		select {
 		case <-tuiCtx.Done():
@@ -388,6 +586,70 @@ func (app *App) Subscribe(program *tea.Program) {
 	}
 }
 
+// watchFiles watches the files system prompts are built from and asks the
+// coordinator to rebuild the prompts when they change. Custom command
+// directories are added by the TUI.
+func (app *App) watchFiles(ctx context.Context) {
+	cfg := app.config
+	app.Watcher.Watch(watcher.KindConfig, cfg.ConfigPaths()...)
+	app.watchPromptPaths()
+	app.Watcher.Watch(watcher.KindModes, agent.ModeTemplateDirs(cfg)...)
+
+	events := app.Watcher.Subscribe(ctx)
+	if err := app.Watcher.Start(ctx); err != nil {
+		slog.Warn("Failed to start file watcher, hot reload is disabled", "error", err)
+		return
+	}
+	go func() {
+		for event := range events {
+			// A config change may name new context or skills paths.
+			if slices.Contains(event.Payload.Kinds, watcher.KindConfig) {
+				if err := cfg.ReloadPromptPaths(); err != nil {
+					slog.Warn("Failed to reload prompt paths from config", "error", err)
+				} else {
+					app.watchPromptPaths()
+				}
+			}
+			if event.Payload.AffectsPrompt() && app.AgentCoordinator != nil {
+				app.AgentCoordinator.ReloadPrompts()
+			}
+		}
+	}()
+}
+
+// removeDebugWorkspaces removes the debugging workspaces of the sessions
+// deleted until ctx is done.
+func (app *App) removeDebugWorkspaces(ctx context.Context) {
+	for event := range app.Sessions.Subscribe(ctx) {
+		if event.Type != pubsub.DeletedEvent {
+			continue
+		}
+		if err := debugging.RemoveWorkspaces(event.Payload.ID); err != nil {
+			slog.Warn("Failed to remove debugging workspaces", "session", event.Payload.ID, "error", err)
+		}
+	}
+}
+
+// watchPromptPaths watches the configured context and skills paths,
+// expanded and resolved as the prompt reads them. Watching a path twice is
+// a no-op, so it can be called again after the config changes.
+func (app *App) watchPromptPaths() {
+	cfg := app.config
+	resolve := func(paths []string) []string {
+		resolved := make([]string, 0, len(paths))
+		for _, path := range paths {
+			path = cfg.ExpandPath(path)
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(cfg.WorkingDir(), path)
+			}
+			resolved = append(resolved, path)
+		}
+		return resolved
+	}
+	app.Watcher.Watch(watcher.KindContext, resolve(cfg.ContextPaths())...)
+	app.Watcher.Watch(watcher.KindSkills, resolve(cfg.SkillsPaths())...)
+}
+
 // Shutdown performs a graceful shutdown of the application.
 func (app *App) Shutdown() {
 	start := time.Now()
@@ -38,7 +38,7 @@ type LSPClientInfo struct {
 
 var (
 	lspStates = csync.NewMap[string, LSPClientInfo]()
-	lspBroker = pubsub.NewBroker[LSPEvent]()
+	lspBroker = pubsub.NewBroker(pubsub.WithName[LSPEvent]("lsp"))
 )
 
 // SubscribeLSPEvents returns a channel for LSP events
@@ -0,0 +1,245 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/trankhanh040147/prepf/internal/message"
+	"github.com/trankhanh040147/prepf/internal/session"
+)
+
+// OutputFormat controls how non-interactive runs write to stdout.
+type OutputFormat string
+
+const (
+	// OutputFormatText prints the assistant's text as it streams.
+	OutputFormatText OutputFormat = "text"
+	// OutputFormatJSON prints a single [RunResult] once the run is done.
+	OutputFormatJSON OutputFormat = "json"
+	// OutputFormatStreamJSON prints one [RunEvent] per line as the run
+	// progresses, followed by a final "result" event.
+	OutputFormatStreamJSON OutputFormat = "stream-json"
+)
+
+// ParseOutputFormat validates an output format name.
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch f := OutputFormat(s); f {
+	case OutputFormatText, OutputFormatJSON, OutputFormatStreamJSON:
+		return f, nil
+	case "":
+		return OutputFormatText, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q: must be one of text, json, stream-json", s)
+	}
+}
+
+// Event types emitted in stream-json output.
+const (
+	RunEventSession    = "session"
+	RunEventUser       = "user"
+	RunEventAssistant  = "assistant_delta"
+	RunEventReasoning  = "reasoning_delta"
+	RunEventToolCall   = "tool_call"
+	RunEventToolResult = "tool_result"
+	RunEventFinish     = "finish"
+	RunEventUsage      = "usage"
+	RunEventError      = "error"
+	RunEventResult     = "result"
+)
+
+// RunEvent is a single line of stream-json output.
+type RunEvent struct {
+	Type         string         `json:"type"`
+	SessionID    string         `json:"session_id"`
+	MessageID    string         `json:"message_id,omitempty"`
+	Text         string         `json:"text,omitempty"`
+	ToolCall     *RunToolCall   `json:"tool_call,omitempty"`
+	ToolResult   *RunToolResult `json:"tool_result,omitempty"`
+	FinishReason string         `json:"finish_reason,omitempty"`
+	Usage        *RunUsage      `json:"usage,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	Result       *RunResult     `json:"result,omitempty"`
+}
+
+type RunToolCall struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Input string `json:"input"`
+}
+
+type RunToolResult struct {
+	ToolCallID string `json:"tool_call_id"`
+	Name       string `json:"name"`
+	Content    string `json:"content"`
+	IsError    bool   `json:"is_error"`
+}
+
+type RunUsage struct {
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// RunResult summarizes a finished non-interactive run.
+type RunResult struct {
+	SessionID    string        `json:"session_id"`
+	Result       string        `json:"result"`
+	FinishReason string        `json:"finish_reason,omitempty"`
+	ToolCalls    []RunToolCall `json:"tool_calls"`
+	Usage        RunUsage      `json:"usage"`
+	IsError      bool          `json:"is_error"`
+	Error        string        `json:"error,omitempty"`
+}
+
+func usageFromSession(s session.Session) RunUsage {
+	return RunUsage{
+		PromptTokens:     s.PromptTokens,
+		CompletionTokens: s.CompletionTokens,
+		Cost:             s.Cost,
+	}
+}
+
+// runTracker turns message updates into run events. Message updates carry
+// the full message each time, so it remembers what was already emitted and
+// only reports what's new.
+type runTracker struct {
+	sessionID string
+	emit      func(RunEvent)
+	// earlier holds the messages the session had before the run, which
+	// belong to earlier runs and are not reported.
+	earlier map[string]bool
+
+	users       map[string]bool
+	content     map[string]int
+	reasoning   map[string]int
+	toolCalls   map[string]bool
+	toolResults map[string]bool
+	finished    map[string]bool
+
+	lastText     string
+	finishReason string
+	calls        []RunToolCall
+}
+
+func newRunTracker(sessionID string, emit func(RunEvent)) *runTracker {
+	return &runTracker{
+		sessionID:   sessionID,
+		emit:        emit,
+		earlier:     make(map[string]bool),
+		users:       make(map[string]bool),
+		content:     make(map[string]int),
+		reasoning:   make(map[string]int),
+		toolCalls:   make(map[string]bool),
+		toolResults: make(map[string]bool),
+		finished:    make(map[string]bool),
+	}
+}
+
+// ignore marks the messages of a continued session as earlier ones.
+func (t *runTracker) ignore(msgs []message.Message) {
+	for _, msg := range msgs {
+		t.earlier[msg.ID] = true
+	}
+}
+
+func (t *runTracker) event(typ string, msg message.Message) RunEvent {
+	return RunEvent{Type: typ, SessionID: t.sessionID, MessageID: msg.ID}
+}
+
+func (t *runTracker) handle(msg message.Message) {
+	if msg.SessionID != t.sessionID || t.earlier[msg.ID] {
+		return
+	}
+
+	switch msg.Role {
+	case message.User:
+		if t.users[msg.ID] {
+			return
+		}
+		t.users[msg.ID] = true
+		ev := t.event(RunEventUser, msg)
+		ev.Text = msg.Content().String()
+		t.emit(ev)
+
+	case message.Assistant:
+		if reasoning := msg.ReasoningContent().Thinking; len(reasoning) > t.reasoning[msg.ID] {
+			ev := t.event(RunEventReasoning, msg)
+			ev.Text = reasoning[t.reasoning[msg.ID]:]
+			t.reasoning[msg.ID] = len(reasoning)
+			t.emit(ev)
+		}
+		if content := msg.Content().String(); len(content) > t.content[msg.ID] {
+			ev := t.event(RunEventAssistant, msg)
+			ev.Text = content[t.content[msg.ID]:]
+			t.content[msg.ID] = len(content)
+			t.emit(ev)
+		}
+		if text := strings.TrimSpace(msg.Content().String()); text != "" {
+			t.lastText = text
+		}
+		for _, tc := range msg.ToolCalls() {
+			if !tc.Finished || t.toolCalls[tc.ID] {
+				continue
+			}
+			t.toolCalls[tc.ID] = true
+			call := RunToolCall{ID: tc.ID, Name: tc.Name, Input: tc.Input}
+			t.calls = append(t.calls, call)
+			ev := t.event(RunEventToolCall, msg)
+			ev.ToolCall = &call
+			t.emit(ev)
+		}
+		if finish := msg.FinishPart(); finish != nil && !t.finished[msg.ID] {
+			t.finished[msg.ID] = true
+			t.finishReason = string(finish.Reason)
+			ev := t.event(RunEventFinish, msg)
+			ev.FinishReason = t.finishReason
+			ev.Error = finish.Message
+			t.emit(ev)
+		}
+
+	case message.Tool:
+		for _, tr := range msg.ToolResults() {
+			if t.toolResults[tr.ToolCallID] {
+				continue
+			}
+			t.toolResults[tr.ToolCallID] = true
+			ev := t.event(RunEventToolResult, msg)
+			ev.ToolResult = &RunToolResult{
+				ToolCallID: tr.ToolCallID,
+				Name:       tr.Name,
+				Content:    tr.Content,
+				IsError:    tr.IsError,
+			}
+			t.emit(ev)
+		}
+	}
+}
+
+func (t *runTracker) result(usage RunUsage, err error) RunResult {
+	result := RunResult{
+		SessionID:    t.sessionID,
+		Result:       t.lastText,
+		FinishReason: t.finishReason,
+		ToolCalls:    t.calls,
+		Usage:        usage,
+	}
+	if result.ToolCalls == nil {
+		result.ToolCalls = []RunToolCall{}
+	}
+	if err != nil {
+		result.IsError = true
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func writeJSONLine(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/message"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]OutputFormat{
+		"":            OutputFormatText,
+		"text":        OutputFormatText,
+		"json":        OutputFormatJSON,
+		"stream-json": OutputFormatStreamJSON,
+	} {
+		got, err := ParseOutputFormat(in)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+
+	_, err := ParseOutputFormat("yaml")
+	require.Error(t, err)
+}
+
+func TestRunTracker(t *testing.T) {
+	t.Parallel()
+
+	var events []RunEvent
+	tracker := newRunTracker("s1", func(ev RunEvent) {
+		events = append(events, ev)
+	})
+
+	user := message.Message{ID: "u1", SessionID: "s1", Role: message.User, Parts: []message.ContentPart{
+		message.TextContent{Text: "Ask me about channels"},
+	}}
+	tracker.handle(user)
+	tracker.handle(user)
+
+	// Messages from other sessions are ignored.
+	tracker.handle(message.Message{ID: "x", SessionID: "other", Role: message.User})
+
+	assistant := message.Message{ID: "a1", SessionID: "s1", Role: message.Assistant}
+	assistant.AppendReasoningContent("Thinking")
+	tracker.handle(assistant)
+	assistant.AppendContent("What is")
+	tracker.handle(assistant)
+	assistant.AppendContent(" a channel?")
+	assistant.AddToolCall(message.ToolCall{ID: "t1", Name: "view", Input: `{"path":"a.go"}`, Finished: true})
+	assistant.AddFinish(message.FinishReasonToolUse, "", "")
+	tracker.handle(assistant)
+	tracker.handle(assistant)
+
+	tracker.handle(message.Message{ID: "r1", SessionID: "s1", Role: message.Tool, Parts: []message.ContentPart{
+		message.ToolResult{ToolCallID: "t1", Name: "view", Content: "package a"},
+	}})
+
+	var types []string
+	for _, ev := range events {
+		require.Equal(t, "s1", ev.SessionID)
+		types = append(types, ev.Type)
+	}
+	require.Equal(t, []string{
+		RunEventUser,
+		RunEventReasoning,
+		RunEventAssistant,
+		RunEventAssistant,
+		RunEventToolCall,
+		RunEventFinish,
+		RunEventToolResult,
+	}, types)
+	require.Equal(t, "Ask me about channels", events[0].Text)
+	require.Equal(t, "What is", events[2].Text)
+	require.Equal(t, " a channel?", events[3].Text)
+	require.Equal(t, "view", events[4].ToolCall.Name)
+	require.Equal(t, string(message.FinishReasonToolUse), events[5].FinishReason)
+	require.Equal(t, "package a", events[6].ToolResult.Content)
+
+	result := tracker.result(RunUsage{PromptTokens: 10, CompletionTokens: 5, Cost: 0.01}, errors.New("boom"))
+	require.Equal(t, "What is a channel?", result.Result)
+	require.Len(t, result.ToolCalls, 1)
+	require.True(t, result.IsError)
+	require.Equal(t, "boom", result.Error)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeJSONLine(&buf, result))
+	var decoded RunResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, result, decoded)
+}
+
+func TestRunTrackerContinuedSession(t *testing.T) {
+	t.Parallel()
+
+	var events []RunEvent
+	tracker := newRunTracker("s1", func(ev RunEvent) {
+		events = append(events, ev)
+	})
+
+	earlier := message.Message{ID: "a0", SessionID: "s1", Role: message.Assistant}
+	earlier.AppendContent("Earlier answer")
+	earlier.AddToolCall(message.ToolCall{ID: "t0", Name: "view", Finished: true})
+	tracker.ignore([]message.Message{
+		{ID: "u0", SessionID: "s1", Role: message.User, Parts: []message.ContentPart{message.TextContent{Text: "Earlier question"}}},
+		earlier,
+	})
+
+	// Catching up from the database lists the whole session.
+	answer := message.Message{ID: "a1", SessionID: "s1", Role: message.Assistant}
+	answer.AppendContent("New answer")
+	answer.AddToolCall(message.ToolCall{ID: "t1", Name: "ls", Finished: true})
+	tracker.handle(message.Message{ID: "u0", SessionID: "s1", Role: message.User, Parts: []message.ContentPart{message.TextContent{Text: "Earlier question"}}})
+	tracker.handle(earlier)
+	tracker.handle(message.Message{ID: "u1", SessionID: "s1", Role: message.User, Parts: []message.ContentPart{message.TextContent{Text: "New question"}}})
+	tracker.handle(answer)
+
+	var texts []string
+	for _, ev := range events {
+		if ev.Text != "" {
+			texts = append(texts, ev.Text)
+		}
+	}
+	require.Equal(t, []string{"New question", "New answer"}, texts)
+	result := tracker.result(RunUsage{}, nil)
+	require.Equal(t, []RunToolCall{{ID: "t1", Name: "ls"}}, result.ToolCalls)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/flashcard"
+)
+
+var cardsCmd = &cobra.Command{
+	Use:   "cards",
+	Short: "List and export flashcards",
+	Long:  "List and export the flashcards saved from corrections in mock interviews and gym sessions",
+}
+
+var cardsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List flashcards",
+	Example: `
+# List all flashcards with their next review date
+prepf cards list
+
+# List the cards due for review as JSON
+prepf cards list --due --json
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		due, _ := cmd.Flags().GetBool("due")
+
+		cards, err := loadCards(cmd, due)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			data, err := json.Marshal(cards)
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(data))
+			return nil
+		}
+
+		if len(cards) == 0 {
+			cmd.Println("No flashcards saved yet.")
+			return nil
+		}
+
+		if term.IsTerminal(os.Stdout.Fd()) {
+			// We're in a TTY: make it fancy.
+			t := table.New().
+				Border(lipgloss.RoundedBorder()).
+				StyleFunc(func(row, col int) lipgloss.Style {
+					return lipgloss.NewStyle().Padding(0, 2)
+				}).
+				Headers("Topic", "Front", "Due", "Reviews")
+			for _, c := range cards {
+				t.Row(orDash(c.Topic), c.Front, formatDue(c.DueAt), fmt.Sprint(c.Reps+c.Lapses))
+			}
+			lipgloss.Println(t)
+			return nil
+		}
+
+		// Not a TTY: plain output
+		for _, c := range cards {
+			cmd.Printf("%s\t%s\t%s\t%s\t%d\n", c.ID, orDash(c.Topic), c.Front, formatDue(c.DueAt), c.Reps+c.Lapses)
+		}
+		return nil
+	},
+}
+
+var cardsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export flashcards",
+	Long:  "Export flashcards as JSON, or as a tab-separated file Anki can import with --anki",
+	Example: `
+# Export all cards for Anki
+prepf cards export --anki -o prepf.txt
+
+# Export the cards due for review as JSON
+prepf cards export --due
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		anki, _ := cmd.Flags().GetBool("anki")
+		due, _ := cmd.Flags().GetBool("due")
+		output, _ := cmd.Flags().GetString("output")
+
+		cards, err := loadCards(cmd, due)
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = cmd.OutOrStdout()
+		if output != "" && output != "-" {
+			f, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+
+		if anki {
+			return flashcard.WriteAnki(w, cards)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(cards)
+	},
+}
+
+func loadCards(cmd *cobra.Command, dueOnly bool) ([]flashcard.Card, error) {
+	_, conn, err := connectDB(cmd)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	cards := flashcard.NewService(db.New(conn))
+	if dueOnly {
+		return cards.Due(cmd.Context(), time.Now())
+	}
+	return cards.List(cmd.Context())
+}
+
+func formatDue(dueAt int64) string {
+	due := time.Unix(dueAt, 0)
+	if !due.After(time.Now()) {
+		return "now"
+	}
+	return due.Format(time.DateOnly)
+}
+
+func init() {
+	cardsListCmd.Flags().Bool("json", false, "Output as JSON")
+	cardsListCmd.Flags().Bool("due", false, "Only list cards due for review")
+
+	cardsExportCmd.Flags().Bool("anki", false, "Export as a tab-separated file Anki can import")
+	cardsExportCmd.Flags().Bool("due", false, "Only export cards due for review")
+	cardsExportCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+
+	cardsCmd.AddCommand(cardsListCmd, cardsExportCmd)
+}
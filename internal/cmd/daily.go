@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/daily"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/event"
+)
+
+var dailyCmd = &cobra.Command{
+	Use:   "daily",
+	Short: "Answer today's daily challenge",
+	Long:  "Start prepf in a short gym session answering the question of the day. Answering a question every day builds a streak; set options.daily.freezes to allow missing a few days a month",
+	Example: `
+# Answer today's question
+prepf daily
+
+# Show the streak and past challenges
+prepf daily history
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTUI(cmd, true)
+	},
+	PostRun: func(cmd *cobra.Command, args []string) {
+		event.AppExited()
+	},
+}
+
+var dailyHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the daily challenge streak and history",
+	Example: `
+# Show the streak and past challenges
+prepf daily history
+
+# Show them as JSON
+prepf daily history --json
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		cfg, conn, err := connectDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		var opts config.Daily
+		if cfg.Options.Daily != nil {
+			opts = *cfg.Options.Daily
+		}
+		q := db.New(conn)
+		svc := daily.NewService(q, drill.NewService(q, conn), opts)
+		history, err := svc.History(cmd.Context())
+		if err != nil {
+			return err
+		}
+		streak, err := svc.Streak(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			data, err := json.Marshal(struct {
+				Streak     daily.Streak      `json:"streak"`
+				Challenges []daily.Challenge `json:"challenges"`
+			}{streak, history})
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(data))
+			return nil
+		}
+
+		cmd.Printf("Current streak: %d days (longest %d", streak.Current, streak.Longest)
+		if opts.Freezes > 0 {
+			cmd.Printf(", %d freezes left this month", streak.FreezesLeft)
+		}
+		cmd.Println(")")
+		if len(history) == 0 {
+			cmd.Println("No daily challenges yet.")
+			return nil
+		}
+
+		if term.IsTerminal(os.Stdout.Fd()) {
+			// We're in a TTY: make it fancy.
+			t := table.New().
+				Border(lipgloss.RoundedBorder()).
+				StyleFunc(func(row, col int) lipgloss.Style {
+					return lipgloss.NewStyle().Padding(0, 2)
+				}).
+				Headers("Day", "Topic", "Question", "Score")
+			for _, c := range history {
+				t.Row(c.Day, orDash(c.Topic), ansi.Truncate(firstLine(c.Question), 60, "…"), formatDailyScore(c))
+			}
+			lipgloss.Println(t)
+			return nil
+		}
+
+		// Not a TTY: plain output
+		for _, c := range history {
+			cmd.Printf("%s\t%s\t%s\t%s\n", c.Day, orDash(c.Topic), formatDailyScore(c), firstLine(c.Question))
+		}
+		return nil
+	},
+}
+
+func formatDailyScore(c daily.Challenge) string {
+	if !c.Done() {
+		return "-"
+	}
+	return fmt.Sprint(*c.Score)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+func init() {
+	dailyHistoryCmd.Flags().Bool("json", false, "Output as JSON")
+	dailyCmd.AddCommand(dailyHistoryCmd)
+}
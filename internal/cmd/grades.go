@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/drill"
+)
+
+var gradesCmd = &cobra.Command{
+	Use:   "grades",
+	Short: "Audit judged grades",
+	Long:  "List the grades flagged because the independent grader calls disagreed, show what each grader replied and clear the flags",
+}
+
+var gradesFlaggedCmd = &cobra.Command{
+	Use:   "flagged",
+	Short: "List flagged grades",
+	Example: `
+# List the grades to look at again
+prepf grades flagged
+
+# List them as JSON
+prepf grades flagged --json
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		_, conn, err := connectDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		questions, err := drill.NewService(db.New(conn), conn).Flagged(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			data, err := json.Marshal(questions)
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(data))
+			return nil
+		}
+
+		if len(questions) == 0 {
+			cmd.Println("No flagged grades.")
+			return nil
+		}
+
+		if term.IsTerminal(os.Stdout.Fd()) {
+			// We're in a TTY: make it fancy.
+			t := table.New().
+				Border(lipgloss.RoundedBorder()).
+				StyleFunc(func(row, col int) lipgloss.Style {
+					return lipgloss.NewStyle().Padding(0, 2)
+				}).
+				Headers("ID", "Date", "Topic", "Score", "Agreement")
+			for _, q := range questions {
+				t.Row(q.ID, formatDate(q.CreatedAt), orDash(q.Topic), formatScore(q.Score), formatAgreement(q.Agreement))
+			}
+			lipgloss.Println(t)
+			return nil
+		}
+
+		// Not a TTY: plain output
+		for _, q := range questions {
+			cmd.Printf("%s\t%s\t%s\t%s\t%s\n", q.ID, formatDate(q.CreatedAt), orDash(q.Topic), formatScore(q.Score), formatAgreement(q.Agreement))
+		}
+		return nil
+	},
+}
+
+var gradesShowCmd = &cobra.Command{
+	Use:   "show <question-id>",
+	Short: "Show the grader calls behind a grade",
+	Args:  cobra.ExactArgs(1),
+	Example: `
+# Show what each grader replied, as received
+prepf grades show 3f2c9a1e-... --raw
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		raw, _ := cmd.Flags().GetBool("raw")
+
+		_, conn, err := connectDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		samples, err := drill.NewService(db.New(conn), conn).Samples(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			data, err := json.Marshal(samples)
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(data))
+			return nil
+		}
+
+		if len(samples) == 0 {
+			cmd.Println("The grade wasn't judged by independent grader calls.")
+			return nil
+		}
+		for i, s := range samples {
+			cmd.Printf("%d. %s: %s\n", i+1, s.Model, formatScore(s.Score))
+			for _, d := range s.Dimensions {
+				cmd.Printf("   %s: %d\n", d.Dimension, d.Points)
+			}
+			if s.Rationale != "" {
+				cmd.Printf("   %s\n", s.Rationale)
+			}
+			if s.Err != "" {
+				cmd.Printf("   error: %s\n", s.Err)
+			}
+			if raw && s.Raw != "" {
+				cmd.Printf("   reply:\n%s\n", s.Raw)
+			}
+		}
+		return nil
+	},
+}
+
+var gradesResolveCmd = &cobra.Command{
+	Use:   "resolve <question-id>",
+	Short: "Clear the flag of a grade",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, conn, err := connectDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		q, err := drill.NewService(db.New(conn), conn).Resolve(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Cleared the flag of %q.\n", q.Text)
+		return nil
+	},
+}
+
+func formatDate(unix int64) string {
+	return time.Unix(unix, 0).Format(time.DateOnly)
+}
+
+func formatScore(score *int) string {
+	if score == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d/100", *score)
+}
+
+func formatAgreement(agreement *float64) string {
+	if agreement == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", 100*(*agreement))
+}
+
+func init() {
+	gradesFlaggedCmd.Flags().Bool("json", false, "Output as JSON")
+	gradesShowCmd.Flags().Bool("json", false, "Output as JSON")
+	gradesShowCmd.Flags().Bool("raw", false, "Include each grader's reply as received")
+
+	gradesCmd.AddCommand(gradesFlaggedCmd, gradesShowCmd, gradesResolveCmd)
+}
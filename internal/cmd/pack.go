@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/trankhanh040147/prepf/internal/pack"
+	"github.com/trankhanh040147/prepf/internal/rubric"
+)
+
+var packCmd = &cobra.Command{
+	Use:   "pack",
+	Short: "Inspect company interview packs",
+	Long:  "List, show and validate interview packs: directories in the skills paths with a pack.yaml describing a company's interview rounds, an optional persona.md for the interviewer and an optional questions.yaml. Start a round from the mode selector of a new session",
+}
+
+var packListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the interview packs",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		packs, problems := pack.DiscoverConfigured(cfg)
+
+		if jsonOutput {
+			data, err := json.Marshal(packs)
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(data))
+			return nil
+		}
+
+		if len(packs) == 0 {
+			cmd.Println("No interview packs found; add a directory with a pack.yaml to a skills path.")
+		} else if term.IsTerminal(os.Stdout.Fd()) {
+			// We're in a TTY: make it fancy.
+			t := table.New().
+				Border(lipgloss.RoundedBorder()).
+				StyleFunc(func(row, col int) lipgloss.Style {
+					return lipgloss.NewStyle().Padding(0, 2)
+				}).
+				Headers("Name", "Company", "Title", "Rounds", "Questions")
+			for _, p := range packs {
+				t.Row(p.Name, p.Company, p.Title, fmt.Sprint(len(p.Rounds)), fmt.Sprint(len(p.Questions)))
+			}
+			lipgloss.Println(t)
+		} else {
+			// Not a TTY: plain output
+			for _, p := range packs {
+				cmd.Printf("%s\t%s\t%s\t%d\t%d\n", p.Name, p.Company, p.Title, len(p.Rounds), len(p.Questions))
+			}
+		}
+		if len(problems) > 0 {
+			cmd.PrintErrf("%d invalid pack(s) left out; run prepf pack validate for details.\n", len(problems))
+		}
+		return nil
+	},
+}
+
+var packShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show the rounds of an interview pack",
+	Args:  cobra.ExactArgs(1),
+	Example: `
+# Show the rounds of the acme pack
+prepf pack show acme
+
+# Show the whole pack, persona and questions included, as JSON
+prepf pack show acme --json
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		packs, _ := pack.DiscoverConfigured(cfg)
+		p, ok := pack.Find(packs, args[0])
+		if !ok {
+			return fmt.Errorf("there is no valid pack named %q", args[0])
+		}
+
+		if jsonOutput {
+			data, err := json.Marshal(p)
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(data))
+			return nil
+		}
+
+		cmd.Printf("%s (%s)\n", p.DisplayName(), p.Dir)
+		if p.Description != "" {
+			cmd.Println(strings.TrimSpace(p.Description))
+		}
+		available, _ := rubric.Discover(rubric.Dirs(cfg))
+		for i, r := range p.Rounds {
+			n := i + 1
+			cmd.Printf("\n%d. %s: %s, %d min\n", n, r.Mode, r.Name, r.Minutes)
+			if len(r.Focus) > 0 {
+				cmd.Printf("   Focus: %s\n", strings.Join(r.Focus, ", "))
+			}
+			if grading, ok := rubric.Pick(available, p.RubricName(n), cfg.Options.Rubrics[r.Mode], r.Mode); ok {
+				cmd.Printf("   Rubric: %s%s\n", grading.Ref(), formatWeights(p.WeightsFor(n)))
+			}
+			cmd.Printf("   Questions: %d\n", len(p.QuestionsFor(n)))
+		}
+		if p.Persona != "" {
+			persona, _, _ := strings.Cut(p.Persona, "\n")
+			cmd.Printf("\nPersona: %s\n", persona)
+		}
+		return nil
+	},
+}
+
+var packValidateCmd = &cobra.Command{
+	Use:   "validate [dir...]",
+	Short: "Check interview packs for mistakes",
+	Long:  "Check the packs in the given directories, or every pack in the skills paths, including that the rubrics their rounds are graded against exist and have the weighted dimensions",
+	Example: `
+# Check every pack in the skills paths
+prepf pack validate
+
+# Check a pack being written
+prepf pack validate ./packs/acme
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		var packs []*pack.Pack
+		var problems []pack.Problem
+		if len(args) == 0 {
+			packs, problems = pack.DiscoverConfigured(cfg)
+		}
+		for _, dir := range args {
+			p, err := pack.Load(dir)
+			if err == nil {
+				err = p.Validate()
+			}
+			if err != nil {
+				problems = append(problems, pack.Problem{Path: dir, Err: err})
+				continue
+			}
+			packs = append(packs, p)
+		}
+
+		available, _ := rubric.Discover(rubric.Dirs(cfg))
+		for _, p := range packs {
+			if err := p.CheckRubrics(available, cfg.Options.Rubrics); err != nil {
+				problems = append(problems, pack.Problem{Path: p.Dir, Err: err})
+				continue
+			}
+			cmd.Printf("ok\t%s\t%s\n", p.Name, p.Dir)
+		}
+		for _, problem := range problems {
+			cmd.Printf("invalid\t%s\n", filepath.Base(problem.Path))
+			for _, err := range unjoin(problem.Err) {
+				cmd.Printf("  %s\n", err)
+			}
+		}
+
+		if len(problems) > 0 {
+			return fmt.Errorf("%d invalid pack(s)", len(problems))
+		}
+		if len(packs) == 0 {
+			cmd.Println("No interview packs found.")
+		}
+		return nil
+	},
+}
+
+// unjoin splits an error made with errors.Join into its parts.
+func unjoin(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+func formatWeights(weights map[string]float64) string {
+	if len(weights) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(weights))
+	for _, name := range slices.Sorted(maps.Keys(weights)) {
+		parts = append(parts, fmt.Sprintf("%s×%g", name, weights[name]))
+	}
+	return " weighted " + strings.Join(parts, ", ")
+}
+
+func init() {
+	packListCmd.Flags().Bool("json", false, "Output as JSON")
+	packShowCmd.Flags().Bool("json", false, "Output as JSON")
+
+	packCmd.AddCommand(packListCmd, packShowCmd, packValidateCmd)
+}
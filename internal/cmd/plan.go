@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/plan"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Plan practice toward upcoming interviews",
+	Long:  "Record upcoming interviews and generate a day-by-day practice plan leading up to them, fit to the minutes set in options.plan and weighted towards the weakest topics. Start today's items with /plan in the TUI",
+	Example: `
+# Record an interview
+prepf plan target add --company Acme --role "Backend Engineer" --date 2026-11-02 --topic "System design"
+
+# Plan the days until then and show the next week
+prepf plan generate
+prepf plan show
+
+# Add the plan to a calendar, practicing from 7pm
+prepf plan export --start 19:00 -o plan.ics
+  `,
+}
+
+var planTargetCmd = &cobra.Command{
+	Use:   "target",
+	Short: "Manage the interviews the plan works towards",
+}
+
+var planTargetAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Record an upcoming interview",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		company, _ := cmd.Flags().GetString("company")
+		role, _ := cmd.Flags().GetString("role")
+		date, _ := cmd.Flags().GetString("date")
+		topics, _ := cmd.Flags().GetStringArray("topic")
+
+		svc, conn, err := connectPlan(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		t, err := svc.AddTarget(cmd.Context(), plan.Target{
+			Company: company,
+			Role:    role,
+			Day:     date,
+			Topics:  topics,
+		})
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Added %s on %s (%s). Run prepf plan generate to plan for it.\n", t.Name(), t.Day, t.ID)
+		return nil
+	},
+}
+
+var planTargetListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the interviews",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		svc, conn, err := connectPlan(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		targets, err := svc.Targets(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			data, err := json.Marshal(targets)
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(data))
+			return nil
+		}
+
+		if len(targets) == 0 {
+			cmd.Println("No interviews yet.")
+			return nil
+		}
+
+		if term.IsTerminal(os.Stdout.Fd()) {
+			// We're in a TTY: make it fancy.
+			t := table.New().
+				Border(lipgloss.RoundedBorder()).
+				StyleFunc(func(row, col int) lipgloss.Style {
+					return lipgloss.NewStyle().Padding(0, 2)
+				}).
+				Headers("ID", "Date", "Interview", "Topics")
+			for _, target := range targets {
+				t.Row(target.ID, target.Day, target.Name(), strings.Join(target.Topics, ", "))
+			}
+			lipgloss.Println(t)
+			return nil
+		}
+
+		// Not a TTY: plain output
+		for _, target := range targets {
+			cmd.Printf("%s\t%s\t%s\t%s\n", target.ID, target.Day, target.Name(), strings.Join(target.Topics, ", "))
+		}
+		return nil
+	},
+}
+
+var planTargetRemoveCmd = &cobra.Command{
+	Use:     "rm <id>",
+	Aliases: []string{"remove"},
+	Short:   "Remove an interview and its plan",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		svc, conn, err := connectPlan(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		if err := svc.RemoveTarget(cmd.Context(), args[0]); err != nil {
+			return err
+		}
+		cmd.Println("Removed the interview and its plan.")
+		return nil
+	},
+}
+
+var planGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Plan the days until the last interview",
+	Long:  "Replace the plan from today on, keeping the items already started. Run it again after missing a day, adding an interview or practicing a lot to reschedule",
+	Example: `
+# Plan with the minutes from options.plan
+prepf plan generate
+
+# Plan with 45 minutes on weekdays and two hours on weekends
+prepf plan generate --minutes 45 --weekend-minutes 120
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, conn, err := connectDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		var opts config.Plan
+		if cfg.Options.Plan != nil {
+			opts = *cfg.Options.Plan
+		}
+		if cmd.Flags().Changed("minutes") {
+			opts.Minutes, _ = cmd.Flags().GetInt("minutes")
+		}
+		if cmd.Flags().Changed("weekend-minutes") {
+			opts.WeekendMinutes, _ = cmd.Flags().GetInt("weekend-minutes")
+		}
+
+		q := db.New(conn)
+		items, err := plan.NewService(q, drill.NewService(q, conn), cfg).Generate(cmd.Context(), &opts)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			cmd.Println("Nothing to plan; add an interview with prepf plan target add.")
+			return nil
+		}
+		minutes := 0
+		for _, item := range items {
+			minutes += item.Minutes
+		}
+		cmd.Printf("Planned %d item(s), %d hour(s) %d minute(s) in all, through %s.\n", len(items), minutes/60, minutes%60, items[len(items)-1].Day)
+		return nil
+	},
+}
+
+var planShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the upcoming plan",
+	Example: `
+# Show the next week
+prepf plan show
+
+# Show the whole plan as JSON
+prepf plan show --days 0 --json
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		svc, conn, err := connectPlan(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		now := time.Now()
+		to := "9999-12-31"
+		if days > 0 {
+			to = plan.Day(now.AddDate(0, 0, days-1))
+		}
+		items, err := svc.Items(cmd.Context(), plan.Day(now), to)
+		if err != nil {
+			return err
+		}
+		missed, err := svc.Missed(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			data, err := json.Marshal(struct {
+				Items  []plan.Item `json:"items"`
+				Missed []plan.Item `json:"missed"`
+			}{items, missed})
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(data))
+			return nil
+		}
+
+		if len(missed) > 0 {
+			cmd.Printf("%d item(s) of past days weren't done; run prepf plan generate to reschedule.\n", len(missed))
+		}
+		if len(items) == 0 {
+			cmd.Println("Nothing planned; add an interview with prepf plan target add and run prepf plan generate.")
+			return nil
+		}
+
+		if term.IsTerminal(os.Stdout.Fd()) {
+			// We're in a TTY: make it fancy.
+			t := table.New().
+				Border(lipgloss.RoundedBorder()).
+				StyleFunc(func(row, col int) lipgloss.Style {
+					return lipgloss.NewStyle().Padding(0, 2)
+				}).
+				Headers("Day", "Item", "Minutes", "Progress", "Interview")
+			for _, item := range items {
+				t.Row(item.Day, item.Title(), fmt.Sprint(item.Minutes), formatPlanProgress(item), item.InterviewDay)
+			}
+			lipgloss.Println(t)
+			return nil
+		}
+
+		// Not a TTY: plain output
+		for _, item := range items {
+			cmd.Printf("%s\t%s\t%d\t%s\t%s\n", item.Day, item.Title(), item.Minutes, formatPlanProgress(item), item.InterviewDay)
+		}
+		return nil
+	},
+}
+
+var planExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the interviews and plan as an iCalendar file",
+	Example: `
+# Print the calendar with all-day items
+prepf plan export
+
+# Write it to a file with items starting at 7pm, one after another
+prepf plan export --start 19:00 -o plan.ics
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		start, _ := cmd.Flags().GetString("start")
+
+		svc, conn, err := connectPlan(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		now := time.Now()
+		targets, err := svc.Targets(cmd.Context())
+		if err != nil {
+			return err
+		}
+		items, err := svc.Items(cmd.Context(), plan.Day(now), "9999-12-31")
+		if err != nil {
+			return err
+		}
+		ics, err := plan.ICS(targets, items, plan.ICSOptions{Start: start}, now)
+		if err != nil {
+			return err
+		}
+
+		if output == "" {
+			cmd.Print(ics)
+			return nil
+		}
+		if err := os.WriteFile(output, []byte(ics), 0o644); err != nil {
+			return err
+		}
+		cmd.Printf("Exported %d interview(s) and %d item(s) to %s.\n", len(targets), len(items), output)
+		return nil
+	},
+}
+
+// connectPlan opens the database and the practice plan service.
+func connectPlan(cmd *cobra.Command) (plan.Service, *sql.DB, error) {
+	cfg, conn, err := connectDB(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	q := db.New(conn)
+	return plan.NewService(q, drill.NewService(q, conn), cfg), conn, nil
+}
+
+func formatPlanProgress(item plan.Item) string {
+	if item.Done() {
+		return "done"
+	}
+	return fmt.Sprintf("%d/%d", item.Graded, item.Questions)
+}
+
+func init() {
+	planTargetAddCmd.Flags().String("company", "", "Company the interview is with")
+	planTargetAddCmd.Flags().String("role", "", "Role interviewed for")
+	planTargetAddCmd.Flags().String("date", "", "Date of the interview, as in 2026-11-02")
+	planTargetAddCmd.Flags().StringArray("topic", nil, "Topic the interview is known to cover, repeatable")
+	_ = planTargetAddCmd.MarkFlagRequired("company")
+	_ = planTargetAddCmd.MarkFlagRequired("date")
+	planTargetListCmd.Flags().Bool("json", false, "Output as JSON")
+
+	planGenerateCmd.Flags().Int("minutes", 0, "Minutes to practice on weekdays, overriding options.plan.minutes")
+	planGenerateCmd.Flags().Int("weekend-minutes", 0, "Minutes to practice on weekends, overriding options.plan.weekend_minutes")
+
+	planShowCmd.Flags().Int("days", 7, "Number of days to show, 0 for the whole plan")
+	planShowCmd.Flags().Bool("json", false, "Output as JSON")
+
+	planExportCmd.Flags().StringP("output", "o", "", "File to write instead of stdout")
+	planExportCmd.Flags().String("start", "", "Local time items start at, as in 19:00; all-day items when empty")
+
+	planTargetCmd.AddCommand(planTargetAddCmd, planTargetListCmd, planTargetRemoveCmd)
+	planCmd.AddCommand(planTargetCmd, planGenerateCmd, planShowCmd, planExportCmd)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/usage"
+)
+
+var progressCmd = &cobra.Command{
+	Use:   "progress",
+	Short: "Show drill progress per topic",
+	Long:  "Show the questions answered in gym sessions grouped by topic, with the average score, hints used and the time spent thinking about and typing answers",
+	Example: `
+# Show progress for the last 30 days
+prepf progress
+
+# Show progress for the last week as JSON
+prepf progress --days 7 --json
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if days <= 0 {
+			return fmt.Errorf("--days must be positive")
+		}
+
+		_, conn, err := connectDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		since := usage.StartOfDay(time.Now()).AddDate(0, 0, -(days - 1))
+		stats, err := drill.NewService(db.New(conn), conn).Progress(cmd.Context(), since)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			data, err := json.Marshal(stats)
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(data))
+			return nil
+		}
+
+		if len(stats) == 0 {
+			cmd.Println("No drill questions answered yet.")
+			return nil
+		}
+
+		if term.IsTerminal(os.Stdout.Fd()) {
+			// We're in a TTY: make it fancy.
+			t := table.New().
+				Border(lipgloss.RoundedBorder()).
+				StyleFunc(func(row, col int) lipgloss.Style {
+					return lipgloss.NewStyle().Padding(0, 2)
+				}).
+				Headers("Topic", "Questions", "Avg Score", "Avg Hints", "Thinking", "Typing", "Pastes")
+			for _, s := range stats {
+				t.Row(
+					orDash(s.Topic),
+					fmt.Sprint(s.Questions),
+					fmt.Sprintf("%.0f", s.AvgScore),
+					fmt.Sprintf("%.1f", s.AvgHints),
+					formatSeconds(s.AvgThinkSeconds),
+					formatSeconds(s.AvgComposeSeconds),
+					fmt.Sprint(s.Pastes),
+				)
+			}
+			lipgloss.Println(t)
+			return nil
+		}
+
+		// Not a TTY: plain output
+		for _, s := range stats {
+			cmd.Printf("%s\t%d\t%.1f\t%.1f\t%.1f\t%.1f\t%d\n", orDash(s.Topic), s.Questions, s.AvgScore, s.AvgHints, s.AvgThinkSeconds, s.AvgComposeSeconds, s.Pastes)
+		}
+		return nil
+	},
+}
+
+func formatSeconds(seconds float64) string {
+	if seconds == 0 {
+		return "-"
+	}
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second).String()
+}
+
+func init() {
+	progressCmd.Flags().Int("days", 30, "Number of days to include")
+	progressCmd.Flags().Bool("json", false, "Output as JSON")
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"io"
@@ -46,6 +47,14 @@ func init() {
 		logsCmd,
 		schemaCmd,
 		loginCmd,
+		usageCmd,
+		progressCmd,
+		cardsCmd,
+		sessionCmd,
+		dailyCmd,
+		gradesCmd,
+		planCmd,
+		packCmd,
 	)
 }
 
@@ -76,38 +85,45 @@ crush run "Explain the use of context in Go"
 crush -y
   `,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		app, err := setupAppWithProgressBar(cmd)
-		if err != nil {
-			return err
-		}
-		defer app.Shutdown()
-
-		event.AppInitialized()
-
-		// Set up the TUI.
-		var env uv.Environ = os.Environ()
-		ui := tui.New(app)
-		ui.QueryVersion = shouldQueryTerminalVersion(env)
-
-		program := tea.NewProgram(
-			ui,
-			tea.WithEnvironment(env),
-			tea.WithContext(cmd.Context()),
-			tea.WithFilter(tui.MouseEventFilter)) // Filter mouse events based on focus state
-		go app.Subscribe(program)
-
-		if _, err := program.Run(); err != nil {
-			event.Error(err)
-			slog.Error("TUI run error", "error", err)
-			return errors.New("Crush crashed. If metrics are enabled, we were notified about it. If you'd like to report it, please copy the stacktrace above and open an issue at https://github.com/trankhanh040147/prepf/issues/new?template=bug.yml") //nolint:staticcheck
-		}
-		return nil
+		return runTUI(cmd, false)
 	},
 	PostRun: func(cmd *cobra.Command, args []string) {
 		event.AppExited()
 	},
 }
 
+// runTUI sets up the app and runs the TUI until it exits. With startDaily
+// the TUI opens today's daily challenge right away.
+func runTUI(cmd *cobra.Command, startDaily bool) error {
+	app, err := setupAppWithProgressBar(cmd)
+	if err != nil {
+		return err
+	}
+	defer app.Shutdown()
+
+	event.AppInitialized()
+
+	// Set up the TUI.
+	var env uv.Environ = os.Environ()
+	ui := tui.New(app)
+	ui.QueryVersion = shouldQueryTerminalVersion(env)
+	ui.StartDaily = startDaily
+
+	program := tea.NewProgram(
+		ui,
+		tea.WithEnvironment(env),
+		tea.WithContext(cmd.Context()),
+		tea.WithFilter(tui.MouseEventFilter)) // Filter mouse events based on focus state
+	go app.Subscribe(program)
+
+	if _, err := program.Run(); err != nil {
+		event.Error(err)
+		slog.Error("TUI run error", "error", err)
+		return errors.New("Crush crashed. If metrics are enabled, we were notified about it. If you'd like to report it, please copy the stacktrace above and open an issue at https://github.com/trankhanh040147/prepf/issues/new?template=bug.yml") //nolint:staticcheck
+	}
+	return nil
+}
+
 var heartbit = lipgloss.NewStyle().Foreground(charmtone.Dolly).SetString(`
     ▄▄▄▄▄▄▄▄    ▄▄▄▄▄▄▄▄
   ███████████  ███████████
@@ -224,6 +240,47 @@ func setupApp(cmd *cobra.Command) (*app.App, error) {
 	return appInstance, nil
 }
 
+// loadConfig loads the configuration without starting the full app.
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+
+	cwd, err := ResolveCwd(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.Load(cwd, dataDir, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %v", err)
+	}
+	return cfg, nil
+}
+
+// connectDB loads the configuration and opens the project database without
+// starting the full app. It is meant for commands that only read or write
+// stored data.
+func connectDB(cmd *cobra.Command) (*config.Config, *sql.DB, error) {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, err := db.Connect(cmd.Context(), cfg.Options.DataDirectory)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, conn, nil
+}
+
+// orDash returns s, or a dash in its place in tables and plain output when
+// it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 func shouldEnableMetrics() bool {
 	if v, _ := strconv.ParseBool(os.Getenv("CRUSH_DISABLE_METRICS")); v {
 		return false
@@ -2,13 +2,19 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 
+	"github.com/trankhanh040147/prepf/internal/app"
 	"github.com/trankhanh040147/prepf/internal/event"
+	"github.com/trankhanh040147/prepf/internal/message"
+	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs/filepicker"
 	"github.com/spf13/cobra"
 )
 
@@ -29,21 +35,51 @@ crush run "What is this code doing?" <<< prrr.go
 
 # Run in quiet mode (hide the spinner)
 crush run --quiet "Generate a README for this project"
+
+# Emit one JSON event per line for scripts
+crush run --output-format stream-json "Ask me a Go interview question"
+
+# Start a one-shot gym drill
+crush run --mode gym "Give me a medium sliding window problem"
+
+# Answer the last question of the most recent session
+crush run --continue < answer.md
+
+# Continue a specific session with an attachment and a turn limit
+crush run --session 3f2a... --attach solution.go --max-turns 3 "Review my solution"
   `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		quiet, _ := cmd.Flags().GetBool("quiet")
+		outputFormat, _ := cmd.Flags().GetString("output-format")
+		format, err := app.ParseOutputFormat(outputFormat)
+		if err != nil {
+			return err
+		}
+		mode, _ := cmd.Flags().GetString("mode")
+		sessionID, _ := cmd.Flags().GetString("session")
+		continueLast, _ := cmd.Flags().GetBool("continue")
+		attachPaths, _ := cmd.Flags().GetStringSlice("attach")
+		maxTurns, _ := cmd.Flags().GetInt("max-turns")
+		if maxTurns < 0 {
+			return errors.New("--max-turns must not be negative")
+		}
+
+		attachments, err := readAttachments(attachPaths)
+		if err != nil {
+			return err
+		}
 
 		// Cancel on SIGINT or SIGTERM.
 		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
 		defer cancel()
 
-		app, err := setupApp(cmd)
+		appInstance, err := setupApp(cmd)
 		if err != nil {
 			return err
 		}
-		defer app.Shutdown()
+		defer appInstance.Shutdown()
 
-		if !app.Config().IsConfigured() {
+		if !appInstance.Config().IsConfigured() {
 			return fmt.Errorf("no providers configured - please run 'prepf' to set up a provider interactively")
 		}
 
@@ -55,14 +91,22 @@ crush run --quiet "Generate a README for this project"
 			return err
 		}
 
-		if prompt == "" {
+		if prompt == "" && !message.ContainsTextAttachment(attachments) {
 			return fmt.Errorf("no prompt provided")
 		}
 
 		event.SetNonInteractive(true)
 		event.AppInitialized()
 
-		return app.RunNonInteractive(ctx, os.Stdout, prompt, quiet)
+		return appInstance.RunNonInteractive(ctx, os.Stdout, prompt, app.NonInteractiveOptions{
+			Quiet:        quiet,
+			OutputFormat: format,
+			Mode:         mode,
+			SessionID:    sessionID,
+			Continue:     continueLast,
+			Attachments:  attachments,
+			MaxTurns:     maxTurns,
+		})
 	},
 	PostRun: func(cmd *cobra.Command, args []string) {
 		event.AppExited()
@@ -71,4 +115,38 @@ crush run --quiet "Generate a README for this project"
 
 func init() {
 	runCmd.Flags().BoolP("quiet", "q", false, "Hide spinner")
+	runCmd.Flags().String("output-format", string(app.OutputFormatText), "Output format: text, json or stream-json")
+	runCmd.Flags().StringP("mode", "m", "", "Session mode for new sessions, e.g. mock or gym")
+	runCmd.Flags().StringP("session", "s", "", "Continue the session with this ID")
+	runCmd.Flags().Bool("continue", false, "Continue the most recent session")
+	runCmd.Flags().StringSliceP("attach", "a", nil, "Attach a file to the prompt (repeatable)")
+	runCmd.Flags().Int("max-turns", 0, "Stop after this many model turns (0 for no limit)")
+	runCmd.MarkFlagsMutuallyExclusive("session", "continue")
+}
+
+// readAttachments loads files passed with --attach, applying the same size
+// limit as the file picker.
+func readAttachments(paths []string) ([]message.Attachment, error) {
+	attachments := make([]message.Attachment, 0, len(paths))
+	for _, path := range paths {
+		tooBig, err := filepicker.IsFileTooBig(path, filepicker.MaxAttachmentSize)
+		if err != nil {
+			return nil, fmt.Errorf("unable to attach %s: %w", path, err)
+		}
+		if tooBig {
+			return nil, fmt.Errorf("unable to attach %s: file too large, max 5MB", path)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to attach %s: %w", path, err)
+		}
+		mimeType := http.DetectContentType(content[:min(512, len(content))])
+		attachments = append(attachments, message.Attachment{
+			FilePath: path,
+			FileName: filepath.Base(path),
+			MimeType: mimeType,
+			Content:  content,
+		})
+	}
+	return attachments, nil
 }
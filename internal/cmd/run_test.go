@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAttachments(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "solution.go")
+	require.NoError(t, os.WriteFile(path, []byte("package main\n"), 0o644))
+
+	attachments, err := readAttachments([]string{path})
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+	require.Equal(t, "solution.go", attachments[0].FileName)
+	require.True(t, attachments[0].IsText())
+	require.Equal(t, "package main\n", string(attachments[0].Content))
+
+	_, err = readAttachments([]string{filepath.Join(dir, "missing.go")})
+	require.Error(t, err)
+}
@@ -0,0 +1,395 @@
+package cmd
+
+import (
+	"cmp"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/history"
+	"github.com/trankhanh040147/prepf/internal/message"
+	"github.com/trankhanh040147/prepf/internal/session"
+	"github.com/trankhanh040147/prepf/internal/transcript"
+)
+
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Inspect, share and revert sessions",
+	Long:  "List sessions, export and import their transcripts with annotations, show the file changes made in them and revert those changes",
+}
+
+var sessionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List sessions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, conn, err := connectDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		sessions, err := session.NewService(db.New(conn)).List(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if len(sessions) == 0 {
+			cmd.Println("No sessions yet.")
+			return nil
+		}
+
+		rows := make([][]string, len(sessions))
+		for i, s := range sessions {
+			reviewed := "-"
+			if s.Reviewed() {
+				reviewed = s.ReviewedBy
+			}
+			rows[i] = []string{s.ID, s.Title, time.Unix(s.UpdatedAt, 0).Format(time.DateTime), reviewed}
+		}
+		printRows(cmd, []string{"ID", "Title", "Updated", "Reviewed By"}, rows)
+		return nil
+	},
+}
+
+var sessionExportCmd = &cobra.Command{
+	Use:   "export <session-id>",
+	Short: "Export a session transcript with its annotations",
+	Long:  "Export a session with its messages and annotations as JSON, to share it with a peer or mentor for review",
+	Example: `
+# Export a session to send it for review
+prepf session export 3f2a... -o mock.json
+  `,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+
+		_, conn, err := connectDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		t, err := transcript.Export(cmd.Context(), db.New(conn), args[0])
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("session %s not found", args[0])
+		}
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = cmd.OutOrStdout()
+		if output != "" && output != "-" {
+			f, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(t)
+	},
+}
+
+var sessionImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import a session transcript with its annotations",
+	Long:  "Import a session exported with prepf session export. A new session is added with its messages; for one that is already here, such as your own session coming back from a reviewer, the annotations and review status are merged.",
+	Example: `
+# Import a session a peer sent for review
+prepf session import mock.json
+
+# Merge the annotations of a reviewed session
+prepf session import mock-reviewed.json
+  `,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var r io.Reader = cmd.InOrStdin()
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		var t transcript.Transcript
+		if err := json.NewDecoder(r).Decode(&t); err != nil {
+			return fmt.Errorf("read transcript: %w", err)
+		}
+
+		_, conn, err := connectDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		result, err := transcript.Import(cmd.Context(), conn, t)
+		if err != nil {
+			return err
+		}
+		if result.Created {
+			cmd.Printf("Imported session %s (%s) with %d message(s) and %d annotation(s).\n", result.SessionID, t.Session.Title, len(t.Messages), result.Annotations)
+		} else {
+			cmd.Printf("Merged %d annotation(s) into session %s (%s).\n", result.Annotations, result.SessionID, t.Session.Title)
+		}
+		if result.Skipped > 0 {
+			cmd.Printf("Skipped %d annotation(s) on messages this session doesn't have.\n", result.Skipped)
+		}
+		return nil
+	},
+}
+
+var sessionHistoryCmd = &cobra.Command{
+	Use:   "history <session-id>",
+	Short: "Show the file changes made in a session",
+	Long:  "Show the file changes the agent made in a session, with the number of the message that made each one for revert --to",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, conn, err := connectDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		files, messages, err := loadSessionHistory(cmd, conn, args[0])
+		if err != nil {
+			return err
+		}
+
+		// Number each tool call after its message, as revert --to takes it.
+		numbers := make(map[string]int)
+		for i, m := range messages {
+			for _, call := range m.ToolCalls() {
+				numbers[call.ID] = i + 1
+			}
+		}
+		var rows [][]string
+		for _, f := range files {
+			number := "-"
+			if n, ok := numbers[f.ToolCallID]; ok {
+				number = strconv.Itoa(n)
+			}
+			rows = append(rows, []string{number, cmp.Or(f.ToolCallID, "-"), relativePath(cmd, f.Path), strconv.FormatInt(f.Version, 10)})
+		}
+		if len(rows) == 0 {
+			cmd.Println("No file changes recorded in this session.")
+			return nil
+		}
+		printRows(cmd, []string{"Message", "Tool Call", "File", "Version"}, rows)
+		return nil
+	},
+}
+
+// revertTargets are the flags choosing what session revert undoes.
+var revertTargets = []string{"to", "tool-call", "file"}
+
+var sessionRevertCmd = &cobra.Command{
+	Use:   "revert <session-id>",
+	Short: "Revert file changes made in a session",
+	Long:  "Revert the file changes the agent made in a session: everything from a message on, a single tool call, or one file to an earlier version. Files changed on disk since the agent last wrote them are left alone unless --force is given.",
+	Example: `
+# Revert everything from the 12th message of the session on
+prepf session revert 3f2a... --to 12
+
+# Revert a single tool call
+prepf session revert 3f2a... --tool-call toolu_01...
+
+# Restore a file to an earlier version, as listed by prepf session history
+prepf session revert 3f2a... --file main.go --version 4
+  `,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		toolCall, _ := cmd.Flags().GetString("tool-call")
+		file, _ := cmd.Flags().GetString("file")
+		version, _ := cmd.Flags().GetInt64("version")
+		force, _ := cmd.Flags().GetBool("force")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		// Exactly one of the target flags is set, as enforced by cobra, but
+		// set to nothing it would select nothing to revert.
+		for _, name := range revertTargets {
+			if value, _ := cmd.Flags().GetString(name); cmd.Flags().Changed(name) && value == "" {
+				return fmt.Errorf("--%s needs a value", name)
+			}
+		}
+
+		_, conn, err := connectDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		sessionID := args[0]
+		files := history.NewService(db.New(conn), conn)
+		var changes []history.Change
+		switch {
+		case cmd.Flags().Changed("to"):
+			_, messages, err := loadSessionHistory(cmd, conn, sessionID)
+			if err != nil {
+				return err
+			}
+			messageID, err := resolveMessage(messages, to)
+			if err != nil {
+				return err
+			}
+			calls, err := history.ToolCallsFrom(messages, messageID)
+			if err != nil {
+				return err
+			}
+			changes, err = files.PlanRevert(cmd.Context(), sessionID, calls)
+			if err != nil {
+				return err
+			}
+		case cmd.Flags().Changed("tool-call"):
+			changes, err = files.PlanRevert(cmd.Context(), sessionID, []string{toolCall})
+			if err != nil {
+				return err
+			}
+		case cmd.Flags().Changed("file"):
+			if !cmd.Flags().Changed("version") {
+				return fmt.Errorf("--file needs --version")
+			}
+			path, err := absolutePath(cmd, file)
+			if err != nil {
+				return err
+			}
+			change, err := files.PlanRestore(cmd.Context(), sessionID, path, version)
+			if err != nil {
+				return err
+			}
+			changes = []history.Change{change}
+		}
+
+		if len(changes) == 0 {
+			cmd.Println("Nothing to revert.")
+			return nil
+		}
+		for _, c := range changes {
+			if c.Remove {
+				cmd.Printf("remove  %s\n", relativePath(cmd, c.Path))
+			} else {
+				cmd.Printf("restore %s to version %d\n", relativePath(cmd, c.Path), c.Target.Version)
+			}
+			if len(c.Discards) > 0 {
+				cmd.Printf("        also undoing the later changes of %s\n", strings.Join(c.Discards, ", "))
+			}
+		}
+		if dryRun {
+			return nil
+		}
+		if err := files.Revert(cmd.Context(), sessionID, changes, force); err != nil {
+			if errors.Is(err, history.ErrConflict) {
+				return fmt.Errorf("%w\nnothing was reverted; use --force to overwrite these changes", err)
+			}
+			return err
+		}
+		cmd.Printf("Reverted %d file(s).\n", len(changes))
+		return nil
+	},
+}
+
+func loadSessionHistory(cmd *cobra.Command, conn *sql.DB, sessionID string) ([]history.File, []message.Message, error) {
+	q := db.New(conn)
+	if _, err := session.NewService(q).Get(cmd.Context(), sessionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, fmt.Errorf("session %s not found", sessionID)
+		}
+		return nil, nil, err
+	}
+	files, err := history.NewService(q, conn).ListBySession(cmd.Context(), sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	messages, err := message.NewService(q).List(cmd.Context(), sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return files, messages, nil
+}
+
+// resolveMessage takes a message ID or its 1-based number in the session.
+func resolveMessage(messages []message.Message, ref string) (string, error) {
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(messages) {
+			return "", fmt.Errorf("message %d is out of range: the session has %d messages", n, len(messages))
+		}
+		return messages[n-1].ID, nil
+	}
+	return ref, nil
+}
+
+func absolutePath(cmd *cobra.Command, path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path), nil
+	}
+	cwd, err := ResolveCwd(cmd)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cwd, path), nil
+}
+
+func relativePath(cmd *cobra.Command, path string) string {
+	cwd, err := ResolveCwd(cmd)
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(cwd, path); err == nil && filepath.IsLocal(rel) {
+		return rel
+	}
+	return path
+}
+
+// printRows prints a table on a terminal and tab-separated rows otherwise.
+func printRows(cmd *cobra.Command, headers []string, rows [][]string) {
+	if term.IsTerminal(os.Stdout.Fd()) {
+		t := table.New().
+			Border(lipgloss.RoundedBorder()).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				return lipgloss.NewStyle().Padding(0, 2)
+			}).
+			Headers(headers...)
+		for _, row := range rows {
+			t.Row(row...)
+		}
+		lipgloss.Println(t)
+		return
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i > 0 {
+				cmd.Print("\t")
+			}
+			cmd.Print(cell)
+		}
+		cmd.Println()
+	}
+}
+
+func init() {
+	sessionRevertCmd.Flags().String("to", "", "Revert everything from this message on, given as its number in prepf session history or its ID")
+	sessionRevertCmd.Flags().String("tool-call", "", "Revert a single tool call")
+	sessionRevertCmd.Flags().String("file", "", "Restore this file to the version given with --version")
+	sessionRevertCmd.Flags().Int64("version", 0, "Version to restore --file to")
+	sessionRevertCmd.Flags().Bool("force", false, "Overwrite files changed on disk since the agent last wrote them")
+	sessionRevertCmd.Flags().Bool("dry-run", false, "Only show what would be reverted")
+	sessionRevertCmd.MarkFlagsMutuallyExclusive(revertTargets...)
+	sessionRevertCmd.MarkFlagsOneRequired(revertTargets...)
+
+	sessionExportCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+
+	sessionCmd.AddCommand(sessionListCmd, sessionExportCmd, sessionImportCmd, sessionHistoryCmd, sessionRevertCmd)
+}
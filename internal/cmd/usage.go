@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/usage"
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show spend and token usage",
+	Long:  "Show spend and token usage grouped by day, mode and model, as recorded for budget enforcement",
+	Example: `
+# Show usage for the last 30 days
+prepf usage
+
+# Show usage for the last week as JSON
+prepf usage --days 7 --json
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if days <= 0 {
+			return fmt.Errorf("--days must be positive")
+		}
+
+		_, conn, err := connectDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		since := usage.StartOfDay(time.Now()).AddDate(0, 0, -(days - 1))
+		report, err := usage.NewService(db.New(conn)).Report(cmd.Context(), since)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			data, err := json.Marshal(report)
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(data))
+			return nil
+		}
+
+		if len(report.ByDay) == 0 {
+			cmd.Println("No usage recorded yet.")
+			return nil
+		}
+
+		sections := []struct {
+			title string
+			rows  []usage.Row
+		}{
+			{"Day", report.ByDay},
+			{"Mode", report.ByMode},
+			{"Model", report.ByModel},
+		}
+
+		if term.IsTerminal(os.Stdout.Fd()) {
+			// We're in a TTY: make it fancy.
+			for _, section := range sections {
+				t := table.New().
+					Border(lipgloss.RoundedBorder()).
+					StyleFunc(func(row, col int) lipgloss.Style {
+						return lipgloss.NewStyle().Padding(0, 2)
+					}).
+					Headers(section.title, "Prompt Tokens", "Completion Tokens", "Cost")
+				for _, r := range section.rows {
+					t.Row(orDash(r.Key), fmt.Sprint(r.PromptTokens), fmt.Sprint(r.CompletionTokens), fmt.Sprintf("$%.4f", r.Cost))
+				}
+				lipgloss.Println(t)
+			}
+			return nil
+		}
+
+		// Not a TTY: plain output
+		for _, section := range sections {
+			for _, r := range section.rows {
+				cmd.Printf("%s\t%s\t%d\t%d\t%.4f\n", section.title, orDash(r.Key), r.PromptTokens, r.CompletionTokens, r.Cost)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	usageCmd.Flags().Int("days", 30, "Number of days to include")
+	usageCmd.Flags().Bool("json", false, "Output as JSON")
+}
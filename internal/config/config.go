@@ -97,7 +97,7 @@ type ProviderConfig struct {
 	// The provider's API endpoint.
 	BaseURL string `json:"base_url,omitempty" jsonschema:"description=Base URL for the provider's API,format=uri,example=https://api.openai.com/v1"`
 	// The provider type, e.g. "openai", "anthropic", etc. if empty it defaults to openai.
-	Type catwalk.Type `json:"type,omitempty" jsonschema:"description=Provider type that determines the API format,enum=openai,enum=openai-compat,enum=anthropic,enum=gemini,enum=azure,enum=vertexai,default=openai"`
+	Type catwalk.Type `json:"type,omitempty" jsonschema:"description=Provider type that determines the API format,enum=openai,enum=openai-compat,enum=anthropic,enum=gemini,enum=azure,enum=vertexai,enum=ollama,enum=llama.cpp,default=openai"`
 	// The provider's API key.
 	APIKey string `json:"api_key,omitempty" jsonschema:"description=API key for authentication with the provider,example=$OPENAI_API_KEY"`
 	// The original API key template before resolution (for re-resolution on auth errors).
@@ -194,8 +194,7 @@ type LSPConfig struct {
 type TUIOptions struct {
 	CompactMode bool   `json:"compact_mode,omitempty" jsonschema:"description=Enable compact mode for the TUI interface,default=false"`
 	DiffMode    string `json:"diff_mode,omitempty" jsonschema:"description=Diff mode for the TUI interface,enum=unified,enum=split"`
-	// Here we can add themes later or any TUI related options
-	//
+	Theme       string `json:"theme,omitempty" jsonschema:"description=Name of the TUI theme: a built-in one or a JSON file in the themes directory,default=charmtone,example=light"`
 
 	Completions Completions `json:"completions,omitzero" jsonschema:"description=Completions UI options"`
 }
@@ -239,18 +238,133 @@ func (Attribution) JSONSchemaExtend(schema *jsonschema.Schema) {
 }
 
 type Options struct {
-	ContextPaths              []string     `json:"context_paths,omitempty" jsonschema:"description=Paths to files containing context information for the AI,example=.cursorrules,example=PREPF.md"`
-	SkillsPaths               []string     `json:"skills_paths,omitempty" jsonschema:"description=Paths to directories containing Agent Skills (folders with SKILL.md files),example=~/.config/prepf/skills,example=./skills"`
-	TUI                       *TUIOptions  `json:"tui,omitempty" jsonschema:"description=Terminal user interface options"`
-	Debug                     bool         `json:"debug,omitempty" jsonschema:"description=Enable debug logging,default=false"`
-	DebugLSP                  bool         `json:"debug_lsp,omitempty" jsonschema:"description=Enable debug logging for LSP servers,default=false"`
-	DisableAutoSummarize      bool         `json:"disable_auto_summarize,omitempty" jsonschema:"description=Disable automatic conversation summarization,default=false"`
-	DataDirectory             string       `json:"data_directory,omitempty" jsonschema:"description=Directory for storing application data (relative to working directory),default=.prepf,example=.prepf"` // Relative to the cwd
-	DisabledTools             []string     `json:"disabled_tools,omitempty" jsonschema:"description=List of built-in tools to disable and hide from the agent,example=bash,example=sourcegraph"`
-	DisableProviderAutoUpdate bool         `json:"disable_provider_auto_update,omitempty" jsonschema:"description=Disable providers auto-update,default=false"`
-	Attribution               *Attribution `json:"attribution,omitempty" jsonschema:"description=Attribution settings for generated content"`
-	DisableMetrics            bool         `json:"disable_metrics,omitempty" jsonschema:"description=Disable sending metrics,default=false"`
-	InitializeAs              string       `json:"initialize_as,omitempty" jsonschema:"description=Name of the context file to create/update during project initialization,default=AGENTS.md,example=AGENTS.md,example=PREPF.md,example=CLAUDE.md,example=docs/LLMs.md"`
+	ContextPaths              []string          `json:"context_paths,omitempty" jsonschema:"description=Paths to files containing context information for the AI,example=.cursorrules,example=PREPF.md"`
+	SkillsPaths               []string          `json:"skills_paths,omitempty" jsonschema:"description=Paths to directories containing Agent Skills (folders with SKILL.md files) and interview packs (folders with pack.yaml files),example=~/.config/prepf/skills,example=./skills"`
+	TUI                       *TUIOptions       `json:"tui,omitempty" jsonschema:"description=Terminal user interface options"`
+	Debug                     bool              `json:"debug,omitempty" jsonschema:"description=Enable debug logging,default=false"`
+	DebugLSP                  bool              `json:"debug_lsp,omitempty" jsonschema:"description=Enable debug logging for LSP servers,default=false"`
+	DisableAutoSummarize      bool              `json:"disable_auto_summarize,omitempty" jsonschema:"description=Disable automatic conversation summarization,default=false"`
+	DataDirectory             string            `json:"data_directory,omitempty" jsonschema:"description=Directory for storing application data (relative to working directory),default=.prepf,example=.prepf"` // Relative to the cwd
+	DisabledTools             []string          `json:"disabled_tools,omitempty" jsonschema:"description=List of built-in tools to disable and hide from the agent,example=bash,example=sourcegraph"`
+	DisableProviderAutoUpdate bool              `json:"disable_provider_auto_update,omitempty" jsonschema:"description=Disable providers auto-update,default=false"`
+	DisableLocalDiscovery     bool              `json:"disable_local_discovery,omitempty" jsonschema:"description=Disable automatic discovery of models served by local Ollama and llama.cpp servers,default=false"`
+	Attribution               *Attribution      `json:"attribution,omitempty" jsonschema:"description=Attribution settings for generated content"`
+	DisableMetrics            bool              `json:"disable_metrics,omitempty" jsonschema:"description=Disable sending metrics,default=false"`
+	InitializeAs              string            `json:"initialize_as,omitempty" jsonschema:"description=Name of the context file to create/update during project initialization,default=AGENTS.md,example=AGENTS.md,example=PREPF.md,example=CLAUDE.md,example=docs/LLMs.md"`
+	Budget                    *Budget           `json:"budget,omitempty" jsonschema:"description=Local spending limits enforced before each prompt"`
+	Replay                    *Replay           `json:"replay,omitempty" jsonschema:"description=Record or replay provider HTTP traffic for offline testing"`
+	Daily                     *Daily            `json:"daily,omitempty" jsonschema:"description=Daily challenge settings"`
+	Author                    string            `json:"author,omitempty" jsonschema:"description=Name transcript annotations and reviews are signed with; defaults to the system user name,example=Alice"`
+	Rubrics                   map[string]string `json:"rubrics,omitempty" jsonschema:"description=Rubric the answers of each mode are graded against; a mode uses the rubric named after it otherwise"`
+	Judging                   *Judging          `json:"judging,omitempty" jsonschema:"description=Grade each answer with several independent grader calls instead of the interviewer's single grade"`
+	Plan                      *Plan             `json:"plan,omitempty" jsonschema:"description=Time available for the practice plan leading up to interviews"`
+}
+
+// Plan configures the time the practice plan may fill each day.
+type Plan struct {
+	Minutes        int `json:"minutes,omitempty" jsonschema:"description=Minutes available for practice on weekdays,minimum=0,default=60,example=45"`
+	WeekendMinutes int `json:"weekend_minutes,omitempty" jsonschema:"description=Minutes available for practice on Saturdays and Sundays; same as on weekdays when unset,minimum=0,example=120"`
+}
+
+const defaultPlanMinutes = 60
+
+// MinutesOn returns the minutes available for practice on the given
+// weekday.
+func (p *Plan) MinutesOn(day time.Weekday) int {
+	var opts Plan
+	if p != nil {
+		opts = *p
+	}
+	minutes := cmp.Or(opts.Minutes, defaultPlanMinutes)
+	if day == time.Saturday || day == time.Sunday {
+		return cmp.Or(opts.WeekendMinutes, minutes)
+	}
+	return minutes
+}
+
+// Judging configures grading answers with several independent grader calls
+// whose grades are aggregated.
+type Judging struct {
+	Samples      int             `json:"samples,omitempty" jsonschema:"description=Independent grader calls per answer; judging is off below 2,minimum=0,example=3"`
+	Models       []SelectedModel `json:"models,omitempty" jsonschema:"description=Models the grader calls take turns on; the large model when empty"`
+	Concurrency  int             `json:"concurrency,omitempty" jsonschema:"description=Grader calls run at the same time,minimum=1,default=3"`
+	Tolerance    *int            `json:"tolerance,omitempty" jsonschema:"description=Points a grade may be off the median and still agree with it,minimum=0,maximum=100,default=10"`
+	MinAgreement *float64        `json:"min_agreement,omitempty" jsonschema:"description=Share of grades that must agree with the median; grades below it are flagged,minimum=0,maximum=1,default=0.6"`
+}
+
+const (
+	defaultJudgingConcurrency  = 3
+	defaultJudgingTolerance    = 10
+	defaultJudgingMinAgreement = 0.6
+)
+
+// Enabled reports whether answers are judged by several grader calls.
+func (j *Judging) Enabled() bool {
+	return j != nil && j.Samples >= 2
+}
+
+// Limit returns how many grader calls run at the same time.
+func (j Judging) Limit() int {
+	if j.Concurrency < 1 {
+		return defaultJudgingConcurrency
+	}
+	return j.Concurrency
+}
+
+// AgreementTolerance returns how many points a grade may be off the median
+// and still agree with it.
+func (j Judging) AgreementTolerance() int {
+	return ptrValOr(j.Tolerance, defaultJudgingTolerance)
+}
+
+// AgreementThreshold returns the share of grades that must agree with the
+// median for the judged grade not to be flagged.
+func (j Judging) AgreementThreshold() float64 {
+	return ptrValOr(j.MinAgreement, defaultJudgingMinAgreement)
+}
+
+// Daily configures the question of the day.
+type Daily struct {
+	Topics  []string `json:"topics,omitempty" jsonschema:"description=Topics the daily challenge is picked from; any topic when empty,example=concurrency,example=system design"`
+	Freezes int      `json:"freezes,omitempty" jsonschema:"description=Days per month that can be missed without breaking the streak,minimum=0,default=0,example=2"`
+}
+
+// Replay configures recording provider traffic to a cassette file and
+// replaying it instead of calling the provider.
+type Replay struct {
+	Mode     string `json:"mode,omitempty" jsonschema:"description=Whether to record provider traffic or replay a previous recording,enum=record,enum=replay"`
+	Cassette string `json:"cassette,omitempty" jsonschema:"description=Path to the cassette file (defaults to replay/cassette.json in the data directory),example=testdata/interview.json"`
+}
+
+type BudgetAction string
+
+const (
+	BudgetActionBlock     BudgetAction = "block"
+	BudgetActionDowngrade BudgetAction = "downgrade"
+)
+
+// Budget defines spending limits in USD. A zero limit means unlimited.
+type Budget struct {
+	Daily    float64      `json:"daily,omitempty" jsonschema:"description=Maximum spend in USD per calendar day,minimum=0,example=2.5"`
+	Monthly  float64      `json:"monthly,omitempty" jsonschema:"description=Maximum spend in USD per calendar month,minimum=0,example=30"`
+	Session  float64      `json:"session,omitempty" jsonschema:"description=Maximum spend in USD per session,minimum=0,example=0.5"`
+	WarnAt   *float64     `json:"warn_at,omitempty" jsonschema:"description=Fraction of a limit at which to warn,minimum=0,maximum=1,default=0.8,example=0.9"`
+	OnExceed BudgetAction `json:"on_exceed,omitempty" jsonschema:"description=What to do once a limit is exceeded,enum=block,enum=downgrade,default=block"`
+}
+
+const defaultBudgetWarnAt = 0.8
+
+// WarnThreshold returns the fraction of a limit at which a warning is issued.
+func (b Budget) WarnThreshold() float64 {
+	return ptrValOr(b.WarnAt, defaultBudgetWarnAt)
+}
+
+// Action returns the configured action, defaulting to blocking.
+func (b Budget) Action() BudgetAction {
+	if b.OnExceed == BudgetActionDowngrade {
+		return BudgetActionDowngrade
+	}
+	return BudgetActionBlock
 }
 
 type MCPs map[string]MCPConfig
@@ -457,6 +571,14 @@ func (c *Config) SetCompactMode(enabled bool) error {
 	return c.SetConfigField("options.tui.compact_mode", enabled)
 }
 
+func (c *Config) SetTheme(name string) error {
+	if c.Options == nil {
+		c.Options = &Options{}
+	}
+	c.Options.TUI.Theme = name
+	return c.SetConfigField("options.tui.theme", name)
+}
+
 func (c *Config) Resolve(key string) (string, error) {
 	if c.resolver == nil {
 		return "", fmt.Errorf("no variable resolver configured")
@@ -685,16 +807,22 @@ func allToolNames() []string {
 		"job_output",
 		"job_kill",
 		"download",
+		"drill_question",
+		"drill_grade",
 		"edit",
 		"multiedit",
 		"lsp_diagnostics",
 		"lsp_references",
 		"fetch",
+		"flashcards",
 		"agentic_fetch",
 		"glob",
 		"grep",
 		"ls",
+		"quiz",
+		"review_exercise",
 		"sourcegraph",
+		"sql_exercise",
 		"todos",
 		"view",
 		"write",
@@ -22,8 +22,10 @@ type ProjectInitFlag struct {
 // TODO: we need to remove the global config instance keeping it now just until everything is migrated
 var instance atomic.Pointer[Config]
 
+// Init loads the configuration for a run of the agent, discovering local
+// models as well, and makes it the global instance.
 func Init(workingDir, dataDir string, debug bool) (*Config, error) {
-	cfg, err := Load(workingDir, dataDir, debug)
+	cfg, err := load(workingDir, dataDir, debug, true)
 	if err != nil {
 		return nil, err
 	}
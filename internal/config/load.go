@@ -8,6 +8,7 @@ import (
 	"io"
 	"log/slog"
 	"maps"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/charmbracelet/catwalk/pkg/catwalk"
@@ -46,6 +48,13 @@ func LoadReader(fd io.Reader) (*Config, error) {
 
 // Load loads the configuration from the default paths.
 func Load(workingDir, dataDir string, debug bool) (*Config, error) {
+	return load(workingDir, dataDir, debug, false)
+}
+
+// load loads the configuration from the default paths. With discoverLocal
+// set it also probes for local inference servers, which takes up to
+// localProbeTimeout, so only the paths that talk to models ask for it.
+func load(workingDir, dataDir string, debug, discoverLocal bool) (*Config, error) {
 	configPaths := lookupConfigs(workingDir)
 
 	cfg, err := loadFromConfigPaths(configPaths)
@@ -91,13 +100,18 @@ func Load(workingDir, dataDir string, debug bool) (*Config, error) {
 	if err := cfg.configureProviders(env, valueResolver, cfg.knownProviders); err != nil {
 		return nil, fmt.Errorf("failed to configure providers: %w", err)
 	}
+	if discoverLocal && !cfg.Options.DisableLocalDiscovery {
+		cfg.discoverLocalProviders(env.Get("OLLAMA_HOST"))
+	}
 
 	if !cfg.IsConfigured() {
 		slog.Warn("No providers configured")
 		return cfg, nil
 	}
 
-	if err := cfg.configureSelectedModels(cfg.knownProviders); err != nil {
+	// Without local discovery a model picked from a local server is unknown,
+	// so the fallback for it is only used, not saved over the user's choice.
+	if err := cfg.configureSelectedModels(cfg.knownProviders, discoverLocal); err != nil {
 		return nil, fmt.Errorf("failed to configure selected models: %w", err)
 	}
 	cfg.SetupAgents()
@@ -279,6 +293,17 @@ func (c *Config) configureProviders(env env.Env, resolver VariableResolver, know
 		if providerConfig.Type == "" {
 			providerConfig.Type = catwalk.TypeOpenAICompat
 		}
+		if IsLocalProviderType(providerConfig.Type) && !providerConfig.Disable {
+			ctx, cancel := context.WithTimeout(context.Background(), localDiscoveryTimeout)
+			local, err := configureLocalProvider(ctx, http.DefaultClient, providerConfig, resolver)
+			cancel()
+			if err != nil {
+				slog.Warn("Skipping local provider due to failed model discovery", "provider", id, "error", err)
+				c.Providers.Del(id)
+				continue
+			}
+			providerConfig = local
+		}
 		if !slices.Contains(catwalk.KnownProviderTypes(), providerConfig.Type) && providerConfig.Type != hyper.Name {
 			slog.Warn("Skipping custom provider due to unsupported provider type", "provider", id)
 			c.Providers.Del(id)
@@ -377,6 +402,22 @@ func (c *Config) setDefaults(workingDir, dataDir string) {
 		c.Options.DisableProviderAutoUpdate, _ = strconv.ParseBool(str)
 	}
 
+	if mode, ok := os.LookupEnv("CRUSH_REPLAY_MODE"); ok {
+		if c.Options.Replay == nil {
+			c.Options.Replay = &Replay{}
+		}
+		c.Options.Replay.Mode = mode
+	}
+	if cassette, ok := os.LookupEnv("CRUSH_REPLAY_CASSETTE"); ok {
+		if c.Options.Replay == nil {
+			c.Options.Replay = &Replay{}
+		}
+		c.Options.Replay.Cassette = cassette
+	}
+	if c.Options.Replay != nil && c.Options.Replay.Cassette == "" {
+		c.Options.Replay.Cassette = filepath.Join(c.Options.DataDirectory, "replay", "cassette.json")
+	}
+
 	if c.Options.Attribution == nil {
 		c.Options.Attribution = &Attribution{
 			TrailerStyle:  TrailerStyleAssistedBy,
@@ -510,7 +551,10 @@ func (c *Config) defaultModelSelection(knownProviders []catwalk.Provider) (large
 	return largeModel, smallModel, err
 }
 
-func (c *Config) configureSelectedModels(knownProviders []catwalk.Provider) error {
+// configureSelectedModels fills in the selected models from the defaults.
+// A selected model that isn't available falls back to the default, which
+// replaces the selection in the user's config only when persist is set.
+func (c *Config) configureSelectedModels(knownProviders []catwalk.Provider, persist bool) error {
 	defaultLarge, defaultSmall, err := c.defaultModelSelection(knownProviders)
 	if err != nil {
 		return fmt.Errorf("failed to select default models: %w", err)
@@ -528,10 +572,12 @@ func (c *Config) configureSelectedModels(knownProviders []catwalk.Provider) erro
 		model := c.GetModel(large.Provider, large.Model)
 		if model == nil {
 			large = defaultLarge
-			// override the model type to large
-			err := c.UpdatePreferredModel(SelectedModelTypeLarge, large)
-			if err != nil {
-				return fmt.Errorf("failed to update preferred large model: %w", err)
+			if persist {
+				// override the model type to large
+				err := c.UpdatePreferredModel(SelectedModelTypeLarge, large)
+				if err != nil {
+					return fmt.Errorf("failed to update preferred large model: %w", err)
+				}
 			}
 		} else {
 			if largeModelSelected.MaxTokens > 0 {
@@ -572,10 +618,12 @@ func (c *Config) configureSelectedModels(knownProviders []catwalk.Provider) erro
 		model := c.GetModel(small.Provider, small.Model)
 		if model == nil {
 			small = defaultSmall
-			// override the model type to small
-			err := c.UpdatePreferredModel(SelectedModelTypeSmall, small)
-			if err != nil {
-				return fmt.Errorf("failed to update preferred small model: %w", err)
+			if persist {
+				// override the model type to small
+				err := c.UpdatePreferredModel(SelectedModelTypeSmall, small)
+				if err != nil {
+					return fmt.Errorf("failed to update preferred small model: %w", err)
+				}
 			}
 		} else {
 			if smallModelSelected.MaxTokens > 0 {
@@ -631,6 +679,61 @@ func lookupConfigs(cwd string) []string {
 	return append(configPaths, foundConfigs...)
 }
 
+// ConfigPaths returns the config files merged for the working directory,
+// lowest priority first. They don't all have to exist.
+func (c *Config) ConfigPaths() []string {
+	return lookupConfigs(c.workingDir)
+}
+
+// promptPathsMu guards the context and skills paths of loaded configs, which
+// ReloadPromptPaths replaces while the app runs. It is shared by all configs
+// so that copying a Config stays cheap and lock free.
+var promptPathsMu sync.RWMutex
+
+// ReloadPromptPaths re-reads the config files and applies changes to the
+// context and skills paths, which only affect the system prompt. Any other
+// change still requires a restart.
+func (c *Config) ReloadPromptPaths() error {
+	fresh, err := loadFromConfigPaths(c.ConfigPaths())
+	if err != nil {
+		return err
+	}
+	fresh.setDefaults(c.workingDir, c.Options.DataDirectory)
+
+	promptPathsMu.Lock()
+	defer promptPathsMu.Unlock()
+	c.Options.ContextPaths = fresh.Options.ContextPaths
+	c.Options.SkillsPaths = fresh.Options.SkillsPaths
+	return nil
+}
+
+// ContextPaths returns the configured context paths. Use it rather than
+// Options.ContextPaths once the config is shared, as it may be reloaded.
+func (c *Config) ContextPaths() []string {
+	promptPathsMu.RLock()
+	defer promptPathsMu.RUnlock()
+	return c.Options.ContextPaths
+}
+
+// SkillsPaths returns the configured skills paths. Use it rather than
+// Options.SkillsPaths once the config is shared, as it may be reloaded.
+func (c *Config) SkillsPaths() []string {
+	promptPathsMu.RLock()
+	defer promptPathsMu.RUnlock()
+	return c.Options.SkillsPaths
+}
+
+// ExpandPath expands ~ and environment variables in a configured path.
+func (c *Config) ExpandPath(path string) string {
+	path = home.Long(path)
+	if strings.HasPrefix(path, "$") {
+		if expanded, err := c.Resolver().ResolveValue(path); err == nil {
+			path = expanded
+		}
+	}
+	return path
+}
+
 func loadFromConfigPaths(configPaths []string) (*Config, error) {
 	var configs []io.Reader
 
@@ -709,6 +812,11 @@ func GlobalConfig() string {
 	return filepath.Join(home.Dir(), ".config", appName, fmt.Sprintf("%s.json", appName))
 }
 
+// GlobalThemesDir returns the directory TUI themes are loaded from.
+func GlobalThemesDir() string {
+	return filepath.Join(filepath.Dir(GlobalConfig()), "themes")
+}
+
 // GlobalConfigData returns the path to the main data directory for the application.
 // this config is used when the app overrides configurations instead of updating the global config.
 func GlobalConfigData() string {
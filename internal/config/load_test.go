@@ -57,6 +57,35 @@ func TestConfig_setDefaults(t *testing.T) {
 	require.Equal(t, "/tmp", cfg.workingDir)
 }
 
+func TestConfig_ReloadPromptPaths(t *testing.T) {
+	t.Setenv("CRUSH_GLOBAL_CONFIG", t.TempDir())
+	t.Setenv("CRUSH_GLOBAL_DATA", t.TempDir())
+	dir := t.TempDir()
+
+	cfg := &Config{}
+	cfg.setDefaults(dir, "")
+	require.NotContains(t, cfg.Options.ContextPaths, "persona.md")
+
+	data := `{"options": {"context_paths": ["persona.md"], "skills_paths": ["./skills"]}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "prepf.json"), []byte(data), 0o644))
+	require.Contains(t, cfg.ConfigPaths(), filepath.Join(dir, "prepf.json"))
+
+	// Readers may look at the paths while they are reloaded.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			_ = cfg.ContextPaths()
+			_ = cfg.SkillsPaths()
+		}
+	}()
+	require.NoError(t, cfg.ReloadPromptPaths())
+	<-done
+	require.Contains(t, cfg.ContextPaths(), "persona.md")
+	require.Contains(t, cfg.SkillsPaths(), "./skills")
+	require.Equal(t, filepath.Join(dir, ".prepf"), cfg.Options.DataDirectory)
+}
+
 func TestConfig_configureProviders(t *testing.T) {
 	knownProviders := []catwalk.Provider{
 		{
@@ -487,7 +516,7 @@ func TestConfig_setupAgentsWithDisabledTools(t *testing.T) {
 	coderAgent, ok := cfg.Agents[AgentCoder]
 	require.True(t, ok)
 
-	assert.Equal(t, []string{"agent", "bash", "job_output", "job_kill", "multiedit", "lsp_diagnostics", "lsp_references", "fetch", "agentic_fetch", "glob", "ls", "sourcegraph", "todos", "view", "write"}, coderAgent.AllowedTools)
+	assert.Equal(t, []string{"agent", "bash", "job_output", "job_kill", "drill_question", "drill_grade", "multiedit", "lsp_diagnostics", "lsp_references", "fetch", "flashcards", "agentic_fetch", "glob", "ls", "quiz", "review_exercise", "sourcegraph", "sql_exercise", "todos", "view", "write"}, coderAgent.AllowedTools)
 
 	taskAgent, ok := cfg.Agents[AgentTask]
 	require.True(t, ok)
@@ -510,7 +539,7 @@ func TestConfig_setupAgentsWithEveryReadOnlyToolDisabled(t *testing.T) {
 	cfg.SetupAgents()
 	coderAgent, ok := cfg.Agents[AgentCoder]
 	require.True(t, ok)
-	assert.Equal(t, []string{"agent", "bash", "job_output", "job_kill", "download", "edit", "multiedit", "lsp_diagnostics", "lsp_references", "fetch", "agentic_fetch", "todos", "write"}, coderAgent.AllowedTools)
+	assert.Equal(t, []string{"agent", "bash", "job_output", "job_kill", "download", "drill_question", "drill_grade", "edit", "multiedit", "lsp_diagnostics", "lsp_references", "fetch", "flashcards", "agentic_fetch", "quiz", "review_exercise", "sql_exercise", "todos", "write"}, coderAgent.AllowedTools)
 
 	taskAgent, ok := cfg.Agents[AgentTask]
 	require.True(t, ok)
@@ -1133,7 +1162,7 @@ func TestConfig_configureSelectedModels(t *testing.T) {
 		err := cfg.configureProviders(env, resolver, knownProviders)
 		require.NoError(t, err)
 
-		err = cfg.configureSelectedModels(knownProviders)
+		err = cfg.configureSelectedModels(knownProviders, true)
 		require.NoError(t, err)
 		large := cfg.Models[SelectedModelTypeLarge]
 		small := cfg.Models[SelectedModelTypeSmall]
@@ -1195,7 +1224,7 @@ func TestConfig_configureSelectedModels(t *testing.T) {
 		err := cfg.configureProviders(env, resolver, knownProviders)
 		require.NoError(t, err)
 
-		err = cfg.configureSelectedModels(knownProviders)
+		err = cfg.configureSelectedModels(knownProviders, true)
 		require.NoError(t, err)
 		large := cfg.Models[SelectedModelTypeLarge]
 		small := cfg.Models[SelectedModelTypeSmall]
@@ -1240,11 +1269,52 @@ func TestConfig_configureSelectedModels(t *testing.T) {
 		err := cfg.configureProviders(env, resolver, knownProviders)
 		require.NoError(t, err)
 
-		err = cfg.configureSelectedModels(knownProviders)
+		err = cfg.configureSelectedModels(knownProviders, true)
 		require.NoError(t, err)
 		large := cfg.Models[SelectedModelTypeLarge]
 		require.Equal(t, "large-model", large.Model)
 		require.Equal(t, "openai", large.Provider)
 		require.Equal(t, int64(100), large.MaxTokens)
 	})
+
+	t.Run("should not save the fallback for an unknown model unless asked", func(t *testing.T) {
+		knownProviders := []catwalk.Provider{
+			{
+				ID:                  "openai",
+				APIKey:              "abc",
+				DefaultLargeModelID: "large-model",
+				DefaultSmallModelID: "small-model",
+				Models: []catwalk.Model{
+					{
+						ID:               "large-model",
+						DefaultMaxTokens: 1000,
+					},
+					{
+						ID:               "small-model",
+						DefaultMaxTokens: 500,
+					},
+				},
+			},
+		}
+
+		cfg := &Config{
+			Models: map[SelectedModelType]SelectedModel{
+				"large": {
+					Model:    "llama3",
+					Provider: "ollama",
+				},
+			},
+		}
+		cfg.setDefaults("/tmp", "")
+		cfg.dataConfigDir = filepath.Join(t.TempDir(), "prepf.json")
+		env := env.NewFromMap(map[string]string{})
+		resolver := NewEnvironmentVariableResolver(env)
+		err := cfg.configureProviders(env, resolver, knownProviders)
+		require.NoError(t, err)
+
+		err = cfg.configureSelectedModels(knownProviders, false)
+		require.NoError(t, err)
+		require.Equal(t, "large-model", cfg.Models[SelectedModelTypeLarge].Model)
+		require.NoFileExists(t, cfg.dataConfigDir)
+	})
 }
@@ -0,0 +1,289 @@
+package config
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+)
+
+// Provider types for local inference servers. Both speak the OpenAI chat
+// completions API, so once their models are discovered they are configured
+// as openai-compat providers.
+const (
+	ProviderTypeOllama   catwalk.Type = "ollama"
+	ProviderTypeLlamaCpp catwalk.Type = "llama.cpp"
+)
+
+const (
+	defaultOllamaURL   = "http://localhost:11434"
+	defaultLlamaCppURL = "http://localhost:8080"
+
+	// localProbeTimeout bounds auto-discovery so startup isn't delayed when
+	// no local server is running.
+	localProbeTimeout = 500 * time.Millisecond
+	// localDiscoveryTimeout bounds discovery for explicitly configured local
+	// providers.
+	localDiscoveryTimeout = 5 * time.Second
+
+	defaultLocalContextWindow = 8192
+	maxLocalOutputTokens      = 8192
+)
+
+// IsLocalProviderType reports whether t is a local inference server type.
+func IsLocalProviderType(t catwalk.Type) bool {
+	return t == ProviderTypeOllama || t == ProviderTypeLlamaCpp
+}
+
+type localServer struct {
+	id      string
+	name    string
+	typ     catwalk.Type
+	baseURL string
+}
+
+func defaultLocalServers(ollamaHost string) []localServer {
+	return []localServer{
+		{
+			id:      "ollama",
+			name:    "Ollama (local)",
+			typ:     ProviderTypeOllama,
+			baseURL: cmp.Or(normalizeLocalURL(ollamaHost), defaultOllamaURL),
+		},
+		{
+			id:      "llamacpp",
+			name:    "llama.cpp (local)",
+			typ:     ProviderTypeLlamaCpp,
+			baseURL: defaultLlamaCppURL,
+		},
+	}
+}
+
+// normalizeLocalURL accepts host:port values such as the ones used in
+// OLLAMA_HOST and turns them into a base URL.
+func normalizeLocalURL(host string) string {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return ""
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return strings.TrimSuffix(host, "/")
+}
+
+// localServerRoot strips the OpenAI API suffix from a base URL so the native
+// listing endpoints can be reached.
+func localServerRoot(baseURL string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/v1")
+}
+
+// configureLocalProvider fills in the defaults for an Ollama or llama.cpp
+// provider, discovers its models if none are configured and converts it to
+// an openai-compat provider.
+func configureLocalProvider(ctx context.Context, client *http.Client, pc ProviderConfig, resolver VariableResolver) (ProviderConfig, error) {
+	baseURL := pc.BaseURL
+	if baseURL != "" {
+		resolved, err := resolver.ResolveValue(baseURL)
+		if err != nil {
+			return pc, fmt.Errorf("failed to resolve base URL: %w", err)
+		}
+		baseURL = resolved
+	}
+	if baseURL == "" {
+		baseURL = defaultLlamaCppURL
+		if pc.Type == ProviderTypeOllama {
+			baseURL = defaultOllamaURL
+		}
+	}
+	root := localServerRoot(baseURL)
+
+	if len(pc.Models) == 0 {
+		models, err := discoverLocalModels(ctx, client, pc.Type, root)
+		if err != nil {
+			return pc, err
+		}
+		pc.Models = models
+	}
+
+	pc.Type = catwalk.TypeOpenAICompat
+	pc.BaseURL = root + "/v1"
+	return pc, nil
+}
+
+// discoverLocalProviders probes the default Ollama and llama.cpp endpoints
+// and registers a provider for each server that responds with at least one
+// model. Providers that are already configured are left alone.
+func (c *Config) discoverLocalProviders(ollamaHost string) {
+	ctx, cancel := context.WithTimeout(context.Background(), localProbeTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, server := range defaultLocalServers(ollamaHost) {
+		if _, ok := c.Providers.Get(server.id); ok {
+			continue
+		}
+		wg.Go(func() {
+			models, err := discoverLocalModels(ctx, http.DefaultClient, server.typ, server.baseURL)
+			if err != nil || len(models) == 0 {
+				slog.Debug("No local models discovered", "provider", server.id, "error", err)
+				return
+			}
+			slog.Info("Discovered local models", "provider", server.id, "count", len(models))
+			c.Providers.Set(server.id, ProviderConfig{
+				ID:      server.id,
+				Name:    server.name,
+				Type:    catwalk.TypeOpenAICompat,
+				BaseURL: server.baseURL + "/v1",
+				Models:  models,
+			})
+		})
+	}
+	wg.Wait()
+}
+
+// discoverLocalModels lists the models served by a local inference server
+// and synthesizes catwalk model entries for them. Local models are free, so
+// all costs are zero.
+func discoverLocalModels(ctx context.Context, client *http.Client, typ catwalk.Type, root string) ([]catwalk.Model, error) {
+	switch typ {
+	case ProviderTypeOllama:
+		return discoverOllamaModels(ctx, client, root)
+	case ProviderTypeLlamaCpp:
+		return discoverLlamaCppModels(ctx, client, root)
+	default:
+		return nil, fmt.Errorf("unsupported local provider type: %q", typ)
+	}
+}
+
+type ollamaTagsResponse struct {
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+type ollamaShowResponse struct {
+	Capabilities []string       `json:"capabilities"`
+	ModelInfo    map[string]any `json:"model_info"`
+}
+
+func discoverOllamaModels(ctx context.Context, client *http.Client, root string) ([]catwalk.Model, error) {
+	var tags ollamaTagsResponse
+	if err := localRequest(ctx, client, http.MethodGet, root+"/api/tags", nil, &tags); err != nil {
+		return nil, err
+	}
+
+	models := make([]catwalk.Model, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		var show ollamaShowResponse
+		body := map[string]string{"model": m.Name}
+		if err := localRequest(ctx, client, http.MethodPost, root+"/api/show", body, &show); err != nil {
+			slog.Debug("Failed to get Ollama model details", "model", m.Name, "error", err)
+		}
+
+		var contextWindow int64
+		for key, value := range show.ModelInfo {
+			if n, ok := value.(float64); ok && strings.HasSuffix(key, ".context_length") {
+				contextWindow = int64(n)
+				break
+			}
+		}
+		models = append(models, localModel(
+			m.Name,
+			contextWindow,
+			slices.Contains(show.Capabilities, "vision"),
+			slices.Contains(show.Capabilities, "thinking"),
+		))
+	}
+	return models, nil
+}
+
+type llamaCppModelsResponse struct {
+	Data []struct {
+		ID   string `json:"id"`
+		Meta struct {
+			NCtxTrain int64 `json:"n_ctx_train"`
+		} `json:"meta"`
+	} `json:"data"`
+}
+
+type llamaCppPropsResponse struct {
+	DefaultGenerationSettings struct {
+		NCtx int64 `json:"n_ctx"`
+	} `json:"default_generation_settings"`
+	Modalities struct {
+		Vision bool `json:"vision"`
+	} `json:"modalities"`
+}
+
+func discoverLlamaCppModels(ctx context.Context, client *http.Client, root string) ([]catwalk.Model, error) {
+	var list llamaCppModelsResponse
+	if err := localRequest(ctx, client, http.MethodGet, root+"/v1/models", nil, &list); err != nil {
+		return nil, err
+	}
+
+	// The server's props describe the loaded context size, which may be
+	// smaller than what the model was trained with.
+	var props llamaCppPropsResponse
+	if err := localRequest(ctx, client, http.MethodGet, root+"/props", nil, &props); err != nil {
+		slog.Debug("Failed to get llama.cpp server properties", "error", err)
+	}
+
+	models := make([]catwalk.Model, 0, len(list.Data))
+	for _, m := range list.Data {
+		contextWindow := cmp.Or(props.DefaultGenerationSettings.NCtx, m.Meta.NCtxTrain)
+		models = append(models, localModel(m.ID, contextWindow, props.Modalities.Vision, false))
+	}
+	return models, nil
+}
+
+func localModel(id string, contextWindow int64, supportsImages, canReason bool) catwalk.Model {
+	if contextWindow <= 0 {
+		contextWindow = defaultLocalContextWindow
+	}
+	return catwalk.Model{
+		ID:               id,
+		Name:             id,
+		ContextWindow:    contextWindow,
+		DefaultMaxTokens: min(contextWindow/4, maxLocalOutputTokens),
+		SupportsImages:   supportsImages,
+		CanReason:        canReason,
+	}
+}
+
+func localRequest(ctx context.Context, client *http.Client, method, url string, body, out any) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: unexpected status %d", method, url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/csync"
+	"github.com/trankhanh040147/prepf/internal/env"
+)
+
+func newOllamaServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/tags", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"models": []map[string]any{
+				{"name": "llama3.2:latest"},
+				{"name": "llava:7b"},
+			},
+		})
+	})
+	mux.HandleFunc("POST /api/show", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Model string `json:"model"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		switch body.Model {
+		case "llama3.2:latest":
+			json.NewEncoder(w).Encode(map[string]any{
+				"capabilities": []string{"completion", "tools", "thinking"},
+				"model_info":   map[string]any{"llama.context_length": 131072},
+			})
+		default:
+			json.NewEncoder(w).Encode(map[string]any{
+				"capabilities": []string{"completion", "vision"},
+			})
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDiscoverOllamaModels(t *testing.T) {
+	t.Parallel()
+
+	srv := newOllamaServer(t)
+	models, err := discoverLocalModels(t.Context(), srv.Client(), ProviderTypeOllama, srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, []catwalk.Model{
+		{
+			ID:               "llama3.2:latest",
+			Name:             "llama3.2:latest",
+			ContextWindow:    131072,
+			DefaultMaxTokens: maxLocalOutputTokens,
+			CanReason:        true,
+		},
+		{
+			ID:               "llava:7b",
+			Name:             "llava:7b",
+			ContextWindow:    defaultLocalContextWindow,
+			DefaultMaxTokens: defaultLocalContextWindow / 4,
+			SupportsImages:   true,
+		},
+	}, models)
+}
+
+func TestDiscoverLlamaCppModels(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/models", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{
+				{"id": "qwen2.5-coder-7b.gguf", "meta": map[string]any{"n_ctx_train": 32768}},
+			},
+		})
+	})
+	mux.HandleFunc("GET /props", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"default_generation_settings": map[string]any{"n_ctx": 16384},
+			"modalities":                  map[string]any{"vision": false},
+		})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	models, err := discoverLocalModels(t.Context(), srv.Client(), ProviderTypeLlamaCpp, srv.URL)
+	require.NoError(t, err)
+	require.Len(t, models, 1)
+	require.Equal(t, "qwen2.5-coder-7b.gguf", models[0].ID)
+	require.Equal(t, int64(16384), models[0].ContextWindow)
+	require.Equal(t, int64(4096), models[0].DefaultMaxTokens)
+	require.Zero(t, models[0].CostPer1MIn)
+	require.Zero(t, models[0].CostPer1MOut)
+}
+
+func TestConfig_configureProvidersLocal(t *testing.T) {
+	t.Parallel()
+
+	srv := newOllamaServer(t)
+	cfg := &Config{
+		Providers: csync.NewMap[string, ProviderConfig](),
+	}
+	cfg.Providers.Set("my-ollama", ProviderConfig{
+		Type:    ProviderTypeOllama,
+		BaseURL: srv.URL + "/v1",
+	})
+	cfg.setDefaults("/tmp", "")
+
+	env := env.NewFromMap(map[string]string{})
+	resolver := NewEnvironmentVariableResolver(env)
+	err := cfg.configureProviders(env, resolver, []catwalk.Provider{})
+	require.NoError(t, err)
+
+	pc, ok := cfg.Providers.Get("my-ollama")
+	require.True(t, ok)
+	require.Equal(t, catwalk.TypeOpenAICompat, pc.Type)
+	require.Equal(t, srv.URL+"/v1", pc.BaseURL)
+	require.Empty(t, pc.APIKey)
+	require.Len(t, pc.Models, 2)
+}
+
+func TestConfig_configureProvidersLocalUnreachable(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	cfg := &Config{
+		Providers: csync.NewMap[string, ProviderConfig](),
+	}
+	cfg.Providers.Set("llamacpp", ProviderConfig{
+		Type:    ProviderTypeLlamaCpp,
+		BaseURL: srv.URL,
+	})
+	cfg.setDefaults("/tmp", "")
+
+	env := env.NewFromMap(map[string]string{})
+	resolver := NewEnvironmentVariableResolver(env)
+	err := cfg.configureProviders(env, resolver, []catwalk.Provider{})
+	require.NoError(t, err)
+
+	_, ok := cfg.Providers.Get("llamacpp")
+	require.False(t, ok)
+}
+
+func TestConfig_discoverLocalProvidersSkipsConfigured(t *testing.T) {
+	t.Parallel()
+
+	srv := newOllamaServer(t)
+	cfg := &Config{
+		Providers: csync.NewMap[string, ProviderConfig](),
+	}
+	cfg.Providers.Set("ollama", ProviderConfig{ID: "ollama", Name: "Mine"})
+	cfg.discoverLocalProviders(srv.URL)
+
+	pc, ok := cfg.Providers.Get("ollama")
+	require.True(t, ok)
+	require.Equal(t, "Mine", pc.Name)
+}
+
+func TestNormalizeLocalURL(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "", normalizeLocalURL(""))
+	require.Equal(t, "http://127.0.0.1:11434", normalizeLocalURL("127.0.0.1:11434"))
+	require.Equal(t, "https://ollama.lan", normalizeLocalURL("https://ollama.lan/"))
+}
+
+func TestConfig_discoverLocalProviders(t *testing.T) {
+	t.Parallel()
+
+	srv := newOllamaServer(t)
+	cfg := &Config{
+		Providers: csync.NewMap[string, ProviderConfig](),
+	}
+	cfg.discoverLocalProviders(srv.URL)
+
+	pc, ok := cfg.Providers.Get("ollama")
+	require.True(t, ok)
+	require.Equal(t, catwalk.TypeOpenAICompat, pc.Type)
+	require.Equal(t, srv.URL+"/v1", pc.BaseURL)
+	require.Len(t, pc.Models, 2)
+}
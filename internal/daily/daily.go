@@ -0,0 +1,361 @@
+// Package daily picks the question of the day, a single drill question
+// answered in a short gym session, and tracks the streak of days it was
+// answered on.
+package daily
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/drill"
+)
+
+// DayLayout is the format of challenge days, in local time.
+const DayLayout = "2006-01-02"
+
+// Day returns the challenge day t falls on.
+func Day(t time.Time) string {
+	return t.Format(DayLayout)
+}
+
+// Challenge is the question of a day.
+type Challenge struct {
+	Day   string `json:"day"`
+	Topic string `json:"topic,omitempty"`
+	// QuestionID is the bank question picked, or empty when the question is
+	// generated in the challenge's session.
+	QuestionID string `json:"question_id,omitempty"`
+	// Question is empty until a generated question has been asked.
+	Question  string   `json:"question,omitempty"`
+	Hints     []string `json:"-"`
+	Answer    string   `json:"-"`
+	SessionID string   `json:"session_id,omitempty"`
+	// Score is the best score earned in the session, or nil until the
+	// question is answered or given up.
+	Score     *int  `json:"score,omitempty"`
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+// Done reports whether the challenge was answered or given up.
+func (c Challenge) Done() bool {
+	return c.Score != nil
+}
+
+// Streak is the run of consecutive days with a done challenge.
+type Streak struct {
+	Current int `json:"current"`
+	Longest int `json:"longest"`
+	// FreezesLeft is how many more days can be missed this month without
+	// breaking the streak.
+	FreezesLeft int `json:"freezes_left"`
+}
+
+type Service interface {
+	// Today returns the current day's challenge, picking it on first use.
+	Today(ctx context.Context) (Challenge, error)
+	// Begin ties today's challenge to a gym session. A known question is
+	// recorded in the session right away, so the interviewer asks it as is.
+	Begin(ctx context.Context, sessionID string) (Challenge, error)
+	// History lists past challenges, most recent first.
+	History(ctx context.Context) ([]Challenge, error)
+	// Streak computes the current and longest streaks.
+	Streak(ctx context.Context) (Streak, error)
+}
+
+type service struct {
+	q      db.Querier
+	drills drill.Service
+	opts   config.Daily
+	now    func() time.Time
+}
+
+func NewService(q db.Querier, drills drill.Service, opts config.Daily) Service {
+	return &service{
+		q:      q,
+		drills: drills,
+		opts:   opts,
+		now:    time.Now,
+	}
+}
+
+func (s *service) Today(ctx context.Context) (Challenge, error) {
+	day := Day(s.now())
+	row, err := s.q.GetDailyChallenge(ctx, day)
+	if errors.Is(err, sql.ErrNoRows) {
+		if err := s.pick(ctx, day); err != nil {
+			return Challenge{}, err
+		}
+		row, err = s.q.GetDailyChallenge(ctx, day)
+	}
+	if err != nil {
+		return Challenge{}, err
+	}
+	c := fromDB(db.DailyChallenge{
+		Day:        row.Day,
+		Topic:      row.Topic,
+		QuestionID: row.QuestionID,
+		Question:   row.Question,
+		Hints:      row.Hints,
+		Answer:     row.Answer,
+		SessionID:  row.SessionID,
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+	}, row.Score)
+	if c.Question == "" && c.SessionID != "" {
+		return s.cacheGenerated(ctx, c)
+	}
+	return c, nil
+}
+
+// pick stores the day's challenge. Another process picking the same day
+// first wins.
+func (s *service) pick(ctx context.Context, day string) error {
+	bank, err := s.q.ListDrillBank(ctx)
+	if err != nil {
+		return err
+	}
+	q := Pick(day, bank, s.opts.Topics)
+	return s.q.CreateDailyChallenge(ctx, db.CreateDailyChallengeParams{
+		Day:        day,
+		Topic:      q.Topic,
+		QuestionID: q.ID,
+		Question:   q.Question,
+		Hints:      q.Hints,
+		Answer:     q.Answer,
+	})
+}
+
+// cacheGenerated keeps the first question asked in the challenge's session
+// as the day's question.
+func (s *service) cacheGenerated(ctx context.Context, c Challenge) (Challenge, error) {
+	q, err := s.q.GetFirstDrillQuestion(ctx, c.SessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, nil
+	}
+	if err != nil {
+		return Challenge{}, err
+	}
+	err = s.q.SetDailyChallengeQuestion(ctx, db.SetDailyChallengeQuestionParams{
+		Topic:    q.Topic,
+		Question: q.Question,
+		Hints:    q.Hints,
+		Answer:   q.Answer,
+		Day:      c.Day,
+	})
+	if err != nil {
+		return Challenge{}, err
+	}
+	c.Topic = q.Topic
+	c.Question = q.Question
+	_ = json.Unmarshal([]byte(q.Hints), &c.Hints)
+	c.Answer = q.Answer
+	return c, nil
+}
+
+func (s *service) Begin(ctx context.Context, sessionID string) (Challenge, error) {
+	c, err := s.Today(ctx)
+	if err != nil {
+		return Challenge{}, err
+	}
+	err = s.q.SetDailyChallengeSession(ctx, db.SetDailyChallengeSessionParams{
+		SessionID: sessionID,
+		Day:       c.Day,
+	})
+	if err != nil {
+		return Challenge{}, err
+	}
+	c.SessionID = sessionID
+	c.Score = nil
+	if c.Question == "" {
+		return c, nil
+	}
+	_, err = s.drills.Ask(ctx, drill.Question{
+		SessionID: sessionID,
+		Topic:     c.Topic,
+		Text:      c.Question,
+		Hints:     c.Hints,
+		Answer:    c.Answer,
+	})
+	if err != nil {
+		return Challenge{}, err
+	}
+	return c, nil
+}
+
+func (s *service) History(ctx context.Context) ([]Challenge, error) {
+	rows, err := s.q.ListDailyChallenges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	challenges := make([]Challenge, len(rows))
+	for i, row := range rows {
+		challenges[i] = fromDB(db.DailyChallenge{
+			Day:        row.Day,
+			Topic:      row.Topic,
+			QuestionID: row.QuestionID,
+			Question:   row.Question,
+			Hints:      row.Hints,
+			Answer:     row.Answer,
+			SessionID:  row.SessionID,
+			CreatedAt:  row.CreatedAt,
+			UpdatedAt:  row.UpdatedAt,
+		}, row.Score)
+	}
+	return challenges, nil
+}
+
+func (s *service) Streak(ctx context.Context) (Streak, error) {
+	history, err := s.History(ctx)
+	if err != nil {
+		return Streak{}, err
+	}
+	var done []string
+	for _, c := range history {
+		if c.Done() {
+			done = append(done, c.Day)
+		}
+	}
+	return ComputeStreak(done, Day(s.now()), s.opts.Freezes), nil
+}
+
+// Pick chooses the day's question from the bank: past questions with a
+// model answer whose topic mentions one of topics, or any topic when topics
+// is empty. Repeated questions count once. With no candidates it returns a
+// question holding just a topic, picked from topics or left empty for the
+// interviewer to choose, to be generated in the session. The same day, bank
+// and topics always give the same pick.
+func Pick(day string, bank []db.DrillQuestion, topics []string) db.DrillQuestion {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(day))
+	n := int(h.Sum32() & 0x7fffffff)
+
+	seen := make(map[string]bool)
+	var candidates []db.DrillQuestion
+	for _, q := range bank {
+		if q.Answer == "" || seen[q.Question] || !matchesTopic(q.Topic, topics) {
+			continue
+		}
+		seen[q.Question] = true
+		candidates = append(candidates, q)
+	}
+	if len(candidates) > 0 {
+		return candidates[n%len(candidates)]
+	}
+	if len(topics) == 0 {
+		return db.DrillQuestion{Hints: "[]"}
+	}
+	return db.DrillQuestion{Topic: topics[n%len(topics)], Hints: "[]"}
+}
+
+func matchesTopic(topic string, topics []string) bool {
+	if len(topics) == 0 {
+		return true
+	}
+	for _, t := range topics {
+		if strings.Contains(strings.ToLower(topic), strings.ToLower(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ComputeStreak counts the consecutive days up to today with a done
+// challenge. Today only breaks the streak once it is over. Up to freezes
+// missed days per calendar month are bridged without breaking it; they
+// don't add to its length.
+func ComputeStreak(done []string, today string, freezes int) Streak {
+	days := make(map[string]bool, len(done))
+	earliest := today
+	for _, d := range done {
+		days[d] = true
+		earliest = min(earliest, d)
+	}
+	t, err := time.Parse(DayLayout, today)
+	if err != nil {
+		return Streak{}
+	}
+
+	from := t
+	if !days[today] {
+		from = t.AddDate(0, 0, -1)
+	}
+	var streak Streak
+	var used map[string]int
+	streak.Current, used = walk(days, from, earliest, freezes)
+	streak.FreezesLeft = max(freezes-used[t.Format("2006-01")], 0)
+	for d := range days {
+		start, _ := time.Parse(DayLayout, d)
+		n, _ := walk(days, start, earliest, freezes)
+		streak.Longest = max(streak.Longest, n)
+	}
+	return streak
+}
+
+// walk counts the done days going back from from, bridging missed days with
+// freezes, and returns the freezes used per month.
+func walk(days map[string]bool, from time.Time, earliest string, freezes int) (int, map[string]int) {
+	n := 0
+	used := make(map[string]int)
+	// Freezes only count once a done day further back is reached.
+	pending := make(map[string]int)
+	for d := from; Day(d) >= earliest; d = d.AddDate(0, 0, -1) {
+		if days[Day(d)] {
+			n++
+			for month, k := range pending {
+				used[month] += k
+			}
+			clear(pending)
+			continue
+		}
+		month := d.Format("2006-01")
+		if used[month]+pending[month] >= freezes {
+			break
+		}
+		pending[month]++
+	}
+	return n, used
+}
+
+// Prompt is the message that opens a challenge's session.
+func Prompt(c Challenge) string {
+	if c.Question != "" {
+		return fmt.Sprintf("This is my daily challenge for %s. The question below is already recorded with its hints, so don't call drill_question: ask it as written, then grade my answer.\n\n%s", c.Day, c.Question)
+	}
+	topic := "a topic of your choice"
+	if c.Topic != "" {
+		topic = c.Topic
+	}
+	return fmt.Sprintf("This is my daily challenge for %s. Ask me a single question on %s, recording it with drill_question, then grade my answer.", c.Day, topic)
+}
+
+func fromDB(row db.DailyChallenge, score int64) Challenge {
+	var hints []string
+	_ = json.Unmarshal([]byte(row.Hints), &hints)
+	c := Challenge{
+		Day:        row.Day,
+		Topic:      row.Topic,
+		QuestionID: row.QuestionID,
+		Question:   row.Question,
+		Hints:      hints,
+		Answer:     row.Answer,
+		SessionID:  row.SessionID,
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+	}
+	// A score of -1 means no question in the session was answered or given
+	// up.
+	if score >= 0 {
+		s := int(score)
+		c.Score = &s
+	}
+	return c
+}
@@ -0,0 +1,126 @@
+package daily
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/message"
+)
+
+func TestPick(t *testing.T) {
+	t.Parallel()
+
+	bank := []db.DrillQuestion{
+		{ID: "1", Topic: "Go channels", Question: "What does close do?", Answer: "..."},
+		{ID: "2", Topic: "SQL indexes", Question: "When is an index ignored?", Answer: "..."},
+		{ID: "3", Topic: "Go channels", Question: "What does close do?", Answer: "..."},
+		{ID: "4", Topic: "Go maps", Question: "Are maps safe for concurrent use?", Answer: ""},
+	}
+
+	q := Pick("2026-10-19", bank, nil)
+	require.Contains(t, []string{"1", "2"}, q.ID)
+	for range 10 {
+		require.Equal(t, q.ID, Pick("2026-10-19", bank, nil).ID)
+	}
+
+	require.Equal(t, "2", Pick("2026-10-19", bank, []string{"sql"}).ID)
+
+	q = Pick("2026-10-19", bank, []string{"system design"})
+	require.Empty(t, q.ID)
+	require.Equal(t, "system design", q.Topic)
+
+	require.Empty(t, Pick("2026-10-19", nil, nil).Topic)
+}
+
+func TestComputeStreak(t *testing.T) {
+	t.Parallel()
+
+	done := []string{"2026-10-10", "2026-10-11", "2026-10-12", "2026-10-15", "2026-10-16", "2026-10-17", "2026-10-18"}
+
+	// Today isn't over yet, so it doesn't break the streak.
+	require.Equal(t, Streak{Current: 4, Longest: 4}, ComputeStreak(done, "2026-10-19", 0))
+	require.Equal(t, Streak{Current: 5, Longest: 5}, ComputeStreak(append(done, "2026-10-19"), "2026-10-19", 0))
+	require.Equal(t, Streak{Current: 0, Longest: 4}, ComputeStreak(done, "2026-10-20", 0))
+
+	// Two freezes bridge the gap on the 13th and 14th, but not a third
+	// missed day in the same month.
+	require.Equal(t, Streak{Current: 7, Longest: 7}, ComputeStreak(done, "2026-10-19", 2))
+	require.Equal(t, Streak{Current: 7, Longest: 7, FreezesLeft: 1}, ComputeStreak(done, "2026-10-19", 3))
+	require.Equal(t, Streak{Current: 4, Longest: 7}, ComputeStreak(done, "2026-10-21", 2))
+	// Once the streak is broken no freezes are spent.
+	require.Equal(t, Streak{Current: 0, Longest: 7, FreezesLeft: 2}, ComputeStreak(done, "2026-10-22", 2))
+	require.Equal(t, Streak{Current: 7, Longest: 7}, ComputeStreak(done, "2026-10-20", 3))
+
+	// Freezes are per month.
+	require.Equal(t, Streak{Current: 3, Longest: 3, FreezesLeft: 1}, ComputeStreak([]string{"2026-09-29", "2026-10-01", "2026-10-02"}, "2026-10-02", 1))
+
+	require.Equal(t, Streak{FreezesLeft: 2}, ComputeStreak(nil, "2026-10-19", 2))
+}
+
+func TestTodayAndBegin(t *testing.T) {
+	t.Parallel()
+
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	q := db.New(conn)
+	drills := drill.NewService(q, conn)
+	svc := NewService(q, drills, config.Daily{Freezes: 1}).(*service)
+	svc.now = func() time.Time { return time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local) }
+
+	// With an empty bank the question is generated in the session and
+	// kept once asked.
+	c, err := svc.Today(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "2026-10-19", c.Day)
+	require.Empty(t, c.Question)
+	require.False(t, c.Done())
+
+	c, err = svc.Begin(t.Context(), "s1")
+	require.NoError(t, err)
+	require.Equal(t, "s1", c.SessionID)
+	_, err = drills.Ask(t.Context(), drill.Question{
+		SessionID: "s1",
+		Topic:     "Go channels",
+		Text:      "What does close do?",
+		Hints:     []string{"nudge", "approach", "partial"},
+		Answer:    "It marks the channel closed.",
+	})
+	require.NoError(t, err)
+	_, err = drills.Grade(t.Context(), "s1", 80, &message.AnswerTiming{})
+	require.NoError(t, err)
+
+	c, err = svc.Today(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "What does close do?", c.Question)
+	require.Equal(t, "Go channels", c.Topic)
+	require.True(t, c.Done())
+	require.Equal(t, 80, *c.Score)
+
+	streak, err := svc.Streak(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, Streak{Current: 1, Longest: 1, FreezesLeft: 1}, streak)
+
+	// The next day picks the question from the bank and records it in the
+	// new session right away.
+	svc.now = func() time.Time { return time.Date(2026, 10, 20, 9, 0, 0, 0, time.Local) }
+	c, err = svc.Begin(t.Context(), "s2")
+	require.NoError(t, err)
+	require.Equal(t, "What does close do?", c.Question)
+	require.NotEmpty(t, c.QuestionID)
+	open, err := drills.Current(t.Context(), "s2")
+	require.NoError(t, err)
+	require.Equal(t, c.Question, open.Text)
+	require.Equal(t, []string{"nudge", "approach", "partial"}, open.Hints)
+
+	history, err := svc.History(t.Context())
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, "2026-10-20", history[0].Day)
+	require.False(t, history[0].Done())
+	require.True(t, history[1].Done())
+}
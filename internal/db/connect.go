@@ -17,7 +17,7 @@ func Connect(ctx context.Context, dataDir string) (*sql.DB, error) {
 	}
 	dbPath := filepath.Join(dataDir, "crush.db")
 
-	db, err := openDB(dbPath)
+	db, err := openDB(dbPath, false)
 	if err != nil {
 		return nil, err
 	}
@@ -41,3 +41,15 @@ func Connect(ctx context.Context, dataDir string) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// Open opens a SQLite database without running the migrations, e.g. for a
+// scratch database.
+func Open(path string) (*sql.DB, error) {
+	return openDB(path, false)
+}
+
+// OpenReadOnly opens a SQLite database with every connection in query_only
+// mode, so nothing can write to it through the returned handle.
+func OpenReadOnly(path string) (*sql.DB, error) {
+	return openDB(path, true)
+}
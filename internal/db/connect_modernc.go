@@ -10,7 +10,7 @@ import (
 	_ "modernc.org/sqlite"
 )
 
-func openDB(dbPath string) (*sql.DB, error) {
+func openDB(dbPath string, queryOnly bool) (*sql.DB, error) {
 	// Set pragmas for better performance via _pragma query params.
 	// Format: _pragma=name(value)
 	params := url.Values{}
@@ -21,6 +21,9 @@ func openDB(dbPath string) (*sql.DB, error) {
 	params.Add("_pragma", "synchronous(NORMAL)")
 	params.Add("_pragma", "secure_delete(on)")
 	params.Add("_pragma", "busy_timeout(5000)")
+	if queryOnly {
+		params.Add("_pragma", "query_only(on)")
+	}
 
 	dsn := fmt.Sprintf("file:%s?%s", dbPath, params.Encode())
 	db, err := sql.Open("sqlite", dsn)
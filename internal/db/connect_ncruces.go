@@ -11,7 +11,7 @@ import (
 	_ "github.com/ncruces/go-sqlite3/embed"
 )
 
-func openDB(dbPath string) (*sql.DB, error) {
+func openDB(dbPath string, queryOnly bool) (*sql.DB, error) {
 	// Set pragmas for better performance.
 	pragmas := []string{
 		"PRAGMA foreign_keys = ON;",
@@ -22,6 +22,9 @@ func openDB(dbPath string) (*sql.DB, error) {
 		"PRAGMA secure_delete = ON;",
 		"PRAGMA busy_timeout = 5000;",
 	}
+	if queryOnly {
+		pragmas = append(pragmas, "PRAGMA query_only = ON;")
+	}
 
 	db, err := driver.Open(dbPath, func(c *sqlite3.Conn) error {
 		for _, pragma := range pragmas {
@@ -24,18 +24,57 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.clearDrillPracticeStmt, err = db.PrepareContext(ctx, clearDrillPractice); err != nil {
+		return nil, fmt.Errorf("error preparing query ClearDrillPractice: %w", err)
+	}
+	if q.createAnnotationStmt, err = db.PrepareContext(ctx, createAnnotation); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAnnotation: %w", err)
+	}
+	if q.createDailyChallengeStmt, err = db.PrepareContext(ctx, createDailyChallenge); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDailyChallenge: %w", err)
+	}
+	if q.createDebugRunStmt, err = db.PrepareContext(ctx, createDebugRun); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDebugRun: %w", err)
+	}
+	if q.createDrillQuestionStmt, err = db.PrepareContext(ctx, createDrillQuestion); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDrillQuestion: %w", err)
+	}
 	if q.createFileStmt, err = db.PrepareContext(ctx, createFile); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateFile: %w", err)
 	}
+	if q.createFlashcardStmt, err = db.PrepareContext(ctx, createFlashcard); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateFlashcard: %w", err)
+	}
+	if q.createGradeSampleStmt, err = db.PrepareContext(ctx, createGradeSample); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateGradeSample: %w", err)
+	}
+	if q.createInterviewTargetStmt, err = db.PrepareContext(ctx, createInterviewTarget); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateInterviewTarget: %w", err)
+	}
 	if q.createMessageStmt, err = db.PrepareContext(ctx, createMessage); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateMessage: %w", err)
 	}
+	if q.createPlanItemStmt, err = db.PrepareContext(ctx, createPlanItem); err != nil {
+		return nil, fmt.Errorf("error preparing query CreatePlanItem: %w", err)
+	}
 	if q.createSessionStmt, err = db.PrepareContext(ctx, createSession); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateSession: %w", err)
 	}
+	if q.createSessionPackStmt, err = db.PrepareContext(ctx, createSessionPack); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSessionPack: %w", err)
+	}
+	if q.createUsageRecordStmt, err = db.PrepareContext(ctx, createUsageRecord); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUsageRecord: %w", err)
+	}
+	if q.deleteAnnotationStmt, err = db.PrepareContext(ctx, deleteAnnotation); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAnnotation: %w", err)
+	}
 	if q.deleteFileStmt, err = db.PrepareContext(ctx, deleteFile); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteFile: %w", err)
 	}
+	if q.deleteInterviewTargetStmt, err = db.PrepareContext(ctx, deleteInterviewTarget); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteInterviewTarget: %w", err)
+	}
 	if q.deleteMessageStmt, err = db.PrepareContext(ctx, deleteMessage); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteMessage: %w", err)
 	}
@@ -48,24 +87,96 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.deleteSessionMessagesStmt, err = db.PrepareContext(ctx, deleteSessionMessages); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteSessionMessages: %w", err)
 	}
+	if q.deleteUnstartedPlanItemsStmt, err = db.PrepareContext(ctx, deleteUnstartedPlanItems); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUnstartedPlanItems: %w", err)
+	}
+	if q.getAnnotationStmt, err = db.PrepareContext(ctx, getAnnotation); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAnnotation: %w", err)
+	}
+	if q.getDailyChallengeStmt, err = db.PrepareContext(ctx, getDailyChallenge); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDailyChallenge: %w", err)
+	}
+	if q.getDebugRunBySessionStmt, err = db.PrepareContext(ctx, getDebugRunBySession); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDebugRunBySession: %w", err)
+	}
+	if q.getDrillQuestionStmt, err = db.PrepareContext(ctx, getDrillQuestion); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDrillQuestion: %w", err)
+	}
 	if q.getFileStmt, err = db.PrepareContext(ctx, getFile); err != nil {
 		return nil, fmt.Errorf("error preparing query GetFile: %w", err)
 	}
 	if q.getFileByPathAndSessionStmt, err = db.PrepareContext(ctx, getFileByPathAndSession); err != nil {
 		return nil, fmt.Errorf("error preparing query GetFileByPathAndSession: %w", err)
 	}
+	if q.getFirstDrillQuestionStmt, err = db.PrepareContext(ctx, getFirstDrillQuestion); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFirstDrillQuestion: %w", err)
+	}
+	if q.getFlashcardStmt, err = db.PrepareContext(ctx, getFlashcard); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFlashcard: %w", err)
+	}
 	if q.getMessageStmt, err = db.PrepareContext(ctx, getMessage); err != nil {
 		return nil, fmt.Errorf("error preparing query GetMessage: %w", err)
 	}
+	if q.getOpenDrillQuestionStmt, err = db.PrepareContext(ctx, getOpenDrillQuestion); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOpenDrillQuestion: %w", err)
+	}
 	if q.getSessionByIDStmt, err = db.PrepareContext(ctx, getSessionByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetSessionByID: %w", err)
 	}
+	if q.getSessionPackStmt, err = db.PrepareContext(ctx, getSessionPack); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSessionPack: %w", err)
+	}
+	if q.getUsageCostSinceStmt, err = db.PrepareContext(ctx, getUsageCostSince); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUsageCostSince: %w", err)
+	}
+	if q.importAnnotationStmt, err = db.PrepareContext(ctx, importAnnotation); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportAnnotation: %w", err)
+	}
+	if q.importMessageStmt, err = db.PrepareContext(ctx, importMessage); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportMessage: %w", err)
+	}
+	if q.importSessionStmt, err = db.PrepareContext(ctx, importSession); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportSession: %w", err)
+	}
+	if q.listAnnotationsBySessionStmt, err = db.PrepareContext(ctx, listAnnotationsBySession); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAnnotationsBySession: %w", err)
+	}
+	if q.listClassifiedDrillQuestionsStmt, err = db.PrepareContext(ctx, listClassifiedDrillQuestions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListClassifiedDrillQuestions: %w", err)
+	}
+	if q.listDailyChallengesStmt, err = db.PrepareContext(ctx, listDailyChallenges); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDailyChallenges: %w", err)
+	}
+	if q.listDrillBankStmt, err = db.PrepareContext(ctx, listDrillBank); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDrillBank: %w", err)
+	}
+	if q.listDrillTopicStatsStmt, err = db.PrepareContext(ctx, listDrillTopicStats); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDrillTopicStats: %w", err)
+	}
+	if q.listDueDrillQuestionsStmt, err = db.PrepareContext(ctx, listDueDrillQuestions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDueDrillQuestions: %w", err)
+	}
+	if q.listDueFlashcardsStmt, err = db.PrepareContext(ctx, listDueFlashcards); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDueFlashcards: %w", err)
+	}
 	if q.listFilesByPathStmt, err = db.PrepareContext(ctx, listFilesByPath); err != nil {
 		return nil, fmt.Errorf("error preparing query ListFilesByPath: %w", err)
 	}
 	if q.listFilesBySessionStmt, err = db.PrepareContext(ctx, listFilesBySession); err != nil {
 		return nil, fmt.Errorf("error preparing query ListFilesBySession: %w", err)
 	}
+	if q.listFlaggedDrillQuestionsStmt, err = db.PrepareContext(ctx, listFlaggedDrillQuestions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListFlaggedDrillQuestions: %w", err)
+	}
+	if q.listFlashcardsStmt, err = db.PrepareContext(ctx, listFlashcards); err != nil {
+		return nil, fmt.Errorf("error preparing query ListFlashcards: %w", err)
+	}
+	if q.listGradeSamplesStmt, err = db.PrepareContext(ctx, listGradeSamples); err != nil {
+		return nil, fmt.Errorf("error preparing query ListGradeSamples: %w", err)
+	}
+	if q.listInterviewTargetsStmt, err = db.PrepareContext(ctx, listInterviewTargets); err != nil {
+		return nil, fmt.Errorf("error preparing query ListInterviewTargets: %w", err)
+	}
 	if q.listLatestSessionFilesStmt, err = db.PrepareContext(ctx, listLatestSessionFiles); err != nil {
 		return nil, fmt.Errorf("error preparing query ListLatestSessionFiles: %w", err)
 	}
@@ -75,9 +186,57 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listNewFilesStmt, err = db.PrepareContext(ctx, listNewFiles); err != nil {
 		return nil, fmt.Errorf("error preparing query ListNewFiles: %w", err)
 	}
+	if q.listPlanItemsStmt, err = db.PrepareContext(ctx, listPlanItems); err != nil {
+		return nil, fmt.Errorf("error preparing query ListPlanItems: %w", err)
+	}
 	if q.listSessionsStmt, err = db.PrepareContext(ctx, listSessions); err != nil {
 		return nil, fmt.Errorf("error preparing query ListSessions: %w", err)
 	}
+	if q.listUnclassifiedDrillQuestionsStmt, err = db.PrepareContext(ctx, listUnclassifiedDrillQuestions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUnclassifiedDrillQuestions: %w", err)
+	}
+	if q.listUsageByDayStmt, err = db.PrepareContext(ctx, listUsageByDay); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUsageByDay: %w", err)
+	}
+	if q.listUsageByModeStmt, err = db.PrepareContext(ctx, listUsageByMode); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUsageByMode: %w", err)
+	}
+	if q.listUsageByModelStmt, err = db.PrepareContext(ctx, listUsageByModel); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUsageByModel: %w", err)
+	}
+	if q.setDailyChallengeQuestionStmt, err = db.PrepareContext(ctx, setDailyChallengeQuestion); err != nil {
+		return nil, fmt.Errorf("error preparing query SetDailyChallengeQuestion: %w", err)
+	}
+	if q.setDailyChallengeSessionStmt, err = db.PrepareContext(ctx, setDailyChallengeSession); err != nil {
+		return nil, fmt.Errorf("error preparing query SetDailyChallengeSession: %w", err)
+	}
+	if q.setDrillQuestionJudgementStmt, err = db.PrepareContext(ctx, setDrillQuestionJudgement); err != nil {
+		return nil, fmt.Errorf("error preparing query SetDrillQuestionJudgement: %w", err)
+	}
+	if q.setDrillQuestionNodeStmt, err = db.PrepareContext(ctx, setDrillQuestionNode); err != nil {
+		return nil, fmt.Errorf("error preparing query SetDrillQuestionNode: %w", err)
+	}
+	if q.setPlanItemSessionStmt, err = db.PrepareContext(ctx, setPlanItemSession); err != nil {
+		return nil, fmt.Errorf("error preparing query SetPlanItemSession: %w", err)
+	}
+	if q.setSessionReviewedStmt, err = db.PrepareContext(ctx, setSessionReviewed); err != nil {
+		return nil, fmt.Errorf("error preparing query SetSessionReviewed: %w", err)
+	}
+	if q.skipOpenDrillQuestionsStmt, err = db.PrepareContext(ctx, skipOpenDrillQuestions); err != nil {
+		return nil, fmt.Errorf("error preparing query SkipOpenDrillQuestions: %w", err)
+	}
+	if q.updateAnnotationStmt, err = db.PrepareContext(ctx, updateAnnotation); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateAnnotation: %w", err)
+	}
+	if q.updateDebugRunCheckStmt, err = db.PrepareContext(ctx, updateDebugRunCheck); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateDebugRunCheck: %w", err)
+	}
+	if q.updateDrillQuestionStmt, err = db.PrepareContext(ctx, updateDrillQuestion); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateDrillQuestion: %w", err)
+	}
+	if q.updateFlashcardReviewStmt, err = db.PrepareContext(ctx, updateFlashcardReview); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateFlashcardReview: %w", err)
+	}
 	if q.updateMessageStmt, err = db.PrepareContext(ctx, updateMessage); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateMessage: %w", err)
 	}
@@ -92,26 +251,91 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 
 func (q *Queries) Close() error {
 	var err error
+	if q.clearDrillPracticeStmt != nil {
+		if cerr := q.clearDrillPracticeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing clearDrillPracticeStmt: %w", cerr)
+		}
+	}
+	if q.createAnnotationStmt != nil {
+		if cerr := q.createAnnotationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAnnotationStmt: %w", cerr)
+		}
+	}
+	if q.createDailyChallengeStmt != nil {
+		if cerr := q.createDailyChallengeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDailyChallengeStmt: %w", cerr)
+		}
+	}
+	if q.createDebugRunStmt != nil {
+		if cerr := q.createDebugRunStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDebugRunStmt: %w", cerr)
+		}
+	}
+	if q.createDrillQuestionStmt != nil {
+		if cerr := q.createDrillQuestionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDrillQuestionStmt: %w", cerr)
+		}
+	}
 	if q.createFileStmt != nil {
 		if cerr := q.createFileStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createFileStmt: %w", cerr)
 		}
 	}
+	if q.createFlashcardStmt != nil {
+		if cerr := q.createFlashcardStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createFlashcardStmt: %w", cerr)
+		}
+	}
+	if q.createGradeSampleStmt != nil {
+		if cerr := q.createGradeSampleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createGradeSampleStmt: %w", cerr)
+		}
+	}
+	if q.createInterviewTargetStmt != nil {
+		if cerr := q.createInterviewTargetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createInterviewTargetStmt: %w", cerr)
+		}
+	}
 	if q.createMessageStmt != nil {
 		if cerr := q.createMessageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createMessageStmt: %w", cerr)
 		}
 	}
+	if q.createPlanItemStmt != nil {
+		if cerr := q.createPlanItemStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createPlanItemStmt: %w", cerr)
+		}
+	}
 	if q.createSessionStmt != nil {
 		if cerr := q.createSessionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createSessionStmt: %w", cerr)
 		}
 	}
+	if q.createSessionPackStmt != nil {
+		if cerr := q.createSessionPackStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSessionPackStmt: %w", cerr)
+		}
+	}
+	if q.createUsageRecordStmt != nil {
+		if cerr := q.createUsageRecordStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUsageRecordStmt: %w", cerr)
+		}
+	}
+	if q.deleteAnnotationStmt != nil {
+		if cerr := q.deleteAnnotationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAnnotationStmt: %w", cerr)
+		}
+	}
 	if q.deleteFileStmt != nil {
 		if cerr := q.deleteFileStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteFileStmt: %w", cerr)
 		}
 	}
+	if q.deleteInterviewTargetStmt != nil {
+		if cerr := q.deleteInterviewTargetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteInterviewTargetStmt: %w", cerr)
+		}
+	}
 	if q.deleteMessageStmt != nil {
 		if cerr := q.deleteMessageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteMessageStmt: %w", cerr)
@@ -132,6 +356,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteSessionMessagesStmt: %w", cerr)
 		}
 	}
+	if q.deleteUnstartedPlanItemsStmt != nil {
+		if cerr := q.deleteUnstartedPlanItemsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUnstartedPlanItemsStmt: %w", cerr)
+		}
+	}
+	if q.getAnnotationStmt != nil {
+		if cerr := q.getAnnotationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAnnotationStmt: %w", cerr)
+		}
+	}
+	if q.getDailyChallengeStmt != nil {
+		if cerr := q.getDailyChallengeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDailyChallengeStmt: %w", cerr)
+		}
+	}
+	if q.getDebugRunBySessionStmt != nil {
+		if cerr := q.getDebugRunBySessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDebugRunBySessionStmt: %w", cerr)
+		}
+	}
+	if q.getDrillQuestionStmt != nil {
+		if cerr := q.getDrillQuestionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDrillQuestionStmt: %w", cerr)
+		}
+	}
 	if q.getFileStmt != nil {
 		if cerr := q.getFileStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getFileStmt: %w", cerr)
@@ -142,16 +391,91 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getFileByPathAndSessionStmt: %w", cerr)
 		}
 	}
+	if q.getFirstDrillQuestionStmt != nil {
+		if cerr := q.getFirstDrillQuestionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFirstDrillQuestionStmt: %w", cerr)
+		}
+	}
+	if q.getFlashcardStmt != nil {
+		if cerr := q.getFlashcardStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFlashcardStmt: %w", cerr)
+		}
+	}
 	if q.getMessageStmt != nil {
 		if cerr := q.getMessageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getMessageStmt: %w", cerr)
 		}
 	}
+	if q.getOpenDrillQuestionStmt != nil {
+		if cerr := q.getOpenDrillQuestionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOpenDrillQuestionStmt: %w", cerr)
+		}
+	}
 	if q.getSessionByIDStmt != nil {
 		if cerr := q.getSessionByIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getSessionByIDStmt: %w", cerr)
 		}
 	}
+	if q.getSessionPackStmt != nil {
+		if cerr := q.getSessionPackStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSessionPackStmt: %w", cerr)
+		}
+	}
+	if q.getUsageCostSinceStmt != nil {
+		if cerr := q.getUsageCostSinceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUsageCostSinceStmt: %w", cerr)
+		}
+	}
+	if q.importAnnotationStmt != nil {
+		if cerr := q.importAnnotationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importAnnotationStmt: %w", cerr)
+		}
+	}
+	if q.importMessageStmt != nil {
+		if cerr := q.importMessageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importMessageStmt: %w", cerr)
+		}
+	}
+	if q.importSessionStmt != nil {
+		if cerr := q.importSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importSessionStmt: %w", cerr)
+		}
+	}
+	if q.listAnnotationsBySessionStmt != nil {
+		if cerr := q.listAnnotationsBySessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAnnotationsBySessionStmt: %w", cerr)
+		}
+	}
+	if q.listClassifiedDrillQuestionsStmt != nil {
+		if cerr := q.listClassifiedDrillQuestionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listClassifiedDrillQuestionsStmt: %w", cerr)
+		}
+	}
+	if q.listDailyChallengesStmt != nil {
+		if cerr := q.listDailyChallengesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDailyChallengesStmt: %w", cerr)
+		}
+	}
+	if q.listDrillBankStmt != nil {
+		if cerr := q.listDrillBankStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDrillBankStmt: %w", cerr)
+		}
+	}
+	if q.listDrillTopicStatsStmt != nil {
+		if cerr := q.listDrillTopicStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDrillTopicStatsStmt: %w", cerr)
+		}
+	}
+	if q.listDueDrillQuestionsStmt != nil {
+		if cerr := q.listDueDrillQuestionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDueDrillQuestionsStmt: %w", cerr)
+		}
+	}
+	if q.listDueFlashcardsStmt != nil {
+		if cerr := q.listDueFlashcardsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDueFlashcardsStmt: %w", cerr)
+		}
+	}
 	if q.listFilesByPathStmt != nil {
 		if cerr := q.listFilesByPathStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listFilesByPathStmt: %w", cerr)
@@ -162,6 +486,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listFilesBySessionStmt: %w", cerr)
 		}
 	}
+	if q.listFlaggedDrillQuestionsStmt != nil {
+		if cerr := q.listFlaggedDrillQuestionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listFlaggedDrillQuestionsStmt: %w", cerr)
+		}
+	}
+	if q.listFlashcardsStmt != nil {
+		if cerr := q.listFlashcardsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listFlashcardsStmt: %w", cerr)
+		}
+	}
+	if q.listGradeSamplesStmt != nil {
+		if cerr := q.listGradeSamplesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listGradeSamplesStmt: %w", cerr)
+		}
+	}
+	if q.listInterviewTargetsStmt != nil {
+		if cerr := q.listInterviewTargetsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listInterviewTargetsStmt: %w", cerr)
+		}
+	}
 	if q.listLatestSessionFilesStmt != nil {
 		if cerr := q.listLatestSessionFilesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listLatestSessionFilesStmt: %w", cerr)
@@ -177,11 +521,91 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listNewFilesStmt: %w", cerr)
 		}
 	}
+	if q.listPlanItemsStmt != nil {
+		if cerr := q.listPlanItemsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listPlanItemsStmt: %w", cerr)
+		}
+	}
 	if q.listSessionsStmt != nil {
 		if cerr := q.listSessionsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listSessionsStmt: %w", cerr)
 		}
 	}
+	if q.listUnclassifiedDrillQuestionsStmt != nil {
+		if cerr := q.listUnclassifiedDrillQuestionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUnclassifiedDrillQuestionsStmt: %w", cerr)
+		}
+	}
+	if q.listUsageByDayStmt != nil {
+		if cerr := q.listUsageByDayStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUsageByDayStmt: %w", cerr)
+		}
+	}
+	if q.listUsageByModeStmt != nil {
+		if cerr := q.listUsageByModeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUsageByModeStmt: %w", cerr)
+		}
+	}
+	if q.listUsageByModelStmt != nil {
+		if cerr := q.listUsageByModelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUsageByModelStmt: %w", cerr)
+		}
+	}
+	if q.setDailyChallengeQuestionStmt != nil {
+		if cerr := q.setDailyChallengeQuestionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setDailyChallengeQuestionStmt: %w", cerr)
+		}
+	}
+	if q.setDailyChallengeSessionStmt != nil {
+		if cerr := q.setDailyChallengeSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setDailyChallengeSessionStmt: %w", cerr)
+		}
+	}
+	if q.setDrillQuestionJudgementStmt != nil {
+		if cerr := q.setDrillQuestionJudgementStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setDrillQuestionJudgementStmt: %w", cerr)
+		}
+	}
+	if q.setDrillQuestionNodeStmt != nil {
+		if cerr := q.setDrillQuestionNodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setDrillQuestionNodeStmt: %w", cerr)
+		}
+	}
+	if q.setPlanItemSessionStmt != nil {
+		if cerr := q.setPlanItemSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setPlanItemSessionStmt: %w", cerr)
+		}
+	}
+	if q.setSessionReviewedStmt != nil {
+		if cerr := q.setSessionReviewedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setSessionReviewedStmt: %w", cerr)
+		}
+	}
+	if q.skipOpenDrillQuestionsStmt != nil {
+		if cerr := q.skipOpenDrillQuestionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing skipOpenDrillQuestionsStmt: %w", cerr)
+		}
+	}
+	if q.updateAnnotationStmt != nil {
+		if cerr := q.updateAnnotationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateAnnotationStmt: %w", cerr)
+		}
+	}
+	if q.updateDebugRunCheckStmt != nil {
+		if cerr := q.updateDebugRunCheckStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateDebugRunCheckStmt: %w", cerr)
+		}
+	}
+	if q.updateDrillQuestionStmt != nil {
+		if cerr := q.updateDrillQuestionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateDrillQuestionStmt: %w", cerr)
+		}
+	}
+	if q.updateFlashcardReviewStmt != nil {
+		if cerr := q.updateFlashcardReviewStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateFlashcardReviewStmt: %w", cerr)
+		}
+	}
 	if q.updateMessageStmt != nil {
 		if cerr := q.updateMessageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateMessageStmt: %w", cerr)
@@ -234,55 +658,161 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                             DBTX
-	tx                             *sql.Tx
-	createFileStmt                 *sql.Stmt
-	createMessageStmt              *sql.Stmt
-	createSessionStmt              *sql.Stmt
-	deleteFileStmt                 *sql.Stmt
-	deleteMessageStmt              *sql.Stmt
-	deleteSessionStmt              *sql.Stmt
-	deleteSessionFilesStmt         *sql.Stmt
-	deleteSessionMessagesStmt      *sql.Stmt
-	getFileStmt                    *sql.Stmt
-	getFileByPathAndSessionStmt    *sql.Stmt
-	getMessageStmt                 *sql.Stmt
-	getSessionByIDStmt             *sql.Stmt
-	listFilesByPathStmt            *sql.Stmt
-	listFilesBySessionStmt         *sql.Stmt
-	listLatestSessionFilesStmt     *sql.Stmt
-	listMessagesBySessionStmt      *sql.Stmt
-	listNewFilesStmt               *sql.Stmt
-	listSessionsStmt               *sql.Stmt
-	updateMessageStmt              *sql.Stmt
-	updateSessionStmt              *sql.Stmt
-	updateSessionTitleAndUsageStmt *sql.Stmt
+	db                                 DBTX
+	tx                                 *sql.Tx
+	clearDrillPracticeStmt             *sql.Stmt
+	createAnnotationStmt               *sql.Stmt
+	createDailyChallengeStmt           *sql.Stmt
+	createDebugRunStmt                 *sql.Stmt
+	createDrillQuestionStmt            *sql.Stmt
+	createFileStmt                     *sql.Stmt
+	createFlashcardStmt                *sql.Stmt
+	createGradeSampleStmt              *sql.Stmt
+	createInterviewTargetStmt          *sql.Stmt
+	createMessageStmt                  *sql.Stmt
+	createPlanItemStmt                 *sql.Stmt
+	createSessionStmt                  *sql.Stmt
+	createSessionPackStmt              *sql.Stmt
+	createUsageRecordStmt              *sql.Stmt
+	deleteAnnotationStmt               *sql.Stmt
+	deleteFileStmt                     *sql.Stmt
+	deleteInterviewTargetStmt          *sql.Stmt
+	deleteMessageStmt                  *sql.Stmt
+	deleteSessionStmt                  *sql.Stmt
+	deleteSessionFilesStmt             *sql.Stmt
+	deleteSessionMessagesStmt          *sql.Stmt
+	deleteUnstartedPlanItemsStmt       *sql.Stmt
+	getAnnotationStmt                  *sql.Stmt
+	getDailyChallengeStmt              *sql.Stmt
+	getDebugRunBySessionStmt           *sql.Stmt
+	getDrillQuestionStmt               *sql.Stmt
+	getFileStmt                        *sql.Stmt
+	getFileByPathAndSessionStmt        *sql.Stmt
+	getFirstDrillQuestionStmt          *sql.Stmt
+	getFlashcardStmt                   *sql.Stmt
+	getMessageStmt                     *sql.Stmt
+	getOpenDrillQuestionStmt           *sql.Stmt
+	getSessionByIDStmt                 *sql.Stmt
+	getSessionPackStmt                 *sql.Stmt
+	getUsageCostSinceStmt              *sql.Stmt
+	importAnnotationStmt               *sql.Stmt
+	importMessageStmt                  *sql.Stmt
+	importSessionStmt                  *sql.Stmt
+	listAnnotationsBySessionStmt       *sql.Stmt
+	listClassifiedDrillQuestionsStmt   *sql.Stmt
+	listDailyChallengesStmt            *sql.Stmt
+	listDrillBankStmt                  *sql.Stmt
+	listDrillTopicStatsStmt            *sql.Stmt
+	listDueDrillQuestionsStmt          *sql.Stmt
+	listDueFlashcardsStmt              *sql.Stmt
+	listFilesByPathStmt                *sql.Stmt
+	listFilesBySessionStmt             *sql.Stmt
+	listFlaggedDrillQuestionsStmt      *sql.Stmt
+	listFlashcardsStmt                 *sql.Stmt
+	listGradeSamplesStmt               *sql.Stmt
+	listInterviewTargetsStmt           *sql.Stmt
+	listLatestSessionFilesStmt         *sql.Stmt
+	listMessagesBySessionStmt          *sql.Stmt
+	listNewFilesStmt                   *sql.Stmt
+	listPlanItemsStmt                  *sql.Stmt
+	listSessionsStmt                   *sql.Stmt
+	listUnclassifiedDrillQuestionsStmt *sql.Stmt
+	listUsageByDayStmt                 *sql.Stmt
+	listUsageByModeStmt                *sql.Stmt
+	listUsageByModelStmt               *sql.Stmt
+	setDailyChallengeQuestionStmt      *sql.Stmt
+	setDailyChallengeSessionStmt       *sql.Stmt
+	setDrillQuestionJudgementStmt      *sql.Stmt
+	setDrillQuestionNodeStmt           *sql.Stmt
+	setPlanItemSessionStmt             *sql.Stmt
+	setSessionReviewedStmt             *sql.Stmt
+	skipOpenDrillQuestionsStmt         *sql.Stmt
+	updateAnnotationStmt               *sql.Stmt
+	updateDebugRunCheckStmt            *sql.Stmt
+	updateDrillQuestionStmt            *sql.Stmt
+	updateFlashcardReviewStmt          *sql.Stmt
+	updateMessageStmt                  *sql.Stmt
+	updateSessionStmt                  *sql.Stmt
+	updateSessionTitleAndUsageStmt     *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                             tx,
-		tx:                             tx,
-		createFileStmt:                 q.createFileStmt,
-		createMessageStmt:              q.createMessageStmt,
-		createSessionStmt:              q.createSessionStmt,
-		deleteFileStmt:                 q.deleteFileStmt,
-		deleteMessageStmt:              q.deleteMessageStmt,
-		deleteSessionStmt:              q.deleteSessionStmt,
-		deleteSessionFilesStmt:         q.deleteSessionFilesStmt,
-		deleteSessionMessagesStmt:      q.deleteSessionMessagesStmt,
-		getFileStmt:                    q.getFileStmt,
-		getFileByPathAndSessionStmt:    q.getFileByPathAndSessionStmt,
-		getMessageStmt:                 q.getMessageStmt,
-		getSessionByIDStmt:             q.getSessionByIDStmt,
-		listFilesByPathStmt:            q.listFilesByPathStmt,
-		listFilesBySessionStmt:         q.listFilesBySessionStmt,
-		listLatestSessionFilesStmt:     q.listLatestSessionFilesStmt,
-		listMessagesBySessionStmt:      q.listMessagesBySessionStmt,
-		listNewFilesStmt:               q.listNewFilesStmt,
-		listSessionsStmt:               q.listSessionsStmt,
-		updateMessageStmt:              q.updateMessageStmt,
-		updateSessionStmt:              q.updateSessionStmt,
-		updateSessionTitleAndUsageStmt: q.updateSessionTitleAndUsageStmt,
+		db:                                 tx,
+		tx:                                 tx,
+		clearDrillPracticeStmt:             q.clearDrillPracticeStmt,
+		createAnnotationStmt:               q.createAnnotationStmt,
+		createDailyChallengeStmt:           q.createDailyChallengeStmt,
+		createDebugRunStmt:                 q.createDebugRunStmt,
+		createDrillQuestionStmt:            q.createDrillQuestionStmt,
+		createFileStmt:                     q.createFileStmt,
+		createFlashcardStmt:                q.createFlashcardStmt,
+		createGradeSampleStmt:              q.createGradeSampleStmt,
+		createInterviewTargetStmt:          q.createInterviewTargetStmt,
+		createMessageStmt:                  q.createMessageStmt,
+		createPlanItemStmt:                 q.createPlanItemStmt,
+		createSessionStmt:                  q.createSessionStmt,
+		createSessionPackStmt:              q.createSessionPackStmt,
+		createUsageRecordStmt:              q.createUsageRecordStmt,
+		deleteAnnotationStmt:               q.deleteAnnotationStmt,
+		deleteFileStmt:                     q.deleteFileStmt,
+		deleteInterviewTargetStmt:          q.deleteInterviewTargetStmt,
+		deleteMessageStmt:                  q.deleteMessageStmt,
+		deleteSessionStmt:                  q.deleteSessionStmt,
+		deleteSessionFilesStmt:             q.deleteSessionFilesStmt,
+		deleteSessionMessagesStmt:          q.deleteSessionMessagesStmt,
+		deleteUnstartedPlanItemsStmt:       q.deleteUnstartedPlanItemsStmt,
+		getAnnotationStmt:                  q.getAnnotationStmt,
+		getDailyChallengeStmt:              q.getDailyChallengeStmt,
+		getDebugRunBySessionStmt:           q.getDebugRunBySessionStmt,
+		getDrillQuestionStmt:               q.getDrillQuestionStmt,
+		getFileStmt:                        q.getFileStmt,
+		getFileByPathAndSessionStmt:        q.getFileByPathAndSessionStmt,
+		getFirstDrillQuestionStmt:          q.getFirstDrillQuestionStmt,
+		getFlashcardStmt:                   q.getFlashcardStmt,
+		getMessageStmt:                     q.getMessageStmt,
+		getOpenDrillQuestionStmt:           q.getOpenDrillQuestionStmt,
+		getSessionByIDStmt:                 q.getSessionByIDStmt,
+		getSessionPackStmt:                 q.getSessionPackStmt,
+		getUsageCostSinceStmt:              q.getUsageCostSinceStmt,
+		importAnnotationStmt:               q.importAnnotationStmt,
+		importMessageStmt:                  q.importMessageStmt,
+		importSessionStmt:                  q.importSessionStmt,
+		listAnnotationsBySessionStmt:       q.listAnnotationsBySessionStmt,
+		listClassifiedDrillQuestionsStmt:   q.listClassifiedDrillQuestionsStmt,
+		listDailyChallengesStmt:            q.listDailyChallengesStmt,
+		listDrillBankStmt:                  q.listDrillBankStmt,
+		listDrillTopicStatsStmt:            q.listDrillTopicStatsStmt,
+		listDueDrillQuestionsStmt:          q.listDueDrillQuestionsStmt,
+		listDueFlashcardsStmt:              q.listDueFlashcardsStmt,
+		listFilesByPathStmt:                q.listFilesByPathStmt,
+		listFilesBySessionStmt:             q.listFilesBySessionStmt,
+		listFlaggedDrillQuestionsStmt:      q.listFlaggedDrillQuestionsStmt,
+		listFlashcardsStmt:                 q.listFlashcardsStmt,
+		listGradeSamplesStmt:               q.listGradeSamplesStmt,
+		listInterviewTargetsStmt:           q.listInterviewTargetsStmt,
+		listLatestSessionFilesStmt:         q.listLatestSessionFilesStmt,
+		listMessagesBySessionStmt:          q.listMessagesBySessionStmt,
+		listNewFilesStmt:                   q.listNewFilesStmt,
+		listPlanItemsStmt:                  q.listPlanItemsStmt,
+		listSessionsStmt:                   q.listSessionsStmt,
+		listUnclassifiedDrillQuestionsStmt: q.listUnclassifiedDrillQuestionsStmt,
+		listUsageByDayStmt:                 q.listUsageByDayStmt,
+		listUsageByModeStmt:                q.listUsageByModeStmt,
+		listUsageByModelStmt:               q.listUsageByModelStmt,
+		setDailyChallengeQuestionStmt:      q.setDailyChallengeQuestionStmt,
+		setDailyChallengeSessionStmt:       q.setDailyChallengeSessionStmt,
+		setDrillQuestionJudgementStmt:      q.setDrillQuestionJudgementStmt,
+		setDrillQuestionNodeStmt:           q.setDrillQuestionNodeStmt,
+		setPlanItemSessionStmt:             q.setPlanItemSessionStmt,
+		setSessionReviewedStmt:             q.setSessionReviewedStmt,
+		skipOpenDrillQuestionsStmt:         q.skipOpenDrillQuestionsStmt,
+		updateAnnotationStmt:               q.updateAnnotationStmt,
+		updateDebugRunCheckStmt:            q.updateDebugRunCheckStmt,
+		updateDrillQuestionStmt:            q.updateDrillQuestionStmt,
+		updateFlashcardReviewStmt:          q.updateFlashcardReviewStmt,
+		updateMessageStmt:                  q.updateMessageStmt,
+		updateSessionStmt:                  q.updateSessionStmt,
+		updateSessionTitleAndUsageStmt:     q.updateSessionTitleAndUsageStmt,
 	}
 }
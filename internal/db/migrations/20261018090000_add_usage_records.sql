@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Usage records keep a per-request ledger of spend. They intentionally do not
+-- reference sessions so deleting a session does not erase budget history.
+CREATE TABLE IF NOT EXISTS usage_records (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    mode TEXT,
+    provider TEXT NOT NULL,
+    model TEXT NOT NULL,
+    prompt_tokens INTEGER NOT NULL DEFAULT 0 CHECK (prompt_tokens >= 0),
+    completion_tokens INTEGER NOT NULL DEFAULT 0 CHECK (completion_tokens >= 0),
+    cost REAL NOT NULL DEFAULT 0.0 CHECK (cost >= 0.0),
+    created_at INTEGER NOT NULL  -- Unix timestamp in seconds
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_records_created_at ON usage_records (created_at);
+CREATE INDEX IF NOT EXISTS idx_usage_records_session_id ON usage_records (session_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_usage_records_session_id;
+DROP INDEX IF EXISTS idx_usage_records_created_at;
+DROP TABLE IF EXISTS usage_records;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Drill questions record every question asked in a gym session along with
+-- its hint ladder and score. Like usage records they do not reference
+-- sessions, so progress survives deleting a session.
+CREATE TABLE IF NOT EXISTS drill_questions (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    question TEXT NOT NULL,
+    hints TEXT NOT NULL,  -- JSON array: nudge, approach, partial solution
+    answer TEXT NOT NULL,
+    hints_used INTEGER NOT NULL DEFAULT 0 CHECK (hints_used >= 0),
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'answered', 'gave_up', 'skipped')),
+    raw_score INTEGER,
+    score INTEGER,
+    practice_at INTEGER,  -- Unix timestamp in seconds when the topic is due again
+    created_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    updated_at INTEGER NOT NULL   -- Unix timestamp in seconds
+);
+
+CREATE INDEX IF NOT EXISTS idx_drill_questions_session_id ON drill_questions (session_id);
+CREATE INDEX IF NOT EXISTS idx_drill_questions_practice_at ON drill_questions (practice_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_drill_questions_practice_at;
+DROP INDEX IF EXISTS idx_drill_questions_session_id;
+DROP TABLE IF EXISTS drill_questions;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- How the graded answer was composed, as recorded by the editor. Durations
+-- are in milliseconds; all three are NULL when no timing was recorded.
+ALTER TABLE drill_questions ADD COLUMN think_ms INTEGER;
+ALTER TABLE drill_questions ADD COLUMN compose_ms INTEGER;
+ALTER TABLE drill_questions ADD COLUMN pastes INTEGER;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE drill_questions DROP COLUMN pastes;
+ALTER TABLE drill_questions DROP COLUMN compose_ms;
+ALTER TABLE drill_questions DROP COLUMN think_ms;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Flashcards are extracted from the corrections in graded answers and
+-- reviewed with spaced repetition. Like drill questions they do not reference
+-- sessions, so cards survive deleting the session they came from.
+CREATE TABLE IF NOT EXISTS flashcards (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    message_id TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    front TEXT NOT NULL,
+    back TEXT NOT NULL,
+    interval_days INTEGER NOT NULL DEFAULT 0 CHECK (interval_days >= 0),
+    ease INTEGER NOT NULL DEFAULT 2500,  -- Interval multiplier in thousandths
+    reps INTEGER NOT NULL DEFAULT 0,
+    lapses INTEGER NOT NULL DEFAULT 0,
+    due_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    reviewed_at INTEGER,  -- Unix timestamp in seconds
+    created_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    updated_at INTEGER NOT NULL   -- Unix timestamp in seconds
+);
+
+CREATE INDEX IF NOT EXISTS idx_flashcards_due_at ON flashcards (due_at);
+CREATE INDEX IF NOT EXISTS idx_flashcards_session_id ON flashcards (session_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_flashcards_session_id;
+DROP INDEX IF EXISTS idx_flashcards_due_at;
+DROP TABLE IF EXISTS flashcards;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A debug run ties a debugging session to the scratch workspace its exercise
+-- was copied into. The workspace is only meaningful for that session, so the
+-- run goes away with it.
+CREATE TABLE IF NOT EXISTS debug_runs (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL UNIQUE,
+    exercise TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    workspace TEXT NOT NULL,
+    check_command TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'fixed')),
+    checks INTEGER NOT NULL DEFAULT 0 CHECK (checks >= 0),
+    fixed_at INTEGER,  -- Unix timestamp in seconds
+    created_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    updated_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    FOREIGN KEY (session_id) REFERENCES sessions (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS debug_runs;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The tool call that wrote a version, so its change can be reverted. Empty for
+-- the snapshots taken of a file before a tool changes it, and for restores.
+ALTER TABLE files ADD COLUMN tool_call_id TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_files_tool_call_id ON files (tool_call_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_files_tool_call_id;
+ALTER TABLE files DROP COLUMN tool_call_id;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A daily challenge is the question of the day. It is picked once and kept,
+-- so the day's question doesn't change as the question bank grows. Picks from
+-- the bank copy the question; generated ones are filled in from the first
+-- question asked in the challenge's session.
+CREATE TABLE IF NOT EXISTS daily_challenges (
+    day TEXT PRIMARY KEY,  -- Local date, YYYY-MM-DD
+    topic TEXT NOT NULL DEFAULT '',
+    question_id TEXT NOT NULL DEFAULT '',  -- The bank question picked, empty when generated
+    question TEXT NOT NULL DEFAULT '',
+    hints TEXT NOT NULL DEFAULT '[]',  -- JSON array of strings
+    answer TEXT NOT NULL DEFAULT '',
+    session_id TEXT NOT NULL DEFAULT '',  -- The gym session answering it, once started
+    created_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    updated_at INTEGER NOT NULL  -- Unix timestamp in seconds
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS daily_challenges;
+-- +goose StatementEnd
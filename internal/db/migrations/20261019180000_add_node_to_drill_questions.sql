@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The node of the topic taxonomy a graded question is filed under, as a path
+-- like "Go > Concurrency > Channels". Empty until the question is classified.
+ALTER TABLE drill_questions ADD COLUMN node TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE drill_questions DROP COLUMN node;
+-- +goose StatementEnd
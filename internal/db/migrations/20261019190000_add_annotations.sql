@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- An annotation is a reviewer's comment on a message of a transcript,
+-- optionally on a range of its text. Annotations travel with exported
+-- sessions, so their IDs are kept on import.
+CREATE TABLE IF NOT EXISTS annotations (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    message_id TEXT NOT NULL,
+    author TEXT NOT NULL,
+    body TEXT NOT NULL,
+    range_start INTEGER,  -- Byte offset into the message text, NULL for the whole message
+    range_end INTEGER,
+    created_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    updated_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    FOREIGN KEY (session_id) REFERENCES sessions (id) ON DELETE CASCADE,
+    FOREIGN KEY (message_id) REFERENCES messages (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_annotations_session_id ON annotations (session_id);
+
+ALTER TABLE sessions ADD COLUMN reviewed_by TEXT;
+ALTER TABLE sessions ADD COLUMN reviewed_at INTEGER;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE sessions DROP COLUMN reviewed_at;
+ALTER TABLE sessions DROP COLUMN reviewed_by;
+DROP TABLE IF EXISTS annotations;
+-- +goose StatementEnd
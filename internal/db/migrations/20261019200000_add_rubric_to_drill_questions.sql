@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The rubric a question is graded against and the version of it the score
+-- was given with, so scores are only compared with ones graded alike, and the
+-- per-dimension grades as JSON. Empty and zero for questions graded with a
+-- single score.
+ALTER TABLE drill_questions ADD COLUMN rubric TEXT NOT NULL DEFAULT '';
+ALTER TABLE drill_questions ADD COLUMN rubric_version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE drill_questions ADD COLUMN dimensions TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE drill_questions DROP COLUMN dimensions;
+ALTER TABLE drill_questions DROP COLUMN rubric_version;
+ALTER TABLE drill_questions DROP COLUMN rubric;
+-- +goose StatementEnd
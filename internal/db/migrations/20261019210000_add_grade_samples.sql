@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A grade sample is the output of one independent grader call on a drill
+-- answer, kept so a judged grade can be audited.
+CREATE TABLE IF NOT EXISTS grade_samples (
+    id TEXT PRIMARY KEY,
+    question_id TEXT NOT NULL,
+    model TEXT NOT NULL,
+    score INTEGER,  -- 0-100, NULL if the call failed or its output was unusable
+    dimensions TEXT NOT NULL DEFAULT '',  -- JSON array of rubric dimension grades
+    rationale TEXT NOT NULL DEFAULT '',
+    raw TEXT NOT NULL DEFAULT '',  -- The grader's reply as received
+    error TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    FOREIGN KEY (question_id) REFERENCES drill_questions (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_grade_samples_question_id ON grade_samples (question_id);
+
+ALTER TABLE drill_questions ADD COLUMN agreement REAL;
+ALTER TABLE drill_questions ADD COLUMN flagged INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE drill_questions DROP COLUMN flagged;
+ALTER TABLE drill_questions DROP COLUMN agreement;
+DROP TABLE IF EXISTS grade_samples;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- An interview target is an upcoming interview the practice plan works
+-- towards.
+CREATE TABLE IF NOT EXISTS interview_targets (
+    id TEXT PRIMARY KEY,
+    company TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT '',
+    day TEXT NOT NULL,  -- Local date of the interview, YYYY-MM-DD
+    topics TEXT NOT NULL DEFAULT '[]',  -- JSON array of topics the interview is known to cover
+    created_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    updated_at INTEGER NOT NULL  -- Unix timestamp in seconds
+);
+
+-- A plan item is a practice session scheduled for a day of the plan. It is
+-- done once its session has graded as many questions as planned.
+CREATE TABLE IF NOT EXISTS plan_items (
+    id TEXT PRIMARY KEY,
+    target_id TEXT NOT NULL,
+    day TEXT NOT NULL,  -- Local date, YYYY-MM-DD
+    position INTEGER NOT NULL,  -- Order within the day
+    mode TEXT NOT NULL,
+    topic TEXT NOT NULL DEFAULT '',
+    minutes INTEGER NOT NULL,
+    questions INTEGER NOT NULL,
+    session_id TEXT NOT NULL DEFAULT '',  -- The session working on it, once started
+    created_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    updated_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    FOREIGN KEY (target_id) REFERENCES interview_targets (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_plan_items_day ON plan_items (day);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS plan_items;
+DROP TABLE IF EXISTS interview_targets;
+-- +goose StatementEnd
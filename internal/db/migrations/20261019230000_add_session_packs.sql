@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A session pack ties a session to the interview pack round it practices,
+-- so the pack's persona, questions and rubric weights apply to every
+-- request in it. The pack itself lives on disk and is looked up by name.
+CREATE TABLE IF NOT EXISTS session_packs (
+    session_id TEXT PRIMARY KEY,
+    pack TEXT NOT NULL,
+    round INTEGER NOT NULL CHECK (round >= 1),
+    created_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    FOREIGN KEY (session_id) REFERENCES sessions (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS session_packs;
+-- +goose StatementEnd
@@ -8,12 +8,115 @@ import (
 	"database/sql"
 )
 
+type Annotation struct {
+	ID         string        `json:"id"`
+	SessionID  string        `json:"session_id"`
+	MessageID  string        `json:"message_id"`
+	Author     string        `json:"author"`
+	Body       string        `json:"body"`
+	RangeStart sql.NullInt64 `json:"range_start"`
+	RangeEnd   sql.NullInt64 `json:"range_end"`
+	CreatedAt  int64         `json:"created_at"`
+	UpdatedAt  int64         `json:"updated_at"`
+}
+
+type DailyChallenge struct {
+	Day        string `json:"day"`
+	Topic      string `json:"topic"`
+	QuestionID string `json:"question_id"`
+	Question   string `json:"question"`
+	Hints      string `json:"hints"`
+	Answer     string `json:"answer"`
+	SessionID  string `json:"session_id"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+}
+
+type DebugRun struct {
+	ID           string        `json:"id"`
+	SessionID    string        `json:"session_id"`
+	Exercise     string        `json:"exercise"`
+	Topic        string        `json:"topic"`
+	Workspace    string        `json:"workspace"`
+	CheckCommand string        `json:"check_command"`
+	Status       string        `json:"status"`
+	Checks       int64         `json:"checks"`
+	FixedAt      sql.NullInt64 `json:"fixed_at"`
+	CreatedAt    int64         `json:"created_at"`
+	UpdatedAt    int64         `json:"updated_at"`
+}
+
+type DrillQuestion struct {
+	ID            string          `json:"id"`
+	SessionID     string          `json:"session_id"`
+	Topic         string          `json:"topic"`
+	Question      string          `json:"question"`
+	Hints         string          `json:"hints"`
+	Answer        string          `json:"answer"`
+	HintsUsed     int64           `json:"hints_used"`
+	Status        string          `json:"status"`
+	RawScore      sql.NullInt64   `json:"raw_score"`
+	Score         sql.NullInt64   `json:"score"`
+	PracticeAt    sql.NullInt64   `json:"practice_at"`
+	CreatedAt     int64           `json:"created_at"`
+	UpdatedAt     int64           `json:"updated_at"`
+	ThinkMs       sql.NullInt64   `json:"think_ms"`
+	ComposeMs     sql.NullInt64   `json:"compose_ms"`
+	Pastes        sql.NullInt64   `json:"pastes"`
+	Node          string          `json:"node"`
+	Rubric        string          `json:"rubric"`
+	RubricVersion int64           `json:"rubric_version"`
+	Dimensions    string          `json:"dimensions"`
+	Agreement     sql.NullFloat64 `json:"agreement"`
+	Flagged       int64           `json:"flagged"`
+}
+
+type Flashcard struct {
+	ID           string        `json:"id"`
+	SessionID    string        `json:"session_id"`
+	MessageID    string        `json:"message_id"`
+	Topic        string        `json:"topic"`
+	Front        string        `json:"front"`
+	Back         string        `json:"back"`
+	IntervalDays int64         `json:"interval_days"`
+	Ease         int64         `json:"ease"`
+	Reps         int64         `json:"reps"`
+	Lapses       int64         `json:"lapses"`
+	DueAt        int64         `json:"due_at"`
+	ReviewedAt   sql.NullInt64 `json:"reviewed_at"`
+	CreatedAt    int64         `json:"created_at"`
+	UpdatedAt    int64         `json:"updated_at"`
+}
+
 type File struct {
+	ID         string `json:"id"`
+	SessionID  string `json:"session_id"`
+	Path       string `json:"path"`
+	Content    string `json:"content"`
+	Version    int64  `json:"version"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+	ToolCallID string `json:"tool_call_id"`
+}
+
+type GradeSample struct {
+	ID         string        `json:"id"`
+	QuestionID string        `json:"question_id"`
+	Model      string        `json:"model"`
+	Score      sql.NullInt64 `json:"score"`
+	Dimensions string        `json:"dimensions"`
+	Rationale  string        `json:"rationale"`
+	Raw        string        `json:"raw"`
+	Error      string        `json:"error"`
+	CreatedAt  int64         `json:"created_at"`
+}
+
+type InterviewTarget struct {
 	ID        string `json:"id"`
-	SessionID string `json:"session_id"`
-	Path      string `json:"path"`
-	Content   string `json:"content"`
-	Version   int64  `json:"version"`
+	Company   string `json:"company"`
+	Role      string `json:"role"`
+	Day       string `json:"day"`
+	Topics    string `json:"topics"`
 	CreatedAt int64  `json:"created_at"`
 	UpdatedAt int64  `json:"updated_at"`
 }
@@ -31,6 +134,20 @@ type Message struct {
 	IsSummaryMessage int64          `json:"is_summary_message"`
 }
 
+type PlanItem struct {
+	ID        string `json:"id"`
+	TargetID  string `json:"target_id"`
+	Day       string `json:"day"`
+	Position  int64  `json:"position"`
+	Mode      string `json:"mode"`
+	Topic     string `json:"topic"`
+	Minutes   int64  `json:"minutes"`
+	Questions int64  `json:"questions"`
+	SessionID string `json:"session_id"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
 type Session struct {
 	ID               string         `json:"id"`
 	ParentSessionID  sql.NullString `json:"parent_session_id"`
@@ -44,4 +161,26 @@ type Session struct {
 	SummaryMessageID sql.NullString `json:"summary_message_id"`
 	Todos            sql.NullString `json:"todos"`
 	Mode             sql.NullString `json:"mode"`
+	Skills           sql.NullString `json:"skills"`
+	ReviewedBy       sql.NullString `json:"reviewed_by"`
+	ReviewedAt       sql.NullInt64  `json:"reviewed_at"`
+}
+
+type SessionPack struct {
+	SessionID string `json:"session_id"`
+	Pack      string `json:"pack"`
+	Round     int64  `json:"round"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type UsageRecord struct {
+	ID               string         `json:"id"`
+	SessionID        string         `json:"session_id"`
+	Mode             sql.NullString `json:"mode"`
+	Provider         string         `json:"provider"`
+	Model            string         `json:"model"`
+	PromptTokens     int64          `json:"prompt_tokens"`
+	CompletionTokens int64          `json:"completion_tokens"`
+	Cost             float64        `json:"cost"`
+	CreatedAt        int64          `json:"created_at"`
 }
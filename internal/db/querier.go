@@ -6,27 +6,81 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 type Querier interface {
+	ClearDrillPractice(ctx context.Context, topic string) error
+	CreateAnnotation(ctx context.Context, arg CreateAnnotationParams) (Annotation, error)
+	CreateDailyChallenge(ctx context.Context, arg CreateDailyChallengeParams) error
+	CreateDebugRun(ctx context.Context, arg CreateDebugRunParams) (DebugRun, error)
+	CreateDrillQuestion(ctx context.Context, arg CreateDrillQuestionParams) (DrillQuestion, error)
 	CreateFile(ctx context.Context, arg CreateFileParams) (File, error)
+	CreateFlashcard(ctx context.Context, arg CreateFlashcardParams) (Flashcard, error)
+	CreateGradeSample(ctx context.Context, arg CreateGradeSampleParams) (GradeSample, error)
+	CreateInterviewTarget(ctx context.Context, arg CreateInterviewTargetParams) (InterviewTarget, error)
 	CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error)
+	CreatePlanItem(ctx context.Context, arg CreatePlanItemParams) error
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateSessionPack(ctx context.Context, arg CreateSessionPackParams) (SessionPack, error)
+	CreateUsageRecord(ctx context.Context, arg CreateUsageRecordParams) (UsageRecord, error)
+	DeleteAnnotation(ctx context.Context, id string) error
 	DeleteFile(ctx context.Context, id string) error
+	DeleteInterviewTarget(ctx context.Context, id string) error
 	DeleteMessage(ctx context.Context, id string) error
 	DeleteSession(ctx context.Context, id string) error
 	DeleteSessionFiles(ctx context.Context, sessionID string) error
 	DeleteSessionMessages(ctx context.Context, sessionID string) error
+	DeleteUnstartedPlanItems(ctx context.Context, day string) error
+	GetAnnotation(ctx context.Context, id string) (Annotation, error)
+	GetDailyChallenge(ctx context.Context, day string) (GetDailyChallengeRow, error)
+	GetDebugRunBySession(ctx context.Context, sessionID string) (DebugRun, error)
+	GetDrillQuestion(ctx context.Context, id string) (DrillQuestion, error)
 	GetFile(ctx context.Context, id string) (File, error)
 	GetFileByPathAndSession(ctx context.Context, arg GetFileByPathAndSessionParams) (File, error)
+	GetFirstDrillQuestion(ctx context.Context, sessionID string) (DrillQuestion, error)
+	GetFlashcard(ctx context.Context, id string) (Flashcard, error)
 	GetMessage(ctx context.Context, id string) (Message, error)
+	GetOpenDrillQuestion(ctx context.Context, sessionID string) (DrillQuestion, error)
 	GetSessionByID(ctx context.Context, id string) (Session, error)
+	GetSessionPack(ctx context.Context, sessionID string) (SessionPack, error)
+	GetUsageCostSince(ctx context.Context, createdAt int64) (float64, error)
+	ImportAnnotation(ctx context.Context, arg ImportAnnotationParams) error
+	ImportMessage(ctx context.Context, arg ImportMessageParams) error
+	ImportSession(ctx context.Context, arg ImportSessionParams) (Session, error)
+	ListAnnotationsBySession(ctx context.Context, sessionID string) ([]Annotation, error)
+	ListClassifiedDrillQuestions(ctx context.Context) ([]DrillQuestion, error)
+	ListDailyChallenges(ctx context.Context) ([]ListDailyChallengesRow, error)
+	ListDrillBank(ctx context.Context) ([]DrillQuestion, error)
+	ListDrillTopicStats(ctx context.Context, createdAt int64) ([]ListDrillTopicStatsRow, error)
+	ListDueDrillQuestions(ctx context.Context, practiceAt sql.NullInt64) ([]DrillQuestion, error)
+	ListDueFlashcards(ctx context.Context, dueAt int64) ([]Flashcard, error)
 	ListFilesByPath(ctx context.Context, path string) ([]File, error)
 	ListFilesBySession(ctx context.Context, sessionID string) ([]File, error)
+	ListFlaggedDrillQuestions(ctx context.Context) ([]DrillQuestion, error)
+	ListFlashcards(ctx context.Context) ([]Flashcard, error)
+	ListGradeSamples(ctx context.Context, questionID string) ([]GradeSample, error)
+	ListInterviewTargets(ctx context.Context) ([]InterviewTarget, error)
 	ListLatestSessionFiles(ctx context.Context, sessionID string) ([]File, error)
 	ListMessagesBySession(ctx context.Context, sessionID string) ([]Message, error)
 	ListNewFiles(ctx context.Context) ([]File, error)
+	ListPlanItems(ctx context.Context, arg ListPlanItemsParams) ([]ListPlanItemsRow, error)
 	ListSessions(ctx context.Context) ([]Session, error)
+	ListUnclassifiedDrillQuestions(ctx context.Context) ([]DrillQuestion, error)
+	ListUsageByDay(ctx context.Context, createdAt int64) ([]ListUsageByDayRow, error)
+	ListUsageByMode(ctx context.Context, createdAt int64) ([]ListUsageByModeRow, error)
+	ListUsageByModel(ctx context.Context, createdAt int64) ([]ListUsageByModelRow, error)
+	SetDailyChallengeQuestion(ctx context.Context, arg SetDailyChallengeQuestionParams) error
+	SetDailyChallengeSession(ctx context.Context, arg SetDailyChallengeSessionParams) error
+	SetDrillQuestionJudgement(ctx context.Context, arg SetDrillQuestionJudgementParams) (DrillQuestion, error)
+	SetDrillQuestionNode(ctx context.Context, arg SetDrillQuestionNodeParams) (DrillQuestion, error)
+	SetPlanItemSession(ctx context.Context, arg SetPlanItemSessionParams) error
+	SetSessionReviewed(ctx context.Context, arg SetSessionReviewedParams) (Session, error)
+	SkipOpenDrillQuestions(ctx context.Context, sessionID string) error
+	UpdateAnnotation(ctx context.Context, arg UpdateAnnotationParams) (Annotation, error)
+	UpdateDebugRunCheck(ctx context.Context, arg UpdateDebugRunCheckParams) (DebugRun, error)
+	UpdateDrillQuestion(ctx context.Context, arg UpdateDrillQuestionParams) (DrillQuestion, error)
+	UpdateFlashcardReview(ctx context.Context, arg UpdateFlashcardReviewParams) (Flashcard, error)
 	UpdateMessage(ctx context.Context, arg UpdateMessageParams) error
 	UpdateSession(ctx context.Context, arg UpdateSessionParams) (Session, error)
 	UpdateSessionTitleAndUsage(ctx context.Context, arg UpdateSessionTitleAndUsageParams) error
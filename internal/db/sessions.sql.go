@@ -35,7 +35,7 @@ INSERT INTO sessions (
     ?,
     strftime('%s', 'now'),
     strftime('%s', 'now')
-) RETURNING id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode
+) RETURNING id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode, skills, reviewed_by, reviewed_at
 `
 
 type CreateSessionParams struct {
@@ -74,6 +74,9 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.SummaryMessageID,
 		&i.Todos,
 		&i.Mode,
+		&i.Skills,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
 	)
 	return i, err
 }
@@ -89,7 +92,7 @@ func (q *Queries) DeleteSession(ctx context.Context, id string) error {
 }
 
 const getSessionByID = `-- name: GetSessionByID :one
-SELECT id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode
+SELECT id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode, skills, reviewed_by, reviewed_at
 FROM sessions
 WHERE id = ? LIMIT 1
 `
@@ -110,12 +113,80 @@ func (q *Queries) GetSessionByID(ctx context.Context, id string) (Session, error
 		&i.SummaryMessageID,
 		&i.Todos,
 		&i.Mode,
+		&i.Skills,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const importSession = `-- name: ImportSession :one
+INSERT INTO sessions (
+    id,
+    title,
+    message_count,
+    prompt_tokens,
+    completion_tokens,
+    cost,
+    mode,
+    reviewed_by,
+    reviewed_at,
+    updated_at,
+    created_at
+) VALUES (
+    ?, ?, 0, ?, ?, ?, ?, ?, ?, ?, ?
+) RETURNING id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode, skills, reviewed_by, reviewed_at
+`
+
+type ImportSessionParams struct {
+	ID               string         `json:"id"`
+	Title            string         `json:"title"`
+	PromptTokens     int64          `json:"prompt_tokens"`
+	CompletionTokens int64          `json:"completion_tokens"`
+	Cost             float64        `json:"cost"`
+	Mode             sql.NullString `json:"mode"`
+	ReviewedBy       sql.NullString `json:"reviewed_by"`
+	ReviewedAt       sql.NullInt64  `json:"reviewed_at"`
+	UpdatedAt        int64          `json:"updated_at"`
+	CreatedAt        int64          `json:"created_at"`
+}
+
+func (q *Queries) ImportSession(ctx context.Context, arg ImportSessionParams) (Session, error) {
+	row := q.queryRow(ctx, q.importSessionStmt, importSession,
+		arg.ID,
+		arg.Title,
+		arg.PromptTokens,
+		arg.CompletionTokens,
+		arg.Cost,
+		arg.Mode,
+		arg.ReviewedBy,
+		arg.ReviewedAt,
+		arg.UpdatedAt,
+		arg.CreatedAt,
+	)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.ParentSessionID,
+		&i.Title,
+		&i.MessageCount,
+		&i.PromptTokens,
+		&i.CompletionTokens,
+		&i.Cost,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.SummaryMessageID,
+		&i.Todos,
+		&i.Mode,
+		&i.Skills,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
 	)
 	return i, err
 }
 
 const listSessions = `-- name: ListSessions :many
-SELECT id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode
+SELECT id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode, skills, reviewed_by, reviewed_at
 FROM sessions
 WHERE parent_session_id is NULL
 ORDER BY updated_at DESC
@@ -143,6 +214,9 @@ func (q *Queries) ListSessions(ctx context.Context) ([]Session, error) {
 			&i.SummaryMessageID,
 			&i.Todos,
 			&i.Mode,
+			&i.Skills,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -157,6 +231,44 @@ func (q *Queries) ListSessions(ctx context.Context) ([]Session, error) {
 	return items, nil
 }
 
+const setSessionReviewed = `-- name: SetSessionReviewed :one
+UPDATE sessions
+SET
+    reviewed_by = ?,
+    reviewed_at = ?
+WHERE id = ?
+RETURNING id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode, skills, reviewed_by, reviewed_at
+`
+
+type SetSessionReviewedParams struct {
+	ReviewedBy sql.NullString `json:"reviewed_by"`
+	ReviewedAt sql.NullInt64  `json:"reviewed_at"`
+	ID         string         `json:"id"`
+}
+
+func (q *Queries) SetSessionReviewed(ctx context.Context, arg SetSessionReviewedParams) (Session, error) {
+	row := q.queryRow(ctx, q.setSessionReviewedStmt, setSessionReviewed, arg.ReviewedBy, arg.ReviewedAt, arg.ID)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.ParentSessionID,
+		&i.Title,
+		&i.MessageCount,
+		&i.PromptTokens,
+		&i.CompletionTokens,
+		&i.Cost,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.SummaryMessageID,
+		&i.Todos,
+		&i.Mode,
+		&i.Skills,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
 const updateSession = `-- name: UpdateSession :one
 UPDATE sessions
 SET
@@ -166,9 +278,10 @@ SET
     summary_message_id = ?,
     cost = ?,
     todos = ?,
-    mode = ?
+    mode = ?,
+    skills = ?
 WHERE id = ?
-RETURNING id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode
+RETURNING id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode, skills, reviewed_by, reviewed_at
 `
 
 type UpdateSessionParams struct {
@@ -179,6 +292,7 @@ type UpdateSessionParams struct {
 	Cost             float64        `json:"cost"`
 	Todos            sql.NullString `json:"todos"`
 	Mode             sql.NullString `json:"mode"`
+	Skills           sql.NullString `json:"skills"`
 	ID               string         `json:"id"`
 }
 
@@ -191,6 +305,7 @@ func (q *Queries) UpdateSession(ctx context.Context, arg UpdateSessionParams) (S
 		arg.Cost,
 		arg.Todos,
 		arg.Mode,
+		arg.Skills,
 		arg.ID,
 	)
 	var i Session
@@ -207,6 +322,9 @@ func (q *Queries) UpdateSession(ctx context.Context, arg UpdateSessionParams) (S
 		&i.SummaryMessageID,
 		&i.Todos,
 		&i.Mode,
+		&i.Skills,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
 	)
 	return i, err
 }
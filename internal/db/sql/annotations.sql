@@ -0,0 +1,60 @@
+-- name: CreateAnnotation :one
+INSERT INTO annotations (
+    id,
+    session_id,
+    message_id,
+    author,
+    body,
+    range_start,
+    range_end,
+    created_at,
+    updated_at
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING *;
+
+-- name: ImportAnnotation :exec
+-- Keeps the most recently edited version of an annotation already there.
+INSERT INTO annotations (
+    id,
+    session_id,
+    message_id,
+    author,
+    body,
+    range_start,
+    range_end,
+    created_at,
+    updated_at
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
+ON CONFLICT (id) DO UPDATE SET
+    body = excluded.body,
+    range_start = excluded.range_start,
+    range_end = excluded.range_end,
+    updated_at = excluded.updated_at
+WHERE excluded.updated_at > annotations.updated_at;
+
+-- name: GetAnnotation :one
+SELECT *
+FROM annotations
+WHERE id = ? LIMIT 1;
+
+-- name: ListAnnotationsBySession :many
+SELECT *
+FROM annotations
+WHERE session_id = ?
+ORDER BY created_at ASC, id ASC;
+
+-- name: UpdateAnnotation :one
+UPDATE annotations
+SET
+    body = ?,
+    updated_at = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteAnnotation :exec
+DELETE FROM annotations
+WHERE id = ?;
@@ -0,0 +1,52 @@
+-- name: CreateDailyChallenge :exec
+INSERT OR IGNORE INTO daily_challenges (
+    day,
+    topic,
+    question_id,
+    question,
+    hints,
+    answer,
+    created_at,
+    updated_at
+) VALUES (
+    ?, ?, ?, ?, ?, ?, strftime('%s', 'now'), strftime('%s', 'now')
+);
+
+-- name: GetDailyChallenge :one
+SELECT
+    d.*,
+    CAST(COALESCE((
+        SELECT MAX(q.score)
+        FROM drill_questions q
+        WHERE q.session_id = d.session_id AND q.status IN ('answered', 'gave_up')
+    ), -1) AS INTEGER) AS score
+FROM daily_challenges d
+WHERE d.day = ?;
+
+-- name: ListDailyChallenges :many
+SELECT
+    d.*,
+    CAST(COALESCE((
+        SELECT MAX(q.score)
+        FROM drill_questions q
+        WHERE q.session_id = d.session_id AND q.status IN ('answered', 'gave_up')
+    ), -1) AS INTEGER) AS score
+FROM daily_challenges d
+ORDER BY d.day DESC;
+
+-- name: SetDailyChallengeSession :exec
+UPDATE daily_challenges
+SET
+    session_id = ?,
+    updated_at = strftime('%s', 'now')
+WHERE day = ?;
+
+-- name: SetDailyChallengeQuestion :exec
+UPDATE daily_challenges
+SET
+    topic = ?,
+    question = ?,
+    hints = ?,
+    answer = ?,
+    updated_at = strftime('%s', 'now')
+WHERE day = ?;
@@ -0,0 +1,29 @@
+-- name: CreateDebugRun :one
+INSERT INTO debug_runs (
+    id,
+    session_id,
+    exercise,
+    topic,
+    workspace,
+    check_command,
+    created_at,
+    updated_at
+) VALUES (
+    ?, ?, ?, ?, ?, ?, strftime('%s', 'now'), strftime('%s', 'now')
+)
+RETURNING *;
+
+-- name: GetDebugRunBySession :one
+SELECT *
+FROM debug_runs
+WHERE session_id = ? LIMIT 1;
+
+-- name: UpdateDebugRunCheck :one
+UPDATE debug_runs
+SET
+    checks = checks + 1,
+    status = ?,
+    fixed_at = ?,
+    updated_at = strftime('%s', 'now')
+WHERE session_id = ?
+RETURNING *;
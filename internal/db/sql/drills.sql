@@ -0,0 +1,128 @@
+-- name: CreateDrillQuestion :one
+INSERT INTO drill_questions (
+    id,
+    session_id,
+    topic,
+    question,
+    hints,
+    answer,
+    rubric,
+    rubric_version,
+    created_at,
+    updated_at
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?, strftime('%s', 'now'), strftime('%s', 'now')
+)
+RETURNING *;
+
+-- name: GetOpenDrillQuestion :one
+SELECT *
+FROM drill_questions
+WHERE session_id = ? AND status = 'open'
+ORDER BY created_at DESC, rowid DESC
+LIMIT 1;
+
+-- name: UpdateDrillQuestion :one
+UPDATE drill_questions
+SET
+    hints_used = ?,
+    status = ?,
+    raw_score = ?,
+    score = ?,
+    practice_at = ?,
+    think_ms = ?,
+    compose_ms = ?,
+    pastes = ?,
+    rubric = ?,
+    rubric_version = ?,
+    dimensions = ?,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?
+RETURNING *;
+
+-- name: SkipOpenDrillQuestions :exec
+UPDATE drill_questions
+SET
+    status = 'skipped',
+    updated_at = strftime('%s', 'now')
+WHERE session_id = ? AND status = 'open';
+
+-- name: ListDueDrillQuestions :many
+SELECT *
+FROM drill_questions
+WHERE practice_at IS NOT NULL AND practice_at <= ?
+ORDER BY practice_at ASC;
+
+-- name: ClearDrillPractice :exec
+UPDATE drill_questions
+SET
+    practice_at = NULL,
+    updated_at = strftime('%s', 'now')
+WHERE topic = ? AND practice_at IS NOT NULL;
+
+-- name: ListDrillTopicStats :many
+SELECT
+    topic,
+    CAST(COUNT(*) AS INTEGER) AS questions,
+    CAST(COALESCE(AVG(score), 0.0) AS REAL) AS avg_score,
+    CAST(COALESCE(AVG(hints_used), 0.0) AS REAL) AS avg_hints,
+    CAST(COALESCE(AVG(think_ms), 0.0) AS REAL) AS avg_think_ms,
+    CAST(COALESCE(AVG(compose_ms), 0.0) AS REAL) AS avg_compose_ms,
+    CAST(COALESCE(SUM(pastes), 0) AS INTEGER) AS pastes
+FROM drill_questions
+WHERE status IN ('answered', 'gave_up') AND created_at >= ?
+GROUP BY topic
+ORDER BY topic ASC;
+
+-- name: ListDrillBank :many
+SELECT *
+FROM drill_questions
+WHERE answer <> ''
+ORDER BY created_at ASC, id ASC;
+
+-- name: GetFirstDrillQuestion :one
+SELECT *
+FROM drill_questions
+WHERE session_id = ?
+ORDER BY created_at ASC, rowid ASC
+LIMIT 1;
+
+-- name: SetDrillQuestionNode :one
+UPDATE drill_questions
+SET
+    node = ?,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?
+RETURNING *;
+
+-- name: ListUnclassifiedDrillQuestions :many
+SELECT *
+FROM drill_questions
+WHERE status IN ('answered', 'gave_up') AND node = ''
+ORDER BY created_at ASC, id ASC;
+
+-- name: ListClassifiedDrillQuestions :many
+SELECT *
+FROM drill_questions
+WHERE status IN ('answered', 'gave_up') AND node <> ''
+ORDER BY created_at DESC, id DESC;
+
+-- name: SetDrillQuestionJudgement :one
+UPDATE drill_questions
+SET
+    agreement = ?,
+    flagged = ?,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?
+RETURNING *;
+
+-- name: ListFlaggedDrillQuestions :many
+SELECT *
+FROM drill_questions
+WHERE flagged = 1
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetDrillQuestion :one
+SELECT *
+FROM drill_questions
+WHERE id = ? LIMIT 1;
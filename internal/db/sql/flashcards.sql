@@ -0,0 +1,44 @@
+-- name: CreateFlashcard :one
+INSERT INTO flashcards (
+    id,
+    session_id,
+    message_id,
+    topic,
+    front,
+    back,
+    due_at,
+    created_at,
+    updated_at
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, strftime('%s', 'now'), strftime('%s', 'now')
+)
+RETURNING *;
+
+-- name: GetFlashcard :one
+SELECT *
+FROM flashcards
+WHERE id = ? LIMIT 1;
+
+-- name: ListFlashcards :many
+SELECT *
+FROM flashcards
+ORDER BY created_at ASC, rowid ASC;
+
+-- name: ListDueFlashcards :many
+SELECT *
+FROM flashcards
+WHERE due_at <= ?
+ORDER BY due_at ASC, rowid ASC;
+
+-- name: UpdateFlashcardReview :one
+UPDATE flashcards
+SET
+    interval_days = ?,
+    ease = ?,
+    reps = ?,
+    lapses = ?,
+    due_at = ?,
+    reviewed_at = ?,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?
+RETURNING *;
@@ -0,0 +1,21 @@
+-- name: CreateGradeSample :one
+INSERT INTO grade_samples (
+    id,
+    question_id,
+    model,
+    score,
+    dimensions,
+    rationale,
+    raw,
+    error,
+    created_at
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?, strftime('%s', 'now')
+)
+RETURNING *;
+
+-- name: ListGradeSamples :many
+SELECT *
+FROM grade_samples
+WHERE question_id = ?
+ORDER BY created_at ASC, rowid ASC;
@@ -0,0 +1,66 @@
+-- name: CreateInterviewTarget :one
+INSERT INTO interview_targets (
+    id,
+    company,
+    role,
+    day,
+    topics,
+    created_at,
+    updated_at
+) VALUES (
+    ?, ?, ?, ?, ?, strftime('%s', 'now'), strftime('%s', 'now')
+)
+RETURNING *;
+
+-- name: ListInterviewTargets :many
+SELECT *
+FROM interview_targets
+ORDER BY day ASC, created_at ASC;
+
+-- name: DeleteInterviewTarget :exec
+DELETE FROM interview_targets
+WHERE id = ?;
+
+-- name: CreatePlanItem :exec
+INSERT INTO plan_items (
+    id,
+    target_id,
+    day,
+    position,
+    mode,
+    topic,
+    minutes,
+    questions,
+    created_at,
+    updated_at
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?, strftime('%s', 'now'), strftime('%s', 'now')
+);
+
+-- name: DeleteUnstartedPlanItems :exec
+-- Clears the plan from the given day on, keeping the items already started.
+DELETE FROM plan_items
+WHERE day >= ? AND session_id = '';
+
+-- name: ListPlanItems :many
+SELECT
+    p.*,
+    t.company,
+    t.role,
+    t.day AS interview_day,
+    CAST((
+        SELECT COUNT(*)
+        FROM drill_questions q
+        WHERE p.session_id <> '' AND q.session_id = p.session_id AND q.status IN ('answered', 'gave_up')
+    ) AS INTEGER) AS graded
+FROM plan_items p
+JOIN interview_targets t ON t.id = p.target_id
+WHERE p.day >= sqlc.arg(from_day) AND p.day <= sqlc.arg(to_day)
+ORDER BY p.day ASC, p.position ASC;
+
+-- name: SetPlanItemSession :exec
+UPDATE plan_items
+SET
+    session_id = ?,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?;
@@ -0,0 +1,15 @@
+-- name: CreateSessionPack :one
+INSERT INTO session_packs (
+    session_id,
+    pack,
+    round,
+    created_at
+) VALUES (
+    ?, ?, ?, strftime('%s', 'now')
+)
+RETURNING *;
+
+-- name: GetSessionPack :one
+SELECT *
+FROM session_packs
+WHERE session_id = ? LIMIT 1;
@@ -23,19 +23,44 @@ INSERT INTO sessions (
     ?,
     strftime('%s', 'now'),
     strftime('%s', 'now')
-) RETURNING id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode;
+) RETURNING id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode, skills, reviewed_by, reviewed_at;
 
 -- name: GetSessionByID :one
-SELECT id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode
+SELECT id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode, skills, reviewed_by, reviewed_at
 FROM sessions
 WHERE id = ? LIMIT 1;
 
+-- name: ImportSession :one
+INSERT INTO sessions (
+    id,
+    title,
+    message_count,
+    prompt_tokens,
+    completion_tokens,
+    cost,
+    mode,
+    reviewed_by,
+    reviewed_at,
+    updated_at,
+    created_at
+) VALUES (
+    ?, ?, 0, ?, ?, ?, ?, ?, ?, ?, ?
+) RETURNING id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode, skills, reviewed_by, reviewed_at;
+
 -- name: ListSessions :many
-SELECT id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode
+SELECT id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode, skills, reviewed_by, reviewed_at
 FROM sessions
 WHERE parent_session_id is NULL
 ORDER BY updated_at DESC;
 
+-- name: SetSessionReviewed :one
+UPDATE sessions
+SET
+    reviewed_by = ?,
+    reviewed_at = ?
+WHERE id = ?
+RETURNING id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode, skills, reviewed_by, reviewed_at;
+
 -- name: UpdateSession :one
 UPDATE sessions
 SET
@@ -45,9 +70,10 @@ SET
     summary_message_id = ?,
     cost = ?,
     todos = ?,
-    mode = ?
+    mode = ?,
+    skills = ?
 WHERE id = ?
-RETURNING id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode;
+RETURNING id, parent_session_id, title, message_count, prompt_tokens, completion_tokens, cost, updated_at, created_at, summary_message_id, todos, mode, skills, reviewed_by, reviewed_at;
 
 -- name: UpdateSessionTitleAndUsage :exec
 UPDATE sessions
@@ -0,0 +1,54 @@
+-- name: CreateUsageRecord :one
+INSERT INTO usage_records (
+    id,
+    session_id,
+    mode,
+    provider,
+    model,
+    prompt_tokens,
+    completion_tokens,
+    cost,
+    created_at
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?, strftime('%s', 'now')
+)
+RETURNING *;
+
+-- name: GetUsageCostSince :one
+SELECT CAST(COALESCE(SUM(cost), 0.0) AS REAL) AS total
+FROM usage_records
+WHERE created_at >= ?;
+
+-- name: ListUsageByDay :many
+SELECT
+    CAST(date(created_at, 'unixepoch', 'localtime') AS TEXT) AS day,
+    CAST(SUM(prompt_tokens) AS INTEGER) AS prompt_tokens,
+    CAST(SUM(completion_tokens) AS INTEGER) AS completion_tokens,
+    CAST(SUM(cost) AS REAL) AS cost
+FROM usage_records
+WHERE created_at >= ?
+GROUP BY day
+ORDER BY day DESC;
+
+-- name: ListUsageByMode :many
+SELECT
+    CAST(COALESCE(mode, '') AS TEXT) AS mode,
+    CAST(SUM(prompt_tokens) AS INTEGER) AS prompt_tokens,
+    CAST(SUM(completion_tokens) AS INTEGER) AS completion_tokens,
+    CAST(SUM(cost) AS REAL) AS cost
+FROM usage_records
+WHERE created_at >= ?
+GROUP BY 1
+ORDER BY cost DESC;
+
+-- name: ListUsageByModel :many
+SELECT
+    provider,
+    model,
+    CAST(SUM(prompt_tokens) AS INTEGER) AS prompt_tokens,
+    CAST(SUM(completion_tokens) AS INTEGER) AS completion_tokens,
+    CAST(SUM(cost) AS REAL) AS cost
+FROM usage_records
+WHERE created_at >= ?
+GROUP BY provider, model
+ORDER BY cost DESC;
@@ -0,0 +1,173 @@
+package debugging
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/pubsub"
+)
+
+// Status is where a debug run stands.
+type Status string
+
+const (
+	StatusOpen  Status = "open"
+	StatusFixed Status = "fixed"
+)
+
+// ErrNoRun is returned when the session is not a debugging session.
+var ErrNoRun = errors.New("no debugging exercise in this session")
+
+// ErrFixed is returned when checking a run that is already fixed; its
+// workspace is gone.
+var ErrFixed = errors.New("the bug is already fixed")
+
+// Run is a debugging exercise being worked on in a session.
+type Run struct {
+	ID           string `json:"id"`
+	SessionID    string `json:"session_id"`
+	Exercise     string `json:"exercise"`
+	Topic        string `json:"topic"`
+	Workspace    string `json:"workspace"`
+	CheckCommand string `json:"check_command"`
+	Status       Status `json:"status"`
+	// Checks counts the times the check was run, passing or not.
+	Checks    int   `json:"checks"`
+	FixedAt   int64 `json:"fixed_at,omitempty"`
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+// Score is what a fix is worth after the given number of checks: full marks
+// for a fix that passes on the first check, less for each failed one before
+// it.
+func Score(checks int) int {
+	return max(100-20*(checks-1), 40)
+}
+
+type Service interface {
+	pubsub.Subscriber[Run]
+	// Start copies the exercise into a scratch workspace and ties it to the
+	// session.
+	Start(ctx context.Context, sessionID string, e *Exercise) (Run, error)
+	// ForSession returns the session's run, or ErrNoRun.
+	ForSession(ctx context.Context, sessionID string) (Run, error)
+	// Check runs the check command in the session's workspace. The first
+	// passing check marks the run fixed, records it towards progress and
+	// ends the run, removing the workspace.
+	Check(ctx context.Context, sessionID string) (Run, CheckResult, error)
+}
+
+type service struct {
+	*pubsub.Broker[Run]
+	q      db.Querier
+	drills drill.Service
+	check  func(ctx context.Context, workspace, command string) (CheckResult, error)
+	now    func() time.Time
+}
+
+func NewService(q db.Querier, drills drill.Service) Service {
+	return &service{
+		Broker: pubsub.NewBroker(pubsub.WithName[Run]("debugging")),
+		q:      q,
+		drills: drills,
+		check:  Check,
+		now:    time.Now,
+	}
+}
+
+func (s *service) Start(ctx context.Context, sessionID string, e *Exercise) (Run, error) {
+	workspace, err := Prepare(e, sessionID)
+	if err != nil {
+		return Run{}, err
+	}
+	row, err := s.q.CreateDebugRun(ctx, db.CreateDebugRunParams{
+		ID:           uuid.New().String(),
+		SessionID:    sessionID,
+		Exercise:     e.Name,
+		Topic:        e.TopicOf(),
+		Workspace:    workspace,
+		CheckCommand: e.Check,
+	})
+	if err != nil {
+		os.RemoveAll(workspace)
+		return Run{}, err
+	}
+	run := fromDB(row)
+	s.Publish(pubsub.CreatedEvent, run)
+	return run, nil
+}
+
+func (s *service) ForSession(ctx context.Context, sessionID string) (Run, error) {
+	row, err := s.q.GetDebugRunBySession(ctx, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Run{}, ErrNoRun
+	}
+	if err != nil {
+		return Run{}, err
+	}
+	return fromDB(row), nil
+}
+
+func (s *service) Check(ctx context.Context, sessionID string) (Run, CheckResult, error) {
+	run, err := s.ForSession(ctx, sessionID)
+	if err != nil {
+		return Run{}, CheckResult{}, err
+	}
+	if run.Status == StatusFixed {
+		return run, CheckResult{}, ErrFixed
+	}
+	result, err := s.check(ctx, run.Workspace, run.CheckCommand)
+	if err != nil {
+		return run, CheckResult{}, err
+	}
+
+	status := run.Status
+	fixedAt := sql.NullInt64{}
+	if result.Passed {
+		status = StatusFixed
+		fixedAt = sql.NullInt64{Int64: s.now().Unix(), Valid: true}
+	}
+	row, err := s.q.UpdateDebugRunCheck(ctx, db.UpdateDebugRunCheckParams{
+		Status:    string(status),
+		FixedAt:   fixedAt,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return run, result, err
+	}
+	run = fromDB(row)
+	s.Publish(pubsub.UpdatedEvent, run)
+
+	if !result.Passed {
+		return run, result, nil
+	}
+	_, err = s.drills.Record(ctx, drill.Question{
+		SessionID: sessionID,
+		Topic:     run.Topic,
+		Text:      "Debug " + run.Exercise,
+	}, Score(run.Checks))
+	return run, result, errors.Join(err, os.RemoveAll(run.Workspace))
+}
+
+func fromDB(row db.DebugRun) Run {
+	return Run{
+		ID:           row.ID,
+		SessionID:    row.SessionID,
+		Exercise:     row.Exercise,
+		Topic:        row.Topic,
+		Workspace:    row.Workspace,
+		CheckCommand: row.CheckCommand,
+		Status:       Status(row.Status),
+		Checks:       int(row.Checks),
+		FixedAt:      row.FixedAt.Int64,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+	}
+}
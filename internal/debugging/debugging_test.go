@@ -0,0 +1,114 @@
+package debugging
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/drill"
+)
+
+const manifest = `title: Off by one
+description: The last item of every page is missing.
+check: test -f fixed
+notes: The loop stops at len-1.
+`
+
+// writeExercise creates an exercise whose check passes once a file named
+// "fixed" exists in the workspace.
+func writeExercise(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Join(path, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(path, ManifestName), []byte(manifest), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "pkg", "page.go"), []byte("package pkg\n"), 0o644))
+	return path
+}
+
+func TestPrepare(t *testing.T) {
+	t.Parallel()
+
+	e, err := Exercises.Load(writeExercise(t, t.TempDir(), "paging"))
+	require.NoError(t, err)
+	require.Equal(t, "paging", e.Name)
+	require.Equal(t, "Debugging", e.TopicOf())
+	require.Equal(t, "The loop stops at len-1.", e.Notes)
+
+	sessionID := "prepare-" + t.Name()
+	workspace, err := Prepare(e, sessionID)
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(workspace) })
+	require.FileExists(t, filepath.Join(workspace, "pkg", "page.go"))
+	require.NoFileExists(t, filepath.Join(workspace, ManifestName))
+
+	require.NoError(t, RemoveWorkspaces(sessionID))
+	require.NoDirExists(t, workspace)
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	result, err := Check(t.Context(), dir, "echo not yet; exit 3")
+	require.NoError(t, err)
+	require.False(t, result.Passed)
+	require.Equal(t, 3, result.ExitCode)
+	require.Equal(t, "not yet", result.Output)
+
+	result, err = Check(t.Context(), dir, "true")
+	require.NoError(t, err)
+	require.True(t, result.Passed)
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	q := db.New(conn)
+	_, err = q.CreateSession(t.Context(), db.CreateSessionParams{
+		ID:   "s1",
+		Mode: sql.NullString{String: "debug", Valid: true},
+	})
+	require.NoError(t, err)
+	drills := drill.NewService(q, conn)
+	svc := NewService(q, drills)
+
+	_, err = svc.ForSession(t.Context(), "s1")
+	require.ErrorIs(t, err, ErrNoRun)
+
+	e, err := Exercises.Load(writeExercise(t, t.TempDir(), "paging"))
+	require.NoError(t, err)
+	run, err := svc.Start(t.Context(), "s1", e)
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(run.Workspace) })
+	require.Equal(t, StatusOpen, run.Status)
+
+	run, result, err := svc.Check(t.Context(), "s1")
+	require.NoError(t, err)
+	require.False(t, result.Passed)
+	require.Equal(t, StatusOpen, run.Status)
+	require.Equal(t, 1, run.Checks)
+
+	require.NoError(t, os.WriteFile(filepath.Join(run.Workspace, "fixed"), nil, 0o644))
+	run, result, err = svc.Check(t.Context(), "s1")
+	require.NoError(t, err)
+	require.True(t, result.Passed)
+	require.Equal(t, StatusFixed, run.Status)
+	require.NotZero(t, run.FixedAt)
+	require.NoDirExists(t, run.Workspace)
+
+	// Checking again after the fix doesn't record it twice.
+	_, _, err = svc.Check(t.Context(), "s1")
+	require.ErrorIs(t, err, ErrFixed)
+	progress, err := drills.Progress(t.Context(), time.Time{})
+	require.NoError(t, err)
+	require.Len(t, progress, 1)
+	require.Equal(t, 1, progress[0].Questions)
+	require.InDelta(t, float64(Score(2)), progress[0].AvgScore, 0.01)
+}
@@ -0,0 +1,182 @@
+// Package debugging runs debugging exercises: a small project with a failing
+// check is copied into a scratch workspace, the user investigates and fixes
+// it there, and the fix is verified by running the exercise's check command.
+package debugging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/trankhanh040147/prepf/internal/exercise"
+	"github.com/trankhanh040147/prepf/internal/shell"
+)
+
+// ManifestName is the file that makes a directory an exercise. It is not
+// copied into the workspace, so the solution notes stay out of reach.
+const ManifestName = "exercise.yaml"
+
+// CheckTimeout bounds how long the check command may run.
+const CheckTimeout = 2 * time.Minute
+
+// maxOutput is how much of the check output is kept, from the end, where
+// test runners print the failures and the summary.
+const maxOutput = 4000
+
+// Exercise is a project with a bug to find.
+type Exercise struct {
+	// Name is the directory name and is what /debug takes.
+	Name  string `yaml:"-" json:"name"`
+	Title string `yaml:"title,omitempty" json:"title,omitempty"`
+	Topic string `yaml:"topic,omitempty" json:"topic,omitempty"`
+	// Description is the bug report the user starts from.
+	Description string `yaml:"description" json:"description"`
+	// Check is the shell command that passes once the bug is fixed, run
+	// from the workspace root.
+	Check string `yaml:"check" json:"check"`
+	// Notes explain the bug for the interviewer and are never shown to the
+	// user directly.
+	Notes string `yaml:"notes,omitempty" json:"notes,omitempty"`
+	// Rubric names the rubric the round is graded against instead of the
+	// mode's.
+	Rubric string `yaml:"rubric,omitempty" json:"rubric,omitempty"`
+	Dir    string `yaml:"-" json:"dir"`
+}
+
+// TopicOf returns the topic the exercise is tracked under.
+func (e *Exercise) TopicOf() string {
+	if e.Topic != "" {
+		return e.Topic
+	}
+	return "Debugging"
+}
+
+// Validate checks that the exercise has a bug report and a check command.
+func (e *Exercise) Validate() error {
+	var errs []error
+	if strings.TrimSpace(e.Description) == "" {
+		errs = append(errs, errors.New("description is required"))
+	}
+	if strings.TrimSpace(e.Check) == "" {
+		errs = append(errs, errors.New("check is required"))
+	}
+	return errors.Join(errs...)
+}
+
+// Exercises loads the exercises in the debug directories. Every
+// subdirectory with a manifest is an exercise, named after the directory.
+var Exercises = exercise.Loader[Exercise, *Exercise]{Kind: "debugging exercise", Dir: "debug", Manifest: ManifestName}
+
+// ExerciseName returns the name the exercise was loaded under.
+func (e *Exercise) ExerciseName() string { return e.Name }
+
+// SetOrigin records the name the exercise is loaded under and its directory.
+func (e *Exercise) SetOrigin(name, dir string) { e.Name, e.Dir = name, dir }
+
+// Prepare copies the exercise into a new scratch directory for the session
+// and returns its path. The manifest is left out and symlinks are skipped,
+// so nothing in the workspace points back at the original.
+func Prepare(e *Exercise, sessionID string) (string, error) {
+	workspace, err := os.MkdirTemp("", workspacePrefix(sessionID)+e.Name+"-")
+	if err != nil {
+		return "", err
+	}
+	err = filepath.WalkDir(e.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(e.Dir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(workspace, rel)
+		switch {
+		case rel == ".":
+			return nil
+		case rel == ManifestName:
+			return nil
+		case d.IsDir():
+			return os.MkdirAll(target, 0o755)
+		case !d.Type().IsRegular():
+			return nil
+		}
+		return copyFile(path, target)
+	})
+	if err != nil {
+		os.RemoveAll(workspace)
+		return "", fmt.Errorf("copying exercise: %w", err)
+	}
+	return workspace, nil
+}
+
+// workspacePrefix starts the names of the session's workspaces, so they can
+// be found once the session's runs are gone.
+func workspacePrefix(sessionID string) string {
+	return "prepf-debug-" + sessionID + "-"
+}
+
+// RemoveWorkspaces removes the workspaces prepared for the session.
+func RemoveWorkspaces(sessionID string) error {
+	workspaces, err := filepath.Glob(filepath.Join(os.TempDir(), workspacePrefix(sessionID)+"*"))
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, workspace := range workspaces {
+		errs = append(errs, os.RemoveAll(workspace))
+	}
+	return errors.Join(errs...)
+}
+
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// CheckResult is the outcome of running an exercise's check command.
+type CheckResult struct {
+	Passed   bool   `json:"passed"`
+	ExitCode int    `json:"exit_code"`
+	Output   string `json:"output"`
+}
+
+// Check runs command from the workspace root. A non-zero exit is a failed
+// check, not an error; errors are for checks that could not run at all.
+func Check(ctx context.Context, workspace, command string) (CheckResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, CheckTimeout)
+	defer cancel()
+
+	sh := shell.NewShell(&shell.Options{WorkingDir: workspace})
+	stdout, stderr, err := sh.Exec(ctx, command)
+	if shell.IsInterrupt(err) {
+		return CheckResult{}, fmt.Errorf("check did not finish: %w", err)
+	}
+	output := strings.TrimSpace(strings.TrimSpace(stdout) + "\n" + strings.TrimSpace(stderr))
+	if len(output) > maxOutput {
+		output = "..." + output[len(output)-maxOutput:]
+	}
+	code := shell.ExitCode(err)
+	return CheckResult{Passed: code == 0, ExitCode: code, Output: output}, nil
+}
@@ -0,0 +1,618 @@
+// Package drill tracks the questions asked in gym sessions: the hint ladder
+// generated with each question, how far up the ladder the user climbed, the
+// resulting score and when a topic is due for re-practice.
+package drill
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/message"
+	"github.com/trankhanh040147/prepf/internal/pubsub"
+	"github.com/trankhanh040147/prepf/internal/rubric"
+)
+
+// HintLevel is a rung of the hint ladder. Each level reveals more than the
+// previous one and lowers the score the answer can earn.
+type HintLevel int
+
+const (
+	HintNone HintLevel = iota
+	HintNudge
+	HintApproach
+	HintPartial
+	HintAnswer
+)
+
+// MaxHintLevel is the last rung, which reveals the model answer.
+const MaxHintLevel = HintAnswer
+
+func (l HintLevel) String() string {
+	switch l {
+	case HintNudge:
+		return "nudge"
+	case HintApproach:
+		return "approach"
+	case HintPartial:
+		return "partial solution"
+	case HintAnswer:
+		return "full answer"
+	default:
+		return "none"
+	}
+}
+
+// scoreFactors is the share of the raw score kept after using hints up to
+// each level, in percent.
+var scoreFactors = [...]int{100, 90, 70, 40, 0}
+
+// ApplyPenalty reduces a raw 0-100 score for the hints used.
+func ApplyPenalty(raw int, used HintLevel) int {
+	raw = min(max(raw, 0), 100)
+	used = min(max(used, HintNone), MaxHintLevel)
+	return raw * scoreFactors[used] / 100
+}
+
+// PracticeDelay is how long after giving up a topic comes back for
+// re-practice.
+const PracticeDelay = 24 * time.Hour
+
+// PassScore is the score, after hint penalties, from which an answer on a
+// topic due for re-practice takes it off the list.
+const PassScore = 50
+
+type Status string
+
+const (
+	StatusOpen     Status = "open"
+	StatusAnswered Status = "answered"
+	StatusGaveUp   Status = "gave_up"
+	StatusSkipped  Status = "skipped"
+)
+
+// ErrNoQuestion is returned when the session has no open question.
+var ErrNoQuestion = errors.New("no open question")
+
+// Question is a drill question with its hint ladder.
+type Question struct {
+	ID        string
+	SessionID string
+	Topic     string
+	Text      string
+	// Hints holds the nudge, approach and partial solution, in order.
+	Hints  []string
+	Answer string
+
+	HintsUsed HintLevel
+	Status    Status
+	// RawScore is the grade the answer earned before hint penalties and
+	// Score the one after. Both are nil until the question is graded.
+	RawScore *int
+	Score    *int
+	// PracticeAt is when the topic is due for re-practice, or zero.
+	PracticeAt int64
+	// Timing is how the graded answer was composed, or nil if the editor
+	// didn't record it.
+	Timing *Timing
+	// Node is the path of the topic taxonomy node the graded question is
+	// filed under, or empty until it is classified.
+	Node string
+	// Rubric names the rubric the answer is graded against, or is empty if
+	// it gets a single score. RubricVersion is the version of the rubric
+	// the question was asked or, once graded, scored with.
+	Rubric        string
+	RubricVersion int
+	// Dimensions holds the per-dimension grades of an answer graded against
+	// the rubric.
+	Dimensions []rubric.DimensionScore
+	// Agreement is the share of independent grader calls whose grade
+	// agreed with the median the question was scored with, or nil if it
+	// wasn't judged. Flagged marks a judged grade to look at again, e.g.
+	// because the graders disagreed.
+	Agreement *float64
+	Flagged   bool
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+// Sample is the outcome of one independent grader call on an answer.
+type Sample struct {
+	ID         string                  `json:"id,omitempty"`
+	Model      string                  `json:"model"`
+	Score      *int                    `json:"score,omitempty"`
+	Dimensions []rubric.DimensionScore `json:"dimensions,omitempty"`
+	Rationale  string                  `json:"rationale,omitempty"`
+	// Raw is the grader's reply as received.
+	Raw string `json:"raw,omitempty"`
+	// Err explains why the call gave no usable grade.
+	Err       string `json:"error,omitempty"`
+	CreatedAt int64  `json:"created_at,omitempty"`
+}
+
+// Timing is the part of an answer's timing kept for progress tracking.
+type Timing struct {
+	Think   time.Duration
+	Compose time.Duration
+	Pastes  int
+}
+
+// TopicStats summarizes the graded questions on a topic.
+type TopicStats struct {
+	Topic     string  `json:"topic"`
+	Questions int     `json:"questions"`
+	AvgScore  float64 `json:"avg_score"`
+	AvgHints  float64 `json:"avg_hints"`
+	// The thinking and composition times only count answers with
+	// recorded timing.
+	AvgThinkSeconds   float64 `json:"avg_think_seconds"`
+	AvgComposeSeconds float64 `json:"avg_compose_seconds"`
+	Pastes            int     `json:"pastes"`
+}
+
+// Hint returns the text revealed at the given level.
+func (q Question) Hint(level HintLevel) string {
+	if level == HintAnswer {
+		return q.Answer
+	}
+	if level < HintNudge || int(level) > len(q.Hints) {
+		return ""
+	}
+	return q.Hints[level-1]
+}
+
+type Service interface {
+	pubsub.Subscriber[Question]
+	// Ask records a new question for the session. Questions left open in
+	// the session are marked as skipped.
+	Ask(ctx context.Context, q Question) (Question, error)
+	// Current returns the session's open question.
+	Current(ctx context.Context, sessionID string) (Question, error)
+	// RevealHint moves the open question one level up the hint ladder.
+	// Revealing the full answer counts as giving up.
+	RevealHint(ctx context.Context, sessionID string) (Question, error)
+	// GiveUp reveals the answer, scores the question zero and schedules the
+	// topic for re-practice.
+	GiveUp(ctx context.Context, sessionID string) (Question, error)
+	// Grade scores the open question, applying the penalty for the hints
+	// used, and records how the answer was composed if timing is not nil.
+	// A passing answer on a topic due for re-practice clears it.
+	Grade(ctx context.Context, sessionID string, raw int, timing *message.AnswerTiming) (Question, error)
+	// GradeRubric scores the open question from its grades on each
+	// dimension of the rubric, which must all be given, and records the
+	// rubric version they were given against. Otherwise it works like
+	// Grade.
+	GradeRubric(ctx context.Context, sessionID string, r *rubric.Rubric, scores []rubric.DimensionScore, timing *message.AnswerTiming) (Question, error)
+	// Record stores a question asked and graded in one go, such as a quiz
+	// question graded locally, so it counts towards progress.
+	Record(ctx context.Context, q Question, raw int) (Question, error)
+	// Due lists the questions whose topic is due for re-practice.
+	Due(ctx context.Context, now time.Time) ([]Question, error)
+	// Progress summarizes the questions graded or given up since the given
+	// time, per topic.
+	Progress(ctx context.Context, since time.Time) ([]TopicStats, error)
+	// Judge records the grader calls an answer was judged with and how well
+	// they agreed, flagging the grade for attention if asked to.
+	Judge(ctx context.Context, id string, samples []Sample, agreement float64, flagged bool) (Question, error)
+	// Samples lists the grader calls a question was judged with.
+	Samples(ctx context.Context, id string) ([]Sample, error)
+	// Flagged lists the questions whose grades are flagged, most recent
+	// first.
+	Flagged(ctx context.Context) ([]Question, error)
+	// Resolve clears the flag of a question's grade.
+	Resolve(ctx context.Context, id string) (Question, error)
+	// SetNode files a question under a node of the topic taxonomy.
+	SetNode(ctx context.Context, id, node string) (Question, error)
+	// Unclassified lists the questions graded or given up that are not
+	// filed under a taxonomy node yet, oldest first.
+	Unclassified(ctx context.Context) ([]Question, error)
+	// Classified lists the questions graded or given up that are filed
+	// under a taxonomy node, most recent first.
+	Classified(ctx context.Context) ([]Question, error)
+}
+
+type service struct {
+	*pubsub.Broker[Question]
+	q   *db.Queries
+	db  *sql.DB
+	now func() time.Time
+}
+
+func NewService(q *db.Queries, db *sql.DB) Service {
+	return &service{
+		Broker: pubsub.NewBroker(pubsub.WithName[Question]("drill")),
+		q:      q,
+		db:     db,
+		now:    time.Now,
+	}
+}
+
+func (s *service) Ask(ctx context.Context, q Question) (Question, error) {
+	if q.Text == "" {
+		return Question{}, errors.New("question is required")
+	}
+	hints, err := json.Marshal(q.Hints)
+	if err != nil {
+		return Question{}, err
+	}
+	if err := s.q.SkipOpenDrillQuestions(ctx, q.SessionID); err != nil {
+		return Question{}, err
+	}
+	row, err := s.q.CreateDrillQuestion(ctx, db.CreateDrillQuestionParams{
+		ID:            uuid.New().String(),
+		SessionID:     q.SessionID,
+		Topic:         q.Topic,
+		Question:      q.Text,
+		Hints:         string(hints),
+		Answer:        q.Answer,
+		Rubric:        q.Rubric,
+		RubricVersion: int64(q.RubricVersion),
+	})
+	if err != nil {
+		return Question{}, err
+	}
+	created := fromDB(row)
+	s.Publish(pubsub.CreatedEvent, created)
+	return created, nil
+}
+
+func (s *service) Current(ctx context.Context, sessionID string) (Question, error) {
+	row, err := s.q.GetOpenDrillQuestion(ctx, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Question{}, ErrNoQuestion
+	}
+	if err != nil {
+		return Question{}, err
+	}
+	return fromDB(row), nil
+}
+
+func (s *service) RevealHint(ctx context.Context, sessionID string) (Question, error) {
+	q, err := s.Current(ctx, sessionID)
+	if err != nil {
+		return Question{}, err
+	}
+	q.HintsUsed++
+	if q.HintsUsed >= MaxHintLevel {
+		return s.giveUp(ctx, q)
+	}
+	return s.update(ctx, q)
+}
+
+func (s *service) GiveUp(ctx context.Context, sessionID string) (Question, error) {
+	q, err := s.Current(ctx, sessionID)
+	if err != nil {
+		return Question{}, err
+	}
+	return s.giveUp(ctx, q)
+}
+
+func (s *service) giveUp(ctx context.Context, q Question) (Question, error) {
+	zero := 0
+	q.HintsUsed = MaxHintLevel
+	q.Status = StatusGaveUp
+	q.Score = &zero
+	q.PracticeAt = s.now().Add(PracticeDelay).Unix()
+	return s.update(ctx, q)
+}
+
+func (s *service) Grade(ctx context.Context, sessionID string, raw int, timing *message.AnswerTiming) (Question, error) {
+	if raw < 0 || raw > 100 {
+		return Question{}, fmt.Errorf("score must be between 0 and 100, got %d", raw)
+	}
+	q, err := s.Current(ctx, sessionID)
+	if err != nil {
+		return Question{}, err
+	}
+	return s.grade(ctx, q, raw, timing)
+}
+
+func (s *service) GradeRubric(ctx context.Context, sessionID string, r *rubric.Rubric, scores []rubric.DimensionScore, timing *message.AnswerTiming) (Question, error) {
+	if err := r.Check(scores); err != nil {
+		return Question{}, err
+	}
+	q, err := s.Current(ctx, sessionID)
+	if err != nil {
+		return Question{}, err
+	}
+	q.Rubric = r.Name
+	q.RubricVersion = r.Version
+	q.Dimensions = scores
+	return s.grade(ctx, q, r.Score(scores), timing)
+}
+
+func (s *service) grade(ctx context.Context, q Question, raw int, timing *message.AnswerTiming) (Question, error) {
+	score := ApplyPenalty(raw, q.HintsUsed)
+	q.Status = StatusAnswered
+	q.RawScore = &raw
+	q.Score = &score
+	if timing != nil {
+		q.Timing = &Timing{
+			Think:   time.Duration(timing.ThinkMs) * time.Millisecond,
+			Compose: time.Duration(timing.ComposeMs) * time.Millisecond,
+			Pastes:  timing.Pastes,
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Question{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.q.WithTx(tx)
+
+	if q.Topic != "" && score >= PassScore {
+		if err := qtx.ClearDrillPractice(ctx, q.Topic); err != nil {
+			return Question{}, err
+		}
+	}
+	row, err := write(ctx, qtx, q)
+	if err != nil {
+		return Question{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return Question{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	updated := fromDB(row)
+	s.Publish(pubsub.UpdatedEvent, updated)
+	return updated, nil
+}
+
+func (s *service) Record(ctx context.Context, q Question, raw int) (Question, error) {
+	if raw < 0 || raw > 100 {
+		return Question{}, fmt.Errorf("score must be between 0 and 100, got %d", raw)
+	}
+	if _, err := s.Ask(ctx, q); err != nil {
+		return Question{}, err
+	}
+	return s.Grade(ctx, q.SessionID, raw, nil)
+}
+
+func (s *service) Due(ctx context.Context, now time.Time) ([]Question, error) {
+	rows, err := s.q.ListDueDrillQuestions(ctx, sql.NullInt64{Int64: now.Unix(), Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	return fromDBRows(rows), nil
+}
+
+func (s *service) Progress(ctx context.Context, since time.Time) ([]TopicStats, error) {
+	rows, err := s.q.ListDrillTopicStats(ctx, since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]TopicStats, len(rows))
+	for i, row := range rows {
+		stats[i] = TopicStats{
+			Topic:             row.Topic,
+			Questions:         int(row.Questions),
+			AvgScore:          row.AvgScore,
+			AvgHints:          row.AvgHints,
+			AvgThinkSeconds:   row.AvgThinkMs / 1000,
+			AvgComposeSeconds: row.AvgComposeMs / 1000,
+			Pastes:            int(row.Pastes),
+		}
+	}
+	return stats, nil
+}
+
+func (s *service) Judge(ctx context.Context, id string, samples []Sample, agreement float64, flagged bool) (Question, error) {
+	for _, sample := range samples {
+		params := db.CreateGradeSampleParams{
+			ID:         uuid.New().String(),
+			QuestionID: id,
+			Model:      sample.Model,
+			Score:      nullInt(sample.Score),
+			Rationale:  sample.Rationale,
+			Raw:        sample.Raw,
+			Error:      sample.Err,
+		}
+		if len(sample.Dimensions) > 0 {
+			dimensions, err := json.Marshal(sample.Dimensions)
+			if err != nil {
+				return Question{}, err
+			}
+			params.Dimensions = string(dimensions)
+		}
+		if _, err := s.q.CreateGradeSample(ctx, params); err != nil {
+			return Question{}, err
+		}
+	}
+	return s.setJudgement(ctx, id, sql.NullFloat64{Float64: agreement, Valid: true}, flagged)
+}
+
+func (s *service) Samples(ctx context.Context, id string) ([]Sample, error) {
+	rows, err := s.q.ListGradeSamples(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]Sample, len(rows))
+	for i, row := range rows {
+		var dimensions []rubric.DimensionScore
+		if row.Dimensions != "" {
+			_ = json.Unmarshal([]byte(row.Dimensions), &dimensions)
+		}
+		samples[i] = Sample{
+			ID:         row.ID,
+			Model:      row.Model,
+			Score:      intPtr(row.Score),
+			Dimensions: dimensions,
+			Rationale:  row.Rationale,
+			Raw:        row.Raw,
+			Err:        row.Error,
+			CreatedAt:  row.CreatedAt,
+		}
+	}
+	return samples, nil
+}
+
+func (s *service) Flagged(ctx context.Context) ([]Question, error) {
+	rows, err := s.q.ListFlaggedDrillQuestions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fromDBRows(rows), nil
+}
+
+func (s *service) Resolve(ctx context.Context, id string) (Question, error) {
+	row, err := s.q.GetDrillQuestion(ctx, id)
+	if err != nil {
+		return Question{}, err
+	}
+	return s.setJudgement(ctx, id, row.Agreement, false)
+}
+
+func (s *service) setJudgement(ctx context.Context, id string, agreement sql.NullFloat64, flagged bool) (Question, error) {
+	var flag int64
+	if flagged {
+		flag = 1
+	}
+	row, err := s.q.SetDrillQuestionJudgement(ctx, db.SetDrillQuestionJudgementParams{
+		Agreement: agreement,
+		Flagged:   flag,
+		ID:        id,
+	})
+	if err != nil {
+		return Question{}, err
+	}
+	updated := fromDB(row)
+	s.Publish(pubsub.UpdatedEvent, updated)
+	return updated, nil
+}
+
+func (s *service) SetNode(ctx context.Context, id, node string) (Question, error) {
+	row, err := s.q.SetDrillQuestionNode(ctx, db.SetDrillQuestionNodeParams{
+		Node: node,
+		ID:   id,
+	})
+	if err != nil {
+		return Question{}, err
+	}
+	updated := fromDB(row)
+	s.Publish(pubsub.UpdatedEvent, updated)
+	return updated, nil
+}
+
+func (s *service) Unclassified(ctx context.Context) ([]Question, error) {
+	rows, err := s.q.ListUnclassifiedDrillQuestions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fromDBRows(rows), nil
+}
+
+func (s *service) Classified(ctx context.Context) ([]Question, error) {
+	rows, err := s.q.ListClassifiedDrillQuestions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fromDBRows(rows), nil
+}
+
+func (s *service) update(ctx context.Context, q Question) (Question, error) {
+	row, err := write(ctx, s.q, q)
+	if err != nil {
+		return Question{}, err
+	}
+	updated := fromDB(row)
+	s.Publish(pubsub.UpdatedEvent, updated)
+	return updated, nil
+}
+
+// write stores the question's grading state with q.
+func write(ctx context.Context, q db.Querier, question Question) (db.DrillQuestion, error) {
+	params := db.UpdateDrillQuestionParams{
+		ID:            question.ID,
+		HintsUsed:     int64(question.HintsUsed),
+		Status:        string(question.Status),
+		RawScore:      nullInt(question.RawScore),
+		Score:         nullInt(question.Score),
+		PracticeAt:    sql.NullInt64{Int64: question.PracticeAt, Valid: question.PracticeAt != 0},
+		Rubric:        question.Rubric,
+		RubricVersion: int64(question.RubricVersion),
+	}
+	if len(question.Dimensions) > 0 {
+		dimensions, err := json.Marshal(question.Dimensions)
+		if err != nil {
+			return db.DrillQuestion{}, err
+		}
+		params.Dimensions = string(dimensions)
+	}
+	if question.Timing != nil {
+		params.ThinkMs = sql.NullInt64{Int64: question.Timing.Think.Milliseconds(), Valid: true}
+		params.ComposeMs = sql.NullInt64{Int64: question.Timing.Compose.Milliseconds(), Valid: true}
+		params.Pastes = sql.NullInt64{Int64: int64(question.Timing.Pastes), Valid: true}
+	}
+	return q.UpdateDrillQuestion(ctx, params)
+}
+
+func nullInt(v *int) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*v), Valid: true}
+}
+
+func intPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
+
+func fromDBRows(rows []db.DrillQuestion) []Question {
+	questions := make([]Question, len(rows))
+	for i, row := range rows {
+		questions[i] = fromDB(row)
+	}
+	return questions
+}
+
+func fromDB(row db.DrillQuestion) Question {
+	var hints []string
+	_ = json.Unmarshal([]byte(row.Hints), &hints)
+	var dimensions []rubric.DimensionScore
+	if row.Dimensions != "" {
+		_ = json.Unmarshal([]byte(row.Dimensions), &dimensions)
+	}
+	var timing *Timing
+	if row.ComposeMs.Valid {
+		timing = &Timing{
+			Think:   time.Duration(row.ThinkMs.Int64) * time.Millisecond,
+			Compose: time.Duration(row.ComposeMs.Int64) * time.Millisecond,
+			Pastes:  int(row.Pastes.Int64),
+		}
+	}
+	var agreement *float64
+	if row.Agreement.Valid {
+		agreement = &row.Agreement.Float64
+	}
+	return Question{
+		ID:            row.ID,
+		SessionID:     row.SessionID,
+		Topic:         row.Topic,
+		Text:          row.Question,
+		Hints:         hints,
+		Answer:        row.Answer,
+		HintsUsed:     HintLevel(row.HintsUsed),
+		Status:        Status(row.Status),
+		RawScore:      intPtr(row.RawScore),
+		Score:         intPtr(row.Score),
+		PracticeAt:    row.PracticeAt.Int64,
+		Timing:        timing,
+		Node:          row.Node,
+		Rubric:        row.Rubric,
+		RubricVersion: int(row.RubricVersion),
+		Dimensions:    dimensions,
+		Agreement:     agreement,
+		Flagged:       row.Flagged != 0,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}
+}
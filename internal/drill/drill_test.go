@@ -0,0 +1,301 @@
+package drill
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/message"
+	"github.com/trankhanh040147/prepf/internal/rubric"
+)
+
+func TestApplyPenalty(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 80, ApplyPenalty(80, HintNone))
+	require.Equal(t, 72, ApplyPenalty(80, HintNudge))
+	require.Equal(t, 56, ApplyPenalty(80, HintApproach))
+	require.Equal(t, 32, ApplyPenalty(80, HintPartial))
+	require.Equal(t, 0, ApplyPenalty(80, HintAnswer))
+	require.Equal(t, 100, ApplyPenalty(150, HintNone))
+}
+
+func newTestService(t *testing.T) *service {
+	t.Helper()
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return NewService(db.New(conn), conn).(*service)
+}
+
+func testQuestion(sessionID string) Question {
+	return Question{
+		SessionID: sessionID,
+		Topic:     "Go channels",
+		Text:      "What happens when you send on a closed channel?",
+		Hints:     []string{"Think about panics.", "Consider the runtime check on send.", "Sending on a closed channel is not a blocking operation..."},
+		Answer:    "It panics.",
+	}
+}
+
+func TestHintLadder(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(t)
+	_, err := svc.Current(t.Context(), "s1")
+	require.ErrorIs(t, err, ErrNoQuestion)
+
+	q, err := svc.Ask(t.Context(), testQuestion("s1"))
+	require.NoError(t, err)
+	require.Equal(t, StatusOpen, q.Status)
+	require.Equal(t, HintNone, q.HintsUsed)
+
+	q, err = svc.RevealHint(t.Context(), "s1")
+	require.NoError(t, err)
+	require.Equal(t, HintNudge, q.HintsUsed)
+	require.Equal(t, "Think about panics.", q.Hint(q.HintsUsed))
+
+	q, err = svc.RevealHint(t.Context(), "s1")
+	require.NoError(t, err)
+	require.Equal(t, HintApproach, q.HintsUsed)
+
+	q, err = svc.Grade(t.Context(), "s1", 90, nil)
+	require.NoError(t, err)
+	require.Equal(t, StatusAnswered, q.Status)
+	require.Equal(t, 90, *q.RawScore)
+	require.Equal(t, 63, *q.Score)
+	require.Zero(t, q.PracticeAt)
+
+	_, err = svc.RevealHint(t.Context(), "s1")
+	require.ErrorIs(t, err, ErrNoQuestion)
+}
+
+func TestGiveUp(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(t)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	first, err := svc.Ask(t.Context(), testQuestion("s1"))
+	require.NoError(t, err)
+	_, err = svc.Ask(t.Context(), testQuestion("s1"))
+	require.NoError(t, err)
+
+	// Asking a new question skips the open one.
+	current, err := svc.Current(t.Context(), "s1")
+	require.NoError(t, err)
+	require.NotEqual(t, first.ID, current.ID)
+
+	q, err := svc.GiveUp(t.Context(), "s1")
+	require.NoError(t, err)
+	require.Equal(t, StatusGaveUp, q.Status)
+	require.Equal(t, HintAnswer, q.HintsUsed)
+	require.Equal(t, "It panics.", q.Hint(q.HintsUsed))
+	require.Equal(t, 0, *q.Score)
+	require.Equal(t, now.Add(PracticeDelay).Unix(), q.PracticeAt)
+
+	due, err := svc.Due(t.Context(), now)
+	require.NoError(t, err)
+	require.Empty(t, due)
+	due, err = svc.Due(t.Context(), now.Add(PracticeDelay))
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, q.ID, due[0].ID)
+
+	// Climbing to the last rung of the ladder counts as giving up.
+	_, err = svc.Ask(t.Context(), testQuestion("s2"))
+	require.NoError(t, err)
+	for range MaxHintLevel {
+		q, err = svc.RevealHint(t.Context(), "s2")
+		require.NoError(t, err)
+	}
+	require.Equal(t, StatusGaveUp, q.Status)
+
+	// A failing answer on the topic leaves it on the list, and so does one
+	// that only passes before the hint penalty.
+	_, err = svc.Ask(t.Context(), testQuestion("s3"))
+	require.NoError(t, err)
+	_, err = svc.Grade(t.Context(), "s3", PassScore-1, nil)
+	require.NoError(t, err)
+	_, err = svc.Ask(t.Context(), testQuestion("s4"))
+	require.NoError(t, err)
+	_, err = svc.RevealHint(t.Context(), "s4")
+	require.NoError(t, err)
+	q, err = svc.Grade(t.Context(), "s4", PassScore, nil)
+	require.NoError(t, err)
+	require.Less(t, *q.Score, PassScore)
+	due, err = svc.Due(t.Context(), now.Add(PracticeDelay))
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+
+	// A passing answer takes it off the list.
+	_, err = svc.Ask(t.Context(), testQuestion("s5"))
+	require.NoError(t, err)
+	_, err = svc.Grade(t.Context(), "s5", 80, nil)
+	require.NoError(t, err)
+	due, err = svc.Due(t.Context(), now.Add(PracticeDelay))
+	require.NoError(t, err)
+	require.Empty(t, due)
+}
+
+func TestGradeRubric(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(t)
+	r, ok := rubric.Find(rubric.Builtin(), "gym")
+	require.True(t, ok)
+
+	asked := testQuestion("s1")
+	asked.Rubric = r.Name
+	asked.RubricVersion = r.Version
+	_, err := svc.Ask(t.Context(), asked)
+	require.NoError(t, err)
+	_, err = svc.RevealHint(t.Context(), "s1")
+	require.NoError(t, err)
+
+	_, err = svc.GradeRubric(t.Context(), "s1", r, []rubric.DimensionScore{{Dimension: "correctness", Points: 3}}, nil)
+	require.ErrorContains(t, err, "completeness is not graded")
+
+	scores := []rubric.DimensionScore{
+		{Dimension: "correctness", Points: 3, Evidence: "It panics."},
+		{Dimension: "completeness", Points: 2},
+		{Dimension: "reasoning", Points: 0},
+	}
+	q, err := svc.GradeRubric(t.Context(), "s1", r, scores, nil)
+	require.NoError(t, err)
+	require.Equal(t, StatusAnswered, q.Status)
+	require.Equal(t, 83, *q.RawScore)
+	require.Equal(t, ApplyPenalty(83, HintNudge), *q.Score)
+	require.Equal(t, "gym", q.Rubric)
+	require.Equal(t, r.Version, q.RubricVersion)
+	require.Equal(t, scores, q.Dimensions)
+}
+
+func TestJudge(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(t)
+	_, err := svc.Ask(t.Context(), testQuestion("s1"))
+	require.NoError(t, err)
+	q, err := svc.Grade(t.Context(), "s1", 70, nil)
+	require.NoError(t, err)
+	require.Nil(t, q.Agreement)
+
+	high, low := 90, 40
+	samples := []Sample{
+		{Model: "large", Score: &high, Rationale: "thorough", Raw: `{"score": 90}`},
+		{Model: "small", Score: &low, Raw: `{"score": 40}`},
+		{Model: "small", Raw: "no idea", Err: "reply holds no JSON object"},
+	}
+	q, err = svc.Judge(t.Context(), q.ID, samples, 0.5, true)
+	require.NoError(t, err)
+	require.InDelta(t, 0.5, *q.Agreement, 0.001)
+	require.True(t, q.Flagged)
+
+	stored, err := svc.Samples(t.Context(), q.ID)
+	require.NoError(t, err)
+	require.Len(t, stored, 3)
+	require.Equal(t, "thorough", stored[0].Rationale)
+	require.Equal(t, 40, *stored[1].Score)
+	require.Nil(t, stored[2].Score)
+	require.Equal(t, "no idea", stored[2].Raw)
+
+	flagged, err := svc.Flagged(t.Context())
+	require.NoError(t, err)
+	require.Len(t, flagged, 1)
+
+	q, err = svc.Resolve(t.Context(), q.ID)
+	require.NoError(t, err)
+	require.False(t, q.Flagged)
+	require.InDelta(t, 0.5, *q.Agreement, 0.001, "resolving keeps the agreement")
+	flagged, err = svc.Flagged(t.Context())
+	require.NoError(t, err)
+	require.Empty(t, flagged)
+}
+
+func TestProgress(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(t)
+	since := time.Now().Add(-time.Hour)
+
+	_, err := svc.Ask(t.Context(), testQuestion("s1"))
+	require.NoError(t, err)
+	q, err := svc.Grade(t.Context(), "s1", 80, &message.AnswerTiming{ThinkMs: 4000, ComposeMs: 30000, Pastes: 1})
+	require.NoError(t, err)
+	require.Equal(t, &Timing{Think: 4 * time.Second, Compose: 30 * time.Second, Pastes: 1}, q.Timing)
+
+	_, err = svc.Ask(t.Context(), testQuestion("s1"))
+	require.NoError(t, err)
+	_, err = svc.Grade(t.Context(), "s1", 60, &message.AnswerTiming{ThinkMs: 8000, ComposeMs: 50000})
+	require.NoError(t, err)
+
+	// Answers without timing don't count towards the average times.
+	_, err = svc.Ask(t.Context(), testQuestion("s1"))
+	require.NoError(t, err)
+	_, err = svc.GiveUp(t.Context(), "s1")
+	require.NoError(t, err)
+
+	// Recorded questions count like graded ones.
+	recorded := testQuestion("quiz")
+	recorded.Topic = "Go maps"
+	q, err = svc.Record(t.Context(), recorded, 100)
+	require.NoError(t, err)
+	require.Equal(t, StatusAnswered, q.Status)
+	_, err = svc.Record(t.Context(), recorded, 101)
+	require.Error(t, err)
+
+	// Open questions are not counted.
+	_, err = svc.Ask(t.Context(), testQuestion("s1"))
+	require.NoError(t, err)
+
+	stats, err := svc.Progress(t.Context(), since)
+	require.NoError(t, err)
+	require.Equal(t, []TopicStats{{
+		Topic:             "Go channels",
+		Questions:         3,
+		AvgScore:          (80.0 + 60.0 + 0.0) / 3,
+		AvgHints:          float64(MaxHintLevel) / 3,
+		AvgThinkSeconds:   6,
+		AvgComposeSeconds: 40,
+		Pastes:            1,
+	}, {
+		Topic:     "Go maps",
+		Questions: 1,
+		AvgScore:  100,
+	}}, stats)
+}
+
+func TestClassification(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(t)
+	_, err := svc.Ask(t.Context(), testQuestion("s1"))
+	require.NoError(t, err)
+
+	// Open questions aren't classified.
+	unclassified, err := svc.Unclassified(t.Context())
+	require.NoError(t, err)
+	require.Empty(t, unclassified)
+
+	graded, err := svc.Grade(t.Context(), "s1", 90, nil)
+	require.NoError(t, err)
+	unclassified, err = svc.Unclassified(t.Context())
+	require.NoError(t, err)
+	require.Len(t, unclassified, 1)
+	require.Equal(t, graded.ID, unclassified[0].ID)
+
+	q, err := svc.SetNode(t.Context(), graded.ID, "Go > Concurrency > Channels")
+	require.NoError(t, err)
+	require.Equal(t, "Go > Concurrency > Channels", q.Node)
+
+	unclassified, err = svc.Unclassified(t.Context())
+	require.NoError(t, err)
+	require.Empty(t, unclassified)
+	classified, err := svc.Classified(t.Context())
+	require.NoError(t, err)
+	require.Len(t, classified, 1)
+	require.Equal(t, 90, *classified[0].Score)
+}
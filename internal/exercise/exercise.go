@@ -0,0 +1,204 @@
+// Package exercise loads practice exercises, such as quizzes or SQL
+// exercises, from YAML files kept in a list of directories.
+package exercise
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/trankhanh040147/prepf/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Extensions lists the exercise file extensions. JSON is a subset of YAML,
+// so both are parsed the same way.
+var Extensions = []string{".yaml", ".yml", ".json"}
+
+// Exercise is implemented by the exercise types a Loader loads.
+type Exercise interface {
+	// Validate reports what is wrong with a loaded exercise.
+	Validate() error
+	// ExerciseName returns the name the exercise was loaded under.
+	ExerciseName() string
+	// SetOrigin records the name the exercise is loaded under and the path
+	// it was loaded from.
+	SetOrigin(name, path string)
+}
+
+// Ptr constrains the pointer to an exercise type a Loader returns.
+type Ptr[T any] interface {
+	*T
+	Exercise
+}
+
+// Loader loads one kind of exercise.
+type Loader[T any, P Ptr[T]] struct {
+	// Kind names an exercise in messages, as in "quiz".
+	Kind string
+	// Dir is the name of the directory exercises are kept in, both in the
+	// data directory and next to the global config.
+	Dir string
+	// Manifest, when set, makes every directory holding a file of that name
+	// an exercise, named after the directory. Otherwise every file with one
+	// of the Extensions is an exercise, named after the file.
+	Manifest string
+}
+
+// Problem describes an exercise that could not be loaded.
+type Problem struct {
+	Path string
+	Err  error
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("%s: %v", p.Path, p.Err)
+}
+
+// Dirs returns the directories exercises are loaded from, highest priority
+// first. Generated exercises are saved to the first one.
+func (l Loader[T, P]) Dirs(cfg *config.Config) []string {
+	return []string{
+		filepath.Join(cfg.Options.DataDirectory, l.Dir),
+		filepath.Join(filepath.Dir(config.GlobalConfig()), l.Dir),
+	}
+}
+
+// Load parses the exercise at path, which is a file, or a directory holding
+// the manifest.
+func (l Loader[T, P]) Load(path string) (P, error) {
+	file, name := path, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if l.Manifest != "" {
+		file, name = filepath.Join(path, l.Manifest), filepath.Base(path)
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	e := P(new(T))
+	if err := yaml.Unmarshal(content, e); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", l.Kind, err)
+	}
+	e.SetOrigin(name, path)
+	return e, nil
+}
+
+// Discover finds the valid exercises in the given directories, sorted by
+// name. An exercise in an earlier directory hides one with the same name in
+// a later one.
+func (l Loader[T, P]) Discover(dirs []string) ([]P, []Problem) {
+	var exercises []P
+	var problems []Problem
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				problems = append(problems, Problem{Path: dir, Err: err})
+			}
+			continue
+		}
+		for _, entry := range entries {
+			if !l.mayHold(entry) {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			e, err := l.Load(path)
+			if l.Manifest != "" && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err == nil {
+				err = e.Validate()
+			}
+			if err != nil {
+				slog.Warn("Failed to load "+l.Kind, "path", path, "error", err)
+				problems = append(problems, Problem{Path: path, Err: err})
+				continue
+			}
+			if seen[e.ExerciseName()] {
+				continue
+			}
+			seen[e.ExerciseName()] = true
+			exercises = append(exercises, e)
+		}
+	}
+	slices.SortFunc(exercises, func(a, b P) int {
+		return strings.Compare(a.ExerciseName(), b.ExerciseName())
+	})
+	return exercises, problems
+}
+
+// mayHold reports whether the directory entry can be an exercise.
+func (l Loader[T, P]) mayHold(entry fs.DirEntry) bool {
+	if l.Manifest != "" {
+		return entry.IsDir()
+	}
+	return !entry.IsDir() && slices.Contains(Extensions, filepath.Ext(entry.Name()))
+}
+
+// Find returns the exercise with the given name, if any.
+func (l Loader[T, P]) Find(exercises []P, name string) (P, bool) {
+	for _, e := range exercises {
+		if e.ExerciseName() == name {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Save validates the exercise and writes it to dir as YAML, named after the
+// title. An existing exercise with the same name is kept and a numeric suffix
+// added. Exercises with a manifest are directories and can't be saved.
+func (l Loader[T, P]) Save(dir, title string, e P) (string, error) {
+	if l.Manifest != "" {
+		return "", fmt.Errorf("cannot save a %s, which is a directory", l.Kind)
+	}
+	if err := e.Validate(); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	content, err := yaml.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+
+	base := slug(title)
+	if base == "" {
+		base = slug(l.Kind)
+	}
+	name := base
+	for i := 2; ; i++ {
+		path := filepath.Join(dir, name+".yaml")
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			name = fmt.Sprintf("%s-%d", base, i)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.Write(content); err != nil {
+			f.Close()
+			return "", err
+		}
+		if err := f.Close(); err != nil {
+			return "", err
+		}
+		e.SetOrigin(name, path)
+		return path, nil
+	}
+}
+
+func slug(s string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
@@ -0,0 +1,107 @@
+package exercise
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type card struct {
+	Name   string `yaml:"-"`
+	Path   string `yaml:"-"`
+	Title  string `yaml:"title"`
+	Answer string `yaml:"answer"`
+}
+
+func (c *card) Validate() error {
+	if c.Answer == "" {
+		return errors.New("answer is required")
+	}
+	return nil
+}
+
+func (c *card) ExerciseName() string { return c.Name }
+
+func (c *card) SetOrigin(name, path string) { c.Name, c.Path = name, path }
+
+var cards = Loader[card, *card]{Kind: "card", Dir: "cards"}
+
+func write(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	project, global := t.TempDir(), t.TempDir()
+	write(t, filepath.Join(project, "maps.yaml"), "title: Maps\nanswer: hash\n")
+	write(t, filepath.Join(global, "maps.json"), `{"title": "Shadowed", "answer": "tree"}`)
+	write(t, filepath.Join(global, "joins.yml"), "title: Joins\nanswer: hash join\n")
+	write(t, filepath.Join(global, "broken.yaml"), "title: Broken\n")
+	write(t, filepath.Join(global, "notes.txt"), "not a card")
+
+	found, problems := cards.Discover([]string{project, global, filepath.Join(project, "missing")})
+	require.Len(t, found, 2)
+	require.Equal(t, "joins", found[0].Name)
+	require.Equal(t, filepath.Join(global, "joins.yml"), found[0].Path)
+	require.Len(t, problems, 1)
+	require.Equal(t, filepath.Join(global, "broken.yaml"), problems[0].Path)
+
+	c, ok := cards.Find(found, "maps")
+	require.True(t, ok)
+	require.Equal(t, "Maps", c.Title, "earlier directories win")
+	_, ok = cards.Find(found, "broken")
+	require.False(t, ok)
+}
+
+func TestDiscoverManifest(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	decks := Loader[card, *card]{Kind: "deck", Dir: "decks", Manifest: "deck.yaml"}
+	write(t, filepath.Join(dir, "paging", "deck.yaml"), "title: Paging\nanswer: off by one\n")
+	write(t, filepath.Join(dir, "broken", "deck.yaml"), "title: Broken\n")
+	write(t, filepath.Join(dir, "notes", "readme.md"), "not a deck")
+	write(t, filepath.Join(dir, "loose.yaml"), "title: Loose\nanswer: a\n")
+
+	found, problems := decks.Discover([]string{dir})
+	require.Len(t, found, 1)
+	require.Equal(t, "paging", found[0].Name)
+	require.Equal(t, filepath.Join(dir, "paging"), found[0].Path)
+	require.Len(t, problems, 1)
+	require.Equal(t, filepath.Join(dir, "broken"), problems[0].Path)
+
+	_, err := decks.Save(dir, "Paging", found[0])
+	require.Error(t, err)
+}
+
+func TestSave(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	c := &card{Title: "Go Maps!", Answer: "hash"}
+	path, err := cards.Save(dir, c.Title, c)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "go-maps.yaml"), path)
+	require.Equal(t, "go-maps", c.Name)
+
+	loaded, err := cards.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, c, loaded)
+
+	second, err := cards.Save(dir, "Go maps", &card{Answer: "hash"})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "go-maps-2.yaml"), second)
+
+	untitled, err := cards.Save(dir, "", &card{Answer: "hash"})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "card.yaml"), untitled)
+
+	_, err = cards.Save(dir, "Empty", &card{})
+	require.Error(t, err)
+}
@@ -62,6 +62,19 @@ func RecordWrite(path string) {
 	records[path] = rec
 }
 
+// LastWriteTime returns when a file was last written. Returns zero time if
+// never written.
+func LastWriteTime(path string) time.Time {
+	recordMutex.RLock()
+	defer recordMutex.RUnlock()
+
+	rec, exists := records[path]
+	if !exists {
+		return time.Time{}
+	}
+	return rec.writeTime
+}
+
 // Reset clears all file tracking records. Useful for testing.
 func Reset() {
 	recordMutex.Lock()
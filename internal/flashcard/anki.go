@@ -0,0 +1,35 @@
+package flashcard
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// WriteAnki writes cards as a tab-separated file Anki can import with
+// File > Import. The header lines tell Anki the separator and which column
+// holds the tags, so no import options need changing. Each card is tagged
+// with "prepf" and its topic.
+func WriteAnki(w io.Writer, cards []Card) error {
+	if _, err := io.WriteString(w, "#separator:tab\n#html:false\n#tags column:3\n"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = '\t'
+	for _, c := range cards {
+		if err := cw.Write([]string{c.Front, c.Back, ankiTags(c.Topic)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ankiTags turns a topic into tags, which can't contain spaces.
+func ankiTags(topic string) string {
+	tags := []string{"prepf"}
+	if topic = strings.Join(strings.Fields(topic), "_"); topic != "" {
+		tags = append(tags, topic)
+	}
+	return strings.Join(tags, " ")
+}
@@ -0,0 +1,245 @@
+// Package flashcard stores the cards extracted from corrections in mock and
+// gym sessions and schedules their reviews with spaced repetition.
+package flashcard
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/pubsub"
+)
+
+// Grade is the user's assessment of how well they recalled a card.
+type Grade int
+
+const (
+	GradeAgain Grade = iota + 1
+	GradeHard
+	GradeGood
+	GradeEasy
+)
+
+func (g Grade) String() string {
+	switch g {
+	case GradeAgain:
+		return "again"
+	case GradeHard:
+		return "hard"
+	case GradeGood:
+		return "good"
+	case GradeEasy:
+		return "easy"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// DefaultEase is the interval multiplier of a new card, in thousandths.
+	DefaultEase = 2500
+	// MinEase keeps cards that are often forgotten from being shown every
+	// day forever.
+	MinEase = 1300
+	// RelearnDelay is when a forgotten card is shown again.
+	RelearnDelay = 10 * time.Minute
+)
+
+// Card is a front/back flashcard with its review schedule.
+type Card struct {
+	ID string `json:"id"`
+	// SessionID and MessageID point at the reply the card was extracted
+	// from.
+	SessionID string `json:"session_id"`
+	MessageID string `json:"message_id"`
+	Topic     string `json:"topic"`
+	Front     string `json:"front"`
+	Back      string `json:"back"`
+
+	// IntervalDays is the number of days until the next review after the
+	// last successful one.
+	IntervalDays int   `json:"interval_days"`
+	Ease         int   `json:"ease"`
+	Reps         int   `json:"reps"`
+	Lapses       int   `json:"lapses"`
+	DueAt        int64 `json:"due_at"`
+	ReviewedAt   int64 `json:"reviewed_at,omitempty"`
+	CreatedAt    int64 `json:"created_at"`
+	UpdatedAt    int64 `json:"updated_at"`
+}
+
+// Schedule returns the card rescheduled after being graded at now. It is a
+// variant of SM-2: forgotten cards start over and lose ease, hard cards grow
+// slowly, and good and easy ones grow by their ease.
+func Schedule(c Card, grade Grade, now time.Time) Card {
+	if c.Ease == 0 {
+		c.Ease = DefaultEase
+	}
+	c.ReviewedAt = now.Unix()
+	if grade == GradeAgain {
+		c.Reps = 0
+		c.Lapses++
+		c.IntervalDays = 0
+		c.Ease = max(c.Ease-200, MinEase)
+		c.DueAt = now.Add(RelearnDelay).Unix()
+		return c
+	}
+
+	var interval float64
+	switch {
+	case grade == GradeHard:
+		c.Ease = max(c.Ease-150, MinEase)
+		interval = max(float64(c.IntervalDays)*1.2, 1)
+	case c.Reps == 0:
+		interval = 1
+	case c.Reps == 1:
+		interval = 3
+	default:
+		interval = float64(c.IntervalDays) * float64(c.Ease) / 1000
+	}
+	if grade == GradeEasy {
+		c.Ease += 150
+		interval = max(interval*1.3, 4)
+	}
+	c.Reps++
+	c.IntervalDays = max(int(math.Round(interval)), c.IntervalDays+1)
+	c.DueAt = now.AddDate(0, 0, c.IntervalDays).Unix()
+	return c
+}
+
+type Service interface {
+	pubsub.Subscriber[Card]
+	// Create stores new cards, due for review straight away. Cards with an
+	// empty front or back are rejected.
+	Create(ctx context.Context, cards ...Card) ([]Card, error)
+	Get(ctx context.Context, id string) (Card, error)
+	List(ctx context.Context) ([]Card, error)
+	// Due lists the cards due for review at now, most overdue first.
+	Due(ctx context.Context, now time.Time) ([]Card, error)
+	// Review records a self-grade and reschedules the card.
+	Review(ctx context.Context, id string, grade Grade) (Card, error)
+}
+
+type service struct {
+	*pubsub.Broker[Card]
+	q   db.Querier
+	now func() time.Time
+}
+
+func NewService(q db.Querier) Service {
+	return &service{
+		Broker: pubsub.NewBroker(pubsub.WithName[Card]("flashcards")),
+		q:      q,
+		now:    time.Now,
+	}
+}
+
+func (s *service) Create(ctx context.Context, cards ...Card) ([]Card, error) {
+	for _, c := range cards {
+		if strings.TrimSpace(c.Front) == "" || strings.TrimSpace(c.Back) == "" {
+			return nil, errors.New("cards need a front and a back")
+		}
+	}
+	created := make([]Card, 0, len(cards))
+	for _, c := range cards {
+		row, err := s.q.CreateFlashcard(ctx, db.CreateFlashcardParams{
+			ID:        uuid.New().String(),
+			SessionID: c.SessionID,
+			MessageID: c.MessageID,
+			Topic:     c.Topic,
+			Front:     strings.TrimSpace(c.Front),
+			Back:      strings.TrimSpace(c.Back),
+			DueAt:     s.now().Unix(),
+		})
+		if err != nil {
+			return created, err
+		}
+		card := fromDB(row)
+		s.Publish(pubsub.CreatedEvent, card)
+		created = append(created, card)
+	}
+	return created, nil
+}
+
+func (s *service) Get(ctx context.Context, id string) (Card, error) {
+	row, err := s.q.GetFlashcard(ctx, id)
+	if err != nil {
+		return Card{}, err
+	}
+	return fromDB(row), nil
+}
+
+func (s *service) List(ctx context.Context) ([]Card, error) {
+	rows, err := s.q.ListFlashcards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fromDBRows(rows), nil
+}
+
+func (s *service) Due(ctx context.Context, now time.Time) ([]Card, error) {
+	rows, err := s.q.ListDueFlashcards(ctx, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	return fromDBRows(rows), nil
+}
+
+func (s *service) Review(ctx context.Context, id string, grade Grade) (Card, error) {
+	if grade < GradeAgain || grade > GradeEasy {
+		return Card{}, fmt.Errorf("invalid grade %d", grade)
+	}
+	c, err := s.Get(ctx, id)
+	if err != nil {
+		return Card{}, err
+	}
+	c = Schedule(c, grade, s.now())
+	row, err := s.q.UpdateFlashcardReview(ctx, db.UpdateFlashcardReviewParams{
+		ID:           c.ID,
+		IntervalDays: int64(c.IntervalDays),
+		Ease:         int64(c.Ease),
+		Reps:         int64(c.Reps),
+		Lapses:       int64(c.Lapses),
+		DueAt:        c.DueAt,
+		ReviewedAt:   sql.NullInt64{Int64: c.ReviewedAt, Valid: true},
+	})
+	if err != nil {
+		return Card{}, err
+	}
+	updated := fromDB(row)
+	s.Publish(pubsub.UpdatedEvent, updated)
+	return updated, nil
+}
+
+func fromDBRows(rows []db.Flashcard) []Card {
+	cards := make([]Card, len(rows))
+	for i, row := range rows {
+		cards[i] = fromDB(row)
+	}
+	return cards
+}
+
+func fromDB(row db.Flashcard) Card {
+	return Card{
+		ID:           row.ID,
+		SessionID:    row.SessionID,
+		MessageID:    row.MessageID,
+		Topic:        row.Topic,
+		Front:        row.Front,
+		Back:         row.Back,
+		IntervalDays: int(row.IntervalDays),
+		Ease:         int(row.Ease),
+		Reps:         int(row.Reps),
+		Lapses:       int(row.Lapses),
+		DueAt:        row.DueAt,
+		ReviewedAt:   row.ReviewedAt.Int64,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+	}
+}
@@ -0,0 +1,104 @@
+package flashcard
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/db"
+)
+
+func TestSchedule(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	c := Card{}
+
+	c = Schedule(c, GradeGood, now)
+	require.Equal(t, 1, c.IntervalDays)
+	require.Equal(t, now.AddDate(0, 0, 1).Unix(), c.DueAt)
+
+	c = Schedule(c, GradeGood, now)
+	require.Equal(t, 3, c.IntervalDays)
+
+	c = Schedule(c, GradeGood, now)
+	require.Equal(t, 8, c.IntervalDays)
+	require.Equal(t, DefaultEase, c.Ease)
+
+	c = Schedule(c, GradeHard, now)
+	require.Equal(t, 10, c.IntervalDays)
+	require.Equal(t, DefaultEase-150, c.Ease)
+
+	c = Schedule(c, GradeAgain, now)
+	require.Equal(t, 0, c.IntervalDays)
+	require.Equal(t, 0, c.Reps)
+	require.Equal(t, 1, c.Lapses)
+	require.Equal(t, now.Add(RelearnDelay).Unix(), c.DueAt)
+
+	c = Schedule(c, GradeEasy, now)
+	require.Equal(t, 4, c.IntervalDays)
+	require.Equal(t, DefaultEase-200, c.Ease)
+
+	// Ease never drops below the minimum.
+	for range 20 {
+		c = Schedule(c, GradeAgain, now)
+	}
+	require.Equal(t, MinEase, c.Ease)
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	svc := NewService(db.New(conn)).(*service)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	_, err = svc.Create(t.Context(), Card{Front: "What does a nil map panic on?"})
+	require.Error(t, err)
+
+	cards, err := svc.Create(t.Context(),
+		Card{SessionID: "s1", MessageID: "m1", Topic: "Go maps", Front: "What does a nil map panic on?", Back: "Writes; reads return the zero value."},
+		Card{SessionID: "s1", MessageID: "m1", Topic: "Go maps", Front: "Is map iteration order stable?", Back: "No, it is randomized."},
+	)
+	require.NoError(t, err)
+	require.Len(t, cards, 2)
+
+	due, err := svc.Due(t.Context(), now)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+
+	reviewed, err := svc.Review(t.Context(), cards[0].ID, GradeGood)
+	require.NoError(t, err)
+	require.Equal(t, 1, reviewed.Reps)
+	require.Equal(t, now.Unix(), reviewed.ReviewedAt)
+
+	due, err = svc.Due(t.Context(), now)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, cards[1].ID, due[0].ID)
+
+	_, err = svc.Review(t.Context(), cards[1].ID, Grade(7))
+	require.Error(t, err)
+
+	all, err := svc.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+}
+
+func TestWriteAnki(t *testing.T) {
+	t.Parallel()
+
+	var sb strings.Builder
+	err := WriteAnki(&sb, []Card{
+		{Topic: "Go maps", Front: "Nil map writes?", Back: "They panic."},
+		{Front: "Two\tcolumns", Back: "Line one\nline two"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "#separator:tab\n#html:false\n#tags column:3\n"+
+		"Nil map writes?\tThey panic.\tprepf Go_maps\n"+
+		"\"Two\tcolumns\"\t\"Line one\nline two\"\tprepf\n", sb.String())
+}
@@ -21,20 +21,38 @@ type File struct {
 	Path      string
 	Content   string
 	Version   int64
-	CreatedAt int64
-	UpdatedAt int64
+	// ToolCallID is the tool call that wrote the version, empty for the
+	// snapshots taken before a change.
+	ToolCallID string
+	CreatedAt  int64
+	UpdatedAt  int64
 }
 
 type Service interface {
 	pubsub.Subscriber[File]
 	Create(ctx context.Context, sessionID, path, content string) (File, error)
 	CreateVersion(ctx context.Context, sessionID, path, content string) (File, error)
+	// CreateToolCallVersion records the version a tool call wrote, so the
+	// change can be reverted later.
+	CreateToolCallVersion(ctx context.Context, sessionID, toolCallID, path, content string) (File, error)
 	Get(ctx context.Context, id string) (File, error)
 	GetByPathAndSession(ctx context.Context, path, sessionID string) (File, error)
 	ListBySession(ctx context.Context, sessionID string) ([]File, error)
 	ListLatestSessionFiles(ctx context.Context, sessionID string) ([]File, error)
 	Delete(ctx context.Context, id string) error
 	DeleteSessionFiles(ctx context.Context, sessionID string) error
+
+	// PlanRevert returns the changes that undo the given tool calls of a
+	// session, and PlanRestore the one that puts a file back to a version.
+	// A file goes back to its version before the first of the calls, which
+	// also undoes later calls to it; Change.Discards lists them. Neither
+	// touches the files; see Revert.
+	PlanRevert(ctx context.Context, sessionID string, toolCallIDs []string) ([]Change, error)
+	PlanRestore(ctx context.Context, sessionID, path string, version int64) (Change, error)
+	// Revert applies the changes and records the restored contents as new
+	// versions, so a revert can itself be reverted. Unless force is set,
+	// nothing is changed when a file was modified since its latest version.
+	Revert(ctx context.Context, sessionID string, changes []Change, force bool) error
 }
 
 type service struct {
@@ -45,17 +63,25 @@ type service struct {
 
 func NewService(q *db.Queries, db *sql.DB) Service {
 	return &service{
-		Broker: pubsub.NewBroker[File](),
+		Broker: pubsub.NewBroker(pubsub.WithName[File]("history")),
 		q:      q,
 		db:     db,
 	}
 }
 
 func (s *service) Create(ctx context.Context, sessionID, path, content string) (File, error) {
-	return s.createWithVersion(ctx, sessionID, path, content, InitialVersion)
+	return s.createWithVersion(ctx, sessionID, path, content, InitialVersion, "")
 }
 
 func (s *service) CreateVersion(ctx context.Context, sessionID, path, content string) (File, error) {
+	return s.createNextVersion(ctx, sessionID, path, content, "")
+}
+
+func (s *service) CreateToolCallVersion(ctx context.Context, sessionID, toolCallID, path, content string) (File, error) {
+	return s.createNextVersion(ctx, sessionID, path, content, toolCallID)
+}
+
+func (s *service) createNextVersion(ctx context.Context, sessionID, path, content, toolCallID string) (File, error) {
 	// Get the latest version for this path
 	files, err := s.q.ListFilesByPath(ctx, path)
 	if err != nil {
@@ -64,17 +90,17 @@ func (s *service) CreateVersion(ctx context.Context, sessionID, path, content st
 
 	if len(files) == 0 {
 		// No previous versions, create initial
-		return s.Create(ctx, sessionID, path, content)
+		return s.createWithVersion(ctx, sessionID, path, content, InitialVersion, toolCallID)
 	}
 
 	// Get the latest version
 	latestFile := files[0] // Files are ordered by version DESC, created_at DESC
 	nextVersion := latestFile.Version + 1
 
-	return s.createWithVersion(ctx, sessionID, path, content, nextVersion)
+	return s.createWithVersion(ctx, sessionID, path, content, nextVersion, toolCallID)
 }
 
-func (s *service) createWithVersion(ctx context.Context, sessionID, path, content string, version int64) (File, error) {
+func (s *service) createWithVersion(ctx context.Context, sessionID, path, content string, version int64, toolCallID string) (File, error) {
 	// Maximum number of retries for transaction conflicts
 	const maxRetries = 3
 	var file File
@@ -93,11 +119,12 @@ func (s *service) createWithVersion(ctx context.Context, sessionID, path, conten
 
 		// Try to create the file within the transaction
 		dbFile, txErr := qtx.CreateFile(ctx, db.CreateFileParams{
-			ID:        uuid.New().String(),
-			SessionID: sessionID,
-			Path:      path,
-			Content:   content,
-			Version:   version,
+			ID:         uuid.New().String(),
+			SessionID:  sessionID,
+			Path:       path,
+			Content:    content,
+			Version:    version,
+			ToolCallID: toolCallID,
 		})
 		if txErr != nil {
 			// Rollback the transaction
@@ -199,12 +226,13 @@ func (s *service) DeleteSessionFiles(ctx context.Context, sessionID string) erro
 
 func (s *service) fromDBItem(item db.File) File {
 	return File{
-		ID:        item.ID,
-		SessionID: item.SessionID,
-		Path:      item.Path,
-		Content:   item.Content,
-		Version:   item.Version,
-		CreatedAt: item.CreatedAt,
-		UpdatedAt: item.UpdatedAt,
+		ID:         item.ID,
+		SessionID:  item.SessionID,
+		Path:       item.Path,
+		Content:    item.Content,
+		Version:    item.Version,
+		ToolCallID: item.ToolCallID,
+		CreatedAt:  item.CreatedAt,
+		UpdatedAt:  item.UpdatedAt,
 	}
 }
@@ -0,0 +1,202 @@
+package history
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/trankhanh040147/prepf/internal/filetracker"
+	"github.com/trankhanh040147/prepf/internal/message"
+)
+
+// ErrConflict is returned, wrapped with the path, for files that changed on
+// disk since their latest recorded version: reverting them would lose those
+// changes.
+var ErrConflict = errors.New("changed on disk since its latest version")
+
+// Change puts a file back the way an earlier version recorded it.
+type Change struct {
+	Path string
+	// Latest is the latest version of the file in the session, which the
+	// file on disk must still match.
+	Latest File
+	// Target is the version the file is restored to. Remove is set when
+	// the target is the empty snapshot taken before the session created
+	// the file, so restoring it means removing the file.
+	Target File
+	Remove bool
+	// Discards lists the other tool calls that changed the file after the
+	// ones being undone. The file goes back to a whole earlier version, so
+	// their changes are undone as well.
+	Discards []string
+}
+
+// ToolCallsFrom returns the tool calls of the message and of every message
+// after it, i.e. what reverting the session to before the message undoes.
+func ToolCallsFrom(messages []message.Message, messageID string) ([]string, error) {
+	i := slices.IndexFunc(messages, func(m message.Message) bool { return m.ID == messageID })
+	if i < 0 {
+		return nil, fmt.Errorf("message %s not found in the session", messageID)
+	}
+	var ids []string
+	for _, m := range messages[i:] {
+		for _, call := range m.ToolCalls() {
+			ids = append(ids, call.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *service) PlanRevert(ctx context.Context, sessionID string, toolCallIDs []string) ([]Change, error) {
+	versions, err := s.versionsByPath(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for _, files := range versions {
+		first := slices.IndexFunc(files, func(f File) bool {
+			return f.ToolCallID != "" && slices.Contains(toolCallIDs, f.ToolCallID)
+		})
+		// The tools snapshot a file before changing it, so there is always
+		// a version before the first one a tool call wrote. Without it
+		// there is nothing to go back to.
+		if first < 1 {
+			continue
+		}
+		change := newChange(files, first-1)
+		if !change.changes() {
+			continue
+		}
+		for _, f := range files[first+1:] {
+			if f.ToolCallID != "" && !slices.Contains(toolCallIDs, f.ToolCallID) && !slices.Contains(change.Discards, f.ToolCallID) {
+				change.Discards = append(change.Discards, f.ToolCallID)
+			}
+		}
+		changes = append(changes, change)
+	}
+	slices.SortFunc(changes, func(a, b Change) int { return cmp.Compare(a.Path, b.Path) })
+	return changes, nil
+}
+
+func (s *service) PlanRestore(ctx context.Context, sessionID, path string, version int64) (Change, error) {
+	versions, err := s.versionsByPath(ctx, sessionID)
+	if err != nil {
+		return Change{}, err
+	}
+	files, ok := versions[path]
+	if !ok {
+		return Change{}, fmt.Errorf("no history of %s in this session", path)
+	}
+	i := slices.IndexFunc(files, func(f File) bool { return f.Version == version })
+	if i < 0 {
+		return Change{}, fmt.Errorf("no version %d of %s in this session", version, path)
+	}
+	return newChange(files, i), nil
+}
+
+func (s *service) Revert(ctx context.Context, sessionID string, changes []Change, force bool) error {
+	if !force {
+		var errs []error
+		for _, c := range changes {
+			errs = append(errs, checkConflict(c))
+		}
+		if err := errors.Join(errs...); err != nil {
+			return err
+		}
+	}
+	for _, c := range changes {
+		if err := c.apply(); err != nil {
+			return fmt.Errorf("reverting %s: %w", c.Path, err)
+		}
+		filetracker.RecordWrite(c.Path)
+		if _, err := s.CreateVersion(ctx, sessionID, c.Path, c.content()); err != nil {
+			return fmt.Errorf("recording the revert of %s: %w", c.Path, err)
+		}
+	}
+	return nil
+}
+
+// versionsByPath returns the versions of each file changed in the session,
+// oldest first.
+func (s *service) versionsByPath(ctx context.Context, sessionID string) (map[string][]File, error) {
+	files, err := s.ListBySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string][]File)
+	for _, f := range files {
+		versions[f.Path] = append(versions[f.Path], f)
+	}
+	return versions, nil
+}
+
+func newChange(files []File, target int) Change {
+	return Change{
+		Path:   files[target].Path,
+		Latest: files[len(files)-1],
+		Target: files[target],
+		Remove: target == 0 && files[0].Content == "" && files[0].ToolCallID == "",
+	}
+}
+
+// content is what the file holds after the change; a removed file is
+// recorded as empty, like before it was created.
+func (c Change) content() string {
+	if c.Remove {
+		return ""
+	}
+	return c.Target.Content
+}
+
+// changes reports whether applying the change does anything.
+func (c Change) changes() bool {
+	return c.Remove || c.Target.Content != c.Latest.Content
+}
+
+func (c Change) apply() error {
+	if c.Remove {
+		if err := os.Remove(c.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	// A file that is still there keeps its mode, e.g. stays executable.
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(c.Path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.Path, []byte(c.Target.Content), mode)
+}
+
+// checkConflict makes sure the file on disk is still the latest version.
+// When prepf wrote the file in this process, the error also says when.
+func checkConflict(c Change) error {
+	data, err := os.ReadFile(c.Path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		if c.Latest.Content == "" {
+			return nil
+		}
+		return fmt.Errorf("%s was removed: %w", c.Path, ErrConflict)
+	case err != nil:
+		return err
+	case string(data) == c.Latest.Content:
+		return nil
+	}
+	if written := filetracker.LastWriteTime(c.Path); !written.IsZero() {
+		if info, err := os.Stat(c.Path); err == nil && info.ModTime().After(written) {
+			return fmt.Errorf("%s was modified at %s, after prepf last wrote it at %s: %w",
+				c.Path, info.ModTime().Format(time.TimeOnly), written.Format(time.TimeOnly), ErrConflict)
+		}
+	}
+	return fmt.Errorf("%s: %w", c.Path, ErrConflict)
+}
@@ -0,0 +1,125 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/message"
+)
+
+func TestRevert(t *testing.T) {
+	t.Parallel()
+
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	q := db.New(conn)
+	_, err = q.CreateSession(t.Context(), db.CreateSessionParams{ID: "s1"})
+	require.NoError(t, err)
+	files := NewService(q, conn)
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "main.go")
+	created := filepath.Join(dir, "pkg", "util.go")
+	require.NoError(t, os.WriteFile(existing, []byte("v1"), 0o755))
+
+	// write records what the tools do: snapshot the file, change it, then
+	// record the change under the tool call.
+	write := func(call, path, content string) {
+		t.Helper()
+		if _, err := files.GetByPathAndSession(t.Context(), path, "s1"); err != nil {
+			old, _ := os.ReadFile(path)
+			_, err := files.Create(t.Context(), "s1", path, string(old))
+			require.NoError(t, err)
+		}
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		_, err := files.CreateToolCallVersion(t.Context(), "s1", call, path, content)
+		require.NoError(t, err)
+	}
+	write("c1", existing, "v2")
+	write("c2", created, "package pkg")
+	write("c3", existing, "v3")
+
+	requireContent := func(path, content string) {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, content, string(data))
+	}
+
+	// Undoing a single call goes back to the version before it, undoing
+	// the later calls to the same file too.
+	changes, err := files.PlanRevert(t.Context(), "s1", []string{"c1"})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, "v1", changes[0].Target.Content)
+	require.Equal(t, []string{"c3"}, changes[0].Discards)
+
+	changes, err = files.PlanRevert(t.Context(), "s1", []string{"c3"})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, "v2", changes[0].Target.Content)
+	require.Empty(t, changes[0].Discards)
+	require.NoError(t, files.Revert(t.Context(), "s1", changes, false))
+	requireContent(existing, "v2")
+	info, err := os.Stat(existing)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o755), info.Mode().Perm(), "the file keeps its mode")
+
+	changes, err = files.PlanRevert(t.Context(), "s1", []string{"c1", "c2", "c3"})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, existing, changes[0].Path)
+	require.Equal(t, "v1", changes[0].Target.Content)
+	require.Equal(t, created, changes[1].Path)
+	require.True(t, changes[1].Remove)
+
+	// A file changed behind prepf's back is a conflict and nothing is
+	// reverted, unless forced.
+	require.NoError(t, os.WriteFile(created, []byte("package pkg // mine"), 0o644))
+	err = files.Revert(t.Context(), "s1", changes, false)
+	require.ErrorIs(t, err, ErrConflict)
+	require.ErrorContains(t, err, created)
+	requireContent(existing, "v2")
+
+	require.NoError(t, files.Revert(t.Context(), "s1", changes, true))
+	requireContent(existing, "v1")
+	require.NoFileExists(t, created)
+
+	// Reverts are recorded, so the versions before them can be restored.
+	versions, err := files.ListBySession(t.Context(), "s1")
+	require.NoError(t, err)
+	var v3 File
+	for _, f := range versions {
+		if f.ToolCallID == "c3" {
+			v3 = f
+		}
+	}
+	change, err := files.PlanRestore(t.Context(), "s1", existing, v3.Version)
+	require.NoError(t, err)
+	require.NoError(t, files.Revert(t.Context(), "s1", []Change{change}, false))
+	requireContent(existing, "v3")
+
+	_, err = files.PlanRestore(t.Context(), "s1", existing, 99)
+	require.ErrorContains(t, err, "no version 99")
+}
+
+func TestToolCallsFrom(t *testing.T) {
+	t.Parallel()
+
+	messages := []message.Message{
+		{ID: "m1", Parts: []message.ContentPart{message.ToolCall{ID: "c1"}}},
+		{ID: "m2", Parts: []message.ContentPart{message.TextContent{Text: "undo that"}}},
+		{ID: "m3", Parts: []message.ContentPart{message.ToolCall{ID: "c2"}, message.ToolCall{ID: "c3"}}},
+	}
+	ids, err := ToolCallsFrom(messages, "m2")
+	require.NoError(t, err)
+	require.Equal(t, []string{"c2", "c3"}, ids)
+
+	_, err = ToolCallsFrom(messages, "m4")
+	require.Error(t, err)
+}
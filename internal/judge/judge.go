@@ -0,0 +1,195 @@
+// Package judge grades an answer with several independent grader calls and
+// aggregates their grades into one, measuring how well the graders agreed
+// so that unreliable grades can be flagged for attention.
+package judge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/rubric"
+)
+
+// Verdict is the aggregate of the grader calls on an answer.
+type Verdict struct {
+	Samples []drill.Sample
+	// Score is the median 0-100 grade. For an answer graded against a
+	// rubric it is scored from Dimensions, the median points given on each
+	// dimension.
+	Score      int
+	Dimensions []rubric.DimensionScore
+	// Graded is the number of samples with a usable grade.
+	Graded int
+	// Agreement is the share of usable grades within the tolerance of the
+	// median.
+	Agreement float64
+	// Flagged is set when the graders disagreed or too few of them gave a
+	// usable grade.
+	Flagged bool
+}
+
+// Run makes n grader calls, at most limit at a time, and returns their
+// samples in call order. Calls still pending when ctx is done are recorded
+// as failed.
+func Run(ctx context.Context, n, limit int, grade func(ctx context.Context, i int) drill.Sample) []drill.Sample {
+	samples := make([]drill.Sample, n)
+	sem := make(chan struct{}, max(limit, 1))
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Go(func() {
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				samples[i] = drill.Sample{Err: ctx.Err().Error()}
+				return
+			}
+			samples[i] = grade(ctx, i)
+		})
+	}
+	wg.Wait()
+	return samples
+}
+
+// reply is the shape grader calls are asked to reply in.
+type reply struct {
+	Score      *int                    `json:"score"`
+	Dimensions []rubric.DimensionScore `json:"dimensions"`
+	Rationale  string                  `json:"rationale"`
+}
+
+var (
+	thinkTags = regexp.MustCompile(`(?s)<think>.*?</think>`)
+	fences    = regexp.MustCompile("(?m)^```[a-z]*\\s*$")
+)
+
+// Parse reads a grader's reply into the sample, keeping the raw reply.
+// Against a rubric the reply must grade every dimension with the points of
+// one of its levels, otherwise it must give a 0-100 score.
+func Parse(sample *drill.Sample, text string, r *rubric.Rubric) {
+	sample.Raw = text
+	if err := parse(sample, text, r); err != nil {
+		sample.Err = err.Error()
+		sample.Score = nil
+		sample.Dimensions = nil
+	}
+}
+
+func parse(sample *drill.Sample, text string, r *rubric.Rubric) error {
+	text = fences.ReplaceAllString(thinkTags.ReplaceAllString(text, ""), "")
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return errors.New("reply holds no JSON object")
+	}
+	var rep reply
+	if err := json.Unmarshal([]byte(text[start:end+1]), &rep); err != nil {
+		return fmt.Errorf("parsing reply: %w", err)
+	}
+	sample.Rationale = rep.Rationale
+	if r != nil {
+		if err := r.Check(rep.Dimensions); err != nil {
+			return err
+		}
+		score := r.Score(rep.Dimensions)
+		sample.Score = &score
+		sample.Dimensions = rep.Dimensions
+		return nil
+	}
+	if rep.Score == nil {
+		return errors.New("reply holds no score")
+	}
+	if *rep.Score < 0 || *rep.Score > 100 {
+		return fmt.Errorf("score must be between 0 and 100, got %d", *rep.Score)
+	}
+	sample.Score = rep.Score
+	return nil
+}
+
+// Aggregate combines the samples into a verdict. A grade agrees with the
+// median if it is at most tolerance points off; the verdict is flagged if
+// the share of agreeing grades is under minAgreement or fewer than two
+// samples have a usable grade. With no usable grade at all, the verdict
+// has none either and is flagged.
+func Aggregate(samples []drill.Sample, r *rubric.Rubric, tolerance int, minAgreement float64) Verdict {
+	v := Verdict{Samples: samples}
+	var graded []drill.Sample
+	for _, s := range samples {
+		if s.Err == "" && s.Score != nil {
+			graded = append(graded, s)
+		}
+	}
+	v.Graded = len(graded)
+	if v.Graded == 0 {
+		v.Flagged = true
+		return v
+	}
+
+	if r != nil {
+		for _, d := range r.Dimensions {
+			points := make([]int, 0, len(graded))
+			for _, s := range graded {
+				i := slices.IndexFunc(s.Dimensions, func(ds rubric.DimensionScore) bool { return ds.Dimension == d.Name })
+				if i >= 0 {
+					points = append(points, s.Dimensions[i].Points)
+				}
+			}
+			v.Dimensions = append(v.Dimensions, rubric.DimensionScore{
+				Dimension: d.Name,
+				Points:    lowerMedian(points),
+			})
+		}
+		v.Score = r.Score(v.Dimensions)
+	} else {
+		scores := make([]int, len(graded))
+		for i, s := range graded {
+			scores[i] = *s.Score
+		}
+		v.Score = median(scores)
+	}
+
+	agreeing := 0
+	for _, s := range graded {
+		if abs(*s.Score-v.Score) <= tolerance {
+			agreeing++
+		}
+	}
+	v.Agreement = float64(agreeing) / float64(v.Graded)
+	v.Flagged = v.Graded < 2 || v.Agreement < minAgreement
+	return v
+}
+
+// median returns the middle value, or the rounded mean of the two middle
+// ones.
+func median(values []int) int {
+	sorted := slices.Sorted(slices.Values(values))
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return int(math.Round(float64(sorted[n/2-1]+sorted[n/2]) / 2))
+}
+
+// lowerMedian returns the middle value, or the lower of the two middle
+// ones, so the median of level points is a level too.
+func lowerMedian(values []int) int {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Sorted(slices.Values(values))
+	return sorted[(len(sorted)-1)/2]
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
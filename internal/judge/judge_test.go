@@ -0,0 +1,157 @@
+package judge
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/rubric"
+)
+
+func testRubric() *rubric.Rubric {
+	levels := func(top int) []rubric.Level {
+		var ls []rubric.Level
+		for p := 0; p <= top; p++ {
+			ls = append(ls, rubric.Level{Points: p, Label: "l", Descriptor: "d"})
+		}
+		return ls
+	}
+	return &rubric.Rubric{
+		Name:    "test",
+		Version: 1,
+		Dimensions: []rubric.Dimension{
+			{Name: "correctness", Weight: 3, Levels: levels(3)},
+			{Name: "clarity", Levels: levels(1)},
+		},
+	}
+}
+
+func scored(scores ...int) []drill.Sample {
+	samples := make([]drill.Sample, len(scores))
+	for i, score := range scores {
+		if score < 0 {
+			samples[i] = drill.Sample{Err: "unusable"}
+			continue
+		}
+		samples[i] = drill.Sample{Score: &score}
+	}
+	return samples
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	var running, peak atomic.Int32
+	samples := Run(t.Context(), 6, 2, func(ctx context.Context, i int) drill.Sample {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		score := i * 10
+		return drill.Sample{Model: "m", Score: &score}
+	})
+	require.Len(t, samples, 6)
+	require.LessOrEqual(t, peak.Load(), int32(2))
+	for i, s := range samples {
+		require.Equal(t, i*10, *s.Score, "samples keep the call order")
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	samples = Run(ctx, 3, 1, func(ctx context.Context, i int) drill.Sample {
+		return drill.Sample{Err: ctx.Err().Error()}
+	})
+	for _, s := range samples {
+		require.NotEmpty(t, s.Err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	var s drill.Sample
+	Parse(&s, "<think>hmm {}</think>\n```json\n{\"score\": 72, \"rationale\": \"solid\"}\n```", nil)
+	require.Empty(t, s.Err)
+	require.Equal(t, 72, *s.Score)
+	require.Equal(t, "solid", s.Rationale)
+	require.Contains(t, s.Raw, "<think>")
+
+	s = drill.Sample{}
+	Parse(&s, `{"score": 120}`, nil)
+	require.Equal(t, "score must be between 0 and 100, got 120", s.Err)
+	require.Nil(t, s.Score)
+
+	s = drill.Sample{}
+	Parse(&s, "I'd give it a 7", nil)
+	require.Equal(t, "reply holds no JSON object", s.Err)
+
+	r := testRubric()
+	s = drill.Sample{}
+	Parse(&s, `{"dimensions": [{"dimension": "correctness", "points": 2}, {"dimension": "clarity", "points": 1}]}`, r)
+	require.Empty(t, s.Err)
+	require.Equal(t, 75, *s.Score)
+	require.Len(t, s.Dimensions, 2)
+
+	s = drill.Sample{}
+	Parse(&s, `{"score": 90, "dimensions": [{"dimension": "correctness", "points": 2}]}`, r)
+	require.Equal(t, "clarity is not graded", s.Err)
+	require.Nil(t, s.Score)
+}
+
+func TestAggregate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		samples   []drill.Sample
+		score     int
+		agreement float64
+		flagged   bool
+	}{
+		{name: "agreeing", samples: scored(70, 75, 80), score: 75, agreement: 1},
+		{name: "outlier", samples: scored(60, 90, 88), score: 88, agreement: 2.0 / 3},
+		{name: "spread", samples: scored(20, 60, 95), score: 60, agreement: 1.0 / 3, flagged: true},
+		{name: "even", samples: scored(60, 71), score: 66, agreement: 1},
+		{name: "failed calls", samples: scored(70, -1, -1), score: 70, agreement: 1, flagged: true},
+		{name: "no grade", samples: scored(-1, -1), flagged: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			v := Aggregate(tt.samples, nil, 10, 0.6)
+			require.Equal(t, tt.score, v.Score)
+			require.InDelta(t, tt.agreement, v.Agreement, 0.001)
+			require.Equal(t, tt.flagged, v.Flagged)
+		})
+	}
+}
+
+func TestAggregateRubric(t *testing.T) {
+	t.Parallel()
+
+	r := testRubric()
+	var samples []drill.Sample
+	for _, points := range [][2]int{{3, 1}, {2, 1}, {2, 0}, {3, 1}} {
+		var s drill.Sample
+		s.Dimensions = []rubric.DimensionScore{{Dimension: "correctness", Points: points[0]}, {Dimension: "clarity", Points: points[1]}}
+		score := r.Score(s.Dimensions)
+		s.Score = &score
+		samples = append(samples, s)
+	}
+
+	v := Aggregate(samples, r, 10, 0.6)
+	require.Equal(t, []rubric.DimensionScore{{Dimension: "correctness", Points: 2}, {Dimension: "clarity", Points: 1}}, v.Dimensions)
+	require.NoError(t, r.Check(v.Dimensions), "median points are levels")
+	require.Equal(t, 75, v.Score)
+	require.Equal(t, 4, v.Graded)
+	require.InDelta(t, 0.25, v.Agreement, 0.001)
+	require.True(t, v.Flagged)
+}
@@ -125,6 +125,37 @@ type Finish struct {
 
 func (Finish) isPart() {}
 
+// AnswerTiming describes how the user composed a message, as recorded by the
+// editor. Durations are in milliseconds.
+type AnswerTiming struct {
+	// ThinkMs is the time from the question being shown to the first
+	// keystroke. It is zero when the editor didn't see the question.
+	ThinkMs int64 `json:"think_ms,omitempty"`
+	// ComposeMs is the time from the first keystroke to sending.
+	ComposeMs   int64 `json:"compose_ms"`
+	Keystrokes  int   `json:"keystrokes"`
+	Backspaces  int   `json:"backspaces"`
+	Pastes      int   `json:"pastes"`
+	PastedChars int   `json:"pasted_chars"`
+}
+
+func (AnswerTiming) isPart() {}
+
+// String renders the timing as a tag the model can take into account when
+// grading, e.g. a quick answer that was mostly pasted.
+func (t AnswerTiming) String() string {
+	return fmt.Sprintf(`<answer_timing think_seconds="%.1f" compose_seconds="%.1f" keystrokes="%d" backspaces="%d" pastes="%d" pasted_chars="%d"/>`,
+		float64(t.ThinkMs)/1000, float64(t.ComposeMs)/1000, t.Keystrokes, t.Backspaces, t.Pastes, t.PastedChars)
+}
+
+// PromptWithTiming appends the answer timing to a user prompt.
+func PromptWithTiming(prompt string, timing *AnswerTiming) string {
+	if timing == nil {
+		return prompt
+	}
+	return prompt + "\n\n" + timing.String()
+}
+
 type Message struct {
 	ID               string
 	Role             MessageRole
@@ -195,6 +226,17 @@ func (m *Message) ToolResults() []ToolResult {
 	return toolResults
 }
 
+// Timing returns how the user composed the message, or nil if it wasn't
+// recorded.
+func (m *Message) Timing() *AnswerTiming {
+	for _, part := range m.Parts {
+		if t, ok := part.(AnswerTiming); ok {
+			return &t
+		}
+	}
+	return nil
+}
+
 func (m *Message) IsFinished() bool {
 	for _, part := range m.Parts {
 		if _, ok := part.(Finish); ok {
@@ -479,7 +521,7 @@ func (m *Message) ToAIMessage() []fantasy.Message {
 		}
 		text = PromptWithTextAttachments(text, textAttachments)
 		if text != "" {
-			parts = append(parts, fantasy.TextPart{Text: text})
+			parts = append(parts, fantasy.TextPart{Text: PromptWithTiming(text, m.Timing())})
 		}
 		for _, content := range m.BinaryContent() {
 			// skip text attachements
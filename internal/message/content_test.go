@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+
+	"charm.land/fantasy"
+	"github.com/stretchr/testify/require"
 )
 
 func makeTestAttachments(n int, contentSize int) []Attachment {
@@ -43,3 +46,22 @@ func BenchmarkPromptWithTextAttachments(b *testing.B) {
 		})
 	}
 }
+
+func TestAnswerTiming(t *testing.T) {
+	t.Parallel()
+
+	timing := AnswerTiming{ThinkMs: 12500, ComposeMs: 40000, Keystrokes: 180, Backspaces: 12, Pastes: 1, PastedChars: 64}
+	data, err := marshallParts([]ContentPart{TextContent{Text: "It panics."}, timing})
+	require.NoError(t, err)
+	parts, err := unmarshallParts(data)
+	require.NoError(t, err)
+
+	msg := Message{Role: User, Parts: parts}
+	require.Equal(t, &timing, msg.Timing())
+
+	ai := msg.ToAIMessage()
+	require.Len(t, ai, 1)
+	text, ok := ai[0].Content[0].(fantasy.TextPart)
+	require.True(t, ok)
+	require.Equal(t, "It panics.\n\n"+`<answer_timing think_seconds="12.5" compose_seconds="40.0" keystrokes="180" backspaces="12" pastes="1" pasted_chars="64"/>`, text.Text)
+}
@@ -7,9 +7,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/trankhanh040147/prepf/internal/db"
 	"github.com/trankhanh040147/prepf/internal/pubsub"
-	"github.com/google/uuid"
 )
 
 type CreateMessageParams struct {
@@ -37,8 +37,13 @@ type service struct {
 
 func NewService(q db.Querier) Service {
 	return &service{
-		Broker: pubsub.NewBroker[Message](),
-		q:      q,
+		Broker: pubsub.NewBroker(
+			pubsub.WithName[Message]("messages"),
+			// Streaming updates a message many times; a slow UI only
+			// needs its latest version.
+			pubsub.WithCoalesce(func(m Message) string { return m.ID }),
+		),
+		q: q,
 	}
 }
 
@@ -185,6 +190,7 @@ const (
 	toolCallType   partType = "tool_call"
 	toolResultType partType = "tool_result"
 	finishType     partType = "finish"
+	timingType     partType = "timing"
 )
 
 type partWrapper struct {
@@ -213,6 +219,8 @@ func marshallParts(parts []ContentPart) ([]byte, error) {
 			typ = toolResultType
 		case Finish:
 			typ = finishType
+		case AnswerTiming:
+			typ = timingType
 		default:
 			return nil, fmt.Errorf("unknown part type: %T", part)
 		}
@@ -287,6 +295,12 @@ func unmarshallParts(data []byte) ([]ContentPart, error) {
 				return nil, err
 			}
 			parts = append(parts, part)
+		case timingType:
+			part := AnswerTiming{}
+			if err := json.Unmarshal(wrapper.Data, &part); err != nil {
+				return nil, err
+			}
+			parts = append(parts, part)
 		default:
 			return nil, fmt.Errorf("unknown part type: %s", wrapper.Type)
 		}
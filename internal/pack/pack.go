@@ -0,0 +1,389 @@
+// Package pack loads company interview packs: a directory bundling how a
+// company interviews, round by round, with the persona the interviewer
+// plays, the questions it is known to ask and how much each rubric
+// dimension counts there. Packs live next to skills, in the skills paths.
+package pack
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charlievieth/fastwalk"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/rubric"
+	"gopkg.in/yaml.v3"
+)
+
+// Files making up a pack. Only the manifest is required.
+const (
+	// ManifestName is the file that makes a directory a pack.
+	ManifestName = "pack.yaml"
+	// PersonaName holds the instructions for the interviewer to play the
+	// company's interviewer.
+	PersonaName = "persona.md"
+	// QuestionsName holds the questions the company is known to ask.
+	QuestionsName = "questions.yaml"
+)
+
+// Modes a round can be practiced in.
+var Modes = []string{"mock", "gym"}
+
+// Round is one interview of the company's loop.
+type Round struct {
+	Name string `yaml:"name" json:"name"`
+	// Mode is the session mode the round is practiced in.
+	Mode    string `yaml:"mode" json:"mode"`
+	Minutes int    `yaml:"minutes" json:"minutes"`
+	// Focus lists the areas the round covers.
+	Focus []string `yaml:"focus,omitempty" json:"focus,omitempty"`
+	// Notes describe the round's format for the interviewer.
+	Notes string `yaml:"notes,omitempty" json:"notes,omitempty"`
+	// Rubric and Weights override the pack's for the round.
+	Rubric  string             `yaml:"rubric,omitempty" json:"rubric,omitempty"`
+	Weights map[string]float64 `yaml:"weights,omitempty" json:"weights,omitempty"`
+}
+
+// Question is a question the company is known to ask.
+type Question struct {
+	Topic  string   `yaml:"topic,omitempty" json:"topic,omitempty"`
+	Text   string   `yaml:"question" json:"question"`
+	Answer string   `yaml:"answer,omitempty" json:"answer,omitempty"`
+	Hints  []string `yaml:"hints,omitempty" json:"hints,omitempty"`
+	// Rounds lists the numbers of the rounds the question is asked in,
+	// counted from 1; all of them when empty.
+	Rounds []int `yaml:"rounds,omitempty" json:"rounds,omitempty"`
+}
+
+// Pack is a company's interview loop.
+type Pack struct {
+	// Name is the directory name.
+	Name        string `yaml:"-" json:"name"`
+	Company     string `yaml:"company" json:"company"`
+	Title       string `yaml:"title,omitempty" json:"title,omitempty"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Rubric names the rubric the rounds are graded against instead of the
+	// mode's.
+	Rubric string `yaml:"rubric,omitempty" json:"rubric,omitempty"`
+	// Weights set how much rubric dimensions count at the company, by
+	// dimension name.
+	Weights   map[string]float64 `yaml:"weights,omitempty" json:"weights,omitempty"`
+	Rounds    []Round            `yaml:"rounds" json:"rounds"`
+	Persona   string             `yaml:"-" json:"persona,omitempty"`
+	Questions []Question         `yaml:"-" json:"questions,omitempty"`
+	Dir       string             `yaml:"-" json:"dir"`
+}
+
+// DisplayName names the pack, as in "Acme onsite".
+func (p *Pack) DisplayName() string {
+	return cmp.Or(p.Title, p.Company)
+}
+
+// Round returns the round with the given number, counted from 1.
+func (p *Pack) Round(n int) (Round, bool) {
+	if n < 1 || n > len(p.Rounds) {
+		return Round{}, false
+	}
+	return p.Rounds[n-1], true
+}
+
+// SessionTitle names a session practicing the round, as in
+// "Mock: Acme onsite, round 2".
+func (p *Pack) SessionTitle(n int) string {
+	r, _ := p.Round(n)
+	mode := r.Mode
+	if mode != "" {
+		mode = strings.ToUpper(mode[:1]) + mode[1:]
+	}
+	return fmt.Sprintf("%s: %s, round %d", mode, p.DisplayName(), n)
+}
+
+// QuestionsFor returns the questions asked in the round.
+func (p *Pack) QuestionsFor(n int) []Question {
+	var questions []Question
+	for _, q := range p.Questions {
+		if len(q.Rounds) == 0 || slices.Contains(q.Rounds, n) {
+			questions = append(questions, q)
+		}
+	}
+	return questions
+}
+
+// RubricName returns the rubric the round is graded against, or "" to
+// leave it to the mode.
+func (p *Pack) RubricName(n int) string {
+	r, _ := p.Round(n)
+	return cmp.Or(r.Rubric, p.Rubric)
+}
+
+// WeightsFor returns the dimension weights of the round: the pack's,
+// overridden by the round's.
+func (p *Pack) WeightsFor(n int) map[string]float64 {
+	weights := maps.Clone(p.Weights)
+	if r, ok := p.Round(n); ok && len(r.Weights) > 0 {
+		if weights == nil {
+			weights = make(map[string]float64, len(r.Weights))
+		}
+		maps.Copy(weights, r.Weights)
+	}
+	return weights
+}
+
+// Weigh returns a copy of the rubric with the round's dimension weights.
+// Weights of dimensions the rubric doesn't have are ignored. The copy keeps
+// the rubric's version: its levels, which the points given are checked
+// against, are the same.
+func (p *Pack) Weigh(r *rubric.Rubric, n int) *rubric.Rubric {
+	weights := p.WeightsFor(n)
+	if r == nil || len(weights) == 0 {
+		return r
+	}
+	weighted := *r
+	weighted.Dimensions = slices.Clone(r.Dimensions)
+	for i, d := range weighted.Dimensions {
+		if w, ok := weights[d.Name]; ok {
+			weighted.Dimensions[i].Weight = w
+		}
+	}
+	return &weighted
+}
+
+// Prompt describes the round to the interviewer: the company, the round's
+// format, the persona to play and the questions to draw from.
+func (p *Pack) Prompt(n int) string {
+	r, ok := p.Round(n)
+	if !ok {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("<interview_pack>\n")
+	fmt.Fprintf(&sb, "This session practices round %d of %d of the interview loop at %s: %s, %d minutes.\n", n, len(p.Rounds), p.Company, r.Name, r.Minutes)
+	if len(r.Focus) > 0 {
+		fmt.Fprintf(&sb, "The round focuses on %s.\n", strings.Join(r.Focus, ", "))
+	}
+	if r.Notes != "" {
+		fmt.Fprintf(&sb, "\nRound format:\n%s\n", strings.TrimSpace(r.Notes))
+	}
+	if p.Persona != "" {
+		fmt.Fprintf(&sb, "\nPlay the company's interviewer:\n%s\n", strings.TrimSpace(p.Persona))
+	}
+	if questions := p.QuestionsFor(n); len(questions) > 0 {
+		sb.WriteString("\nThe company is known to ask these questions. Draw on them before making up your own, and never reveal the answers or hints before the user earns them:\n")
+		for i, q := range questions {
+			fmt.Fprintf(&sb, "%d. ", i+1)
+			if q.Topic != "" {
+				fmt.Fprintf(&sb, "[%s] ", q.Topic)
+			}
+			sb.WriteString(strings.TrimSpace(q.Text))
+			sb.WriteString("\n")
+			if q.Answer != "" {
+				fmt.Fprintf(&sb, "   Answer: %s\n", strings.TrimSpace(q.Answer))
+			}
+			if len(q.Hints) > 0 {
+				fmt.Fprintf(&sb, "   Hints: %s\n", strings.Join(q.Hints, " | "))
+			}
+		}
+	}
+	sb.WriteString("</interview_pack>")
+	return sb.String()
+}
+
+// Validate checks that the pack names its company and has rounds that can
+// be practiced, and that its questions refer to existing rounds.
+func (p *Pack) Validate() error {
+	var errs []error
+	if strings.TrimSpace(p.Company) == "" {
+		errs = append(errs, errors.New("company is required"))
+	}
+	if len(p.Rounds) == 0 {
+		errs = append(errs, errors.New("at least one round is required"))
+	}
+	errs = append(errs, checkWeights("", p.Weights)...)
+	for i, r := range p.Rounds {
+		prefix := fmt.Sprintf("round %d: ", i+1)
+		if strings.TrimSpace(r.Name) == "" {
+			errs = append(errs, errors.New(prefix+"name is required"))
+		}
+		if !slices.Contains(Modes, r.Mode) {
+			errs = append(errs, fmt.Errorf("%smode must be one of %s, got %q", prefix, strings.Join(Modes, ", "), r.Mode))
+		}
+		if r.Minutes <= 0 {
+			errs = append(errs, errors.New(prefix+"minutes must be positive"))
+		}
+		errs = append(errs, checkWeights(prefix, r.Weights)...)
+	}
+	for i, q := range p.Questions {
+		prefix := fmt.Sprintf("question %d: ", i+1)
+		if strings.TrimSpace(q.Text) == "" {
+			errs = append(errs, errors.New(prefix+"question is required"))
+		}
+		for _, n := range q.Rounds {
+			if _, ok := p.Round(n); !ok {
+				errs = append(errs, fmt.Errorf("%sthere is no round %d", prefix, n))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func checkWeights(prefix string, weights map[string]float64) []error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(weights)) {
+		if weights[name] < 0 {
+			errs = append(errs, fmt.Errorf("%sweight of %s must not be negative", prefix, name))
+		}
+	}
+	return errs
+}
+
+// CheckRubrics checks that the rubric each round is graded against exists
+// and has the dimensions the weights are given for. configured maps modes
+// to the rubric set for them in options.rubrics.
+func (p *Pack) CheckRubrics(available []*rubric.Rubric, configured map[string]string) error {
+	var errs []error
+	for i, round := range p.Rounds {
+		n := i + 1
+		if name := p.RubricName(n); name != "" {
+			if _, ok := rubric.Find(available, name); !ok {
+				errs = append(errs, fmt.Errorf("round %d: there is no rubric named %q", n, name))
+				continue
+			}
+		}
+		r, ok := rubric.Pick(available, p.RubricName(n), configured[round.Mode], round.Mode)
+		weights := p.WeightsFor(n)
+		if !ok {
+			if len(weights) > 0 {
+				errs = append(errs, fmt.Errorf("round %d: weights are given but no rubric grades %s rounds", n, round.Mode))
+			}
+			continue
+		}
+		for _, name := range slices.Sorted(maps.Keys(weights)) {
+			if _, ok := r.Dimension(name); !ok {
+				errs = append(errs, fmt.Errorf("round %d: rubric %s has no dimension %q", n, r.Name, name))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Load parses the pack in dir. The pack is named after the directory.
+func Load(dir string) (*Pack, error) {
+	content, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if err != nil {
+		return nil, err
+	}
+	var p Pack
+	if err := yaml.Unmarshal(content, &p); err != nil {
+		return nil, fmt.Errorf("parsing pack: %w", err)
+	}
+	p.Name = filepath.Base(dir)
+	p.Dir = dir
+
+	persona, err := os.ReadFile(filepath.Join(dir, PersonaName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	p.Persona = strings.TrimSpace(string(persona))
+
+	questions, err := os.ReadFile(filepath.Join(dir, QuestionsName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(questions, &p.Questions); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", QuestionsName, err)
+	}
+	return &p, nil
+}
+
+// Problem describes a pack that could not be loaded.
+type Problem struct {
+	Path string
+	Err  error
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("%s: %v", p.Path, p.Err)
+}
+
+// DiscoverConfigured finds the packs in the configured skills paths, along
+// with the packs that are invalid.
+func DiscoverConfigured(cfg *config.Config) ([]*Pack, []Problem) {
+	paths := cfg.SkillsPaths()
+	expanded := make([]string, len(paths))
+	for i, path := range paths {
+		expanded[i] = cfg.ExpandPath(path)
+	}
+	return Discover(expanded)
+}
+
+// Discover finds the valid packs in the given paths, at any depth. A pack
+// in an earlier path hides one with the same name in a later one.
+func Discover(paths []string) ([]*Pack, []Problem) {
+	var packs []*Pack
+	var problems []Problem
+	seen := make(map[string]bool)
+	for _, base := range paths {
+		var found []*Pack
+		var mu sync.Mutex
+		// Like skills, packs in symlinked directories are found too;
+		// fastwalk is concurrent, so found and problems are guarded by mu.
+		conf := fastwalk.Config{
+			Follow:  true,
+			ToSlash: fastwalk.DefaultToSlash(),
+		}
+		fastwalk.Walk(&conf, base, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() || d.Name() != ManifestName {
+				return nil
+			}
+			dir := filepath.Dir(path)
+			p, err := Load(dir)
+			if err == nil {
+				err = p.Validate()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				slog.Warn("Failed to load interview pack", "path", dir, "error", err)
+				problems = append(problems, Problem{Path: dir, Err: err})
+				return nil
+			}
+			found = append(found, p)
+			return nil
+		})
+		slices.SortFunc(found, func(a, b *Pack) int {
+			return strings.Compare(a.Dir, b.Dir)
+		})
+		for _, p := range found {
+			if seen[p.Name] {
+				continue
+			}
+			seen[p.Name] = true
+			packs = append(packs, p)
+		}
+	}
+	slices.SortFunc(packs, func(a, b *Pack) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	slices.SortFunc(problems, func(a, b Problem) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return packs, problems
+}
+
+// Find returns the pack with the given name, if any.
+func Find(packs []*Pack, name string) (*Pack, bool) {
+	for _, p := range packs {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
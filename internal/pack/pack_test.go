@@ -0,0 +1,207 @@
+package pack
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/rubric"
+)
+
+const manifest = `company: Acme
+title: Acme onsite
+rubric: acme
+weights:
+  correctness: 2
+rounds:
+  - name: Coding
+    mode: gym
+    minutes: 45
+    focus: [algorithms]
+  - name: System design
+    mode: mock
+    minutes: 60
+    focus: [system design, scaling]
+    notes: Whiteboard a feed service, then dig into one component.
+    weights:
+      depth: 4
+`
+
+const questions = `- topic: Algorithms
+  question: Merge k sorted lists.
+  answer: Use a min-heap of list heads.
+  rounds: [1]
+- topic: System design
+  question: Design a news feed.
+  hints: [fan-out, caching]
+  rounds: [2]
+- question: Tell me about a hard bug.
+`
+
+// writePack creates a pack in dir with the given manifest.
+func writePack(t *testing.T, dir, name, manifest string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(path, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(path, ManifestName), []byte(manifest), 0o644))
+	return path
+}
+
+func testRubric() *rubric.Rubric {
+	levels := []rubric.Level{{Points: 0, Label: "no", Descriptor: "d"}, {Points: 1, Label: "yes", Descriptor: "d"}}
+	return &rubric.Rubric{
+		Name:    "acme",
+		Version: 2,
+		Dimensions: []rubric.Dimension{
+			{Name: "correctness", Weight: 3, Levels: levels},
+			{Name: "depth", Weight: 2, Levels: levels},
+			{Name: "communication", Levels: levels},
+		},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := writePack(t, t.TempDir(), "acme", manifest)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PersonaName), []byte("\nYou are a staff engineer at Acme.\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, QuestionsName), []byte(questions), 0o644))
+
+	p, err := Load(dir)
+	require.NoError(t, err)
+	require.NoError(t, p.Validate())
+	require.Equal(t, "acme", p.Name)
+	require.Equal(t, "You are a staff engineer at Acme.", p.Persona)
+	require.Len(t, p.Questions, 3)
+	require.Equal(t, "Mock: Acme onsite, round 2", p.SessionTitle(2))
+
+	require.Len(t, p.QuestionsFor(1), 2)
+	second := p.QuestionsFor(2)
+	require.Len(t, second, 2)
+	require.Equal(t, "Design a news feed.", second[0].Text)
+
+	prompt := p.Prompt(2)
+	require.Contains(t, prompt, "round 2 of 2 of the interview loop at Acme: System design, 60 minutes")
+	require.Contains(t, prompt, "The round focuses on system design, scaling.")
+	require.Contains(t, prompt, "Whiteboard a feed service")
+	require.Contains(t, prompt, "You are a staff engineer at Acme.")
+	require.Contains(t, prompt, "1. [System design] Design a news feed.\n   Hints: fan-out | caching\n")
+	require.NotContains(t, prompt, "Merge k sorted lists.")
+	require.Empty(t, p.Prompt(3))
+
+	// Persona and questions are optional.
+	p, err = Load(writePack(t, t.TempDir(), "bare", manifest))
+	require.NoError(t, err)
+	require.Empty(t, p.Persona)
+	require.Empty(t, p.Questions)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	p := &Pack{
+		Weights: map[string]float64{"depth": -1},
+		Rounds: []Round{
+			{Name: "Coding", Mode: "debug", Minutes: 0},
+		},
+		Questions: []Question{{Text: "Why?", Rounds: []int{2}}, {}},
+	}
+	err := p.Validate()
+	require.Error(t, err)
+	for _, want := range []string{
+		"company is required",
+		"weight of depth must not be negative",
+		`round 1: mode must be one of mock, gym, got "debug"`,
+		"round 1: minutes must be positive",
+		"question 1: there is no round 2",
+		"question 2: question is required",
+	} {
+		require.ErrorContains(t, err, want)
+	}
+
+	require.ErrorContains(t, (&Pack{Company: "Acme"}).Validate(), "at least one round is required")
+}
+
+func TestWeigh(t *testing.T) {
+	t.Parallel()
+
+	dir := writePack(t, t.TempDir(), "acme", manifest)
+	p, err := Load(dir)
+	require.NoError(t, err)
+	r := testRubric()
+
+	require.Equal(t, map[string]float64{"correctness": 2, "depth": 4}, p.WeightsFor(2))
+	weighted := p.Weigh(r, 2)
+	require.Equal(t, 2.0, weighted.Dimensions[0].Weight)
+	require.Equal(t, 4.0, weighted.Dimensions[1].Weight)
+	require.Zero(t, weighted.Dimensions[2].Weight)
+	require.Equal(t, "acme@2", weighted.Ref())
+	require.Equal(t, 3.0, r.Dimensions[0].Weight, "the rubric itself is left alone")
+
+	weighted = p.Weigh(r, 1)
+	require.Equal(t, 2.0, weighted.Dimensions[0].Weight)
+	require.Equal(t, 2.0, weighted.Dimensions[1].Weight)
+
+	require.NoError(t, p.CheckRubrics([]*rubric.Rubric{r}, nil))
+	require.ErrorContains(t, p.CheckRubrics(nil, nil), `round 1: there is no rubric named "acme"`)
+	p.Rounds[1].Weights = map[string]float64{"style": 1}
+	require.ErrorContains(t, p.CheckRubrics([]*rubric.Rubric{r}, nil), `round 2: rubric acme has no dimension "style"`)
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	first, second := t.TempDir(), t.TempDir()
+	writePack(t, filepath.Join(first, "companies"), "acme", manifest)
+	writePack(t, first, "broken", "title: x\n")
+	writePack(t, second, "acme", "company: Other\nrounds: [{name: r, mode: gym, minutes: 30}]\n")
+	writePack(t, second, "globex", "company: Globex\nrounds: [{name: r, mode: mock, minutes: 30}]\n")
+
+	packs, problems := Discover([]string{first, second})
+	require.Len(t, packs, 2)
+	require.Len(t, problems, 1)
+	require.Equal(t, filepath.Join(first, "broken"), problems[0].Path)
+
+	p, ok := Find(packs, "acme")
+	require.True(t, ok)
+	require.Equal(t, "Acme", p.Company, "earlier paths win")
+	_, ok = Find(packs, "globex")
+	require.True(t, ok)
+	_, ok = Find(packs, "broken")
+	require.False(t, ok)
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	q := db.New(conn)
+	_, err = q.CreateSession(t.Context(), db.CreateSessionParams{
+		ID:   "s1",
+		Mode: sql.NullString{String: "mock", Valid: true},
+	})
+	require.NoError(t, err)
+	svc := NewService(q)
+
+	_, err = svc.ForSession(t.Context(), "s1")
+	require.ErrorIs(t, err, ErrNoPack)
+
+	p, err := Load(writePack(t, t.TempDir(), "acme", manifest))
+	require.NoError(t, err)
+	_, err = svc.Begin(t.Context(), "s1", p, 3)
+	require.ErrorContains(t, err, "pack acme has no round 3")
+
+	sel, err := svc.Begin(t.Context(), "s1", p, 2)
+	require.NoError(t, err)
+	require.Equal(t, "acme", sel.Pack)
+	require.Equal(t, 2, sel.Round)
+
+	got, err := svc.ForSession(t.Context(), "s1")
+	require.NoError(t, err)
+	require.Equal(t, sel, got)
+}
@@ -0,0 +1,73 @@
+package pack
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/trankhanh040147/prepf/internal/db"
+)
+
+// ErrNoPack is returned when the session doesn't practice a pack round.
+var ErrNoPack = errors.New("no interview pack in this session")
+
+// Selection is the pack round a session practices.
+type Selection struct {
+	SessionID string `json:"session_id"`
+	Pack      string `json:"pack"`
+	// Round is the round's number, counted from 1.
+	Round     int   `json:"round"`
+	CreatedAt int64 `json:"created_at"`
+}
+
+type Service interface {
+	// Begin ties the session to the round of the pack.
+	Begin(ctx context.Context, sessionID string, p *Pack, round int) (Selection, error)
+	// ForSession returns the pack round the session practices, or
+	// ErrNoPack.
+	ForSession(ctx context.Context, sessionID string) (Selection, error)
+}
+
+type service struct {
+	q db.Querier
+}
+
+func NewService(q db.Querier) Service {
+	return &service{q: q}
+}
+
+func (s *service) Begin(ctx context.Context, sessionID string, p *Pack, round int) (Selection, error) {
+	if _, ok := p.Round(round); !ok {
+		return Selection{}, fmt.Errorf("pack %s has no round %d", p.Name, round)
+	}
+	row, err := s.q.CreateSessionPack(ctx, db.CreateSessionPackParams{
+		SessionID: sessionID,
+		Pack:      p.Name,
+		Round:     int64(round),
+	})
+	if err != nil {
+		return Selection{}, err
+	}
+	return fromDB(row), nil
+}
+
+func (s *service) ForSession(ctx context.Context, sessionID string) (Selection, error) {
+	row, err := s.q.GetSessionPack(ctx, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Selection{}, ErrNoPack
+	}
+	if err != nil {
+		return Selection{}, err
+	}
+	return fromDB(row), nil
+}
+
+func fromDB(row db.SessionPack) Selection {
+	return Selection{
+		SessionID: row.SessionID,
+		Pack:      row.Pack,
+		Round:     int(row.Round),
+		CreatedAt: row.CreatedAt,
+	}
+}
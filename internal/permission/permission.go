@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/trankhanh040147/prepf/internal/csync"
 	"github.com/trankhanh040147/prepf/internal/pubsub"
@@ -222,8 +223,13 @@ func (s *permissionService) SkipRequests() bool {
 
 func NewPermissionService(workingDir string, skip bool, allowedTools []string) Service {
 	return &permissionService{
-		Broker:              pubsub.NewBroker[PermissionRequest](),
-		notificationBroker:  pubsub.NewBroker[PermissionNotification](),
+		Broker: pubsub.NewBroker(
+			pubsub.WithName[PermissionRequest]("permissions"),
+			// A dropped request would leave the agent waiting for an
+			// answer nobody was asked for.
+			pubsub.WithBlock[PermissionRequest](30*time.Second),
+		),
+		notificationBroker:  pubsub.NewBroker(pubsub.WithName[PermissionNotification]("permission-notifications")),
 		workingDir:          workingDir,
 		sessionPermissions:  make([]PermissionRequest, 0),
 		autoApproveSessions: make(map[string]bool),
@@ -0,0 +1,126 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ICSOptions configures the calendar export.
+type ICSOptions struct {
+	// Start is the local time of day, as in "19:00", the day's practice
+	// starts at, its items following each other. Items are all-day events
+	// when it is empty.
+	Start string
+	// Location the start time is in; local time when nil.
+	Location *time.Location
+}
+
+// ICS renders the interviews and plan items as an iCalendar file.
+func ICS(targets []Target, items []Item, opts ICSOptions, now time.Time) (string, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	var start time.Duration
+	if opts.Start != "" {
+		t, err := time.Parse("15:04", opts.Start)
+		if err != nil {
+			return "", fmt.Errorf("start time must look like 19:00, got %q", opts.Start)
+		}
+		start = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		b.WriteString(fold(fmt.Sprintf(format, args...)))
+		b.WriteString("\r\n")
+	}
+	stamp := now.UTC().Format("20060102T150405Z")
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//prepf//practice plan//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:Interview practice")
+
+	for _, t := range targets {
+		day, err := time.Parse(time.DateOnly, t.Day)
+		if err != nil {
+			continue
+		}
+		line("BEGIN:VEVENT")
+		line("UID:target-%s@prepf", t.ID)
+		line("DTSTAMP:%s", stamp)
+		line("DTSTART;VALUE=DATE:%s", day.Format("20060102"))
+		line("DTEND;VALUE=DATE:%s", day.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:%s", escapeText("Interview: "+t.Name()))
+		if len(t.Topics) > 0 {
+			line("DESCRIPTION:%s", escapeText("Covers "+strings.Join(t.Topics, ", ")))
+		}
+		line("END:VEVENT")
+	}
+
+	offsets := make(map[string]time.Duration)
+	for _, item := range items {
+		day, err := time.Parse(time.DateOnly, item.Day)
+		if err != nil {
+			continue
+		}
+		line("BEGIN:VEVENT")
+		line("UID:item-%s@prepf", item.ID)
+		line("DTSTAMP:%s", stamp)
+		if opts.Start == "" {
+			line("DTSTART;VALUE=DATE:%s", day.Format("20060102"))
+			line("DTEND;VALUE=DATE:%s", day.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+			from := midnight.Add(start + offsets[item.Day])
+			to := from.Add(time.Duration(item.Minutes) * time.Minute)
+			offsets[item.Day] += time.Duration(item.Minutes) * time.Minute
+			line("DTSTART:%s", from.UTC().Format("20060102T150405Z"))
+			line("DTEND:%s", to.UTC().Format("20060102T150405Z"))
+		}
+		// Events have no status for work that is done, so it goes in the
+		// summary.
+		summary := fmt.Sprintf("%s (%d min)", item.Title(), item.Minutes)
+		if item.Done() && item.SessionID != "" {
+			summary = "Done: " + summary
+		}
+		line("SUMMARY:%s", escapeText(summary))
+		line("DESCRIPTION:%s", escapeText(fmt.Sprintf("Preparing for %s on %s. Start it with /plan in prepf.", Target{Company: item.Company, Role: item.Role}.Name(), item.InterviewDay)))
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String(), nil
+}
+
+// escapeText escapes a TEXT property value.
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// fold splits a content line longer than 75 octets into continuation lines
+// starting with a space, without splitting a UTF-8 sequence.
+func fold(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var b strings.Builder
+	width := limit
+	for len(s) > width {
+		cut := width
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// The leading space of a continuation line counts towards its
+		// length.
+		width = limit - 1
+	}
+	b.WriteString(s)
+	return b.String()
+}
@@ -0,0 +1,320 @@
+// Package plan schedules practice leading up to interviews: a day-by-day
+// plan of gym and mock sessions, fit to the time available each day and
+// weighted towards the weakest topics, that tracks which of its items are
+// done.
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/taxonomy"
+)
+
+// Modes of the sessions plan items are practiced in.
+const (
+	ModeGym  = "gym"
+	ModeMock = "mock"
+)
+
+// Day returns the plan day t falls on.
+func Day(t time.Time) string {
+	return t.Format(time.DateOnly)
+}
+
+// Target is an upcoming interview.
+type Target struct {
+	ID      string `json:"id"`
+	Company string `json:"company"`
+	Role    string `json:"role,omitempty"`
+	// Day is the local date of the interview.
+	Day string `json:"day"`
+	// Topics the interview is known to cover get practiced even if they
+	// aren't weak.
+	Topics    []string `json:"topics,omitempty"`
+	CreatedAt int64    `json:"created_at"`
+	UpdatedAt int64    `json:"updated_at"`
+}
+
+// Name describes the interview, as in "Backend Engineer at Acme".
+func (t Target) Name() string {
+	if t.Role == "" {
+		return t.Company
+	}
+	return t.Role + " at " + t.Company
+}
+
+// Item is a practice session scheduled for a day.
+type Item struct {
+	ID        string `json:"id"`
+	TargetID  string `json:"target_id"`
+	Day       string `json:"day"`
+	Position  int    `json:"position"`
+	Mode      string `json:"mode"`
+	Topic     string `json:"topic,omitempty"`
+	Minutes   int    `json:"minutes"`
+	Questions int    `json:"questions"`
+	// SessionID is the session working on the item, once started.
+	SessionID string `json:"session_id,omitempty"`
+	// The interview the item prepares for.
+	Company      string `json:"company"`
+	Role         string `json:"role,omitempty"`
+	InterviewDay string `json:"interview_day"`
+	// Graded is the number of questions graded in the item's session.
+	Graded    int   `json:"graded"`
+	CreatedAt int64 `json:"created_at,omitempty"`
+	UpdatedAt int64 `json:"updated_at,omitempty"`
+}
+
+// Done reports whether the session graded as many questions as planned.
+func (i Item) Done() bool {
+	return i.Graded >= i.Questions
+}
+
+// Title names the item, as in "Gym: Go > Concurrency".
+func (i Item) Title() string {
+	if i.Mode == ModeMock {
+		return "Mock: " + Target{Company: i.Company, Role: i.Role}.Name()
+	}
+	topic := i.Topic
+	if topic == "" {
+		topic = "mixed topics"
+	}
+	return "Gym: " + topic
+}
+
+// Prompt is the message that opens the item's session.
+func Prompt(i Item) string {
+	interview := fmt.Sprintf("my %s interview on %s", Target{Company: i.Company, Role: i.Role}.Name(), i.InterviewDay)
+	if i.Mode == ModeMock {
+		prompt := fmt.Sprintf("This is a %d-minute mock interview from my practice plan, preparing for %s. Interview me as they would, asking about %d question(s)", i.Minutes, interview, i.Questions)
+		if i.Topic != "" {
+			prompt += " covering " + i.Topic
+		}
+		return prompt + ", recording each with drill_question and grading my answers with drill_grade."
+	}
+	topic := i.Topic
+	if topic == "" {
+		topic = "the topics I'm weakest at"
+	}
+	return fmt.Sprintf("This is a %d-minute practice session from my plan, preparing for %s. Drill me on %s with about %d question(s), recording each with drill_question and grading my answers.", i.Minutes, interview, topic, i.Questions)
+}
+
+type Service interface {
+	// AddTarget records an upcoming interview.
+	AddTarget(ctx context.Context, t Target) (Target, error)
+	// Targets lists the interviews, soonest first.
+	Targets(ctx context.Context) ([]Target, error)
+	// RemoveTarget deletes an interview and the items preparing for it.
+	RemoveTarget(ctx context.Context, id string) error
+	// Generate replaces the plan from today to the last interview, keeping
+	// the items already started, and returns it.
+	Generate(ctx context.Context, opts *config.Plan) ([]Item, error)
+	// Items lists the items scheduled between the two days, inclusive.
+	Items(ctx context.Context, from, to string) ([]Item, error)
+	// Today lists today's items.
+	Today(ctx context.Context) ([]Item, error)
+	// Missed lists the items of past days that aren't done.
+	Missed(ctx context.Context) ([]Item, error)
+	// Begin ties an item to the session practicing it.
+	Begin(ctx context.Context, item Item, sessionID string) (Item, error)
+}
+
+type service struct {
+	q      db.Querier
+	drills drill.Service
+	cfg    *config.Config
+	// focuses returns the topics to practice, weighted by need.
+	focuses func(ctx context.Context) ([]Focus, error)
+	now     func() time.Time
+}
+
+func NewService(q db.Querier, drills drill.Service, cfg *config.Config) Service {
+	s := &service{
+		q:      q,
+		drills: drills,
+		cfg:    cfg,
+		now:    time.Now,
+	}
+	s.focuses = s.weakness
+	return s
+}
+
+func (s *service) AddTarget(ctx context.Context, t Target) (Target, error) {
+	t.Company = strings.TrimSpace(t.Company)
+	if t.Company == "" {
+		return Target{}, errors.New("company is required")
+	}
+	if _, err := time.Parse(time.DateOnly, t.Day); err != nil {
+		return Target{}, fmt.Errorf("interview date must look like 2006-01-02, got %q", t.Day)
+	}
+	if t.Day <= Day(s.now()) {
+		return Target{}, fmt.Errorf("interview date %s is not in the future", t.Day)
+	}
+	topics, err := json.Marshal(append([]string{}, t.Topics...))
+	if err != nil {
+		return Target{}, err
+	}
+	row, err := s.q.CreateInterviewTarget(ctx, db.CreateInterviewTargetParams{
+		ID:      uuid.New().String(),
+		Company: t.Company,
+		Role:    strings.TrimSpace(t.Role),
+		Day:     t.Day,
+		Topics:  string(topics),
+	})
+	if err != nil {
+		return Target{}, err
+	}
+	return targetFromDB(row), nil
+}
+
+func (s *service) Targets(ctx context.Context) ([]Target, error) {
+	rows, err := s.q.ListInterviewTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]Target, len(rows))
+	for i, row := range rows {
+		targets[i] = targetFromDB(row)
+	}
+	return targets, nil
+}
+
+func (s *service) RemoveTarget(ctx context.Context, id string) error {
+	return s.q.DeleteInterviewTarget(ctx, id)
+}
+
+func (s *service) Generate(ctx context.Context, opts *config.Plan) ([]Item, error) {
+	targets, err := s.Targets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	focuses, err := s.focuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	today := Day(now)
+	if err := s.q.DeleteUnstartedPlanItems(ctx, today); err != nil {
+		return nil, err
+	}
+	kept, err := s.Items(ctx, today, lastDay)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range Schedule(targets, focuses, kept, now, opts.MinutesOn) {
+		err := s.q.CreatePlanItem(ctx, db.CreatePlanItemParams{
+			ID:        uuid.New().String(),
+			TargetID:  item.TargetID,
+			Day:       item.Day,
+			Position:  int64(item.Position),
+			Mode:      item.Mode,
+			Topic:     item.Topic,
+			Minutes:   int64(item.Minutes),
+			Questions: int64(item.Questions),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.Items(ctx, today, lastDay)
+}
+
+// lastDay sorts after every plan day.
+const lastDay = "9999-12-31"
+
+func (s *service) Items(ctx context.Context, from, to string) ([]Item, error) {
+	rows, err := s.q.ListPlanItems(ctx, db.ListPlanItemsParams{
+		FromDay: from,
+		ToDay:   to,
+	})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Item, len(rows))
+	for i, row := range rows {
+		items[i] = Item{
+			ID:           row.ID,
+			TargetID:     row.TargetID,
+			Day:          row.Day,
+			Position:     int(row.Position),
+			Mode:         row.Mode,
+			Topic:        row.Topic,
+			Minutes:      int(row.Minutes),
+			Questions:    int(row.Questions),
+			SessionID:    row.SessionID,
+			Company:      row.Company,
+			Role:         row.Role,
+			InterviewDay: row.InterviewDay,
+			Graded:       int(row.Graded),
+			CreatedAt:    row.CreatedAt,
+			UpdatedAt:    row.UpdatedAt,
+		}
+	}
+	return items, nil
+}
+
+func (s *service) Today(ctx context.Context) ([]Item, error) {
+	today := Day(s.now())
+	return s.Items(ctx, today, today)
+}
+
+func (s *service) Missed(ctx context.Context) ([]Item, error) {
+	items, err := s.Items(ctx, "", Day(s.now().AddDate(0, 0, -1)))
+	if err != nil {
+		return nil, err
+	}
+	var missed []Item
+	for _, item := range items {
+		if !item.Done() {
+			missed = append(missed, item)
+		}
+	}
+	return missed, nil
+}
+
+func (s *service) Begin(ctx context.Context, item Item, sessionID string) (Item, error) {
+	err := s.q.SetPlanItemSession(ctx, db.SetPlanItemSessionParams{
+		SessionID: sessionID,
+		ID:        item.ID,
+	})
+	if err != nil {
+		return Item{}, err
+	}
+	item.SessionID = sessionID
+	item.Graded = 0
+	return item, nil
+}
+
+// weakness weighs the topics of the taxonomy by how the questions filed
+// under them went.
+func (s *service) weakness(ctx context.Context) ([]Focus, error) {
+	tx, _ := taxonomy.Load(s.cfg)
+	questions, err := s.drills.Classified(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Focuses(tx.Mastery(questions)), nil
+}
+
+func targetFromDB(row db.InterviewTarget) Target {
+	var topics []string
+	_ = json.Unmarshal([]byte(row.Topics), &topics)
+	return Target{
+		ID:        row.ID,
+		Company:   row.Company,
+		Role:      row.Role,
+		Day:       row.Day,
+		Topics:    topics,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
@@ -0,0 +1,212 @@
+package plan
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/message"
+	"github.com/trankhanh040147/prepf/internal/taxonomy"
+)
+
+func TestSchedule(t *testing.T) {
+	t.Parallel()
+
+	targets := []Target{{ID: "t1", Company: "Acme", Role: "Backend Engineer", Day: "2026-10-23", Topics: []string{"System design"}}}
+	focuses := []Focus{{Topic: "Go > Concurrency", Weight: 80}, {Topic: "SQL", Weight: 40}}
+	kept := []Item{{Day: "2026-10-20", Mode: ModeGym, Topic: "SQL", Minutes: 30, Questions: 3}}
+	today := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)
+
+	items := Schedule(targets, focuses, kept, today, func(time.Weekday) int { return 60 })
+	type slot struct {
+		day, mode, topic  string
+		position, minutes int
+	}
+	var got []slot
+	for _, item := range items {
+		require.Equal(t, "t1", item.TargetID)
+		require.Equal(t, "2026-10-23", item.InterviewDay)
+		got = append(got, slot{item.Day, item.Mode, item.Topic, item.Position, item.Minutes})
+	}
+	require.Equal(t, []slot{
+		{"2026-10-19", ModeGym, "Go > Concurrency", 0, 30},
+		{"2026-10-19", ModeGym, "System design", 1, 30},
+		// The kept item leaves half an hour.
+		{"2026-10-20", ModeGym, "Go > Concurrency", 1, 30},
+		// Topics rotate by weight per session assigned.
+		{"2026-10-21", ModeGym, "System design", 0, 30},
+		{"2026-10-21", ModeGym, "Go > Concurrency", 1, 30},
+		// The day before the interview starts with a mock.
+		{"2026-10-22", ModeMock, "System design", 0, 45},
+		{"2026-10-22", ModeGym, "Go > Concurrency", 1, 15},
+	}, got)
+	require.Equal(t, 3, items[0].Questions)
+	require.Equal(t, 3, items[5].Questions)
+
+	// No time on weekends and none after the interview.
+	items = Schedule(targets, focuses, nil, time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local), func(d time.Weekday) int {
+		if d == time.Saturday || d == time.Sunday {
+			return 0
+		}
+		return 30
+	})
+	require.Equal(t, "2026-10-19", items[0].Day)
+	require.Equal(t, "2026-10-22", items[len(items)-1].Day)
+	require.Equal(t, ModeMock, items[len(items)-1].Mode)
+	require.Equal(t, 30, items[len(items)-1].Minutes)
+
+	require.Empty(t, Schedule(targets, focuses, nil, time.Date(2026, 10, 23, 9, 0, 0, 0, time.Local), func(time.Weekday) int { return 60 }))
+}
+
+func TestBlocks(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, blocks(10))
+	require.Equal(t, []int{15}, blocks(15))
+	require.Equal(t, []int{40}, blocks(40))
+	require.Equal(t, []int{30, 15}, blocks(45))
+	require.Equal(t, []int{30, 30}, blocks(60))
+	require.Equal(t, []int{30, 40}, blocks(70))
+	require.Equal(t, []int{30, 30, 30}, blocks(90))
+}
+
+func TestFocuses(t *testing.T) {
+	t.Parallel()
+
+	graded := func(n int) []drill.Question { return make([]drill.Question, n) }
+	masteries := []*taxonomy.Mastery{
+		{
+			Node:      &taxonomy.Node{Path: "Go"},
+			Score:     70,
+			Questions: graded(3),
+			Children: []*taxonomy.Mastery{
+				{Node: &taxonomy.Node{Path: "Go > Concurrency"}, Score: 40, Questions: graded(2)},
+				{Node: &taxonomy.Node{Path: "Go > Maps"}, Score: 95, Questions: graded(1)},
+				{Node: &taxonomy.Node{Path: "Go > Generics"}},
+			},
+		},
+		{Node: &taxonomy.Node{Path: "SQL"}, Score: 80, Questions: graded(1)},
+		{Node: &taxonomy.Node{Path: "System design"}},
+		{Node: &taxonomy.Node{Path: "Networking"}},
+	}
+	require.Equal(t, []Focus{
+		{Topic: "Go > Concurrency", Weight: 60},
+		{Topic: "SQL", Weight: 20},
+		{Topic: "Go > Maps", Weight: minWeight},
+		{Topic: "System design", Weight: untestedWeight},
+	}, Focuses(masteries))
+}
+
+func TestICS(t *testing.T) {
+	t.Parallel()
+
+	targets := []Target{{ID: "t1", Company: "Acme, Inc.", Role: "SRE", Day: "2026-10-23"}}
+	items := []Item{
+		{ID: "i1", Day: "2026-10-22", Mode: ModeMock, Minutes: 45, Questions: 3, Graded: 3, SessionID: "s1", Company: "Acme, Inc.", Role: "SRE", InterviewDay: "2026-10-23"},
+		{ID: "i2", Day: "2026-10-22", Mode: ModeGym, Topic: strings.Repeat("Distributed systems; ", 5), Minutes: 30, Questions: 3, Company: "Acme, Inc.", InterviewDay: "2026-10-23"},
+	}
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	out, err := ICS(targets, items, ICSOptions{}, now)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	require.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	require.Contains(t, out, "SUMMARY:Interview: SRE at Acme\\, Inc.\r\n")
+	require.Contains(t, out, "UID:item-i1@prepf\r\nDTSTAMP:20261019T090000Z\r\nDTSTART;VALUE=DATE:20261022\r\nDTEND;VALUE=DATE:20261023\r\n")
+	require.Contains(t, out, "SUMMARY:Done: Mock: SRE at Acme\\, Inc. (45 min)\r\n")
+	require.NotContains(t, out, "STATUS:")
+	require.Contains(t, out, "Distributed systems\\;")
+	for line := range strings.SplitSeq(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), 75, line)
+	}
+	require.Contains(t, out, "\r\n ", "long lines are folded")
+
+	out, err = ICS(nil, items, ICSOptions{Start: "19:00", Location: time.UTC}, now)
+	require.NoError(t, err)
+	require.Contains(t, out, "DTSTART:20261022T190000Z\r\nDTEND:20261022T194500Z\r\n")
+	require.Contains(t, out, "DTSTART:20261022T194500Z\r\nDTEND:20261022T201500Z\r\n")
+
+	_, err = ICS(nil, items, ICSOptions{Start: "7pm"}, now)
+	require.Error(t, err)
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	q := db.New(conn)
+	drills := drill.NewService(q, conn)
+	svc := NewService(q, drills, nil).(*service)
+	svc.now = func() time.Time { return time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local) }
+	svc.focuses = func(context.Context) ([]Focus, error) {
+		return []Focus{{Topic: "Go > Concurrency", Weight: 80}}, nil
+	}
+
+	_, err = svc.AddTarget(t.Context(), Target{Company: " ", Day: "2026-10-23"})
+	require.Error(t, err)
+	_, err = svc.AddTarget(t.Context(), Target{Company: "Acme", Day: "23/10/2026"})
+	require.Error(t, err)
+	_, err = svc.AddTarget(t.Context(), Target{Company: "Acme", Day: "2026-10-19"})
+	require.Error(t, err)
+
+	target, err := svc.AddTarget(t.Context(), Target{Company: "Acme", Role: "Backend Engineer", Day: "2026-10-21", Topics: []string{"SQL"}})
+	require.NoError(t, err)
+	targets, err := svc.Targets(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, []Target{target}, targets)
+	require.Equal(t, []string{"SQL"}, targets[0].Topics)
+
+	opts := &config.Plan{Minutes: 60}
+	items, err := svc.Generate(t.Context(), opts)
+	require.NoError(t, err)
+	require.Len(t, items, 4)
+	require.Equal(t, "2026-10-20", items[2].Day)
+	require.Equal(t, ModeMock, items[2].Mode)
+	require.Equal(t, "Backend Engineer at Acme", items[0].Role+" at "+items[0].Company)
+
+	today, err := svc.Today(t.Context())
+	require.NoError(t, err)
+	require.Len(t, today, 2)
+	item, err := svc.Begin(t.Context(), today[0], "s1")
+	require.NoError(t, err)
+	require.Equal(t, "s1", item.SessionID)
+	for range item.Questions {
+		_, err = drills.Ask(t.Context(), drill.Question{SessionID: "s1", Topic: item.Topic, Text: "Why?"})
+		require.NoError(t, err)
+		_, err = drills.Grade(t.Context(), "s1", 70, &message.AnswerTiming{})
+		require.NoError(t, err)
+	}
+
+	// The next day the started item is kept and the other one is missed.
+	svc.now = func() time.Time { return time.Date(2026, 10, 20, 9, 0, 0, 0, time.Local) }
+	missed, err := svc.Missed(t.Context())
+	require.NoError(t, err)
+	require.Len(t, missed, 1)
+	require.Equal(t, today[1].ID, missed[0].ID)
+
+	items, err = svc.Items(t.Context(), "2026-10-19", "2026-10-19")
+	require.NoError(t, err)
+	require.True(t, items[0].Done())
+	require.Equal(t, item.Questions, items[0].Graded)
+
+	items, err = svc.Generate(t.Context(), &config.Plan{Minutes: 30})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, ModeMock, items[0].Mode)
+	require.Equal(t, 30, items[0].Minutes)
+	past, err := svc.Items(t.Context(), "", "2026-10-19")
+	require.NoError(t, err)
+	require.Len(t, past, 2, "generating leaves past days alone")
+
+	require.NoError(t, svc.RemoveTarget(t.Context(), target.ID))
+	items, err = svc.Items(t.Context(), "", lastDay)
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
@@ -0,0 +1,211 @@
+package plan
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/trankhanh040147/prepf/internal/taxonomy"
+)
+
+// Focus is a topic practice time is spent on, weighted by how much it needs
+// it.
+type Focus struct {
+	Topic  string
+	Weight float64
+}
+
+// Weights of topics that haven't been practiced and of topics an interview
+// is known to cover, on the same 0-100 scale as the weight of a practiced
+// topic, which is how far its score is from 100.
+const (
+	untestedWeight  = 50
+	interviewWeight = 60
+	minWeight       = 10
+	// minFocuses is how many topics the plan should rotate through before
+	// it picks untested ones.
+	minFocuses = 4
+)
+
+// Focuses picks the topics to practice from the weakness map: the most
+// specific topics with graded questions, weighted by how far their score
+// is from 100, topped up with untested top-level topics when there are few.
+func Focuses(masteries []*taxonomy.Mastery) []Focus {
+	var focuses []Focus
+	var walk func(ms []*taxonomy.Mastery)
+	walk = func(ms []*taxonomy.Mastery) {
+		for _, m := range ms {
+			if len(m.Questions) == 0 {
+				continue
+			}
+			tested := slices.ContainsFunc(m.Children, func(c *taxonomy.Mastery) bool { return len(c.Questions) > 0 })
+			if tested {
+				walk(m.Children)
+				continue
+			}
+			focuses = append(focuses, Focus{Topic: m.Node.Path, Weight: max(100-m.Score, minWeight)})
+		}
+	}
+	walk(masteries)
+	slices.SortStableFunc(focuses, func(a, b Focus) int {
+		return cmp.Compare(b.Weight, a.Weight)
+	})
+	for _, m := range masteries {
+		if len(focuses) >= minFocuses {
+			break
+		}
+		if len(m.Questions) == 0 {
+			focuses = append(focuses, Focus{Topic: m.Node.Path, Weight: untestedWeight})
+		}
+	}
+	return focuses
+}
+
+// Lengths of the sessions the plan is made of, in minutes.
+const (
+	gymMinutes  = 30
+	mockMinutes = 45
+	// minMinutes is the shortest session worth scheduling.
+	minMinutes = 15
+	// mockEvery is how many days apart mock interviews are held, counting
+	// back from the day before the interview.
+	mockEvery = 4
+)
+
+// Schedule plans the days from today up to the day before the last
+// interview. Each day works towards the next interview and is filled with
+// gym sessions on the focus topics, the neediest most often, up to the
+// minutes available that day less those of the kept items. Mock interviews
+// are held on the day before each interview and every few days before that.
+func Schedule(targets []Target, focuses []Focus, kept []Item, today time.Time, minutesOn func(time.Weekday) int) []Item {
+	targets = slices.Clone(targets)
+	slices.SortStableFunc(targets, func(a, b Target) int {
+		return strings.Compare(a.Day, b.Day)
+	})
+	booked := make(map[string]int)
+	positions := make(map[string]int)
+	assigned := make(map[string]int)
+	for _, item := range kept {
+		booked[item.Day] += item.Minutes
+		positions[item.Day] = max(positions[item.Day], item.Position+1)
+		if item.Mode == ModeGym {
+			assigned[item.Topic]++
+		}
+	}
+
+	var items []Item
+	for d := today; ; d = d.AddDate(0, 0, 1) {
+		day := Day(d)
+		i := slices.IndexFunc(targets, func(t Target) bool { return t.Day > day })
+		if i < 0 {
+			break
+		}
+		target := targets[i]
+		available := minutesOn(d.Weekday()) - booked[day]
+		if available < minMinutes {
+			continue
+		}
+		add := func(mode, topic string, minutes int) {
+			questions := max(minutes/10, 1)
+			if mode == ModeMock {
+				questions = max(minutes/15, 2)
+			}
+			items = append(items, Item{
+				TargetID:     target.ID,
+				Day:          day,
+				Position:     positions[day],
+				Mode:         mode,
+				Topic:        topic,
+				Minutes:      minutes,
+				Questions:    questions,
+				Company:      target.Company,
+				Role:         target.Role,
+				InterviewDay: target.Day,
+			})
+			positions[day]++
+		}
+
+		interview, _ := time.Parse(time.DateOnly, target.Day)
+		midnight, _ := time.Parse(time.DateOnly, day)
+		daysLeft := int(interview.Sub(midnight).Hours() / 24)
+		if daysLeft%mockEvery == 1 {
+			minutes := min(available, mockMinutes)
+			add(ModeMock, strings.Join(target.Topics, ", "), minutes)
+			available -= minutes
+		}
+
+		topics := withInterviewTopics(focuses, target.Topics)
+		var practiced []string
+		for _, minutes := range blocks(available) {
+			topic := pick(topics, assigned, practiced)
+			assigned[topic]++
+			practiced = append(practiced, topic)
+			add(ModeGym, topic, minutes)
+		}
+	}
+	return items
+}
+
+// blocks splits the minutes into gym sessions. A remainder too short for a
+// session of its own goes to the last one.
+func blocks(minutes int) []int {
+	if minutes < minMinutes {
+		return nil
+	}
+	if minutes < gymMinutes+minMinutes {
+		return []int{minutes}
+	}
+	var bs []int
+	for minutes >= gymMinutes {
+		bs = append(bs, gymMinutes)
+		minutes -= gymMinutes
+	}
+	if minutes >= minMinutes {
+		bs = append(bs, minutes)
+	} else {
+		bs[len(bs)-1] += minutes
+	}
+	return bs
+}
+
+// withInterviewTopics adds the topics the interview covers, raising the
+// weight of focus topics that mention them.
+func withInterviewTopics(focuses []Focus, topics []string) []Focus {
+	focuses = slices.Clone(focuses)
+	for _, topic := range topics {
+		found := false
+		for i, f := range focuses {
+			if strings.Contains(strings.ToLower(f.Topic), strings.ToLower(topic)) {
+				focuses[i].Weight = max(f.Weight, interviewWeight)
+				found = true
+			}
+		}
+		if !found {
+			focuses = append(focuses, Focus{Topic: topic, Weight: interviewWeight})
+		}
+	}
+	return focuses
+}
+
+// pick returns the topic with the most weight per session already assigned
+// to it, avoiding the ones practiced earlier the same day while there are
+// others. With no topics at all it returns an empty one, left to the
+// interviewer.
+func pick(focuses []Focus, assigned map[string]int, practiced []string) string {
+	best, bestScore := "", -1.0
+	for _, avoidToday := range []bool{true, false} {
+		for _, f := range focuses {
+			if avoidToday && slices.Contains(practiced, f.Topic) {
+				continue
+			}
+			if score := f.Weight / float64(1+assigned[f.Topic]); score > bestScore {
+				best, bestScore = f.Topic, score
+			}
+		}
+		if bestScore >= 0 {
+			break
+		}
+	}
+	return best
+}
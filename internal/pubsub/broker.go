@@ -2,34 +2,127 @@ package pubsub
 
 import (
 	"context"
+	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const bufferSize = 64
 
-type Broker[T any] struct {
-	subs      map[chan Event[T]]struct{}
-	mu        sync.RWMutex
-	done      chan struct{}
-	subCount  int
-	maxEvents int
+// Policy decides what happens to an event published while a subscriber's
+// buffer is full.
+type Policy int
+
+const (
+	// DropOldest discards the oldest buffered event to make room, so a slow
+	// subscriber always ends up with the latest state.
+	DropOldest Policy = iota
+	// Coalesce replaces a buffered event of the same type and key with the
+	// new one, so only the latest version of each resource waits to be
+	// delivered. When the buffer is still full it falls back to DropOldest.
+	Coalesce
+	// Block makes the publisher wait for room, up to a timeout after which
+	// the new event is dropped.
+	Block
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Coalesce:
+		return "coalesce"
+	case Block:
+		return "block"
+	default:
+		return "drop-oldest"
+	}
+}
+
+// Option configures a broker's subscribers. Options given to NewBroker apply
+// to every subscriber; those given to SubscribeWith override them for one.
+type Option[T any] func(*config[T])
+
+type config[T any] struct {
+	name    string
+	size    int
+	policy  Policy
+	key     func(T) string
+	timeout time.Duration
+}
+
+// WithName names the broker in logs and stats.
+func WithName[T any](name string) Option[T] {
+	return func(c *config[T]) { c.name = name }
 }
 
-func NewBroker[T any]() *Broker[T] {
-	return NewBrokerWithOptions[T](bufferSize, 1000)
+// WithBufferSize sets how many events a subscriber can fall behind before its
+// policy applies.
+func WithBufferSize[T any](size int) Option[T] {
+	return func(c *config[T]) { c.size = max(size, 1) }
 }
 
-func NewBrokerWithOptions[T any](channelBufferSize, maxEvents int) *Broker[T] {
+// WithDropOldest selects the DropOldest policy, the default.
+func WithDropOldest[T any]() Option[T] {
+	return func(c *config[T]) { c.policy = DropOldest }
+}
+
+// WithCoalesce selects the Coalesce policy, with key identifying the resource
+// an event is about.
+func WithCoalesce[T any](key func(T) string) Option[T] {
+	return func(c *config[T]) {
+		c.policy = Coalesce
+		c.key = key
+	}
+}
+
+// WithBlock selects the Block policy, waiting at most timeout for room.
+func WithBlock[T any](timeout time.Duration) Option[T] {
+	return func(c *config[T]) {
+		c.policy = Block
+		c.timeout = timeout
+	}
+}
+
+// SubscriberStats reports how a subscriber keeps up with its broker.
+type SubscriberStats struct {
+	Broker     string
+	Subscriber int
+	Policy     Policy
+	// Queued is the number of events waiting to be delivered.
+	Queued int
+	// Dropped counts events the subscriber never received.
+	Dropped uint64
+	// Coalesced counts events replaced by a later version before delivery.
+	Coalesced uint64
+}
+
+type Broker[T any] struct {
+	config   config[T]
+	subs     map[*subscription[T]]struct{}
+	snapshot atomic.Pointer[[]*subscription[T]]
+	mu       sync.Mutex
+	done     chan struct{}
+	nextID   int
+}
+
+func NewBroker[T any](opts ...Option[T]) *Broker[T] {
 	b := &Broker[T]{
-		subs:      make(map[chan Event[T]]struct{}),
-		done:      make(chan struct{}),
-		subCount:  0,
-		maxEvents: maxEvents,
+		config: config[T]{size: bufferSize},
+		subs:   make(map[*subscription[T]]struct{}),
+		done:   make(chan struct{}),
 	}
+	for _, opt := range opts {
+		opt(&b.config)
+	}
+	b.snapshot.Store(&[]*subscription[T]{})
 	return b
 }
 
 func (b *Broker[T]) Shutdown() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	select {
 	case <-b.done: // Already closed
 		return
@@ -37,18 +130,21 @@ func (b *Broker[T]) Shutdown() {
 		close(b.done)
 	}
 
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	for ch := range b.subs {
-		delete(b.subs, ch)
-		close(ch)
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		sub.close()
 	}
-
-	b.subCount = 0
+	b.snapshot.Store(&[]*subscription[T]{})
 }
 
+// Subscribe subscribes with the broker's options until ctx is done.
 func (b *Broker[T]) Subscribe(ctx context.Context) <-chan Event[T] {
+	return b.SubscribeWith(ctx)
+}
+
+// SubscribeWith subscribes with opts applied over the broker's options until
+// ctx is done.
+func (b *Broker[T]) SubscribeWith(ctx context.Context, opts ...Option[T]) <-chan Event[T] {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -60,40 +156,67 @@ func (b *Broker[T]) Subscribe(ctx context.Context) <-chan Event[T] {
 	default:
 	}
 
-	sub := make(chan Event[T], bufferSize)
+	cfg := b.config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.policy == Coalesce && cfg.key == nil {
+		cfg.policy = DropOldest
+	}
+	b.nextID++
+	sub := newSubscription(cfg, b.nextID)
 	b.subs[sub] = struct{}{}
-	b.subCount++
+	b.storeSnapshot()
 
 	go func() {
-		<-ctx.Done()
+		select {
+		case <-ctx.Done():
+		case <-sub.done:
+			return
+		}
 
 		b.mu.Lock()
 		defer b.mu.Unlock()
 
-		select {
-		case <-b.done:
+		if _, ok := b.subs[sub]; !ok {
 			return
-		default:
 		}
-
 		delete(b.subs, sub)
-		close(sub)
-		b.subCount--
+		b.storeSnapshot()
+		sub.close()
 	}()
 
-	return sub
+	return sub.out
+}
+
+// storeSnapshot publishes the current subscribers to Publish, which reads
+// them without locking. The caller holds b.mu.
+func (b *Broker[T]) storeSnapshot() {
+	subs := make([]*subscription[T], 0, len(b.subs))
+	for sub := range b.subs {
+		subs = append(subs, sub)
+	}
+	b.snapshot.Store(&subs)
 }
 
 func (b *Broker[T]) GetSubscriberCount() int {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
-	return b.subCount
+	return len(*b.snapshot.Load())
 }
 
-func (b *Broker[T]) Publish(t EventType, payload T) {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
+// Stats reports on every current subscriber.
+func (b *Broker[T]) Stats() []SubscriberStats {
+	subs := *b.snapshot.Load()
+	stats := make([]SubscriberStats, len(subs))
+	for i, sub := range subs {
+		stats[i] = sub.stats()
+	}
+	slices.SortFunc(stats, func(a, b SubscriberStats) int { return a.Subscriber - b.Subscriber })
+	return stats
+}
 
+// Publish hands the event to every subscriber, applying each one's policy
+// when it is behind. It only blocks for subscribers with the Block policy.
+func (b *Broker[T]) Publish(t EventType, payload T) {
 	select {
 	case <-b.done:
 		return
@@ -101,13 +224,180 @@ func (b *Broker[T]) Publish(t EventType, payload T) {
 	}
 
 	event := Event[T]{Type: t, Payload: payload}
+	for _, sub := range *b.snapshot.Load() {
+		sub.push(event)
+	}
+}
 
-	for sub := range b.subs {
+// subscription buffers events for one subscriber and delivers them from its
+// own goroutine, so policies can act on the whole buffer rather than on a
+// channel.
+type subscription[T any] struct {
+	config[T]
+	id  int
+	out chan Event[T]
+
+	mu     sync.Mutex
+	queue  []Event[T]
+	keys   []string
+	space  chan struct{} // closed and replaced whenever an event is taken
+	ready  chan struct{}
+	done   chan struct{}
+	closed bool
+
+	dropped   atomic.Uint64
+	coalesced atomic.Uint64
+}
+
+func newSubscription[T any](cfg config[T], id int) *subscription[T] {
+	s := &subscription[T]{
+		config: cfg,
+		id:     id,
+		out:    make(chan Event[T]),
+		space:  make(chan struct{}),
+		ready:  make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *subscription[T]) run() {
+	defer close(s.out)
+	for {
 		select {
-		case sub <- event:
-		default:
-			// Channel is full, subscriber is slow - skip this event
-			// This prevents blocking the publisher
+		case <-s.ready:
+		case <-s.done:
+			return
+		}
+		for {
+			event, ok := s.pop()
+			if !ok {
+				break
+			}
+			select {
+			case s.out <- event:
+			case <-s.done:
+				return
+			}
+		}
+	}
+}
+
+func (s *subscription[T]) pop() (Event[T], bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.queue) == 0 {
+		return Event[T]{}, false
+	}
+	event := s.queue[0]
+	s.queue = slices.Delete(s.queue, 0, 1)
+	if s.policy == Coalesce {
+		s.keys = slices.Delete(s.keys, 0, 1)
+	}
+	close(s.space)
+	s.space = make(chan struct{})
+	return event, true
+}
+
+func (s *subscription[T]) push(event Event[T]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	var key string
+	if s.policy == Coalesce {
+		key = s.key(event.Payload)
+		for i, queued := range s.queue {
+			if queued.Type == event.Type && s.keys[i] == key {
+				s.queue[i] = event
+				s.coalesced.Add(1)
+				return
+			}
+		}
+	}
+
+	var timeout <-chan time.Time
+	for len(s.queue) >= s.size {
+		if s.policy != Block {
+			s.queue = slices.Delete(s.queue, 0, 1)
+			if s.policy == Coalesce {
+				s.keys = slices.Delete(s.keys, 0, 1)
+			}
+			s.drop()
+			continue
 		}
+
+		if timeout == nil {
+			timer := time.NewTimer(s.timeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		space := s.space
+		s.mu.Unlock()
+		select {
+		case <-space:
+		case <-timeout:
+			s.mu.Lock()
+			s.drop()
+			return
+		case <-s.done:
+			s.mu.Lock()
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			return
+		}
+	}
+
+	s.queue = append(s.queue, event)
+	if s.policy == Coalesce {
+		s.keys = append(s.keys, key)
+	}
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// drop counts a dropped event, logging the first one and then every time
+// the count doubles so a stuck subscriber can't flood the log.
+func (s *subscription[T]) drop() {
+	n := s.dropped.Add(1)
+	if n&(n-1) == 0 {
+		slog.Warn("Subscriber is behind, dropped events", "broker", s.name, "subscriber", s.id, "policy", s.policy, "dropped", n)
+	}
+}
+
+func (s *subscription[T]) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.done)
+	if dropped, coalesced := s.dropped.Load(), s.coalesced.Load(); dropped > 0 || coalesced > 0 {
+		slog.Debug("Subscriber closed", "broker", s.name, "subscriber", s.id, "policy", s.policy, "dropped", dropped, "coalesced", coalesced)
+	}
+}
+
+func (s *subscription[T]) stats() SubscriberStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return SubscriberStats{
+		Broker:     s.name,
+		Subscriber: s.id,
+		Policy:     s.policy,
+		Queued:     len(s.queue),
+		Dropped:    s.dropped.Load(),
+		Coalesced:  s.coalesced.Load(),
 	}
 }
@@ -0,0 +1,281 @@
+package pubsub
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	ID      string
+	Version int
+}
+
+func itemKey(i item) string { return i.ID }
+
+// receiveUntil reads events until one matches last.
+func receiveUntil(t *testing.T, ch <-chan Event[item], last item) []Event[item] {
+	t.Helper()
+	var events []Event[item]
+	for {
+		select {
+		case e, ok := <-ch:
+			require.True(t, ok, "channel closed before %v", last)
+			events = append(events, e)
+			if e.Payload == last {
+				return events
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %v, got %v", last, events)
+		}
+	}
+}
+
+func TestDropOldest(t *testing.T) {
+	t.Parallel()
+
+	b := NewBroker(WithName[item]("test"), WithBufferSize[item](2))
+	t.Cleanup(b.Shutdown)
+	ch := b.Subscribe(t.Context())
+
+	for v := 1; v <= 5; v++ {
+		b.Publish(CreatedEvent, item{"a", v})
+	}
+	events := receiveUntil(t, ch, item{"a", 5})
+
+	// The subscriber is behind by more than its buffer, so the oldest
+	// events went and the latest ones arrived.
+	require.Equal(t, item{"a", 4}, events[len(events)-2].Payload)
+	stats := b.Stats()
+	require.Len(t, stats, 1)
+	require.Equal(t, "test", stats[0].Broker)
+	require.Equal(t, DropOldest, stats[0].Policy)
+	require.EqualValues(t, 5, uint64(len(events))+stats[0].Dropped)
+}
+
+func TestCoalesce(t *testing.T) {
+	t.Parallel()
+
+	b := NewBroker(WithCoalesce(itemKey))
+	t.Cleanup(b.Shutdown)
+	ch := b.Subscribe(t.Context())
+
+	b.Publish(CreatedEvent, item{"a", 0})
+	for v := 1; v <= 10; v++ {
+		b.Publish(UpdatedEvent, item{"a", v})
+	}
+	b.Publish(CreatedEvent, item{"b", 0})
+	events := receiveUntil(t, ch, item{"b", 0})
+
+	// Updates collapse into the latest version, but the creation isn't
+	// swallowed by them.
+	require.Equal(t, Event[item]{CreatedEvent, item{"a", 0}}, events[0])
+	require.Equal(t, Event[item]{UpdatedEvent, item{"a", 10}}, events[len(events)-2])
+	for i := 2; i < len(events)-1; i++ {
+		require.Greater(t, events[i].Payload.Version, events[i-1].Payload.Version)
+	}
+	stats := b.Stats()
+	require.Zero(t, stats[0].Dropped)
+	require.EqualValues(t, 12, uint64(len(events))+stats[0].Coalesced)
+}
+
+func TestCoalesceFull(t *testing.T) {
+	t.Parallel()
+
+	b := NewBroker(WithCoalesce(itemKey), WithBufferSize[item](2))
+	t.Cleanup(b.Shutdown)
+	ch := b.Subscribe(t.Context())
+
+	for i := range 5 {
+		b.Publish(UpdatedEvent, item{strconv.Itoa(i), 1})
+	}
+	events := receiveUntil(t, ch, item{"4", 1})
+	require.EqualValues(t, 5, uint64(len(events))+b.Stats()[0].Dropped)
+}
+
+func TestBlock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("waits for the subscriber", func(t *testing.T) {
+		t.Parallel()
+
+		b := NewBroker(WithBlock[item](time.Minute), WithBufferSize[item](1))
+		t.Cleanup(b.Shutdown)
+		ch := b.Subscribe(t.Context())
+
+		go func() {
+			for v := 1; v <= 20; v++ {
+				b.Publish(CreatedEvent, item{"a", v})
+			}
+		}()
+		events := receiveUntil(t, ch, item{"a", 20})
+		require.Len(t, events, 20)
+		require.Zero(t, b.Stats()[0].Dropped)
+	})
+
+	t.Run("drops after the timeout", func(t *testing.T) {
+		t.Parallel()
+
+		b := NewBroker(WithBlock[item](10*time.Millisecond), WithBufferSize[item](1))
+		t.Cleanup(b.Shutdown)
+		ch := b.Subscribe(t.Context())
+
+		for v := 1; v <= 3; v++ {
+			b.Publish(CreatedEvent, item{"a", v})
+		}
+		dropped := b.Stats()[0].Dropped
+		require.NotZero(t, dropped)
+		for range 3 - dropped {
+			<-ch
+		}
+	})
+
+	t.Run("unblocks when the subscriber leaves", func(t *testing.T) {
+		t.Parallel()
+
+		b := NewBroker(WithBlock[item](time.Minute), WithBufferSize[item](1))
+		ctx, cancel := context.WithCancel(t.Context())
+		b.Subscribe(ctx)
+
+		published := make(chan struct{})
+		go func() {
+			for v := 1; v <= 3; v++ {
+				b.Publish(CreatedEvent, item{"a", v})
+			}
+			close(published)
+		}()
+		cancel()
+		select {
+		case <-published:
+		case <-time.After(5 * time.Second):
+			t.Fatal("publisher still blocked")
+		}
+	})
+}
+
+func TestSubscribeWith(t *testing.T) {
+	t.Parallel()
+
+	b := NewBroker(WithName[item]("test"), WithBlock[item](time.Minute))
+	t.Cleanup(b.Shutdown)
+	b.Subscribe(t.Context())
+	b.SubscribeWith(t.Context(), WithCoalesce(itemKey))
+	// Coalescing without a key has nothing to go by.
+	b.SubscribeWith(t.Context(), WithCoalesce[item](nil))
+
+	stats := b.Stats()
+	require.Len(t, stats, 3)
+	require.Equal(t, []Policy{Block, Coalesce, DropOldest}, []Policy{stats[0].Policy, stats[1].Policy, stats[2].Policy})
+	require.Equal(t, "test", stats[1].Broker)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	t.Parallel()
+
+	b := NewBroker[item]()
+	ctx, cancel := context.WithCancel(t.Context())
+	ch := b.Subscribe(ctx)
+	other := b.Subscribe(t.Context())
+	require.Equal(t, 2, b.GetSubscriberCount())
+
+	cancel()
+	for range ch {
+	}
+	require.Eventually(t, func() bool { return b.GetSubscriberCount() == 1 }, 5*time.Second, time.Millisecond)
+
+	b.Shutdown()
+	for range other {
+	}
+	require.Zero(t, b.GetSubscriberCount())
+	_, ok := <-b.Subscribe(t.Context())
+	require.False(t, ok)
+	b.Publish(CreatedEvent, item{"a", 1})
+	b.Shutdown()
+}
+
+// TestConcurrent is meant for the race detector: publishers, subscribers
+// coming and going and a shutdown, all at once.
+func TestConcurrent(t *testing.T) {
+	t.Parallel()
+
+	const publishers, events = 4, 500
+	b := NewBroker(WithBufferSize[item](8))
+
+	var wg sync.WaitGroup
+	lossless := b.SubscribeWith(t.Context(), WithBlock[item](time.Minute))
+	received := 0
+	wg.Go(func() {
+		for range lossless {
+			received++
+		}
+	})
+	for _, opt := range []Option[item]{WithDropOldest[item](), WithCoalesce(itemKey)} {
+		for range 3 {
+			ctx, cancel := context.WithCancel(t.Context())
+			ch := b.SubscribeWith(ctx, opt)
+			wg.Go(func() {
+				defer cancel()
+				for e := range ch {
+					if e.Payload.Version%100 == 0 {
+						return
+					}
+				}
+			})
+		}
+	}
+
+	var published sync.WaitGroup
+	for p := range publishers {
+		published.Go(func() {
+			for v := 1; v <= events; v++ {
+				b.Publish(UpdatedEvent, item{strconv.Itoa(p), v})
+				if v%50 == 0 {
+					_ = b.Stats()
+				}
+			}
+		})
+	}
+	published.Wait()
+	require.Eventually(t, func() bool { return b.Stats()[0].Queued == 0 }, 5*time.Second, time.Millisecond)
+	b.Shutdown()
+	wg.Wait()
+	require.GreaterOrEqual(t, received, publishers*events-1)
+}
+
+func BenchmarkPublish(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		opt  Option[item]
+	}{
+		{"drop-oldest", WithDropOldest[item]()},
+		{"coalesce", WithCoalesce(itemKey)},
+		{"block", WithBlock[item](time.Minute)},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			broker := NewBroker(bench.opt)
+			defer broker.Shutdown()
+			for range 4 {
+				ch := broker.Subscribe(b.Context())
+				go func() {
+					for range ch {
+					}
+				}()
+			}
+			keys := make([]string, 16)
+			for i := range keys {
+				keys[i] = strconv.Itoa(i)
+			}
+
+			b.ReportAllocs()
+			i := 0
+			for b.Loop() {
+				broker.Publish(UpdatedEvent, item{keys[i%len(keys)], i})
+				i++
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package quiz
+
+import "github.com/trankhanh040147/prepf/internal/exercise"
+
+// Quizzes loads quizzes, named after their file, from the quizzes
+// directories.
+var Quizzes = exercise.Loader[Quiz, *Quiz]{Kind: "quiz", Dir: "quizzes"}
+
+// ExerciseName returns the name the quiz was loaded under.
+func (q *Quiz) ExerciseName() string { return q.Name }
+
+// SetOrigin records the name the quiz is loaded under and its file.
+func (q *Quiz) SetOrigin(name, path string) { q.Name, q.Path = name, path }
@@ -0,0 +1,318 @@
+// Package quiz loads multiple-choice, true/false and short-answer quizzes
+// and grades them locally, without a model call.
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Type is the kind of a quiz question.
+type Type string
+
+const (
+	// TypeSingle asks for exactly one of the choices.
+	TypeSingle Type = "single"
+	// TypeMulti asks for every correct choice.
+	TypeMulti Type = "multi"
+	// TypeTrueFalse is a single choice question between true and false.
+	TypeTrueFalse Type = "true_false"
+	// TypeShort asks for a typed answer, matched against the accepted
+	// answers or a regular expression.
+	TypeShort Type = "short"
+)
+
+// MaxQuestions caps the questions in a quiz so a generated one stays a quick
+// drill.
+const MaxQuestions = 50
+
+// Answers is a list of answers. In YAML it may also be written as a single
+// scalar, so `answer: true` and `answer: It panics` work as expected.
+type Answers []string
+
+func (a *Answers) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*a = Answers{value.Value}
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// Question is a quiz question with its correct answer.
+type Question struct {
+	Type Type `yaml:"type" json:"type"`
+	// Topic overrides the quiz topic for this question in progress stats.
+	Topic   string   `yaml:"topic,omitempty" json:"topic,omitempty"`
+	Prompt  string   `yaml:"prompt" json:"prompt"`
+	Choices []string `yaml:"choices,omitempty" json:"choices,omitempty"`
+	// Answer holds the correct choices of single and multi questions,
+	// "true" or "false" for true/false questions, and the accepted answers
+	// of short ones.
+	Answer Answers `yaml:"answer,omitempty" json:"answer,omitempty"`
+	// Pattern is a regular expression a short answer may match instead.
+	Pattern string `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	// Explanation is shown once the question is answered.
+	Explanation string `yaml:"explanation,omitempty" json:"explanation,omitempty"`
+}
+
+// Quiz is a named list of questions.
+type Quiz struct {
+	// Name is the file name without extension and is what /quiz takes.
+	Name      string     `yaml:"-" json:"name"`
+	Title     string     `yaml:"title,omitempty" json:"title,omitempty"`
+	Topic     string     `yaml:"topic,omitempty" json:"topic,omitempty"`
+	Questions []Question `yaml:"questions" json:"questions"`
+	Path      string     `yaml:"-" json:"path"`
+}
+
+// TopicOf returns the topic a question is tracked under.
+func (q *Quiz) TopicOf(question Question) string {
+	if question.Topic != "" {
+		return question.Topic
+	}
+	if q.Topic != "" {
+		return q.Topic
+	}
+	return q.Title
+}
+
+// Validate checks that every question can be graded.
+func (q *Quiz) Validate() error {
+	if len(q.Questions) == 0 {
+		return errors.New("at least one question is required")
+	}
+	if len(q.Questions) > MaxQuestions {
+		return fmt.Errorf("at most %d questions are allowed", MaxQuestions)
+	}
+	var errs []error
+	for i, question := range q.Questions {
+		if err := question.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("question %d: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Options returns the choices shown for the question. True/false questions
+// don't need to list theirs.
+func (q Question) Options() []string {
+	if q.Type == TypeTrueFalse && len(q.Choices) == 0 {
+		return []string{"True", "False"}
+	}
+	return q.Choices
+}
+
+// Validate checks that the question has an answer that can be graded.
+func (q Question) Validate() error {
+	if strings.TrimSpace(q.Prompt) == "" {
+		return errors.New("prompt is required")
+	}
+	switch q.Type {
+	case TypeSingle, TypeMulti:
+		if len(q.Choices) < 2 {
+			return errors.New("at least two choices are required")
+		}
+		if len(q.Answer) == 0 {
+			return errors.New("answer is required")
+		}
+		if q.Type == TypeSingle && len(q.Answer) > 1 {
+			return errors.New("single choice questions take exactly one answer")
+		}
+		for _, answer := range q.Answer {
+			if !slices.Contains(q.Choices, answer) {
+				return fmt.Errorf("answer %q is not one of the choices", answer)
+			}
+		}
+	case TypeTrueFalse:
+		if len(q.Answer) != 1 {
+			return errors.New("answer must be true or false")
+		}
+		if _, ok := parseBool(q.Answer[0]); !ok {
+			return fmt.Errorf("answer must be true or false, got %q", q.Answer[0])
+		}
+		if len(q.Choices) != 0 && len(q.Choices) != 2 {
+			return errors.New("true/false questions take two choices, for true and false")
+		}
+	case TypeShort:
+		if len(q.Answer) == 0 && q.Pattern == "" {
+			return errors.New("answer or pattern is required")
+		}
+		if q.Pattern != "" {
+			if _, err := regexp.Compile(q.Pattern); err != nil {
+				return fmt.Errorf("invalid pattern: %w", err)
+			}
+		}
+	case "":
+		return errors.New("type is required")
+	default:
+		return fmt.Errorf("unknown type %q", q.Type)
+	}
+	return nil
+}
+
+// Response is the user's answer to a question: the indexes of the selected
+// options for choice questions, or the typed text for short ones.
+type Response struct {
+	Selected []int
+	Text     string
+}
+
+// Result is the grade of a response.
+type Result struct {
+	Correct bool
+	// Score is between 0 and 100. Multi choice questions earn partial
+	// credit; the others score all or nothing.
+	Score int
+	// Expected lists the correct options, or the first accepted answer of a
+	// short question.
+	Expected []string
+}
+
+// Grade scores a response to the question.
+func Grade(q Question, r Response) Result {
+	switch q.Type {
+	case TypeShort:
+		return gradeShort(q, r.Text)
+	case TypeTrueFalse:
+		want, _ := parseBool(q.Answer[0])
+		options := q.Options()
+		expected := options[0]
+		if !want {
+			expected = options[1]
+		}
+		correct := len(r.Selected) == 1 && (r.Selected[0] == 0) == want
+		return allOrNothing(correct, expected)
+	case TypeMulti:
+		return gradeMulti(q, r.Selected)
+	default:
+		correct := len(r.Selected) == 1 &&
+			r.Selected[0] >= 0 && r.Selected[0] < len(q.Choices) &&
+			q.Choices[r.Selected[0]] == q.Answer[0]
+		return allOrNothing(correct, q.Answer[0])
+	}
+}
+
+// gradeMulti gives credit per correct choice selected and takes it back per
+// wrong one, so selecting every choice scores zero.
+func gradeMulti(q Question, selected []int) Result {
+	var hits, misses int
+	seen := make(map[int]bool, len(selected))
+	for _, i := range selected {
+		if seen[i] || i < 0 || i >= len(q.Choices) {
+			continue
+		}
+		seen[i] = true
+		if slices.Contains(q.Answer, q.Choices[i]) {
+			hits++
+		} else {
+			misses++
+		}
+	}
+	score := max(hits-misses, 0) * 100 / len(q.Answer)
+	return Result{
+		Correct:  hits == len(q.Answer) && misses == 0,
+		Score:    score,
+		Expected: q.Answer,
+	}
+}
+
+func gradeShort(q Question, text string) Result {
+	answer := normalize(text)
+	var expected string
+	if len(q.Answer) > 0 {
+		expected = q.Answer[0]
+	}
+	if answer == "" {
+		return allOrNothing(false, expected)
+	}
+	for _, accepted := range q.Answer {
+		if normalize(accepted) == answer {
+			return allOrNothing(true, expected)
+		}
+	}
+	if q.Pattern != "" {
+		if re, err := regexp.Compile(q.Pattern); err == nil && re.MatchString(strings.TrimSpace(text)) {
+			return allOrNothing(true, expected)
+		}
+	}
+	return allOrNothing(false, expected)
+}
+
+func allOrNothing(correct bool, expected string) Result {
+	r := Result{Correct: correct}
+	if correct {
+		r.Score = 100
+	}
+	if expected != "" {
+		r.Expected = []string{expected}
+	}
+	return r
+}
+
+// normalize makes short answers compare case and whitespace insensitively.
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+func parseBool(s string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "yes":
+		return true, true
+	case "false", "no":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// ExplainPrompt asks the model why a response was wrong. It stands on its
+// own so it can be sent to any session.
+func ExplainPrompt(q Question, r Response, result Result) string {
+	var sb strings.Builder
+	sb.WriteString("I got this quiz question wrong. Explain briefly why the correct answer is right and where my answer went wrong.\n\n")
+	fmt.Fprintf(&sb, "Question: %s\n", q.Prompt)
+	if options := q.Options(); q.Type != TypeShort {
+		sb.WriteString("Choices:\n")
+		for _, option := range options {
+			fmt.Fprintf(&sb, "- %s\n", option)
+		}
+	}
+	fmt.Fprintf(&sb, "My answer: %s\n", describe(q, r))
+	if len(result.Expected) > 0 {
+		fmt.Fprintf(&sb, "Correct answer: %s\n", strings.Join(result.Expected, "; "))
+	}
+	if q.Type == TypeShort && q.Pattern != "" {
+		fmt.Fprintf(&sb, "Answers matching %s are accepted.\n", q.Pattern)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func describe(q Question, r Response) string {
+	if q.Type == TypeShort {
+		if strings.TrimSpace(r.Text) == "" {
+			return "(blank)"
+		}
+		return strings.TrimSpace(r.Text)
+	}
+	options := q.Options()
+	var picked []string
+	for _, i := range r.Selected {
+		if i >= 0 && i < len(options) {
+			picked = append(picked, options[i])
+		}
+	}
+	if len(picked) == 0 {
+		return "(nothing selected)"
+	}
+	return strings.Join(picked, "; ")
+}
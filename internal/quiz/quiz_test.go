@@ -0,0 +1,136 @@
+package quiz
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrade(t *testing.T) {
+	t.Parallel()
+
+	single := Question{Type: TypeSingle, Prompt: "p", Choices: []string{"a", "b", "c"}, Answer: Answers{"b"}}
+	multi := Question{Type: TypeMulti, Prompt: "p", Choices: []string{"a", "b", "c", "d"}, Answer: Answers{"a", "c"}}
+	trueFalse := Question{Type: TypeTrueFalse, Prompt: "p", Answer: Answers{"false"}}
+	short := Question{Type: TypeShort, Prompt: "p", Answer: Answers{"sync.Mutex"}, Pattern: `^(?i)(sync\.)?rwmutex$`}
+
+	tests := []struct {
+		name     string
+		question Question
+		response Response
+		correct  bool
+		score    int
+	}{
+		{"single right", single, Response{Selected: []int{1}}, true, 100},
+		{"single wrong", single, Response{Selected: []int{0}}, false, 0},
+		{"single none", single, Response{}, false, 0},
+		{"multi exact", multi, Response{Selected: []int{2, 0}}, true, 100},
+		{"multi partial", multi, Response{Selected: []int{0}}, false, 50},
+		{"multi with a wrong choice", multi, Response{Selected: []int{0, 1}}, false, 0},
+		{"multi everything", multi, Response{Selected: []int{0, 1, 2, 3}}, false, 0},
+		{"multi duplicates", multi, Response{Selected: []int{0, 0}}, false, 50},
+		{"true/false right", trueFalse, Response{Selected: []int{1}}, true, 100},
+		{"true/false wrong", trueFalse, Response{Selected: []int{0}}, false, 0},
+		{"short exact", short, Response{Text: "  SYNC.mutex "}, true, 100},
+		{"short pattern", short, Response{Text: "RWMutex"}, true, 100},
+		{"short wrong", short, Response{Text: "a channel"}, false, 0},
+		{"short blank", short, Response{Text: " "}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := Grade(tt.question, tt.response)
+			require.Equal(t, tt.correct, result.Correct)
+			require.Equal(t, tt.score, result.Score)
+		})
+	}
+
+	require.Equal(t, []string{"False"}, Grade(trueFalse, Response{}).Expected)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		question Question
+		err      string
+	}{
+		{"valid", Question{Type: TypeSingle, Prompt: "p", Choices: []string{"a", "b"}, Answer: Answers{"a"}}, ""},
+		{"missing type", Question{Prompt: "p"}, "type is required"},
+		{"unknown type", Question{Type: "essay", Prompt: "p"}, "unknown type"},
+		{"missing prompt", Question{Type: TypeShort, Answer: Answers{"a"}}, "prompt is required"},
+		{"answer not a choice", Question{Type: TypeSingle, Prompt: "p", Choices: []string{"a", "b"}, Answer: Answers{"c"}}, "not one of the choices"},
+		{"two answers to single", Question{Type: TypeSingle, Prompt: "p", Choices: []string{"a", "b"}, Answer: Answers{"a", "b"}}, "exactly one answer"},
+		{"bad true/false", Question{Type: TypeTrueFalse, Prompt: "p", Answer: Answers{"maybe"}}, "true or false"},
+		{"short without answer", Question{Type: TypeShort, Prompt: "p"}, "answer or pattern"},
+		{"bad pattern", Question{Type: TypeShort, Prompt: "p", Pattern: "("}, "invalid pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.question.Validate()
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	q, err := Quizzes.Load(write("go-basics.yaml", `
+title: Go basics
+topic: Go
+questions:
+  - type: true_false
+    prompt: Reading from a nil map panics.
+    answer: false
+  - type: single
+    prompt: What does sending on a closed channel do?
+    choices: [Blocks, Panics, Returns an error]
+    answer: Panics
+`))
+	require.NoError(t, err)
+	require.NoError(t, q.Validate())
+	require.Equal(t, "go-basics", q.Name)
+	require.Equal(t, Answers{"false"}, q.Questions[0].Answer)
+	require.Equal(t, Answers{"Panics"}, q.Questions[1].Answer)
+
+	q, err = Quizzes.Load(write("sql.json", `{"title": "SQL", "questions": [{"type": "short", "prompt": "Keyword to remove duplicate rows?", "answer": ["DISTINCT"]}]}`))
+	require.NoError(t, err)
+	require.NoError(t, q.Validate())
+	require.Equal(t, "SQL", q.TopicOf(q.Questions[0]))
+}
+
+func TestSave(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	q := &Quiz{
+		Title: "Go Channels!",
+		Questions: []Question{
+			{Type: TypeMulti, Prompt: "Which block forever?", Choices: []string{"nil send", "nil receive", "closed receive"}, Answer: Answers{"nil send", "nil receive"}},
+		},
+	}
+	path, err := Quizzes.Save(dir, q.Title, q)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "go-channels.yaml"), path)
+
+	loaded, err := Quizzes.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, "go-channels", loaded.Name)
+	require.Equal(t, q.Questions, loaded.Questions)
+}
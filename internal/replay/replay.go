@@ -0,0 +1,276 @@
+// Package replay provides an [http.RoundTripper] that records provider
+// traffic to a cassette file and replays it byte-for-byte, so sessions can be
+// exercised end to end without calling a real LLM API.
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type Mode string
+
+const (
+	ModeRecord Mode = "record"
+	ModeReplay Mode = "replay"
+)
+
+// Valid reports whether m is a known mode.
+func (m Mode) Valid() bool {
+	return m == ModeRecord || m == ModeReplay
+}
+
+// ErrNoInteraction is returned in replay mode when a request has no matching
+// recorded interaction left.
+var ErrNoInteraction = errors.New("replay: no recorded interaction for request")
+
+// Cassette is the on-disk format of a recording.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Transport records or replays HTTP interactions. It is safe for concurrent
+// use and is meant to be shared by every provider client in a process so a
+// whole session ends up in a single cassette.
+type Transport struct {
+	mode Mode
+	path string
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New creates a transport for the given mode. In record mode requests are
+// sent through next (or [http.DefaultTransport] when nil) and the cassette at
+// path is overwritten. In replay mode the cassette is loaded from path and no
+// network requests are made.
+func New(mode Mode, path string, next http.RoundTripper) (*Transport, error) {
+	if !mode.Valid() {
+		return nil, fmt.Errorf("replay: unknown mode %q", mode)
+	}
+	if path == "" {
+		return nil, errors.New("replay: cassette path is required")
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	t := &Transport{
+		mode: mode,
+		path: path,
+		next: next,
+	}
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("replay: failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &t.cassette); err != nil {
+			return nil, fmt.Errorf("replay: failed to parse cassette %s: %w", path, err)
+		}
+		t.used = make([]bool, len(t.cassette.Interactions))
+	}
+	return t, nil
+}
+
+// Mode returns the mode the transport was created with.
+func (t *Transport) Mode() Mode {
+	return t.mode
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := Request{
+		Method: req.Method,
+		URL:    sanitizeURL(req.URL),
+		Body:   string(body),
+	}
+
+	if t.mode == ModeReplay {
+		return t.replay(req, recorded)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	resp.Body = &recordingBody{
+		ReadCloser: resp.Body,
+		onClose: func(data []byte) error {
+			return t.record(Interaction{
+				Request: recorded,
+				Response: Response{
+					StatusCode: resp.StatusCode,
+					Headers:    filterHeaders(resp.Header),
+					Body:       string(data),
+				},
+			})
+		},
+	}
+	return resp, nil
+}
+
+func (t *Transport) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Prefer an exact match, then fall back to the next unused interaction
+	// for the same endpoint, since request bodies may contain volatile data
+	// such as dates in the system prompt.
+	match := -1
+	for i, interaction := range t.cassette.Interactions {
+		if t.used[i] || interaction.Request.Method != recorded.Method || interaction.Request.URL != recorded.URL {
+			continue
+		}
+		if interaction.Request.Body == recorded.Body {
+			match = i
+			break
+		}
+		if match == -1 {
+			match = i
+		}
+	}
+	if match == -1 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, recorded.Method, recorded.URL)
+	}
+	t.used[match] = true
+
+	recordedResp := t.cassette.Interactions[match].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recordedResp.StatusCode, http.StatusText(recordedResp.StatusCode)),
+		StatusCode:    recordedResp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recordedResp.Headers.Clone(),
+		Body:          io.NopCloser(strings.NewReader(recordedResp.Body)),
+		ContentLength: int64(len(recordedResp.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *Transport) record(interaction Interaction) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cassette.Interactions = append(t.cassette.Interactions, interaction)
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(t.path, data, 0o644)
+}
+
+// recordingBody captures a response body as it is read and hands the full
+// content to onClose, so streamed responses still reach the caller
+// incrementally while being recorded.
+type recordingBody struct {
+	io.ReadCloser
+	buf     bytes.Buffer
+	onClose func([]byte) error
+	once    sync.Once
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	var recordErr error
+	b.once.Do(func() {
+		// Drain whatever the caller didn't read so the recording is complete.
+		_, _ = b.buf.ReadFrom(b.ReadCloser)
+		recordErr = b.onClose(b.buf.Bytes())
+	})
+	if err := b.ReadCloser.Close(); err != nil {
+		return err
+	}
+	return recordErr
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Body.Close(); err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// sanitizeURL drops query parameters that carry credentials.
+func sanitizeURL(u *url.URL) string {
+	clean := *u
+	query := clean.Query()
+	for key := range query {
+		if isSensitive(key) || strings.EqualFold(key, "key") {
+			query.Del(key)
+		}
+	}
+	clean.RawQuery = query.Encode()
+	return clean.String()
+}
+
+// filterHeaders removes response headers that are either sensitive or vary
+// between runs and would make cassettes noisy.
+func filterHeaders(headers http.Header) http.Header {
+	filtered := make(http.Header)
+	for key, values := range headers {
+		lower := strings.ToLower(key)
+		if isSensitive(lower) || lower == "set-cookie" || lower == "date" {
+			continue
+		}
+		filtered[key] = values
+	}
+	return filtered
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "authorization") ||
+		strings.Contains(key, "api-key") ||
+		strings.Contains(key, "token") ||
+		strings.Contains(key, "secret")
+}
@@ -0,0 +1,115 @@
+package replay
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	const stream = "data: {\"delta\":\"Hello\"}\n\ndata: {\"delta\":\" world\"}\n\ndata: [DONE]\n\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("X-Request-Token", "secret")
+		if strings.Contains(string(body), "summarize") {
+			io.WriteString(w, "data: {\"delta\":\"Summary\"}\n\n")
+			return
+		}
+		io.WriteString(w, stream)
+	}))
+
+	path := filepath.Join(t.TempDir(), "cassettes", "session.json")
+	recorder, err := New(ModeRecord, path, nil)
+	require.NoError(t, err)
+	client := &http.Client{Transport: recorder}
+
+	post := func(client *http.Client, body string) (int, string) {
+		req, err := http.NewRequest(http.MethodPost, srv.URL+"/v1/chat/completions?key=abc", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer sk-test")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	_, got := post(client, `{"prompt":"hi"}`)
+	require.Equal(t, stream, got)
+	_, got = post(client, `{"prompt":"summarize"}`)
+	require.Equal(t, "data: {\"delta\":\"Summary\"}\n\n", got)
+	srv.Close()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "sk-test")
+	require.NotContains(t, string(data), "key=abc")
+	var cassette Cassette
+	require.NoError(t, json.Unmarshal(data, &cassette))
+	require.Len(t, cassette.Interactions, 2)
+	require.Empty(t, cassette.Interactions[0].Response.Headers.Get("X-Request-Token"))
+
+	player, err := New(ModeReplay, path, nil)
+	require.NoError(t, err)
+	client = &http.Client{Transport: player}
+
+	// Replayed out of order: matched by body.
+	status, got := post(client, `{"prompt":"summarize"}`)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "data: {\"delta\":\"Summary\"}\n\n", got)
+	_, got = post(client, `{"prompt":"hi"}`)
+	require.Equal(t, stream, got)
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/v1/chat/completions", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	require.ErrorIs(t, err, ErrNoInteraction)
+}
+
+func TestReplayFallsBackToEndpointOrder(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := Cassette{Interactions: []Interaction{
+		{Request: Request{Method: "POST", URL: "http://api.test/v1/messages", Body: "first"}, Response: Response{StatusCode: 200, Body: "one"}},
+		{Request: Request{Method: "POST", URL: "http://api.test/v1/messages", Body: "second"}, Response: Response{StatusCode: 200, Body: "two"}},
+	}}
+	data, err := json.Marshal(cassette)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	player, err := New(ModeReplay, path, nil)
+	require.NoError(t, err)
+	client := &http.Client{Transport: player}
+
+	for _, want := range []string{"one", "two"} {
+		resp, err := client.Post("http://api.test/v1/messages", "application/json", strings.NewReader("changed"))
+		require.NoError(t, err)
+		got, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, want, string(got))
+	}
+}
+
+func TestNewValidates(t *testing.T) {
+	t.Parallel()
+
+	_, err := New("bogus", "cassette.json", nil)
+	require.Error(t, err)
+	_, err = New(ModeRecord, "", nil)
+	require.Error(t, err)
+	_, err = New(ModeReplay, filepath.Join(t.TempDir(), "missing.json"), nil)
+	require.Error(t, err)
+}
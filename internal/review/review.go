@@ -0,0 +1,245 @@
+// Package review loads code review exercises, buggy changes with seeded
+// issues, and grades line-anchored review comments against them.
+package review
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aymanbagabas/go-udiff"
+)
+
+// Kind is the category of a seeded issue.
+type Kind string
+
+const (
+	KindBug         Kind = "bug"
+	KindRace        Kind = "race"
+	KindSecurity    Kind = "security"
+	KindNaming      Kind = "naming"
+	KindPerformance Kind = "performance"
+	KindDesign      Kind = "design"
+)
+
+// Kinds lists the valid issue kinds.
+var Kinds = []Kind{KindBug, KindRace, KindSecurity, KindNaming, KindPerformance, KindDesign}
+
+// LineTolerance is how many lines away from an issue a comment may be and
+// still count as catching it. Reviewers often anchor on the line before or
+// after the one at fault.
+const LineTolerance = 2
+
+// Issue is a problem seeded in the change.
+type Issue struct {
+	// Line is where the issue is in the changed file. EndLine is the last
+	// line of a multi-line issue, or zero.
+	Line        int    `yaml:"line" json:"line"`
+	EndLine     int    `yaml:"end_line,omitempty" json:"end_line,omitempty"`
+	Kind        Kind   `yaml:"kind" json:"kind"`
+	Description string `yaml:"description" json:"description"`
+}
+
+func (i Issue) lastLine() int {
+	return max(i.Line, i.EndLine)
+}
+
+// Exercise is a change to review.
+type Exercise struct {
+	// Name is the file name without extension and is what /review takes.
+	Name        string  `yaml:"-" json:"name"`
+	Title       string  `yaml:"title,omitempty" json:"title,omitempty"`
+	Topic       string  `yaml:"topic,omitempty" json:"topic,omitempty"`
+	Description string  `yaml:"description,omitempty" json:"description,omitempty"`
+	File        string  `yaml:"file" json:"file"`
+	Before      string  `yaml:"before,omitempty" json:"before,omitempty"`
+	After       string  `yaml:"after" json:"after"`
+	Issues      []Issue `yaml:"issues" json:"issues"`
+	Path        string  `yaml:"-" json:"path"`
+}
+
+// TopicOf returns the topic the exercise is tracked under.
+func (e *Exercise) TopicOf() string {
+	if e.Topic != "" {
+		return e.Topic
+	}
+	return "Code review"
+}
+
+// Validate checks that the exercise has a change and that every issue points
+// at a line of it.
+func (e *Exercise) Validate() error {
+	var errs []error
+	if strings.TrimSpace(e.File) == "" {
+		errs = append(errs, errors.New("file is required"))
+	}
+	if strings.TrimSpace(e.After) == "" {
+		errs = append(errs, errors.New("after is required"))
+	} else if e.Before == e.After {
+		errs = append(errs, errors.New("before and after are the same"))
+	}
+	if len(e.Issues) == 0 {
+		errs = append(errs, errors.New("at least one issue is required"))
+	}
+	lines := strings.Count(strings.TrimSuffix(e.After, "\n"), "\n") + 1
+	for i, issue := range e.Issues {
+		switch {
+		case issue.Line < 1 || issue.Line > lines:
+			errs = append(errs, fmt.Errorf("issue %d: line %d is outside the file (1-%d)", i+1, issue.Line, lines))
+		case issue.EndLine != 0 && (issue.EndLine < issue.Line || issue.EndLine > lines):
+			errs = append(errs, fmt.Errorf("issue %d: end_line %d must be between line and %d", i+1, issue.EndLine, lines))
+		}
+		if !slices.Contains(Kinds, issue.Kind) {
+			errs = append(errs, fmt.Errorf("issue %d: unknown kind %q", i+1, issue.Kind))
+		}
+		if strings.TrimSpace(issue.Description) == "" {
+			errs = append(errs, fmt.Errorf("issue %d: description is required", i+1))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Diff returns the change as a unified diff with the whole file as context.
+func (e *Exercise) Diff() string {
+	context := strings.Count(e.Before, "\n") + strings.Count(e.After, "\n") + 1
+	edits := udiff.Strings(e.Before, e.After)
+	diff, err := udiff.ToUnified("a/"+e.File, "b/"+e.File, e.Before, edits, context)
+	if err != nil {
+		return ""
+	}
+	return diff
+}
+
+// Comment is a review comment anchored on a line of the changed file.
+type Comment struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+var commentPattern = regexp.MustCompile(`^\s*[Ll]?(\d+)\s*[:\s]\s*(\S.*)$`)
+
+// ParseComment parses a comment typed as "<line>: <text>" or "L<line> <text>".
+func ParseComment(s string) (Comment, error) {
+	m := commentPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Comment{}, errors.New(`start the comment with its line number, e.g. "12: this read races with the writer"`)
+	}
+	line, err := strconv.Atoi(m[1])
+	if err != nil {
+		return Comment{}, err
+	}
+	return Comment{Line: line, Text: strings.TrimSpace(m[2])}, nil
+}
+
+// Finding pairs a seeded issue with the comment that caught it.
+type Finding struct {
+	Issue   Issue   `json:"issue"`
+	Comment Comment `json:"comment"`
+}
+
+// Report is the outcome of a review.
+type Report struct {
+	Caught []Finding `json:"caught"`
+	Missed []Issue   `json:"missed"`
+	// Unmatched are the comments that are not near any seeded issue. They
+	// may still be fair points, so they don't cost anything.
+	Unmatched []Comment `json:"unmatched"`
+	// Score is the share of issues caught, between 0 and 100.
+	Score int `json:"score"`
+}
+
+// Grade matches the comments against the seeded issues. Comments within
+// LineTolerance lines of an issue catch it, closest pairs first; each comment
+// catches at most one issue.
+func Grade(e *Exercise, comments []Comment) Report {
+	type pair struct{ issue, comment, distance int }
+	var pairs []pair
+	for i, issue := range e.Issues {
+		for j, c := range comments {
+			if d := distance(issue, c.Line); d <= LineTolerance {
+				pairs = append(pairs, pair{i, j, d})
+			}
+		}
+	}
+	slices.SortStableFunc(pairs, func(a, b pair) int {
+		return a.distance - b.distance
+	})
+
+	caught := make([]int, len(e.Issues))
+	used := make([]bool, len(comments))
+	for i := range caught {
+		caught[i] = -1
+	}
+	for _, p := range pairs {
+		if caught[p.issue] >= 0 || used[p.comment] {
+			continue
+		}
+		caught[p.issue] = p.comment
+		used[p.comment] = true
+	}
+
+	var report Report
+	for i, issue := range e.Issues {
+		if caught[i] < 0 {
+			report.Missed = append(report.Missed, issue)
+			continue
+		}
+		report.Caught = append(report.Caught, Finding{Issue: issue, Comment: comments[caught[i]]})
+	}
+	for i, c := range comments {
+		if !used[i] {
+			report.Unmatched = append(report.Unmatched, c)
+		}
+	}
+	if len(e.Issues) > 0 {
+		report.Score = len(report.Caught) * 100 / len(e.Issues)
+	}
+	return report
+}
+
+func distance(issue Issue, line int) int {
+	switch {
+	case line < issue.Line:
+		return issue.Line - line
+	case line > issue.lastLine():
+		return line - issue.lastLine()
+	default:
+		return 0
+	}
+}
+
+// DiscussPrompt asks the model to go over a graded review. Matching by line
+// can't tell whether a comment named the actual problem, so the model is
+// asked to check that too. It stands on its own so it can be sent to any
+// session.
+func DiscussPrompt(e *Exercise, comments []Comment, report Report) string {
+	var sb strings.Builder
+	sb.WriteString("I just reviewed this change as a code review interview exercise. Go over my review: for each caught issue, say whether my comment really identified the problem; explain the issues I missed and how I could have spotted them; and say whether my other comments are fair.\n\n")
+	if e.Description != "" {
+		fmt.Fprintf(&sb, "Change description: %s\n\n", e.Description)
+	}
+	fmt.Fprintf(&sb, "```diff\n%s```\n\n", e.Diff())
+	sb.WriteString("Seeded issues (line numbers are in the changed file):\n")
+	for _, issue := range e.Issues {
+		fmt.Fprintf(&sb, "- line %s [%s] %s\n", lineRange(issue), issue.Kind, issue.Description)
+	}
+	sb.WriteString("\nMy comments:\n")
+	if len(comments) == 0 {
+		sb.WriteString("- (none)\n")
+	}
+	for _, c := range comments {
+		fmt.Fprintf(&sb, "- line %d: %s\n", c.Line, c.Text)
+	}
+	fmt.Fprintf(&sb, "\nMatching by line, I caught %d of %d issues.", len(report.Caught), len(e.Issues))
+	return sb.String()
+}
+
+func lineRange(issue Issue) string {
+	if issue.EndLine > issue.Line {
+		return fmt.Sprintf("%d-%d", issue.Line, issue.EndLine)
+	}
+	return strconv.Itoa(issue.Line)
+}
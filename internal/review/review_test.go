@@ -0,0 +1,143 @@
+package review
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const before = `package cache
+
+type Cache struct {
+	items map[string]string
+}
+
+func (c *Cache) Get(key string) string {
+	return c.items[key]
+}
+`
+
+const after = `package cache
+
+import "sync"
+
+type Cache struct {
+	mu    sync.Mutex
+	items map[string]string
+}
+
+func (c *Cache) Get(key string) string {
+	return c.items[key]
+}
+
+func (c *Cache) Set(k, v string) {
+	c.mu.Lock()
+	c.items[k] = v
+	c.mu.Unlock()
+}
+`
+
+func testExercise() *Exercise {
+	return &Exercise{
+		Title:  "Cache setter",
+		File:   "cache.go",
+		Before: before,
+		After:  after,
+		Issues: []Issue{
+			{Line: 11, Kind: KindRace, Description: "Get reads the map without holding the lock"},
+			{Line: 14, Kind: KindNaming, Description: "k and v should be key and value"},
+			{Line: 16, Kind: KindBug, Description: "Writing to the nil map panics"},
+		},
+	}
+}
+
+func TestGrade(t *testing.T) {
+	t.Parallel()
+
+	e := testExercise()
+	report := Grade(e, []Comment{
+		{Line: 10, Text: "Get needs the lock too"},
+		{Line: 16, Text: "items is never initialized"},
+		{Line: 3, Text: "nit: group imports"},
+	})
+	require.Equal(t, []Finding{
+		{Issue: e.Issues[0], Comment: Comment{Line: 10, Text: "Get needs the lock too"}},
+		{Issue: e.Issues[2], Comment: Comment{Line: 16, Text: "items is never initialized"}},
+	}, report.Caught)
+	require.Equal(t, []Issue{e.Issues[1]}, report.Missed)
+	require.Equal(t, []Comment{{Line: 3, Text: "nit: group imports"}}, report.Unmatched)
+	require.Equal(t, 66, report.Score)
+
+	// A comment catches at most one issue, the closest one.
+	report = Grade(e, []Comment{{Line: 15, Text: "naming and panics"}})
+	require.Len(t, report.Caught, 1)
+	require.Equal(t, e.Issues[1], report.Caught[0].Issue)
+
+	require.Zero(t, Grade(e, nil).Score)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, testExercise().Validate())
+
+	e := testExercise()
+	e.Issues = append(e.Issues,
+		Issue{Line: 40, Kind: KindBug, Description: "past the end"},
+		Issue{Line: 2, Kind: "typo", Description: "unknown kind"},
+		Issue{Line: 5, EndLine: 4, Kind: KindBug, Description: "backwards range"},
+	)
+	err := e.Validate()
+	require.ErrorContains(t, err, "issue 4: line 40 is outside the file")
+	require.ErrorContains(t, err, `issue 5: unknown kind "typo"`)
+	require.ErrorContains(t, err, "issue 6: end_line 4")
+
+	require.ErrorContains(t, (&Exercise{File: "a.go", After: "x"}).Validate(), "at least one issue")
+}
+
+func TestParseComment(t *testing.T) {
+	t.Parallel()
+
+	c, err := ParseComment("12: this read races with the writer")
+	require.NoError(t, err)
+	require.Equal(t, Comment{Line: 12, Text: "this read races with the writer"}, c)
+
+	c, err = ParseComment("L7 rename k to key ")
+	require.NoError(t, err)
+	require.Equal(t, Comment{Line: 7, Text: "rename k to key"}, c)
+
+	_, err = ParseComment("this has no line")
+	require.Error(t, err)
+	_, err = ParseComment("12:")
+	require.Error(t, err)
+}
+
+func TestDiscussPrompt(t *testing.T) {
+	t.Parallel()
+
+	e := testExercise()
+	comments := []Comment{{Line: 16, Text: "items is never initialized"}}
+	prompt := DiscussPrompt(e, comments, Grade(e, comments))
+	require.Contains(t, prompt, "+func (c *Cache) Set(k, v string) {")
+	require.Contains(t, prompt, "- line 11 [race] Get reads the map without holding the lock")
+	require.Contains(t, prompt, "- line 16: items is never initialized")
+	require.True(t, strings.HasSuffix(prompt, "I caught 1 of 3 issues."))
+}
+
+func TestSave(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path, err := Exercises.Save(dir, testExercise().Title, testExercise())
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "cache-setter.yaml"), path)
+
+	e, err := Exercises.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, "cache-setter", e.Name)
+	require.Equal(t, testExercise().Issues, e.Issues)
+	require.Equal(t, after, e.After)
+	require.Equal(t, "Code review", e.TopicOf())
+}
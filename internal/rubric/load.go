@@ -0,0 +1,187 @@
+package rubric
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/trankhanh040147/prepf/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed defaults/*.yaml
+var defaults embed.FS
+
+// Extensions lists the rubric file extensions. JSON is a subset of YAML, so
+// both are parsed the same way.
+var Extensions = []string{".yaml", ".yml", ".json"}
+
+// Dirs returns the directories rubrics are loaded from, highest priority
+// first. New rubrics are saved to the first one.
+func Dirs(cfg *config.Config) []string {
+	return []string{
+		filepath.Join(cfg.Options.DataDirectory, "rubrics"),
+		filepath.Join(filepath.Dir(config.GlobalConfig()), "rubrics"),
+	}
+}
+
+// Parse parses a rubric. A missing version counts as the first.
+func Parse(name string, content []byte) (*Rubric, error) {
+	var r Rubric
+	if err := yaml.Unmarshal(content, &r); err != nil {
+		return nil, fmt.Errorf("parsing rubric: %w", err)
+	}
+	r.Name = name
+	r.Version = max(r.Version, 1)
+	return &r, nil
+}
+
+// Load parses a rubric file. The rubric is named after the file.
+func Load(path string) (*Rubric, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := Parse(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), content)
+	if err != nil {
+		return nil, err
+	}
+	r.Path = path
+	return r, nil
+}
+
+// Builtin returns the rubrics shipped with prepf, named after the modes
+// they grade.
+func Builtin() []*Rubric {
+	entries, _ := defaults.ReadDir("defaults")
+	var rubrics []*Rubric
+	for _, entry := range entries {
+		content, err := defaults.ReadFile(path.Join("defaults", entry.Name()))
+		if err != nil {
+			continue
+		}
+		r, err := Parse(strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())), content)
+		if err != nil {
+			slog.Error("Failed to parse built-in rubric", "name", entry.Name(), "error", err)
+			continue
+		}
+		rubrics = append(rubrics, r)
+	}
+	return rubrics
+}
+
+// Problem describes a rubric file that could not be loaded.
+type Problem struct {
+	Path string
+	Err  error
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("%s: %v", p.Path, p.Err)
+}
+
+// Discover finds the valid rubrics in the given directories and the
+// built-in ones. A rubric in an earlier directory hides one with the same
+// name in a later one, and any file hides the built-in rubric it is named
+// after.
+func Discover(dirs []string) ([]*Rubric, []Problem) {
+	var rubrics []*Rubric
+	var problems []Problem
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				problems = append(problems, Problem{Path: dir, Err: err})
+			}
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !slices.Contains(Extensions, filepath.Ext(entry.Name())) {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			r, err := Load(path)
+			if err == nil {
+				err = r.Validate()
+			}
+			if err != nil {
+				slog.Warn("Failed to load rubric", "path", path, "error", err)
+				problems = append(problems, Problem{Path: path, Err: err})
+				continue
+			}
+			if seen[r.Name] {
+				continue
+			}
+			seen[r.Name] = true
+			rubrics = append(rubrics, r)
+		}
+	}
+	for _, r := range Builtin() {
+		if !seen[r.Name] {
+			rubrics = append(rubrics, r)
+		}
+	}
+	slices.SortFunc(rubrics, func(a, b *Rubric) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return rubrics, problems
+}
+
+// Find returns the rubric with the given name, if any.
+func Find(rubrics []*Rubric, name string) (*Rubric, bool) {
+	for _, r := range rubrics {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// Pick returns the first of the named rubrics that exists, skipping empty
+// names. Callers list the names from the most to the least specific.
+func Pick(rubrics []*Rubric, names ...string) (*Rubric, bool) {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if r, ok := Find(rubrics, name); ok {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// Save validates the rubric and writes it as YAML to the file it was loaded
+// from or, for a new or built-in rubric, to dir.
+func Save(dir string, r *Rubric) (string, error) {
+	if !ValidName(r.Name) {
+		return "", fmt.Errorf("invalid rubric name %q: use lowercase letters, digits and hyphens", r.Name)
+	}
+	if err := r.Validate(); err != nil {
+		return "", err
+	}
+	content, err := yaml.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	path := r.Path
+	if path == "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", err
+		}
+		path = filepath.Join(dir, r.Name+".yaml")
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return "", err
+	}
+	r.Path = path
+	return path, nil
+}
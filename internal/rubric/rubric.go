@@ -0,0 +1,319 @@
+// Package rubric loads the rubrics answers are graded against: named
+// dimensions with a weight and a descriptor for each level, and checks and
+// scores the per-dimension grades given against them.
+package rubric
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Level is a grade an answer can get on a dimension.
+type Level struct {
+	Points     int    `yaml:"points" json:"points"`
+	Label      string `yaml:"label" json:"label"`
+	Descriptor string `yaml:"descriptor" json:"descriptor"`
+	// Examples are answers, or parts of answers, that earn the level.
+	Examples []string `yaml:"examples,omitempty" json:"examples,omitempty"`
+}
+
+// Dimension is an aspect of the answer graded on its own.
+type Dimension struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Weight is how much the dimension counts towards the score relative
+	// to the others. Zero counts as one.
+	Weight float64 `yaml:"weight,omitempty" json:"weight,omitempty"`
+	Levels []Level `yaml:"levels" json:"levels"`
+}
+
+// Rubric is a named set of dimensions.
+type Rubric struct {
+	// Name is the file name without extension and is what modes, skills
+	// and questions refer to the rubric by.
+	Name        string `yaml:"-" json:"name"`
+	Title       string `yaml:"title,omitempty" json:"title,omitempty"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Version is stored with every score given against the rubric, so
+	// scores are only compared with ones graded the same way. It goes up
+	// whenever the dimensions change.
+	Version    int         `yaml:"version" json:"version"`
+	Dimensions []Dimension `yaml:"dimensions" json:"dimensions"`
+	// Path is the file the rubric was loaded from, or empty for a built-in
+	// one.
+	Path string `yaml:"-" json:"path,omitempty"`
+}
+
+// DimensionScore is the grade an answer got on a dimension.
+type DimensionScore struct {
+	Dimension string `json:"dimension" description:"Name of the rubric dimension"`
+	Points    int    `json:"points" description:"Points of the level the answer reached on the dimension"`
+	Evidence  string `json:"evidence,omitempty" description:"What in the answer earned the level, quoted or paraphrased"`
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ValidName reports whether name can name a rubric: lowercase letters,
+// digits and single hyphens.
+func ValidName(name string) bool {
+	return validName.MatchString(name)
+}
+
+// Ref identifies the rubric and its version, as in "mock@2".
+func (r *Rubric) Ref() string {
+	return fmt.Sprintf("%s@%d", r.Name, r.Version)
+}
+
+// weight returns the dimension's weight, counting zero as one.
+func (d Dimension) weight() float64 {
+	if d.Weight == 0 {
+		return 1
+	}
+	return d.Weight
+}
+
+// MaxPoints returns the points of the dimension's highest level.
+func (d Dimension) MaxPoints() int {
+	top := 0
+	for _, l := range d.Levels {
+		top = max(top, l.Points)
+	}
+	return top
+}
+
+// Level returns the level worth the given points.
+func (d Dimension) Level(points int) (Level, bool) {
+	i := slices.IndexFunc(d.Levels, func(l Level) bool { return l.Points == points })
+	if i < 0 {
+		return Level{}, false
+	}
+	return d.Levels[i], true
+}
+
+// Dimension returns the dimension with the given name.
+func (r *Rubric) Dimension(name string) (Dimension, bool) {
+	i := slices.IndexFunc(r.Dimensions, func(d Dimension) bool { return d.Name == name })
+	if i < 0 {
+		return Dimension{}, false
+	}
+	return r.Dimensions[i], true
+}
+
+// Validate checks that the rubric has uniquely named dimensions with at
+// least two levels each and positive weights.
+func (r *Rubric) Validate() error {
+	var errs []error
+	if r.Version < 1 {
+		errs = append(errs, errors.New("version must be 1 or more"))
+	}
+	if len(r.Dimensions) == 0 {
+		errs = append(errs, errors.New("at least one dimension is required"))
+	}
+	seen := make(map[string]bool)
+	for i, d := range r.Dimensions {
+		name := d.Name
+		if name == "" {
+			name = fmt.Sprintf("dimension %d", i+1)
+			errs = append(errs, fmt.Errorf("%s: name is required", name))
+		} else if seen[name] {
+			errs = append(errs, fmt.Errorf("%s: listed twice", name))
+		}
+		seen[d.Name] = true
+		if d.Weight < 0 {
+			errs = append(errs, fmt.Errorf("%s: weight must not be negative", name))
+		}
+		if len(d.Levels) < 2 {
+			errs = append(errs, fmt.Errorf("%s: at least two levels are required", name))
+		}
+		points := make(map[int]bool)
+		for _, l := range d.Levels {
+			switch {
+			case l.Points < 0:
+				errs = append(errs, fmt.Errorf("%s: level points must not be negative", name))
+			case points[l.Points]:
+				errs = append(errs, fmt.Errorf("%s: two levels are worth %d points", name, l.Points))
+			}
+			points[l.Points] = true
+			if strings.TrimSpace(l.Descriptor) == "" {
+				errs = append(errs, fmt.Errorf("%s: level worth %d points needs a descriptor", name, l.Points))
+			}
+		}
+		if len(d.Levels) >= 2 && d.MaxPoints() == 0 {
+			errs = append(errs, fmt.Errorf("%s: no level is worth any points", name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Check validates grades given against the rubric: every dimension must be
+// graded exactly once with the points of one of its levels.
+func (r *Rubric) Check(scores []DimensionScore) error {
+	var errs []error
+	graded := make(map[string]bool)
+	for _, s := range scores {
+		d, ok := r.Dimension(s.Dimension)
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("%q is not a dimension of the rubric", s.Dimension))
+			continue
+		case graded[s.Dimension]:
+			errs = append(errs, fmt.Errorf("%s is graded more than once", s.Dimension))
+		}
+		graded[s.Dimension] = true
+		if _, ok := d.Level(s.Points); !ok {
+			errs = append(errs, fmt.Errorf("%s: %d points is not a level; use one of %s", s.Dimension, s.Points, levelPoints(d)))
+		}
+	}
+	for _, d := range r.Dimensions {
+		if !graded[d.Name] {
+			errs = append(errs, fmt.Errorf("%s is not graded", d.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Score turns checked grades into a 0-100 score: the weighted average of
+// the share of each dimension's points earned.
+func (r *Rubric) Score(scores []DimensionScore) int {
+	var total, weights float64
+	for _, d := range r.Dimensions {
+		weights += d.weight()
+		i := slices.IndexFunc(scores, func(s DimensionScore) bool { return s.Dimension == d.Name })
+		if i < 0 || d.MaxPoints() == 0 {
+			continue
+		}
+		total += d.weight() * float64(scores[i].Points) / float64(d.MaxPoints())
+	}
+	if weights == 0 {
+		return 0
+	}
+	return int(math.Round(100 * total / weights))
+}
+
+// Bump raises the version over the previous revision of the rubric if the
+// dimensions changed, so scores given against either stay apart.
+func (r *Rubric) Bump(prev *Rubric) {
+	if prev == nil {
+		r.Version = max(r.Version, 1)
+		return
+	}
+	r.Version = max(r.Version, prev.Version)
+	if !reflect.DeepEqual(r.Dimensions, prev.Dimensions) {
+		r.Version = max(r.Version, prev.Version+1)
+	}
+}
+
+// Schema returns the JSON schema of the grades given against the rubric.
+func (r *Rubric) Schema() map[string]any {
+	items := make([]any, len(r.Dimensions))
+	for i, d := range r.Dimensions {
+		points := make([]any, len(d.Levels))
+		for j, l := range d.Levels {
+			points[j] = l.Points
+		}
+		items[i] = map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dimension": map[string]any{"const": d.Name},
+				"points":    map[string]any{"type": "integer", "enum": points},
+				"evidence":  map[string]any{"type": "string"},
+			},
+			"required":             []string{"dimension", "points"},
+			"additionalProperties": false,
+		}
+	}
+	return map[string]any{
+		"type":     "array",
+		"items":    map[string]any{"oneOf": items},
+		"minItems": len(r.Dimensions),
+		"maxItems": len(r.Dimensions),
+	}
+}
+
+// Prompt renders the rubric for the model.
+func (r *Rubric) Prompt() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<rubric name=\"%s\" version=\"%d\">\n", escape(r.Name), r.Version)
+	if r.Description != "" {
+		sb.WriteString(strings.TrimSpace(r.Description) + "\n")
+	}
+	for _, d := range r.Dimensions {
+		fmt.Fprintf(&sb, "<dimension name=\"%s\" weight=\"%g\">\n", escape(d.Name), d.weight())
+		if d.Description != "" {
+			sb.WriteString(strings.TrimSpace(d.Description) + "\n")
+		}
+		for _, l := range d.Levels {
+			fmt.Fprintf(&sb, "- %d points, %s: %s\n", l.Points, l.Label, strings.TrimSpace(l.Descriptor))
+			for _, e := range l.Examples {
+				fmt.Fprintf(&sb, "  Example: %s\n", strings.TrimSpace(e))
+			}
+		}
+		sb.WriteString("</dimension>\n")
+	}
+	sb.WriteString("</rubric>")
+	return sb.String()
+}
+
+// FormatLevels writes levels as text, one per line as "points | label |
+// descriptor", each followed by its examples on indented lines.
+func FormatLevels(levels []Level) string {
+	var lines []string
+	for _, l := range levels {
+		lines = append(lines, fmt.Sprintf("%d | %s | %s", l.Points, l.Label, l.Descriptor))
+		for _, e := range l.Examples {
+			lines = append(lines, "  - "+e)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseLevels reads levels written by FormatLevels.
+func ParseLevels(text string) ([]Level, error) {
+	var levels []Level
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(levels) == 0 {
+				return nil, fmt.Errorf("line %d: example before any level", i+1)
+			}
+			example := strings.TrimPrefix(strings.TrimSpace(line), "- ")
+			levels[len(levels)-1].Examples = append(levels[len(levels)-1].Examples, example)
+			continue
+		}
+		fields := strings.SplitN(line, "|", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected \"points | label | descriptor\"", i+1)
+		}
+		points, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: points must be a number", i+1)
+		}
+		levels = append(levels, Level{
+			Points:     points,
+			Label:      strings.TrimSpace(fields[1]),
+			Descriptor: strings.TrimSpace(fields[2]),
+		})
+	}
+	return levels, nil
+}
+
+func levelPoints(d Dimension) string {
+	points := make([]string, len(d.Levels))
+	for i, l := range d.Levels {
+		points[i] = fmt.Sprint(l.Points)
+	}
+	return strings.Join(points, ", ")
+}
+
+func escape(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;", "'", "&apos;")
+	return r.Replace(s)
+}
@@ -0,0 +1,192 @@
+package rubric
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testRubric() *Rubric {
+	levels := func(top int) []Level {
+		var ls []Level
+		for p := 0; p <= top; p++ {
+			ls = append(ls, Level{Points: p, Label: "l", Descriptor: "d"})
+		}
+		return ls
+	}
+	return &Rubric{
+		Name:    "test",
+		Version: 1,
+		Dimensions: []Dimension{
+			{Name: "correctness", Weight: 3, Levels: levels(3)},
+			{Name: "clarity", Levels: levels(1)},
+		},
+	}
+}
+
+func TestBuiltin(t *testing.T) {
+	t.Parallel()
+
+	rubrics := Builtin()
+	for _, name := range []string{"gym", "mock"} {
+		r, ok := Find(rubrics, name)
+		require.True(t, ok, name)
+		require.NoError(t, r.Validate(), name)
+	}
+}
+
+func TestCheckAndScore(t *testing.T) {
+	t.Parallel()
+
+	r := testRubric()
+	tests := []struct {
+		name   string
+		scores []DimensionScore
+		err    string
+		score  int
+	}{
+		{
+			name:   "all top",
+			scores: []DimensionScore{{Dimension: "correctness", Points: 3}, {Dimension: "clarity", Points: 1}},
+			score:  100,
+		},
+		{
+			name:   "weighted",
+			scores: []DimensionScore{{Dimension: "clarity", Points: 1}, {Dimension: "correctness", Points: 1}},
+			score:  50,
+		},
+		{
+			name:   "missing dimension",
+			scores: []DimensionScore{{Dimension: "correctness", Points: 3}},
+			err:    "clarity is not graded",
+		},
+		{
+			name:   "unknown dimension",
+			scores: []DimensionScore{{Dimension: "correctness", Points: 3}, {Dimension: "clarity", Points: 1}, {Dimension: "style", Points: 1}},
+			err:    `"style" is not a dimension`,
+		},
+		{
+			name:   "graded twice",
+			scores: []DimensionScore{{Dimension: "correctness", Points: 3}, {Dimension: "correctness", Points: 2}, {Dimension: "clarity", Points: 1}},
+			err:    "correctness is graded more than once",
+		},
+		{
+			name:   "not a level",
+			scores: []DimensionScore{{Dimension: "correctness", Points: 4}, {Dimension: "clarity", Points: 1}},
+			err:    "correctness: 4 points is not a level; use one of 0, 1, 2, 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := r.Check(tt.scores)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.score, r.Score(tt.scores))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, testRubric().Validate())
+
+	r := testRubric()
+	r.Dimensions = append(r.Dimensions, Dimension{Name: "clarity", Weight: -1, Levels: []Level{{Points: 0, Descriptor: "d"}, {Points: 0}}})
+	err := r.Validate()
+	require.ErrorContains(t, err, "clarity: listed twice")
+	require.ErrorContains(t, err, "clarity: weight must not be negative")
+	require.ErrorContains(t, err, "clarity: two levels are worth 0 points")
+	require.ErrorContains(t, err, "clarity: level worth 0 points needs a descriptor")
+	require.ErrorContains(t, err, "clarity: no level is worth any points")
+
+	require.ErrorContains(t, (&Rubric{Version: 1}).Validate(), "at least one dimension")
+}
+
+func TestLevelsText(t *testing.T) {
+	t.Parallel()
+
+	levels := []Level{
+		{Points: 0, Label: "Fluff", Descriptor: "No example | no number", Examples: []string{"We would just scale it.", "Add caching."}},
+		{Points: 2, Label: "Concrete", Descriptor: "Backs its claims"},
+	}
+	text := FormatLevels(levels)
+	parsed, err := ParseLevels(text)
+	require.NoError(t, err)
+	require.Equal(t, levels, parsed)
+
+	parsed, err = ParseLevels("\n 1|Vague|Some specifics\n")
+	require.ErrorContains(t, err, "line 2: example before any level")
+	require.Nil(t, parsed)
+
+	_, err = ParseLevels("one | Vague | Some specifics")
+	require.ErrorContains(t, err, "points must be a number")
+
+	_, err = ParseLevels("1 | Vague")
+	require.ErrorContains(t, err, `expected "points | label | descriptor"`)
+}
+
+func TestBump(t *testing.T) {
+	t.Parallel()
+
+	prev := testRubric()
+	prev.Version = 2
+
+	same := testRubric()
+	same.Description = "only the description changed"
+	same.Bump(prev)
+	require.Equal(t, 2, same.Version)
+
+	changed := testRubric()
+	changed.Dimensions[0].Weight = 1
+	changed.Bump(prev)
+	require.Equal(t, 3, changed.Version)
+}
+
+func TestDiscoverAndSave(t *testing.T) {
+	t.Parallel()
+
+	project, global := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(global, "mock.yaml"), []byte(`
+version: 4
+dimensions:
+  - name: depth
+    levels:
+      - {points: 0, label: Shallow, descriptor: Surface only}
+      - {points: 1, label: Deep, descriptor: First principles}
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "broken.yaml"), []byte("dimensions: []\n"), 0o644))
+
+	rubrics, problems := Discover([]string{project, global})
+	require.Len(t, problems, 1)
+	require.Equal(t, filepath.Join(project, "broken.yaml"), problems[0].Path)
+
+	mock, ok := Find(rubrics, "mock")
+	require.True(t, ok)
+	require.Equal(t, 4, mock.Version)
+	require.Equal(t, filepath.Join(global, "mock.yaml"), mock.Path)
+
+	gym, ok := Pick(rubrics, "", "missing", "gym")
+	require.True(t, ok)
+	require.Empty(t, gym.Path, "built-in rubrics are still found")
+
+	gym.Dimensions = gym.Dimensions[:1]
+	gym.Bump(Builtin()[0])
+	path, err := Save(project, gym)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(project, "gym.yaml"), path)
+
+	saved, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, gym.Dimensions, saved.Dimensions)
+	require.Equal(t, 2, saved.Version)
+
+	_, err = Save(project, &Rubric{Name: "Not Valid"})
+	require.ErrorContains(t, err, "invalid rubric name")
+}
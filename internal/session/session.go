@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/trankhanh040147/prepf/internal/db"
@@ -28,6 +30,13 @@ type Todo struct {
 	ActiveForm string     `json:"active_form"`
 }
 
+// SkillActivation records a skill explicitly activated for a session. The
+// skill's instructions are added to the prompt of every later request.
+type SkillActivation struct {
+	Name        string `json:"name"`
+	ActivatedAt int64  `json:"activated_at"`
+}
+
 type Session struct {
 	ID               string
 	ParentSessionID  string
@@ -39,8 +48,13 @@ type Session struct {
 	Cost             float64
 	Todos            []Todo
 	Mode             string
-	CreatedAt        int64
-	UpdatedAt        int64
+	Skills           []SkillActivation
+	// ReviewedBy is who marked the transcript as reviewed and ReviewedAt
+	// when, or empty and zero if nobody did.
+	ReviewedBy string
+	ReviewedAt int64
+	CreatedAt  int64
+	UpdatedAt  int64
 }
 
 type Service interface {
@@ -52,6 +66,8 @@ type Service interface {
 	Get(ctx context.Context, id string) (Session, error)
 	List(ctx context.Context) ([]Session, error)
 	Save(ctx context.Context, session Session) (Session, error)
+	ActivateSkill(ctx context.Context, sessionID, name string) (Session, error)
+	SetReviewed(ctx context.Context, sessionID, reviewer string) (Session, error)
 	UpdateTitleAndUsage(ctx context.Context, sessionID, title string, promptTokens, completionTokens int64, cost float64) error
 	Delete(ctx context.Context, id string) error
 
@@ -145,6 +161,10 @@ func (s *service) Save(ctx context.Context, session Session) (Session, error) {
 	if err != nil {
 		return Session{}, err
 	}
+	skillsJSON, err := marshalSkills(session.Skills)
+	if err != nil {
+		return Session{}, err
+	}
 
 	dbSession, err := s.q.UpdateSession(ctx, db.UpdateSessionParams{
 		ID:               session.ID,
@@ -164,6 +184,10 @@ func (s *service) Save(ctx context.Context, session Session) (Session, error) {
 			String: session.Mode,
 			Valid:  session.Mode != "",
 		},
+		Skills: sql.NullString{
+			String: skillsJSON,
+			Valid:  skillsJSON != "",
+		},
 	})
 	if err != nil {
 		return Session{}, err
@@ -173,6 +197,64 @@ func (s *service) Save(ctx context.Context, session Session) (Session, error) {
 	return session, nil
 }
 
+// ActivateSkill records that the named skill is active in the session.
+// Activating a skill that is already active is a no-op.
+func (s *service) ActivateSkill(ctx context.Context, sessionID, name string) (Session, error) {
+	session, err := s.Get(ctx, sessionID)
+	if err != nil {
+		return Session{}, err
+	}
+	if session.HasSkill(name) {
+		return session, nil
+	}
+	session.Skills = append(session.Skills, SkillActivation{
+		Name:        name,
+		ActivatedAt: time.Now().Unix(),
+	})
+	return s.Save(ctx, session)
+}
+
+// SetReviewed marks the session's transcript as reviewed by reviewer, or
+// as not reviewed when reviewer is empty.
+func (s *service) SetReviewed(ctx context.Context, sessionID, reviewer string) (Session, error) {
+	var reviewedAt sql.NullInt64
+	if reviewer != "" {
+		reviewedAt = sql.NullInt64{Int64: time.Now().Unix(), Valid: true}
+	}
+	dbSession, err := s.q.SetSessionReviewed(ctx, db.SetSessionReviewedParams{
+		ID:         sessionID,
+		ReviewedBy: sql.NullString{String: reviewer, Valid: reviewer != ""},
+		ReviewedAt: reviewedAt,
+	})
+	if err != nil {
+		return Session{}, err
+	}
+	session := s.fromDBItem(dbSession)
+	s.Publish(pubsub.UpdatedEvent, session)
+	return session, nil
+}
+
+// Reviewed reports whether the session's transcript was marked as reviewed.
+func (s Session) Reviewed() bool {
+	return s.ReviewedBy != ""
+}
+
+// HasSkill reports whether the named skill is active in the session.
+func (s Session) HasSkill(name string) bool {
+	return slices.ContainsFunc(s.Skills, func(a SkillActivation) bool {
+		return a.Name == name
+	})
+}
+
+// SkillNames returns the names of the active skills in activation order.
+func (s Session) SkillNames() []string {
+	names := make([]string, len(s.Skills))
+	for i, a := range s.Skills {
+		names[i] = a.Name
+	}
+	return names
+}
+
 // UpdateTitleAndUsage updates only the title and usage fields atomically.
 // This is safer than fetching, modifying, and saving the entire session.
 func (s *service) UpdateTitleAndUsage(ctx context.Context, sessionID, title string, promptTokens, completionTokens int64, cost float64) error {
@@ -202,6 +284,10 @@ func (s service) fromDBItem(item db.Session) Session {
 	if err != nil {
 		slog.Error("failed to unmarshal todos", "session_id", item.ID, "error", err)
 	}
+	skills, err := unmarshalSkills(item.Skills.String)
+	if err != nil {
+		slog.Error("failed to unmarshal skills", "session_id", item.ID, "error", err)
+	}
 	return Session{
 		ID:               item.ID,
 		ParentSessionID:  item.ParentSessionID.String,
@@ -213,6 +299,9 @@ func (s service) fromDBItem(item db.Session) Session {
 		Cost:             item.Cost,
 		Todos:            todos,
 		Mode:             item.Mode.String,
+		Skills:           skills,
+		ReviewedBy:       item.ReviewedBy.String,
+		ReviewedAt:       item.ReviewedAt.Int64,
 		CreatedAt:        item.CreatedAt,
 		UpdatedAt:        item.UpdatedAt,
 	}
@@ -240,8 +329,33 @@ func unmarshalTodos(data string) ([]Todo, error) {
 	return todos, nil
 }
 
+func marshalSkills(skills []SkillActivation) (string, error) {
+	if len(skills) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(skills)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func unmarshalSkills(data string) ([]SkillActivation, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var skills []SkillActivation
+	if err := json.Unmarshal([]byte(data), &skills); err != nil {
+		return nil, err
+	}
+	return skills, nil
+}
+
 func NewService(q db.Querier) Service {
-	broker := pubsub.NewBroker[Session]()
+	broker := pubsub.NewBroker(
+		pubsub.WithName[Session]("sessions"),
+		pubsub.WithCoalesce(func(s Session) string { return s.ID }),
+	)
 	return &service{
 		broker,
 		q,
@@ -0,0 +1,36 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/db"
+)
+
+func TestActivateSkill(t *testing.T) {
+	t.Parallel()
+
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	svc := NewService(db.New(conn))
+	sess, err := svc.CreateWithMode(t.Context(), "Gym", "gym")
+	require.NoError(t, err)
+	require.Empty(t, sess.Skills)
+
+	sess, err = svc.ActivateSkill(t.Context(), sess.ID, "go-concurrency")
+	require.NoError(t, err)
+	sess, err = svc.ActivateSkill(t.Context(), sess.ID, "system-design")
+	require.NoError(t, err)
+	sess, err = svc.ActivateSkill(t.Context(), sess.ID, "go-concurrency")
+	require.NoError(t, err)
+	require.Equal(t, []string{"go-concurrency", "system-design"}, sess.SkillNames())
+
+	sess, err = svc.Get(t.Context(), sess.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"go-concurrency", "system-design"}, sess.SkillNames())
+	require.True(t, sess.HasSkill("system-design"))
+	require.NotZero(t, sess.Skills[0].ActivatedAt)
+	require.Equal(t, "gym", sess.Mode)
+}
@@ -57,6 +57,12 @@ func GetBackgroundShellManager() *BackgroundShellManager {
 
 // Start creates and starts a new background shell with the given command.
 func (m *BackgroundShellManager) Start(ctx context.Context, workingDir string, blockFuncs []BlockFunc, command string, description string) (*BackgroundShell, error) {
+	return m.StartWithOptions(ctx, &Options{WorkingDir: workingDir, BlockFuncs: blockFuncs}, command, description)
+}
+
+// StartWithOptions creates and starts a new background shell with the given
+// options and command.
+func (m *BackgroundShellManager) StartWithOptions(ctx context.Context, opts *Options, command string, description string) (*BackgroundShell, error) {
 	// Check job limit
 	if m.shells.Len() >= MaxBackgroundJobs {
 		return nil, fmt.Errorf("maximum number of background jobs (%d) reached. Please terminate or wait for some jobs to complete", MaxBackgroundJobs)
@@ -64,10 +70,7 @@ func (m *BackgroundShellManager) Start(ctx context.Context, workingDir string, b
 
 	id := fmt.Sprintf("%03X", idCounter.Add(1))
 
-	shell := NewShell(&Options{
-		WorkingDir: workingDir,
-		BlockFuncs: blockFuncs,
-	})
+	shell := NewShell(opts)
 
 	shellCtx, cancel := context.WithCancel(ctx)
 
@@ -75,7 +78,7 @@ func (m *BackgroundShellManager) Start(ctx context.Context, workingDir string, b
 		ID:          id,
 		Command:     command,
 		Description: description,
-		WorkingDir:  workingDir,
+		WorkingDir:  shell.GetWorkingDir(),
 		Shell:       shell,
 		ctx:         shellCtx,
 		cancel:      cancel,
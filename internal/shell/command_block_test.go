@@ -1,6 +1,8 @@
 package shell
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -374,3 +376,44 @@ func TestSplitArgsFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestRootConfinement(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	outside := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("x"), 0o644))
+
+	tests := []struct {
+		name    string
+		command string
+		allowed bool
+	}{
+		{name: "relative path", command: "ls pkg", allowed: true},
+		{name: "absolute path inside", command: "ls " + filepath.ToSlash(filepath.Join(root, "pkg")), allowed: true},
+		{name: "parent inside", command: "cd pkg && ls ..", allowed: true},
+		{name: "redirect inside", command: "echo hi > out.txt", allowed: true},
+		{name: "redirect to dev null", command: "ls pkg > /dev/null", allowed: true},
+		{name: "absolute path outside", command: "cat " + filepath.ToSlash(filepath.Join(outside, "secret"))},
+		{name: "parent outside", command: "ls ../"},
+		{name: "flag value outside", command: "ls --hide=" + filepath.ToSlash(outside)},
+		{name: "cd outside", command: "cd " + filepath.ToSlash(outside) + " && ls"},
+		{name: "redirect outside", command: "echo hi > " + filepath.ToSlash(filepath.Join(outside, "out.txt"))},
+		{name: "nested shell outside", command: "sh -c 'cat " + filepath.ToSlash(filepath.Join(outside, "secret")) + "'"},
+		{name: "nested shell parent", command: "sh -c 'cd .. && ls'"},
+		{name: "nested shell home", command: "sh -c 'cat ~/.profile'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shell := NewShell(&Options{WorkingDir: root, Root: root})
+			_, _, err := shell.Exec(t.Context(), tt.command)
+			if tt.allowed {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, "not allowed outside")
+		})
+	}
+	require.NoFileExists(t, filepath.Join(outside, "out.txt"))
+}
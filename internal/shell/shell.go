@@ -16,9 +16,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"sync"
+	"unicode"
 
 	"github.com/charmbracelet/x/exp/slice"
 	"mvdan.cc/sh/moreinterp/coreutils"
@@ -56,6 +58,7 @@ type Shell struct {
 	mu         sync.Mutex
 	logger     Logger
 	blockFuncs []BlockFunc
+	root       string
 }
 
 // Options for creating a new shell
@@ -64,6 +67,11 @@ type Options struct {
 	Env        []string
 	Logger     Logger
 	BlockFuncs []BlockFunc
+	// Root confines the shell to a directory: commands can't run from
+	// outside it, name paths outside it or redirect to files outside it.
+	// Paths are checked lexically, so this guards against mistakes rather
+	// than against a process set on escaping.
+	Root string
 }
 
 // NewShell creates a new shell instance with the given options
@@ -92,6 +100,7 @@ func NewShell(opts *Options) *Shell {
 		env:        env,
 		logger:     logger,
 		blockFuncs: opts.BlockFuncs,
+		root:       opts.Root,
 	}
 }
 
@@ -230,21 +239,88 @@ func (s *Shell) blockHandler() func(next interp.ExecHandlerFunc) interp.ExecHand
 					return fmt.Errorf("command is not allowed for security reasons: %s", strings.Join(args, " "))
 				}
 			}
+			if s.root != "" {
+				if err := s.checkConfined(interp.HandlerCtx(ctx).Dir, args); err != nil {
+					return err
+				}
+			}
 
 			return next(ctx, args)
 		}
 	}
 }
 
+// checkConfined reports an error if a command run from dir leaves the
+// shell's root. It is a best-effort check of the command line, not a
+// sandbox: each argument is split into words so that paths in flag values,
+// e.g. --out=/tmp/x, and in scripts handed to another program, e.g.
+// sh -c 'cat /etc/passwd', are checked too. Paths are compared lexically
+// and home directories (~) are refused.
+func (s *Shell) checkConfined(dir string, args []string) error {
+	if !Within(s.root, dir) {
+		return fmt.Errorf("command is not allowed outside %s: %s", s.root, strings.Join(args, " "))
+	}
+	for _, arg := range args[1:] {
+		for _, word := range strings.FieldsFunc(arg, isWordBreak) {
+			if strings.HasPrefix(word, "~") {
+				return fmt.Errorf("path is not allowed outside %s: %s", s.root, word)
+			}
+			if word == os.DevNull || !filepath.IsAbs(word) && !slices.Contains(strings.Split(filepath.ToSlash(word), "/"), "..") {
+				continue
+			}
+			if !Within(s.root, resolve(dir, word)) {
+				return fmt.Errorf("path is not allowed outside %s: %s", s.root, word)
+			}
+		}
+	}
+	return nil
+}
+
+// isWordBreak reports whether r separates the words of an argument that
+// may hold a command line or a flag value.
+func isWordBreak(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(";|&<>()'\"`=", r)
+}
+
+// openHandler refuses redirections to files outside the shell's root.
+// /dev/null is always allowed.
+func (s *Shell) openHandler() interp.OpenHandlerFunc {
+	next := interp.DefaultOpenHandler()
+	return func(ctx context.Context, path string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+		if path != os.DevNull && !Within(s.root, resolve(interp.HandlerCtx(ctx).Dir, path)) {
+			return nil, fmt.Errorf("path is not allowed outside %s: %s", s.root, path)
+		}
+		return next(ctx, path, flag, perm)
+	}
+}
+
+func resolve(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(dir, path)
+}
+
+// Within reports whether path is root or inside it, comparing the paths
+// lexically.
+func Within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 // newInterp creates a new interpreter with the current shell state
 func (s *Shell) newInterp(stdout, stderr io.Writer) (*interp.Runner, error) {
-	return interp.New(
+	opts := []interp.RunnerOption{
 		interp.StdIO(nil, stdout, stderr),
 		interp.Interactive(false),
 		interp.Env(expand.ListEnviron(s.env...)),
 		interp.Dir(s.cwd),
 		interp.ExecHandlers(s.execHandlers()...),
-	)
+	}
+	if s.root != "" {
+		opts = append(opts, interp.OpenHandler(s.openHandler()))
+	}
+	return interp.New(opts...)
 }
 
 // updateShellFromRunner updates the shell from the interpreter after execution.
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 
@@ -109,9 +110,27 @@ func splitFrontmatter(content string) (frontmatter, body string, err error) {
 	return before, after, nil
 }
 
+// Problem describes a skill file that could not be loaded.
+type Problem struct {
+	Path string
+	Err  error
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("%s: %v", p.Path, p.Err)
+}
+
 // Discover finds all valid skills in the given paths.
 func Discover(paths []string) []*Skill {
+	skills, _ := DiscoverWithProblems(paths)
+	return skills
+}
+
+// DiscoverWithProblems finds all valid skills in the given paths and also
+// returns the skill files that failed to parse or validate.
+func DiscoverWithProblems(paths []string) ([]*Skill, []Problem) {
 	var skills []*Skill
+	var problems []Problem
 	var mu sync.Mutex
 	seen := make(map[string]bool)
 
@@ -139,12 +158,14 @@ func Discover(paths []string) []*Skill {
 			seen[path] = true
 			mu.Unlock()
 			skill, err := Parse(path)
-			if err != nil {
-				slog.Warn("Failed to parse skill file", "path", path, "error", err)
-				return nil
+			if err == nil {
+				err = skill.Validate()
 			}
-			if err := skill.Validate(); err != nil {
-				slog.Warn("Skill validation failed", "path", path, "error", err)
+			if err != nil {
+				slog.Warn("Failed to load skill", "path", path, "error", err)
+				mu.Lock()
+				problems = append(problems, Problem{Path: path, Err: err})
+				mu.Unlock()
 				return nil
 			}
 			slog.Info("Successfully loaded skill", "name", skill.Name, "path", path)
@@ -155,7 +176,23 @@ func Discover(paths []string) []*Skill {
 		})
 	}
 
-	return skills
+	slices.SortFunc(skills, func(a, b *Skill) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	slices.SortFunc(problems, func(a, b Problem) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return skills, problems
+}
+
+// Find returns the skill with the given name, if any.
+func Find(skills []*Skill, name string) (*Skill, bool) {
+	for _, s := range skills {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return nil, false
 }
 
 // ToPromptXML generates XML for injection into the system prompt.
@@ -177,6 +214,25 @@ func ToPromptXML(skills []*Skill) string {
 	return sb.String()
 }
 
+// ToActiveXML renders the full instructions of explicitly activated skills
+// so the model follows them without having to read the skill files.
+func ToActiveXML(skills []*Skill) string {
+	if len(skills) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<active_skills>\n")
+	sb.WriteString("The user activated these skills for this session. Follow their instructions.\n")
+	for _, s := range skills {
+		fmt.Fprintf(&sb, "<skill name=\"%s\" location=\"%s\">\n", escape(s.Name), escape(s.SkillFilePath))
+		sb.WriteString(s.Instructions)
+		sb.WriteString("\n</skill>\n")
+	}
+	sb.WriteString("</active_skills>")
+	return sb.String()
+}
+
 func escape(s string) string {
 	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;", "'", "&apos;")
 	return r.Replace(s)
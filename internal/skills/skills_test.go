@@ -247,3 +247,48 @@ func TestToPromptXMLEmpty(t *testing.T) {
 	require.Empty(t, ToPromptXML(nil))
 	require.Empty(t, ToPromptXML([]*Skill{}))
 }
+
+func TestDiscoverWithProblems(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	writeSkill := func(dir, content string) string {
+		path := filepath.Join(tmpDir, dir, SkillFileName)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	writeSkill("valid", "---\nname: valid\ndescription: A valid skill.\n---\nDo things.\n")
+	noDescription := writeSkill("no-description", "---\nname: no-description\n---\n")
+	noFrontmatter := writeSkill("no-frontmatter", "# Just markdown\n")
+
+	skills, problems := DiscoverWithProblems([]string{tmpDir})
+	require.Len(t, skills, 1)
+	require.Equal(t, "valid", skills[0].Name)
+
+	require.Len(t, problems, 2)
+	require.Equal(t, noDescription, problems[0].Path)
+	require.ErrorContains(t, problems[0], "description is required")
+	require.Equal(t, noFrontmatter, problems[1].Path)
+	require.ErrorContains(t, problems[1], "no YAML frontmatter found")
+
+	found, ok := Find(skills, "valid")
+	require.True(t, ok)
+	require.Equal(t, "Do things.", found.Instructions)
+	_, ok = Find(skills, "missing")
+	require.False(t, ok)
+}
+
+func TestToActiveXML(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, ToActiveXML(nil))
+
+	xml := ToActiveXML([]*Skill{
+		{Name: "go-concurrency", SkillFilePath: "/skills/go-concurrency/SKILL.md", Instructions: "Ask about channels & mutexes."},
+	})
+	require.Contains(t, xml, "<active_skills>")
+	require.Contains(t, xml, `<skill name="go-concurrency" location="/skills/go-concurrency/SKILL.md">`)
+	require.Contains(t, xml, "Ask about channels & mutexes.")
+}
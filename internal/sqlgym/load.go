@@ -0,0 +1,13 @@
+package sqlgym
+
+import "github.com/trankhanh040147/prepf/internal/exercise"
+
+// Exercises loads SQL exercises, named after their file, from the sql
+// directories.
+var Exercises = exercise.Loader[Exercise, *Exercise]{Kind: "SQL exercise", Dir: "sql"}
+
+// ExerciseName returns the name the exercise was loaded under.
+func (e *Exercise) ExerciseName() string { return e.Name }
+
+// SetOrigin records the name the exercise is loaded under and its file.
+func (e *Exercise) SetOrigin(name, path string) { e.Name, e.Path = name, path }
@@ -0,0 +1,298 @@
+package sqlgym
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/trankhanh040147/prepf/internal/db"
+)
+
+const (
+	// QueryTimeout bounds how long a query may run, so a runaway cross join
+	// doesn't hang the page.
+	QueryTimeout = 5 * time.Second
+	// MaxRows is the most rows read from a query.
+	MaxRows = 500
+)
+
+// ErrNotAQuery is returned for statements that aren't a single read-only
+// query.
+var ErrNotAQuery = errors.New("only a single SELECT, WITH or VALUES query is allowed")
+
+// Sandbox is a scratch database seeded from an exercise. It is read-only
+// once seeded.
+type Sandbox struct {
+	db  *sql.DB
+	dir string
+}
+
+// Open creates the exercise's database in a temporary directory. It is
+// seeded through one handle and then reopened read-only, so that every
+// connection, including those replacing a connection dropped after a
+// timeout, is in query_only mode.
+func Open(ctx context.Context, e *Exercise) (*Sandbox, error) {
+	dir, err := os.MkdirTemp("", "prepf-sql-")
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, "sandbox.db")
+	if err := seed(ctx, path, e); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	conn, err := db.OpenReadOnly(path)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return &Sandbox{db: conn, dir: dir}, nil
+}
+
+// seed creates the exercise's schema and fixtures in the database at path.
+func seed(ctx context.Context, path string, e *Exercise) error {
+	conn, err := db.Open(path)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for _, step := range []struct{ name, sql string }{
+		{"schema", e.Schema},
+		{"fixtures", e.Fixtures},
+	} {
+		if strings.TrimSpace(step.sql) == "" {
+			continue
+		}
+		if _, err := conn.ExecContext(ctx, step.sql); err != nil {
+			return fmt.Errorf("%s: %w", step.name, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the database and removes its files.
+func (s *Sandbox) Close() error {
+	err := s.db.Close()
+	return errors.Join(err, os.RemoveAll(s.dir))
+}
+
+// Query runs a read-only query, reading at most MaxRows rows within
+// QueryTimeout.
+func (s *Sandbox) Query(ctx context.Context, query string) (Result, error) {
+	if err := checkQuery(query); err != nil {
+		return Result{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return Result{}, timeoutError(ctx, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Columns: columns}
+	for rows.Next() {
+		if len(result.Rows) == MaxRows {
+			result.Truncated = true
+			break
+		}
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return Result{}, err
+		}
+		row := make([]string, len(values))
+		for i, v := range values {
+			row[i] = formatValue(v)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return Result{}, timeoutError(ctx, err)
+	}
+	return result, nil
+}
+
+// Plan returns the output of EXPLAIN QUERY PLAN for the query, indented as
+// the tree SQLite reports.
+func (s *Sandbox) Plan(ctx context.Context, query string) (string, error) {
+	if err := checkQuery(query); err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "EXPLAIN QUERY PLAN "+strings.TrimRight(strings.TrimSpace(query), ";"))
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	depth := make(map[int64]int)
+	var lines []string
+	for rows.Next() {
+		var id, parent, unused int64
+		var detail string
+		if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+			return "", err
+		}
+		depth[id] = depth[parent] + 1
+		lines = append(lines, strings.Repeat("  ", depth[id]-1)+detail)
+	}
+	return strings.Join(lines, "\n"), rows.Err()
+}
+
+// Verify seeds a sandbox and runs every reference query, so an exercise that
+// can't be answered is caught before anyone tries.
+func (e *Exercise) Verify(ctx context.Context) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	s, err := Open(ctx, e)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	var errs []error
+	for i, task := range e.Tasks {
+		result, err := s.Query(ctx, task.Reference)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("task %d: reference: %w", i+1, err))
+		case result.Truncated:
+			errs = append(errs, fmt.Errorf("task %d: reference returns more than %d rows", i+1, MaxRows))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func timeoutError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("query took longer than %s", QueryTimeout)
+	}
+	return err
+}
+
+// readOnlyKeywords are the statements a query may start with.
+var readOnlyKeywords = []string{"select", "with", "values"}
+
+// writeKeywords start the statements a WITH clause may lead into besides a
+// query. REPLACE is also a function, so it counts only before INTO.
+var writeKeywords = []string{"insert", "update", "delete"}
+
+// checkQuery rejects anything but a single query. The sandbox is read-only
+// anyway; this gives a clearer error and keeps out statements like ATTACH
+// and writes behind a WITH clause.
+func checkQuery(query string) error {
+	query = strings.TrimRight(strings.TrimSpace(stripComments(query)), "; \t\n")
+	if query == "" {
+		return errors.New("the query is empty")
+	}
+	if !onlyInStrings(query, ';') {
+		return ErrNotAQuery
+	}
+	end := strings.IndexFunc(query, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		end = len(query)
+	}
+	keyword := strings.ToLower(query[:end])
+	if !slices.Contains(readOnlyKeywords, keyword) {
+		return ErrNotAQuery
+	}
+	if keyword == "with" && writes(query) {
+		return ErrNotAQuery
+	}
+	return nil
+}
+
+// writes reports whether the words of query outside quoted strings and
+// identifiers include a write statement.
+func writes(query string) bool {
+	words := strings.FieldsFunc(strings.ToLower(unquoted(query)), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '_'
+	})
+	for i, word := range words {
+		if slices.Contains(writeKeywords, word) || word == "replace" && i+1 < len(words) && words[i+1] == "into" {
+			return true
+		}
+	}
+	return false
+}
+
+// stripComments removes leading -- and /* */ comments.
+func stripComments(query string) string {
+	for {
+		query = strings.TrimSpace(query)
+		switch {
+		case strings.HasPrefix(query, "--"):
+			_, rest, _ := strings.Cut(query, "\n")
+			query = rest
+		case strings.HasPrefix(query, "/*"):
+			_, rest, ok := strings.Cut(query, "*/")
+			if !ok {
+				return ""
+			}
+			query = rest
+		default:
+			return query
+		}
+	}
+}
+
+// onlyInStrings reports whether every occurrence of c is inside a quoted
+// string or identifier.
+func onlyInStrings(query string, c byte) bool {
+	return strings.IndexByte(unquoted(query), c) < 0
+}
+
+// unquoted returns query with the contents of its quoted strings and
+// identifiers blanked out.
+func unquoted(query string) string {
+	out := []byte(query)
+	var quote byte
+	for i, ch := range out {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			} else {
+				out[i] = ' '
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		}
+	}
+	return string(out)
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,158 @@
+// Package sqlgym runs SQL exercises: queries written by the user are run
+// against a scratch SQLite database seeded from the exercise and their
+// result set compared with the one of a reference query.
+package sqlgym
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MaxTasks caps the tasks in an exercise so a generated one stays a quick
+// round.
+const MaxTasks = 20
+
+// Task is a question answered with a query.
+type Task struct {
+	Prompt    string `yaml:"prompt" json:"prompt"`
+	Reference string `yaml:"reference" json:"reference"`
+	// Ordered makes the row order part of the answer, for tasks that ask
+	// for sorted output. Otherwise rows are compared as a multiset.
+	Ordered bool `yaml:"ordered,omitempty" json:"ordered,omitempty"`
+}
+
+// Exercise is a database and the tasks to query it for.
+type Exercise struct {
+	// Name is the file name without extension and is what /sql takes.
+	Name  string `yaml:"-" json:"name"`
+	Title string `yaml:"title,omitempty" json:"title,omitempty"`
+	Topic string `yaml:"topic,omitempty" json:"topic,omitempty"`
+	// Schema creates the tables and indexes and Fixtures fills them. Both
+	// are SQLite statements separated by semicolons.
+	Schema   string `yaml:"schema" json:"schema"`
+	Fixtures string `yaml:"fixtures,omitempty" json:"fixtures,omitempty"`
+	Tasks    []Task `yaml:"tasks" json:"tasks"`
+	Path     string `yaml:"-" json:"path"`
+}
+
+// TopicOf returns the topic the exercise is tracked under.
+func (e *Exercise) TopicOf() string {
+	if e.Topic != "" {
+		return e.Topic
+	}
+	return "SQL"
+}
+
+// Validate checks that the exercise has a schema and well-formed tasks. It
+// doesn't run anything; see Verify for that.
+func (e *Exercise) Validate() error {
+	var errs []error
+	if strings.TrimSpace(e.Schema) == "" {
+		errs = append(errs, errors.New("schema is required"))
+	}
+	if len(e.Tasks) == 0 {
+		errs = append(errs, errors.New("at least one task is required"))
+	}
+	if len(e.Tasks) > MaxTasks {
+		errs = append(errs, fmt.Errorf("at most %d tasks are allowed, got %d", MaxTasks, len(e.Tasks)))
+	}
+	for i, task := range e.Tasks {
+		if strings.TrimSpace(task.Prompt) == "" {
+			errs = append(errs, fmt.Errorf("task %d: prompt is required", i+1))
+		}
+		if err := checkQuery(task.Reference); err != nil {
+			errs = append(errs, fmt.Errorf("task %d: reference: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Result is a result set with every value rendered as text.
+type Result struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+	// Truncated is set when the query returned more than MaxRows rows.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Comparison is the outcome of comparing a result set with the expected
+// one.
+type Comparison struct {
+	Match bool `json:"match"`
+	// Reason says why the results don't match when it isn't a matter of
+	// missing or extra rows.
+	Reason string `json:"reason,omitempty"`
+	// Missing are the expected rows the query didn't return and Extra the
+	// rows it returned that weren't expected.
+	Missing [][]string `json:"missing,omitempty"`
+	Extra   [][]string `json:"extra,omitempty"`
+}
+
+// Compare checks got against the expected result. Columns are compared by
+// position, not name, so aliases don't matter.
+func Compare(expected, got Result, ordered bool) Comparison {
+	switch {
+	case got.Truncated:
+		return Comparison{Reason: fmt.Sprintf("the query returned more than %d rows", MaxRows)}
+	case len(got.Columns) != len(expected.Columns):
+		return Comparison{Reason: fmt.Sprintf("expected %d column(s), got %d", len(expected.Columns), len(got.Columns))}
+	}
+
+	missing, extra := diffRows(expected.Rows, got.Rows)
+	switch {
+	case len(missing) > 0 || len(extra) > 0:
+		return Comparison{Missing: missing, Extra: extra}
+	case ordered && !slices.EqualFunc(expected.Rows, got.Rows, slices.Equal):
+		return Comparison{Reason: "the rows are right but in the wrong order"}
+	}
+	return Comparison{Match: true}
+}
+
+// diffRows returns the multiset differences between the expected and the
+// actual rows, each in the order the rows appear.
+func diffRows(expected, got [][]string) (missing, extra [][]string) {
+	counts := make(map[string]int)
+	for _, row := range got {
+		counts[rowKey(row)]++
+	}
+	for _, row := range expected {
+		key := rowKey(row)
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		missing = append(missing, row)
+	}
+	for _, row := range got {
+		key := rowKey(row)
+		if counts[key] > 0 {
+			counts[key]--
+			extra = append(extra, row)
+		}
+	}
+	return missing, extra
+}
+
+func rowKey(row []string) string {
+	return strings.Join(row, "\x00")
+}
+
+// ExplainPrompt asks the model to go over the query plan of the user's
+// query. It stands on its own so it can be sent to any session.
+func ExplainPrompt(e *Exercise, task Task, query string, comparison Comparison, plan string) string {
+	var sb strings.Builder
+	sb.WriteString("I just answered this SQL exercise. Explain the query plan of my query step by step, say which indexes would help it and what they would cost on writes and storage, and compare it with how you would write the query.")
+	if !comparison.Match {
+		sb.WriteString(" My query didn't return the expected result, so start by explaining what it gets wrong.")
+	}
+	fmt.Fprintf(&sb, "\n\nSchema (SQLite):\n```sql\n%s\n```\n\nTask: %s\n\nMy query:\n```sql\n%s\n```\n", strings.TrimSpace(e.Schema), task.Prompt, strings.TrimSpace(query))
+	if comparison.Reason != "" {
+		fmt.Fprintf(&sb, "\nResult: %s.\n", comparison.Reason)
+	} else if !comparison.Match {
+		fmt.Fprintf(&sb, "\nResult: %d expected row(s) missing, %d unexpected row(s).\n", len(comparison.Missing), len(comparison.Extra))
+	}
+	fmt.Fprintf(&sb, "\nEXPLAIN QUERY PLAN:\n```\n%s\n```", plan)
+	return sb.String()
+}
@@ -0,0 +1,151 @@
+package sqlgym
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testExercise() *Exercise {
+	return &Exercise{
+		Title: "Orders",
+		Schema: `CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER NOT NULL REFERENCES customers (id), total REAL NOT NULL);`,
+		Fixtures: `INSERT INTO customers VALUES (1, 'Ada'), (2, 'Linus'), (3, 'Grace');
+INSERT INTO orders VALUES (1, 1, 10.5), (2, 1, 20), (3, 2, 5);`,
+		Tasks: []Task{
+			{
+				Prompt:    "Total spent per customer who ordered, biggest first.",
+				Reference: "SELECT c.name, SUM(o.total) FROM customers c JOIN orders o ON o.customer_id = c.id GROUP BY c.id ORDER BY 2 DESC",
+				Ordered:   true,
+			},
+			{
+				Prompt:    "Customers without orders.",
+				Reference: "SELECT name FROM customers WHERE id NOT IN (SELECT customer_id FROM orders)",
+			},
+		},
+	}
+}
+
+func TestSandbox(t *testing.T) {
+	t.Parallel()
+
+	e := testExercise()
+	require.NoError(t, e.Verify(t.Context()))
+
+	s, err := Open(t.Context(), e)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	expected, err := s.Query(t.Context(), e.Tasks[0].Reference)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"Ada", "30.5"}, {"Linus", "5"}}, expected.Rows)
+
+	got, err := s.Query(t.Context(), "-- per customer\nselect name as who, sum(total) as spent from orders join customers on customers.id = customer_id group by name order by spent;")
+	require.NoError(t, err)
+	require.Equal(t, Comparison{Reason: "the rows are right but in the wrong order"}, Compare(expected, got, true))
+	require.True(t, Compare(expected, got, false).Match)
+
+	got, err = s.Query(t.Context(), "SELECT name, total FROM customers JOIN orders ON customers.id = customer_id WHERE total > 6")
+	require.NoError(t, err)
+	require.Equal(t, Comparison{
+		Missing: [][]string{{"Ada", "30.5"}, {"Linus", "5"}},
+		Extra:   [][]string{{"Ada", "10.5"}, {"Ada", "20"}},
+	}, Compare(expected, got, true))
+
+	got, err = s.Query(t.Context(), "SELECT name FROM customers")
+	require.NoError(t, err)
+	require.Equal(t, "expected 2 column(s), got 1", Compare(expected, got, false).Reason)
+
+	got, err = s.Query(t.Context(), "WITH RECURSIVE n(i) AS (VALUES (1) UNION ALL SELECT i + 1 FROM n) SELECT i FROM n")
+	require.NoError(t, err)
+	require.True(t, got.Truncated)
+	require.Len(t, got.Rows, MaxRows)
+
+	for _, query := range []string{
+		"DELETE FROM orders",
+		"SELECT 1; DROP TABLE orders",
+		"ATTACH DATABASE 'x.db' AS x",
+		"WITH x AS (SELECT 1) DELETE FROM orders",
+		"with x as (select 1) insert into orders select 4, 3, total from orders",
+		"WITH x AS (SELECT 1) REPLACE INTO orders VALUES (1, 1, 0)",
+	} {
+		_, err = s.Query(t.Context(), query)
+		require.ErrorIs(t, err, ErrNotAQuery, query)
+	}
+	got, err = s.Query(t.Context(), "WITH x AS (SELECT 'delete') SELECT count(*), replace('a', 'a', 'b') FROM orders WHERE ';' <> ''")
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"3", "b"}}, got.Rows)
+
+	plan, err := s.Plan(t.Context(), e.Tasks[1].Reference)
+	require.NoError(t, err)
+	require.Contains(t, plan, "SCAN customers")
+}
+
+func TestSandboxReadOnlyAfterTimeout(t *testing.T) {
+	t.Parallel()
+
+	s, err := Open(t.Context(), testExercise())
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	// A query cut off by its deadline gets its connection dropped; the one
+	// replacing it must be read-only too.
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	_, err = s.Query(ctx, "WITH RECURSIVE n(i) AS (VALUES (1) UNION ALL SELECT i + 1 FROM n) SELECT count(*) FROM n")
+	require.Error(t, err)
+
+	_, err = s.db.ExecContext(t.Context(), "INSERT INTO customers VALUES (4, 'Barbara')")
+	require.ErrorContains(t, err, "readonly")
+	got, err := s.Query(t.Context(), "SELECT count(*) FROM customers")
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"3"}}, got.Rows)
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	e := testExercise()
+	e.Tasks = append(e.Tasks,
+		Task{Prompt: "Broken", Reference: "SELECT missing FROM orders"},
+		Task{Prompt: "Write", Reference: "UPDATE orders SET total = 0"},
+	)
+	err := e.Verify(t.Context())
+	require.ErrorContains(t, err, "task 4: reference: only a single SELECT")
+
+	e.Tasks = e.Tasks[:3]
+	require.ErrorContains(t, e.Verify(t.Context()), "task 3: reference:")
+
+	e.Schema = "CREATE TABLE oops ("
+	require.ErrorContains(t, e.Verify(t.Context()), "schema:")
+}
+
+func TestExplainPrompt(t *testing.T) {
+	t.Parallel()
+
+	e := testExercise()
+	prompt := ExplainPrompt(e, e.Tasks[1], "SELECT name FROM customers", Comparison{Missing: [][]string{{"Grace"}}}, "SCAN customers")
+	require.Contains(t, prompt, "start by explaining what it gets wrong")
+	require.Contains(t, prompt, "1 expected row(s) missing, 0 unexpected row(s)")
+	require.True(t, strings.HasSuffix(prompt, "EXPLAIN QUERY PLAN:\n```\nSCAN customers\n```"))
+}
+
+func TestSave(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path, err := Exercises.Save(dir, testExercise().Title, testExercise())
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "orders.yaml"), path)
+
+	e, err := Exercises.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, "orders", e.Name)
+	require.Equal(t, testExercise().Tasks, e.Tasks)
+	require.Equal(t, "SQL", e.TopicOf())
+}
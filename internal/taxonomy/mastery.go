@@ -0,0 +1,151 @@
+package taxonomy
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/trankhanh040147/prepf/internal/drill"
+)
+
+// Level buckets a mastery score.
+type Level int
+
+const (
+	LevelUntested Level = iota
+	LevelWeak
+	LevelShaky
+	LevelStrong
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelWeak:
+		return "weak"
+	case LevelShaky:
+		return "shaky"
+	case LevelStrong:
+		return "strong"
+	default:
+		return "untested"
+	}
+}
+
+// Scores from which a node counts as shaky and strong.
+const (
+	shakyFrom  = 50
+	strongFrom = 75
+)
+
+// Mastery is how the questions filed under a node and its descendants went.
+type Mastery struct {
+	Node *Node
+	// Questions were filed at the node or below, most recent first.
+	Questions []drill.Question
+	// Score is the average score of the questions.
+	Score    float64
+	Children []*Mastery
+}
+
+// Level buckets the node's score.
+func (m *Mastery) Level() Level {
+	if len(m.Questions) == 0 {
+		return LevelUntested
+	}
+	return LevelOf(m.Score)
+}
+
+// LevelOf buckets a score.
+func LevelOf(score float64) Level {
+	switch {
+	case score < shakyFrom:
+		return LevelWeak
+	case score < strongFrom:
+		return LevelShaky
+	default:
+		return LevelStrong
+	}
+}
+
+// Mastery aggregates graded questions per node: a question counts towards
+// the node it is filed under and all of its ancestors. Nodes the questions
+// are filed under that the taxonomy lacks, e.g. after an entry was removed
+// from a file, are added to it.
+func (t *Taxonomy) Mastery(questions []drill.Question) []*Mastery {
+	filed := make(map[*Node][]drill.Question)
+	for _, q := range questions {
+		if node := t.Add(q.Node); node != nil {
+			filed[node] = append(filed[node], q)
+		}
+	}
+
+	var build func(nodes []*Node) []*Mastery
+	build = func(nodes []*Node) []*Mastery {
+		masteries := make([]*Mastery, len(nodes))
+		for i, node := range nodes {
+			m := &Mastery{
+				Node:      node,
+				Questions: slices.Clone(filed[node]),
+				Children:  build(node.Children),
+			}
+			for _, child := range m.Children {
+				m.Questions = append(m.Questions, child.Questions...)
+			}
+			slices.SortStableFunc(m.Questions, func(a, b drill.Question) int {
+				return cmp.Compare(b.CreatedAt, a.CreatedAt)
+			})
+			var total int
+			for _, q := range m.Questions {
+				if q.Score != nil {
+					total += *q.Score
+				}
+			}
+			if len(m.Questions) > 0 {
+				m.Score = float64(total) / float64(len(m.Questions))
+			}
+			masteries[i] = m
+		}
+		return masteries
+	}
+	return build(t.Roots)
+}
+
+// DrillPrompt is the message that opens a gym session focused on the node.
+// It points the interviewer at the weakest topics below the node and the
+// questions already asked there.
+func DrillPrompt(m *Mastery) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Drill me on %s.", m.Node.Path)
+
+	var weak []string
+	var walk func(children []*Mastery)
+	walk = func(children []*Mastery) {
+		for _, c := range children {
+			if l := c.Level(); l == LevelWeak || l == LevelShaky {
+				weak = append(weak, fmt.Sprintf("- %s: %.0f over %d question(s)", c.Node.Path, c.Score, len(c.Questions)))
+			}
+			walk(c.Children)
+		}
+	}
+	walk(m.Children)
+	if len(weak) > 0 {
+		b.WriteString(" Focus on my weakest areas there:\n\n")
+		b.WriteString(strings.Join(weak, "\n"))
+	} else if len(m.Questions) == 0 {
+		b.WriteString(" I haven't practiced it yet, so start with the fundamentals.")
+	}
+
+	if len(m.Questions) > 0 {
+		b.WriteString("\n\nI've already had these questions, so ask new ones:\n\n")
+		for _, q := range m.Questions[:min(len(m.Questions), 10)] {
+			fmt.Fprintf(&b, "- %s\n", firstLine(q.Text))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
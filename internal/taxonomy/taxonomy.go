@@ -0,0 +1,175 @@
+// Package taxonomy holds the tree of interview topics that graded questions
+// are filed under, and aggregates how well each topic went.
+package taxonomy
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/trankhanh040147/prepf/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Separator joins the names along a path, e.g. "Go > Concurrency > Channels".
+const Separator = " > "
+
+// FileName is the name of the files that extend the built-in taxonomy.
+const FileName = "taxonomy.yaml"
+
+//go:embed taxonomy.yaml
+var defaultFile []byte
+
+// Node is a topic of the taxonomy.
+type Node struct {
+	Name string
+	// Path is the names from the root down to the node, joined with
+	// Separator.
+	Path     string
+	Children []*Node
+}
+
+// Taxonomy is a tree of topics. Names are matched regardless of case.
+type Taxonomy struct {
+	Roots []*Node
+	nodes map[string]*Node
+}
+
+func New() *Taxonomy {
+	return &Taxonomy{nodes: make(map[string]*Node)}
+}
+
+// Split returns the names along a path.
+func Split(path string) []string {
+	var names []string
+	for name := range strings.SplitSeq(path, ">") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func key(names []string) string {
+	return strings.ToLower(strings.Join(names, Separator))
+}
+
+// Add adds the node at path, along with its missing parents, and returns
+// it. It returns nil for an empty path.
+func (t *Taxonomy) Add(path string) *Node {
+	var node *Node
+	siblings := &t.Roots
+	names := Split(path)
+	for i, name := range names {
+		k := key(names[:i+1])
+		child, ok := t.nodes[k]
+		if !ok {
+			child = &Node{Name: name, Path: name}
+			if node != nil {
+				child.Path = node.Path + Separator + name
+			}
+			*siblings = append(*siblings, child)
+			t.nodes[k] = child
+		}
+		node = child
+		siblings = &child.Children
+	}
+	return node
+}
+
+// Find returns the node at path.
+func (t *Taxonomy) Find(path string) (*Node, bool) {
+	node, ok := t.nodes[key(Split(path))]
+	return node, ok
+}
+
+// Resolve returns the deepest node along path, or nil if not even its root
+// is in the taxonomy.
+func (t *Taxonomy) Resolve(path string) *Node {
+	names := Split(path)
+	for i := len(names); i > 0; i-- {
+		if node, ok := t.nodes[key(names[:i])]; ok {
+			return node
+		}
+	}
+	return nil
+}
+
+// Paths lists the path of every node, parents before their children.
+func (t *Taxonomy) Paths() []string {
+	var paths []string
+	var walk func(nodes []*Node)
+	walk = func(nodes []*Node) {
+		for _, n := range nodes {
+			paths = append(paths, n.Path)
+			walk(n.Children)
+		}
+	}
+	walk(t.Roots)
+	return paths
+}
+
+// Parse reads a taxonomy file: a YAML list of node paths.
+func Parse(data []byte) ([]string, error) {
+	var paths []string
+	if err := yaml.Unmarshal(data, &paths); err != nil {
+		return nil, err
+	}
+	for i, path := range paths {
+		if len(Split(path)) == 0 {
+			return nil, fmt.Errorf("entry %d is empty", i+1)
+		}
+	}
+	return paths, nil
+}
+
+// Default returns the built-in taxonomy.
+func Default() *Taxonomy {
+	paths, err := Parse(defaultFile)
+	if err != nil {
+		panic(fmt.Sprintf("built-in taxonomy: %v", err))
+	}
+	t := New()
+	for _, path := range paths {
+		t.Add(path)
+	}
+	return t
+}
+
+// Files returns the files that extend the built-in taxonomy, in the order
+// they are applied.
+func Files(cfg *config.Config) []string {
+	return []string{
+		filepath.Join(filepath.Dir(config.GlobalConfig()), FileName),
+		filepath.Join(cfg.Options.DataDirectory, FileName),
+	}
+}
+
+// Load returns the built-in taxonomy extended with the user's files. Files
+// that don't parse are skipped and reported; missing ones are not an error.
+func Load(cfg *config.Config) (*Taxonomy, []error) {
+	t := Default()
+	var errs []error
+	for _, file := range Files(cfg) {
+		data, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		var paths []string
+		if err == nil {
+			paths, err = Parse(data)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("taxonomy %s: %w", file, err))
+			continue
+		}
+		for _, path := range paths {
+			t.Add(path)
+		}
+	}
+	return t, errs
+}
@@ -0,0 +1,113 @@
+package taxonomy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/drill"
+)
+
+func TestAdd(t *testing.T) {
+	t.Parallel()
+
+	tx := New()
+	node := tx.Add("Go > Concurrency > Channels")
+	require.Equal(t, "Go > Concurrency > Channels", node.Path)
+	require.Equal(t, "Channels", node.Name)
+
+	// Names match regardless of case and spacing.
+	require.Same(t, node, tx.Add("go>concurrency >  CHANNELS"))
+	tx.Add("Go > Concurrency > Context")
+	tx.Add("Networking")
+	require.Equal(t, []string{
+		"Go",
+		"Go > Concurrency",
+		"Go > Concurrency > Channels",
+		"Go > Concurrency > Context",
+		"Networking",
+	}, tx.Paths())
+
+	require.Nil(t, tx.Add(" > "))
+	_, ok := tx.Find("Go > Memory")
+	require.False(t, ok)
+	require.Equal(t, "Go > Concurrency", tx.Resolve("go > concurrency > select").Path)
+	require.Nil(t, tx.Resolve("Rust > Ownership"))
+}
+
+func TestDefault(t *testing.T) {
+	t.Parallel()
+
+	tx := Default()
+	node, ok := tx.Find("Networking > TCP > Congestion Control")
+	require.True(t, ok)
+	require.Equal(t, "Congestion Control", node.Name)
+	_, ok = tx.Find("Go > Concurrency > Channels")
+	require.True(t, ok)
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	cfg := &config.Config{Options: &config.Options{DataDirectory: dataDir}}
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, FileName), []byte("- Kubernetes > Networking > Services\n- Go > Concurrency > Worker Pools\n"), 0o644))
+
+	tx, errs := Load(cfg)
+	require.Empty(t, errs)
+	_, ok := tx.Find("Kubernetes > Networking > Services")
+	require.True(t, ok)
+	node, ok := tx.Find("Go > Concurrency")
+	require.True(t, ok)
+	require.Equal(t, "Worker Pools", node.Children[len(node.Children)-1].Name)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, FileName), []byte("- Go\n- \" > \"\n"), 0o644))
+	_, errs = Load(cfg)
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "entry 2 is empty")
+}
+
+func TestMastery(t *testing.T) {
+	t.Parallel()
+
+	score := func(v int) *int { return &v }
+	tx := New()
+	tx.Add("Go > Concurrency > Channels")
+	tx.Add("Go > Concurrency > Context")
+	tx.Add("Go > Testing")
+
+	masteries := tx.Mastery([]drill.Question{
+		{Text: "close?", Node: "Go > Concurrency > Channels", Score: score(90), CreatedAt: 3},
+		{Text: "nil channel?", Node: "go > concurrency > channels", Score: score(40), CreatedAt: 2},
+		{Text: "cancel?", Node: "Go > Concurrency > Context", Score: score(20), CreatedAt: 1},
+		{Text: "borrow?", Node: "Rust > Ownership", Score: score(80), CreatedAt: 4},
+	})
+	require.Len(t, masteries, 2)
+
+	golang := masteries[0]
+	require.Len(t, golang.Questions, 3)
+	require.Equal(t, 50.0, golang.Score)
+	require.Equal(t, LevelShaky, golang.Level())
+
+	concurrency := golang.Children[0]
+	channels, context := concurrency.Children[0], concurrency.Children[1]
+	require.Equal(t, 65.0, channels.Score)
+	require.Equal(t, LevelShaky, channels.Level())
+	require.Equal(t, LevelWeak, context.Level())
+	require.Equal(t, LevelUntested, golang.Children[1].Level())
+	require.Equal(t, []string{"close?", "nil channel?", "cancel?"}, []string{
+		concurrency.Questions[0].Text, concurrency.Questions[1].Text, concurrency.Questions[2].Text,
+	})
+
+	// Questions filed under unknown nodes add them.
+	require.Equal(t, "Rust > Ownership", masteries[1].Children[0].Node.Path)
+	require.Equal(t, LevelStrong, masteries[1].Level())
+
+	prompt := DrillPrompt(concurrency)
+	require.Contains(t, prompt, "Drill me on Go > Concurrency.")
+	require.Contains(t, prompt, "- Go > Concurrency > Context: 20 over 1 question(s)")
+	require.Contains(t, prompt, "- nil channel?")
+	require.Contains(t, DrillPrompt(golang.Children[1]), "I haven't practiced it yet")
+}
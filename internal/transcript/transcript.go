@@ -0,0 +1,259 @@
+// Package transcript exports a session with its messages and annotations to
+// a file and imports it back, so a mentor can annotate an exported session
+// and send it back.
+package transcript
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/trankhanh040147/prepf/internal/annotation"
+	"github.com/trankhanh040147/prepf/internal/db"
+)
+
+// Version is the version of the file format written by Export.
+const Version = 1
+
+// Transcript is an exported session.
+type Transcript struct {
+	Version     int                     `json:"version"`
+	Session     Session                 `json:"session"`
+	Messages    []Message               `json:"messages"`
+	Annotations []annotation.Annotation `json:"annotations"`
+}
+
+type Session struct {
+	ID               string  `json:"id"`
+	Title            string  `json:"title"`
+	Mode             string  `json:"mode,omitempty"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+	ReviewedBy       string  `json:"reviewed_by,omitempty"`
+	ReviewedAt       int64   `json:"reviewed_at,omitempty"`
+	CreatedAt        int64   `json:"created_at"`
+	UpdatedAt        int64   `json:"updated_at"`
+}
+
+// Message is a message of the session. Parts are kept as stored, so they
+// round-trip whatever content they hold.
+type Message struct {
+	ID               string          `json:"id"`
+	Role             string          `json:"role"`
+	Parts            json.RawMessage `json:"parts"`
+	Model            string          `json:"model,omitempty"`
+	Provider         string          `json:"provider,omitempty"`
+	IsSummaryMessage bool            `json:"is_summary_message,omitempty"`
+	CreatedAt        int64           `json:"created_at"`
+	UpdatedAt        int64           `json:"updated_at"`
+	FinishedAt       int64           `json:"finished_at,omitempty"`
+}
+
+// Export reads a session with its messages and annotations.
+func Export(ctx context.Context, q db.Querier, sessionID string) (Transcript, error) {
+	s, err := q.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return Transcript{}, err
+	}
+	t := Transcript{
+		Version: Version,
+		Session: Session{
+			ID:               s.ID,
+			Title:            s.Title,
+			Mode:             s.Mode.String,
+			PromptTokens:     s.PromptTokens,
+			CompletionTokens: s.CompletionTokens,
+			Cost:             s.Cost,
+			ReviewedBy:       s.ReviewedBy.String,
+			ReviewedAt:       s.ReviewedAt.Int64,
+			CreatedAt:        s.CreatedAt,
+			UpdatedAt:        s.UpdatedAt,
+		},
+		Messages:    []Message{},
+		Annotations: []annotation.Annotation{},
+	}
+
+	messages, err := q.ListMessagesBySession(ctx, sessionID)
+	if err != nil {
+		return Transcript{}, err
+	}
+	for _, m := range messages {
+		t.Messages = append(t.Messages, Message{
+			ID:               m.ID,
+			Role:             m.Role,
+			Parts:            json.RawMessage(m.Parts),
+			Model:            m.Model.String,
+			Provider:         m.Provider.String,
+			IsSummaryMessage: m.IsSummaryMessage != 0,
+			CreatedAt:        m.CreatedAt,
+			UpdatedAt:        m.UpdatedAt,
+			FinishedAt:       m.FinishedAt.Int64,
+		})
+	}
+
+	annotations, err := q.ListAnnotationsBySession(ctx, sessionID)
+	if err != nil {
+		return Transcript{}, err
+	}
+	for _, a := range annotations {
+		t.Annotations = append(t.Annotations, annotation.FromDB(a))
+	}
+	return t, nil
+}
+
+// Validate checks that the transcript can be imported.
+func (t Transcript) Validate() error {
+	switch {
+	case t.Version < 1:
+		return errors.New("not a prepf transcript: missing version")
+	case t.Version > Version:
+		return fmt.Errorf("transcript version %d is newer than this prepf supports (%d); update prepf", t.Version, Version)
+	case t.Session.ID == "":
+		return errors.New("transcript has no session ID")
+	}
+	ids := make(map[string]bool, len(t.Messages))
+	for i, m := range t.Messages {
+		if m.ID == "" || m.Role == "" {
+			return fmt.Errorf("message %d: missing ID or role", i+1)
+		}
+		if !json.Valid(m.Parts) {
+			return fmt.Errorf("message %d: invalid parts", i+1)
+		}
+		ids[m.ID] = true
+	}
+	for i, a := range t.Annotations {
+		if a.ID == "" || a.Author == "" || a.Body == "" {
+			return fmt.Errorf("annotation %d: missing ID, author or body", i+1)
+		}
+		if !ids[a.MessageID] {
+			return fmt.Errorf("annotation %d: message %s is not in the transcript", i+1, a.MessageID)
+		}
+	}
+	return nil
+}
+
+// Result sums up an import.
+type Result struct {
+	SessionID string
+	// Created is whether the session was new. Otherwise only its
+	// annotations and review status were merged.
+	Created bool
+	// Annotations is the number of annotations merged.
+	Annotations int
+	// Skipped is the number of annotations on messages the local session
+	// doesn't have.
+	Skipped int
+}
+
+// Import stores a transcript. A session that isn't there yet is created
+// with its messages. For one that is, such as a transcript coming back
+// from a mentor, only the annotations are merged, keeping the most recently
+// edited version of each, and the review status is taken if it is newer.
+func Import(ctx context.Context, conn *sql.DB, t Transcript) (Result, error) {
+	if err := t.Validate(); err != nil {
+		return Result{}, err
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return Result{}, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+	q := db.New(tx)
+
+	result := Result{SessionID: t.Session.ID}
+	local, err := q.GetSessionByID(ctx, t.Session.ID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if err := importSession(ctx, q, t); err != nil {
+			return Result{}, err
+		}
+		result.Created = true
+	case err != nil:
+		return Result{}, err
+	case t.Session.ReviewedAt > local.ReviewedAt.Int64:
+		_, err := q.SetSessionReviewed(ctx, db.SetSessionReviewedParams{
+			ID:         local.ID,
+			ReviewedBy: sql.NullString{String: t.Session.ReviewedBy, Valid: t.Session.ReviewedBy != ""},
+			ReviewedAt: sql.NullInt64{Int64: t.Session.ReviewedAt, Valid: true},
+		})
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	messages, err := q.ListMessagesBySession(ctx, t.Session.ID)
+	if err != nil {
+		return Result{}, err
+	}
+	known := make(map[string]bool, len(messages))
+	for _, m := range messages {
+		known[m.ID] = true
+	}
+	for _, a := range t.Annotations {
+		if !known[a.MessageID] {
+			result.Skipped++
+			continue
+		}
+		start, end := annotation.ToDBRange(a.Range)
+		err := q.ImportAnnotation(ctx, db.ImportAnnotationParams{
+			ID:         a.ID,
+			SessionID:  t.Session.ID,
+			MessageID:  a.MessageID,
+			Author:     a.Author,
+			Body:       a.Body,
+			RangeStart: start,
+			RangeEnd:   end,
+			CreatedAt:  a.CreatedAt,
+			UpdatedAt:  a.UpdatedAt,
+		})
+		if err != nil {
+			return Result{}, err
+		}
+		result.Annotations++
+	}
+	return result, tx.Commit()
+}
+
+func importSession(ctx context.Context, q *db.Queries, t Transcript) error {
+	s := t.Session
+	_, err := q.ImportSession(ctx, db.ImportSessionParams{
+		ID:               s.ID,
+		Title:            s.Title,
+		PromptTokens:     s.PromptTokens,
+		CompletionTokens: s.CompletionTokens,
+		Cost:             s.Cost,
+		Mode:             sql.NullString{String: s.Mode, Valid: s.Mode != ""},
+		ReviewedBy:       sql.NullString{String: s.ReviewedBy, Valid: s.ReviewedBy != ""},
+		ReviewedAt:       sql.NullInt64{Int64: s.ReviewedAt, Valid: s.ReviewedBy != ""},
+		CreatedAt:        s.CreatedAt,
+		UpdatedAt:        s.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	for _, m := range t.Messages {
+		var summary int64
+		if m.IsSummaryMessage {
+			summary = 1
+		}
+		err := q.ImportMessage(ctx, db.ImportMessageParams{
+			ID:               m.ID,
+			SessionID:        s.ID,
+			Role:             m.Role,
+			Parts:            string(m.Parts),
+			Model:            sql.NullString{String: m.Model, Valid: m.Model != ""},
+			Provider:         sql.NullString{String: m.Provider, Valid: m.Provider != ""},
+			IsSummaryMessage: summary,
+			CreatedAt:        m.CreatedAt,
+			UpdatedAt:        m.UpdatedAt,
+			FinishedAt:       sql.NullInt64{Int64: m.FinishedAt, Valid: m.FinishedAt != 0},
+		})
+		if err != nil {
+			return fmt.Errorf("message %s: %w", m.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package transcript
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/annotation"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/message"
+	"github.com/trankhanh040147/prepf/internal/session"
+)
+
+func connect(t *testing.T) *sql.DB {
+	t.Helper()
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// roundTrip exports a session and reads it back as it would be from a file.
+func roundTrip(t *testing.T, conn *sql.DB, sessionID string) Transcript {
+	t.Helper()
+	exported, err := Export(t.Context(), db.New(conn), sessionID)
+	require.NoError(t, err)
+	data, err := json.Marshal(exported)
+	require.NoError(t, err)
+	var tr Transcript
+	require.NoError(t, json.Unmarshal(data, &tr))
+	return tr
+}
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+
+	// The candidate's prepf.
+	mine := connect(t)
+	q := db.New(mine)
+	sess, err := session.NewService(q).CreateWithMode(t.Context(), "Mock: Go", "mock")
+	require.NoError(t, err)
+	messages := message.NewService(q)
+	question, err := messages.Create(t.Context(), sess.ID, message.CreateMessageParams{
+		Role:  message.Assistant,
+		Parts: []message.ContentPart{message.TextContent{Text: "What happens when you send on a closed channel?"}},
+		Model: "gpt-test",
+	})
+	require.NoError(t, err)
+	reply, err := messages.Create(t.Context(), sess.ID, message.CreateMessageParams{
+		Role:  message.User,
+		Parts: []message.ContentPart{message.TextContent{Text: "It blocks forever."}},
+	})
+	require.NoError(t, err)
+	_, err = annotation.NewService(q).Create(t.Context(), annotation.Annotation{
+		SessionID: sess.ID,
+		MessageID: reply.ID,
+		Author:    "Me",
+		Body:      "Not sure about this one.",
+	})
+	require.NoError(t, err)
+
+	// The mentor imports it, annotates and marks it as reviewed.
+	mentor := connect(t)
+	result, err := Import(t.Context(), mentor, roundTrip(t, mine, sess.ID))
+	require.NoError(t, err)
+	require.Equal(t, Result{SessionID: sess.ID, Created: true, Annotations: 1}, result)
+
+	mq := db.New(mentor)
+	imported, err := message.NewService(mq).List(t.Context(), sess.ID)
+	require.NoError(t, err)
+	require.Len(t, imported, 2)
+	require.Equal(t, question.Content().Text, imported[0].Content().Text)
+	require.Equal(t, "gpt-test", imported[0].Model)
+	require.Equal(t, message.User, imported[1].Role)
+
+	r, ok := annotation.FindRange(reply.Content().Text, "blocks forever")
+	require.True(t, ok)
+	_, err = annotation.NewService(mq).Create(t.Context(), annotation.Annotation{
+		SessionID: sess.ID,
+		MessageID: reply.ID,
+		Author:    "Mentor",
+		Body:      "It panics.",
+		Range:     &r,
+	})
+	require.NoError(t, err)
+	_, err = session.NewService(mq).SetReviewed(t.Context(), sess.ID, "Mentor")
+	require.NoError(t, err)
+
+	// The reviewed transcript comes back.
+	result, err = Import(t.Context(), mine, roundTrip(t, mentor, sess.ID))
+	require.NoError(t, err)
+	require.Equal(t, Result{SessionID: sess.ID, Annotations: 2}, result)
+
+	annotations, err := annotation.NewService(q).List(t.Context(), sess.ID)
+	require.NoError(t, err)
+	require.Len(t, annotations, 2)
+	var mentorNote annotation.Annotation
+	for _, a := range annotations {
+		if a.Author == "Mentor" {
+			mentorNote = a
+		}
+	}
+	require.Equal(t, "blocks forever", mentorNote.Quote(reply.Content().Text))
+
+	sess, err = session.NewService(q).Get(t.Context(), sess.ID)
+	require.NoError(t, err)
+	require.True(t, sess.Reviewed())
+	require.Equal(t, "Mentor", sess.ReviewedBy)
+	mineMessages, err := message.NewService(q).List(t.Context(), sess.ID)
+	require.NoError(t, err)
+	require.Len(t, mineMessages, 2)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := Transcript{
+		Version:  Version,
+		Session:  Session{ID: "s"},
+		Messages: []Message{{ID: "m", Role: "user", Parts: json.RawMessage(`[]`)}},
+		Annotations: []annotation.Annotation{
+			{ID: "a", MessageID: "m", Author: "Alice", Body: "Nice."},
+		},
+	}
+	require.NoError(t, valid.Validate())
+
+	newer := valid
+	newer.Version = Version + 1
+	require.ErrorContains(t, newer.Validate(), "newer")
+
+	dangling := valid
+	dangling.Annotations = []annotation.Annotation{{ID: "a", MessageID: "x", Author: "Alice", Body: "Nice."}}
+	require.ErrorContains(t, dangling.Validate(), "not in the transcript")
+
+	require.Error(t, Transcript{}.Validate())
+}
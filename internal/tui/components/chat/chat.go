@@ -1,7 +1,9 @@
 package chat
 
 import (
+	"cmp"
 	"context"
+	"slices"
 	"time"
 
 	"charm.land/bubbles/v2/key"
@@ -9,6 +11,7 @@ import (
 	"github.com/atotto/clipboard"
 	"github.com/trankhanh040147/prepf/internal/agent"
 	"github.com/trankhanh040147/prepf/internal/agent/tools"
+	"github.com/trankhanh040147/prepf/internal/annotation"
 	"github.com/trankhanh040147/prepf/internal/app"
 	"github.com/trankhanh040147/prepf/internal/message"
 	"github.com/trankhanh040147/prepf/internal/permission"
@@ -24,6 +27,9 @@ import (
 type SendMsg struct {
 	Text        string
 	Attachments []message.Attachment
+	// Timing describes how the user composed the message, if it was typed
+	// in the editor.
+	Timing *message.AnswerTiming
 }
 
 type SessionSelectedMsg = session.Session
@@ -34,6 +40,9 @@ type SessionCreatedWithModeMsg struct {
 	Session    session.Session
 	Text       string
 	Attachments []message.Attachment
+	// Timing describes how the pending message was composed, if it was
+	// typed in the editor.
+	Timing *message.AnswerTiming
 }
 
 type SelectionCopyMsg struct {
@@ -69,6 +78,9 @@ type messageListCmp struct {
 	session          session.Session
 	listCmp          list.List[list.Item]
 	previousSelected string // Last selected item index for restoring focus
+	// annotations holds the review comments on the session's messages, by
+	// message ID.
+	annotations map[string][]annotation.Annotation
 
 	lastUserMessageTime int64
 	defaultListKeyMap   list.KeyMap
@@ -197,12 +209,16 @@ func (m *messageListCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 		return m, tea.Batch(cmds...)
 	case SessionClearedMsg:
 		m.session = session.Session{}
+		m.annotations = nil
 		cmds = append(cmds, m.listCmp.SetItems([]list.Item{}))
 		return m, tea.Batch(cmds...)
 
 	case pubsub.Event[message.Message]:
 		cmds = append(cmds, m.handleMessageEvent(msg))
 		return m, tea.Batch(cmds...)
+	case pubsub.Event[annotation.Annotation]:
+		m.handleAnnotationEvent(msg)
+		return m, nil
 
 	case tea.MouseWheelMsg:
 		u, cmd := m.listCmp.Update(msg)
@@ -338,6 +354,45 @@ func (m *messageListCmp) handleMessageEvent(event pubsub.Event[message.Message])
 	return nil
 }
 
+// handleAnnotationEvent shows a review comment below its message as it is
+// left, edited or removed.
+func (m *messageListCmp) handleAnnotationEvent(event pubsub.Event[annotation.Annotation]) {
+	a := event.Payload
+	if a.SessionID != m.session.ID {
+		return
+	}
+	if m.annotations == nil {
+		m.annotations = make(map[string][]annotation.Annotation)
+	}
+	// The message component holds the current slice, so edit a copy.
+	annotations := slices.DeleteFunc(slices.Clone(m.annotations[a.MessageID]), func(existing annotation.Annotation) bool {
+		return existing.ID == a.ID
+	})
+	if event.Type != pubsub.DeletedEvent {
+		annotations = append(annotations, a)
+		slices.SortStableFunc(annotations, func(a, b annotation.Annotation) int {
+			return cmp.Compare(a.CreatedAt, b.CreatedAt)
+		})
+	}
+	m.annotations[a.MessageID] = annotations
+
+	for _, item := range m.listCmp.Items() {
+		if msgCmp, ok := item.(messages.MessageCmp); ok && msgCmp.GetMessage().ID == a.MessageID {
+			msgCmp.SetAnnotations(annotations)
+			m.listCmp.UpdateItem(msgCmp.ID(), msgCmp)
+			return
+		}
+	}
+}
+
+// newMessageCmp creates the component for a user or assistant message,
+// with the review comments on it.
+func (m *messageListCmp) newMessageCmp(msg message.Message) messages.MessageCmp {
+	msgCmp := messages.NewMessageCmp(msg)
+	msgCmp.SetAnnotations(m.annotations[msg.ID])
+	return msgCmp
+}
+
 // messageExists checks if a message with the given ID already exists in the list.
 func (m *messageListCmp) messageExists(messageID string) bool {
 	items := m.listCmp.Items()
@@ -378,7 +433,7 @@ func (m *messageListCmp) handleNewMessage(msg message.Message) tea.Cmd {
 // handleNewUserMessage adds a new user message to the list and updates the timestamp.
 func (m *messageListCmp) handleNewUserMessage(msg message.Message) tea.Cmd {
 	m.lastUserMessageTime = msg.CreatedAt
-	return m.listCmp.AppendItem(messages.NewMessageCmp(msg))
+	return m.listCmp.AppendItem(m.newMessageCmp(msg))
 }
 
 // handleToolMessage updates existing tool calls with their results.
@@ -526,11 +581,7 @@ func (m *messageListCmp) handleNewAssistantMessage(msg message.Message) tea.Cmd
 
 	// Add assistant message if it should be displayed
 	if m.shouldShowAssistantMessage(msg) {
-		cmd := m.listCmp.AppendItem(
-			messages.NewMessageCmp(
-				msg,
-			),
-		)
+		cmd := m.listCmp.AppendItem(m.newMessageCmp(msg))
 		cmds = append(cmds, cmd)
 	}
 
@@ -554,6 +605,14 @@ func (m *messageListCmp) SetSession(session session.Session) tea.Cmd {
 	if err != nil {
 		return util.ReportError(err)
 	}
+	annotations, err := m.app.Annotations.List(context.Background(), session.ID)
+	if err != nil {
+		return util.ReportError(err)
+	}
+	m.annotations = make(map[string][]annotation.Annotation)
+	for _, a := range annotations {
+		m.annotations[a.MessageID] = append(m.annotations[a.MessageID], a)
+	}
 
 	if len(sessionMessages) == 0 {
 		return m.listCmp.SetItems([]list.Item{})
@@ -590,7 +649,7 @@ func (m *messageListCmp) convertMessagesToUI(sessionMessages []message.Message,
 		switch msg.Role {
 		case message.User:
 			m.lastUserMessageTime = msg.CreatedAt
-			uiMessages = append(uiMessages, messages.NewMessageCmp(msg))
+			uiMessages = append(uiMessages, m.newMessageCmp(msg))
 		case message.Assistant:
 			uiMessages = append(uiMessages, m.convertAssistantMessage(msg, toolResultMap)...)
 			if msg.FinishPart() != nil && msg.FinishPart().Reason == message.FinishReasonEndTurn {
@@ -608,12 +667,7 @@ func (m *messageListCmp) convertAssistantMessage(msg message.Message, toolResult
 
 	// Add assistant message if it should be displayed
 	if m.shouldShowAssistantMessage(msg) {
-		uiMessages = append(
-			uiMessages,
-			messages.NewMessageCmp(
-				msg,
-			),
-		)
+		uiMessages = append(uiMessages, m.newMessageCmp(msg))
 	}
 
 	// Add tool calls with their results and status
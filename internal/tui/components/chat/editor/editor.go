@@ -10,7 +10,9 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"charm.land/bubbles/v2/key"
 	"charm.land/bubbles/v2/textarea"
@@ -30,6 +32,7 @@ import (
 	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs/quit"
 	"github.com/trankhanh040147/prepf/internal/tui/styles"
 	"github.com/trankhanh040147/prepf/internal/tui/util"
+	"github.com/trankhanh040147/prepf/internal/uicmd"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/editor"
 )
@@ -52,6 +55,10 @@ type FileCompletionItem struct {
 	Path string // The file path
 }
 
+type SlashCompletionItem struct {
+	Name string // The command name, without the slash
+}
+
 type editorCmp struct {
 	width              int
 	height             int
@@ -70,6 +77,9 @@ type editorCmp struct {
 	currentQuery          string
 	completionsStartIndex int
 	isCompletionsOpen     bool
+
+	// Answer timing attached to the next message sent
+	timer answerTimer
 }
 
 var DeleteKeyMaps = DeleteAttachmentKeyMaps{
@@ -94,6 +104,7 @@ type OpenEditorMsg struct {
 }
 
 func (m *editorCmp) openEditor(value string) tea.Cmd {
+	m.timer.start(time.Now())
 	tmpfile, err := os.CreateTemp("", "msg_*.md")
 	if err != nil {
 		return util.ReportError(err)
@@ -145,6 +156,15 @@ func (m *editorCmp) send() tea.Cmd {
 		return util.CmdHandler(dialogs.OpenDialogMsg{Model: quit.NewQuitDialog()})
 	}
 
+	if name, args, ok := uicmd.ParseSlash(value); ok {
+		m.timer.discard()
+		return m.runSlashCommand(name, args)
+	}
+	// A doubled slash sends a message that starts with one.
+	if strings.HasPrefix(value, uicmd.SlashPrefix+uicmd.SlashPrefix) {
+		value = value[len(uicmd.SlashPrefix):]
+	}
+
 	attachments := m.attachments
 
 	if value == "" && !message.ContainsTextAttachment(attachments) {
@@ -160,10 +180,20 @@ func (m *editorCmp) send() tea.Cmd {
 		util.CmdHandler(chat.SendMsg{
 			Text:        value,
 			Attachments: attachments,
+			Timing:      m.timer.finish(time.Now()),
 		}),
 	)
 }
 
+func (m *editorCmp) runSlashCommand(name, args string) tea.Cmd {
+	command, ok := uicmd.FindSlash(uicmd.SlashCommands(m.app.Config()), name)
+	if !ok {
+		return util.ReportWarn(fmt.Sprintf("Unknown command %s%s (start with %s%s to send it as a message)", uicmd.SlashPrefix, name, uicmd.SlashPrefix, uicmd.SlashPrefix))
+	}
+	m.textarea.Reset()
+	return uicmd.RunSlash(command, args)
+}
+
 func (m *editorCmp) repositionCompletions() tea.Msg {
 	x, y := m.completionsPosition()
 	return completions.RepositionCompletionsMsg{X: x, Y: y}
@@ -175,6 +205,9 @@ func (m *editorCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		return m, m.repositionCompletions
+	case styles.ThemeChangedMsg:
+		m.textarea.SetStyles(styles.CurrentTheme().S().TextArea)
+		return m, nil
 	case filepicker.FilePickedMsg:
 		m.attachments = append(m.attachments, msg.Attachment)
 		return m, nil
@@ -224,6 +257,15 @@ func (m *editorCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 				Content:  content,
 			})
 		}
+		if item, ok := msg.Value.(SlashCompletionItem); ok {
+			m.textarea.SetValue(uicmd.SlashPrefix + item.Name + " ")
+			m.textarea.MoveToEnd()
+			if !msg.Insert {
+				m.isCompletionsOpen = false
+				m.currentQuery = ""
+				m.completionsStartIndex = 0
+			}
+		}
 
 	case commands.OpenExternalEditorMsg:
 		if m.app.AgentCoordinator.IsSessionBusy(m.session.ID) {
@@ -233,9 +275,13 @@ func (m *editorCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 	case OpenEditorMsg:
 		m.textarea.SetValue(msg.Text)
 		m.textarea.MoveToEnd()
+	case QuestionShownMsg:
+		m.timer.questionShown(time.Now())
+		return m, nil
 	case tea.PasteMsg:
 		// If pasted text has more than 2 newlines, treat it as a file attachment.
 		if strings.Count(msg.Content, "\n") > 2 {
+			m.timer.paste(time.Now(), utf8.RuneCountInString(msg.Content))
 			content := []byte(msg.Content)
 			if len(content) > maxAttachmentSize {
 				return m, util.ReportWarn("Paste is too big (>5mb)")
@@ -257,6 +303,7 @@ func (m *editorCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 		content, path, err := filepathToFile(msg.Content)
 		if err != nil {
 			// Not a file path, just update the textarea normally.
+			m.timer.paste(time.Now(), utf8.RuneCountInString(msg.Content))
 			m.textarea, cmd = m.textarea.Update(msg)
 			return m, cmd
 		}
@@ -286,11 +333,12 @@ func (m *editorCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 		cur := m.textarea.Cursor()
 		curIdx := m.textarea.Width()*cur.Y + cur.X
 		switch {
-		// Open command palette when "/" is pressed on empty prompt
-		case msg.String() == "/" && m.IsEmpty():
-			return m, util.CmdHandler(dialogs.OpenDialogMsg{
-				Model: commands.NewCommandDialog(m.session.ID),
-			})
+		// Complete slash commands when "/" is pressed on empty prompt
+		case msg.String() == uicmd.SlashPrefix && m.IsEmpty() && !m.isCompletionsOpen:
+			m.isCompletionsOpen = true
+			m.currentQuery = ""
+			m.completionsStartIndex = 0
+			cmds = append(cmds, m.startSlashCompletions)
 		// Completions
 		case msg.String() == "@" && !m.isCompletionsOpen &&
 			// only show if beginning of prompt, or if previous char is a space or newline:
@@ -357,6 +405,9 @@ func (m *editorCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 	if m.textarea.Focused() {
 		kp, ok := msg.(tea.KeyPressMsg)
 		if ok {
+			if deletion := m.isDeletion(kp); deletion || kp.Text != "" || key.Matches(kp, m.keyMap.Newline) {
+				m.timer.key(time.Now(), deletion)
+			}
 			if kp.String() == "space" || m.textarea.Value() == "" {
 				m.isCompletionsOpen = false
 				m.currentQuery = ""
@@ -364,7 +415,21 @@ func (m *editorCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 				cmds = append(cmds, util.CmdHandler(completions.CloseCompletionsMsg{}))
 			} else {
 				word := m.textarea.Word()
-				if strings.HasPrefix(word, "@") {
+				if value := m.textarea.Value(); strings.HasPrefix(value, uicmd.SlashPrefix) && !strings.ContainsAny(value, " \n") {
+					m.completionsStartIndex = 0
+					m.currentQuery = value[len(uicmd.SlashPrefix):]
+					x, y := m.completionsPosition()
+					x -= len(m.currentQuery)
+					m.isCompletionsOpen = true
+					cmds = append(cmds,
+						util.CmdHandler(completions.FilterCompletionsMsg{
+							Query:  m.currentQuery,
+							Reopen: m.isCompletionsOpen,
+							X:      x,
+							Y:      y,
+						}),
+					)
+				} else if strings.HasPrefix(word, "@") {
 					// XXX: wont' work if editing in the middle of the field.
 					m.completionsStartIndex = strings.LastIndex(m.textarea.Value(), word)
 					m.currentQuery = word[1:]
@@ -392,6 +457,18 @@ func (m *editorCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+func (m *editorCmp) isDeletion(msg tea.KeyPressMsg) bool {
+	km := m.textarea.KeyMap
+	return key.Matches(msg,
+		km.DeleteAfterCursor,
+		km.DeleteBeforeCursor,
+		km.DeleteCharacterBackward,
+		km.DeleteCharacterForward,
+		km.DeleteWordBackward,
+		km.DeleteWordForward,
+	)
+}
+
 func (m *editorCmp) setEditorPrompt() {
 	if m.app.Permissions.SkipRequests() {
 		m.textarea.SetPromptFunc(4, yoloPromptFunc)
@@ -552,6 +629,26 @@ func (m *editorCmp) startCompletions() tea.Msg {
 	}
 }
 
+func (m *editorCmp) startSlashCompletions() tea.Msg {
+	commands := uicmd.SlashCommands(m.app.Config())
+	completionItems := make([]completions.Completion, 0, len(commands))
+	for _, command := range commands {
+		completionItems = append(completionItems, completions.Completion{
+			Title: uicmd.SlashPrefix + command.ID,
+			Value: SlashCompletionItem{
+				Name: command.ID,
+			},
+		})
+	}
+
+	x, y := m.completionsPosition()
+	return completions.OpenCompletionsMsg{
+		Completions: completionItems,
+		X:           x,
+		Y:           y,
+	}
+}
+
 // Blur implements Container.
 func (c *editorCmp) Blur() tea.Cmd {
 	c.textarea.Blur()
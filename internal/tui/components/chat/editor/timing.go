@@ -0,0 +1,76 @@
+package editor
+
+import (
+	"time"
+
+	"github.com/trankhanh040147/prepf/internal/message"
+)
+
+// QuestionShownMsg tells the editor the model finished replying, which is
+// when the time spent thinking about the answer starts.
+type QuestionShownMsg struct{}
+
+// answerTimer records how the user composes a message so the timing can be
+// attached to it.
+type answerTimer struct {
+	shownAt     time.Time
+	firstKeyAt  time.Time
+	keystrokes  int
+	backspaces  int
+	pastes      int
+	pastedChars int
+}
+
+// questionShown records when the last reply finished. Replies finishing
+// after the user started typing don't restart the thinking time.
+func (t *answerTimer) questionShown(now time.Time) {
+	if t.firstKeyAt.IsZero() {
+		t.shownAt = now
+	}
+}
+
+func (t *answerTimer) start(now time.Time) {
+	if t.firstKeyAt.IsZero() {
+		t.firstKeyAt = now
+	}
+}
+
+func (t *answerTimer) key(now time.Time, deletion bool) {
+	t.start(now)
+	t.keystrokes++
+	if deletion {
+		t.backspaces++
+	}
+}
+
+func (t *answerTimer) paste(now time.Time, chars int) {
+	t.start(now)
+	t.pastes++
+	t.pastedChars += chars
+}
+
+// discard forgets the input composed so far, e.g. after running a slash
+// command, but keeps when the question was shown.
+func (t *answerTimer) discard() {
+	*t = answerTimer{shownAt: t.shownAt}
+}
+
+// finish returns the timing of the message being sent and resets the timer.
+// It returns nil if nothing was typed or pasted.
+func (t *answerTimer) finish(now time.Time) *message.AnswerTiming {
+	defer func() { *t = answerTimer{} }()
+	if t.firstKeyAt.IsZero() {
+		return nil
+	}
+	timing := &message.AnswerTiming{
+		ComposeMs:   now.Sub(t.firstKeyAt).Milliseconds(),
+		Keystrokes:  t.keystrokes,
+		Backspaces:  t.backspaces,
+		Pastes:      t.pastes,
+		PastedChars: t.pastedChars,
+	}
+	if !t.shownAt.IsZero() && t.shownAt.Before(t.firstKeyAt) {
+		timing.ThinkMs = t.firstKeyAt.Sub(t.shownAt).Milliseconds()
+	}
+	return timing
+}
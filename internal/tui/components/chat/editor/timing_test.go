@@ -0,0 +1,47 @@
+package editor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/message"
+)
+
+func TestAnswerTimer(t *testing.T) {
+	t.Parallel()
+
+	var timer answerTimer
+	shown := time.Now()
+	timer.questionShown(shown)
+	require.Nil(t, timer.finish(shown.Add(time.Second)))
+
+	timer.questionShown(shown)
+	timer.key(shown.Add(5*time.Second), false)
+	// A reply finishing after typing started doesn't restart the clock.
+	timer.questionShown(shown.Add(6 * time.Second))
+	timer.key(shown.Add(7*time.Second), false)
+	timer.key(shown.Add(8*time.Second), true)
+	timer.paste(shown.Add(9*time.Second), 120)
+
+	require.Equal(t, &message.AnswerTiming{
+		ThinkMs:     5000,
+		ComposeMs:   5000,
+		Keystrokes:  3,
+		Backspaces:  1,
+		Pastes:      1,
+		PastedChars: 120,
+	}, timer.finish(shown.Add(10*time.Second)))
+
+	// Sending resets the timer, so the next answer has no thinking time
+	// until the next reply is shown.
+	timer.key(shown.Add(11*time.Second), false)
+	require.Equal(t, &message.AnswerTiming{ComposeMs: 1000, Keystrokes: 1}, timer.finish(shown.Add(12*time.Second)))
+
+	// Slash commands don't count towards the answer.
+	timer.questionShown(shown)
+	timer.key(shown.Add(time.Second), false)
+	timer.discard()
+	timer.key(shown.Add(3*time.Second), false)
+	require.Equal(t, int64(3000), timer.finish(shown.Add(4*time.Second)).ThinkMs)
+}
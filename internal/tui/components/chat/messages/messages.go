@@ -16,6 +16,8 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/atotto/clipboard"
+	"github.com/trankhanh040147/prepf/internal/agent"
+	"github.com/trankhanh040147/prepf/internal/annotation"
 	"github.com/trankhanh040147/prepf/internal/config"
 	"github.com/trankhanh040147/prepf/internal/message"
 	"github.com/trankhanh040147/prepf/internal/tui/components/anim"
@@ -29,6 +31,28 @@ import (
 // CopyKey is the key binding for copying message content to the clipboard.
 var CopyKey = key.NewBinding(key.WithKeys("c", "y", "C", "Y"), key.WithHelp("c/y", "copy"))
 
+// RevertKey is the key binding for reverting the file changes of a message
+// or tool call.
+var RevertKey = key.NewBinding(key.WithKeys("r", "R"), key.WithHelp("r", "revert files"))
+
+// RevertMsg asks for the file changes of a tool call to be reverted or, when
+// only MessageID is set, those of the message and every message after it.
+type RevertMsg struct {
+	MessageID  string
+	ToolCallID string
+}
+
+// AnnotateKey is the key binding for leaving a review comment on a message.
+var AnnotateKey = key.NewBinding(key.WithKeys("a", "A"), key.WithHelp("a", "annotate"))
+
+// AnnotateMsg asks for a review comment on a message.
+type AnnotateMsg struct {
+	SessionID string
+	MessageID string
+	// Text is the message text, which a comment on part of it quotes.
+	Text string
+}
+
 // ClearSelectionKey is the key binding for clearing the current selection in the chat interface.
 var ClearSelectionKey = key.NewBinding(key.WithKeys("esc", "alt+esc"), key.WithHelp("esc", "clear selection"))
 
@@ -42,6 +66,7 @@ type MessageCmp interface {
 	SetMessage(msg message.Message) // Update the message content
 	Spinning() bool                 // Animation state for loading messages
 	ID() string
+	SetAnnotations(annotations []annotation.Annotation)
 }
 
 // messageCmp implements the MessageCmp interface for displaying chat messages.
@@ -58,6 +83,9 @@ type messageCmp struct {
 
 	// Thinking viewport for displaying reasoning content
 	thinkingViewport viewport.Model
+
+	// Review comments left on the message, shown below it
+	annotations []annotation.Annotation
 }
 
 var focusedMessageBorder = lipgloss.Border{
@@ -114,6 +142,16 @@ func (m *messageCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 				util.ReportInfo("Message copied to clipboard"),
 			)
 		}
+		if key.Matches(msg, RevertKey) {
+			return m, util.CmdHandler(RevertMsg{MessageID: m.message.ID})
+		}
+		if key.Matches(msg, AnnotateKey) {
+			return m, util.CmdHandler(AnnotateMsg{
+				SessionID: m.message.SessionID,
+				MessageID: m.message.ID,
+				Text:      m.message.Content().Text,
+			})
+		}
 	}
 	return m, nil
 }
@@ -148,6 +186,10 @@ func (m *messageCmp) SetMessage(msg message.Message) {
 	m.message = msg
 }
 
+func (m *messageCmp) SetAnnotations(annotations []annotation.Annotation) {
+	m.annotations = annotations
+}
+
 // textWidth calculates the available width for text content,
 // accounting for borders and padding
 func (m *messageCmp) textWidth() int {
@@ -215,6 +257,10 @@ func (m *messageCmp) renderAssistantMessage() string {
 		parts = append(parts, m.toMarkdown(content))
 	}
 
+	if annotations := m.renderAnnotations(); annotations != "" {
+		parts = append(parts, "", annotations)
+	}
+
 	joined := lipgloss.JoinVertical(lipgloss.Left, parts...)
 	return m.style().Render(joined)
 }
@@ -226,6 +272,10 @@ func (m *messageCmp) renderUserMessage() string {
 	var parts []string
 
 	if s := m.message.Content().String(); s != "" {
+		// Show interview controls the way they were typed.
+		if control, ok := agent.ParseControl(s); ok {
+			s = strings.TrimSpace("`/" + control.Action + "` " + control.Args)
+		}
 		parts = append(parts, m.toMarkdown(s))
 	}
 
@@ -261,10 +311,42 @@ func (m *messageCmp) renderUserMessage() string {
 		parts = append(parts, strings.Join(attachments, ""))
 	}
 
+	if annotations := m.renderAnnotations(); annotations != "" {
+		parts = append(parts, "", annotations)
+	}
+
 	joined := lipgloss.JoinVertical(lipgloss.Left, parts...)
 	return m.style().Render(joined)
 }
 
+// renderAnnotations renders the review comments on the message, each with
+// its author and the part of the message it quotes.
+func (m *messageCmp) renderAnnotations() string {
+	if len(m.annotations) == 0 {
+		return ""
+	}
+	t := styles.CurrentTheme()
+	width := m.textWidth() - 4
+	text := m.message.Content().Text
+
+	var lines []string
+	for _, a := range m.annotations {
+		header := t.S().Base.Foreground(t.Warning).Bold(true).Render("✎ " + a.Author)
+		if quote := a.Quote(text); quote != "" {
+			quote = strings.Join(strings.Fields(quote), " ")
+			quote = ansi.Truncate(quote, max(width-lipgloss.Width(header)-6, 10), "…")
+			header += t.S().Subtle.Render(" on “" + quote + "”")
+		}
+		lines = append(lines, header, t.S().Text.Width(width).Render(a.Body))
+	}
+	return t.S().Base.
+		BorderLeft(true).
+		BorderStyle(lipgloss.ThickBorder()).
+		BorderForeground(t.Warning).
+		PaddingLeft(1).
+		Render(strings.Join(lines, "\n"))
+}
+
 // toMarkdown converts text content to rendered markdown using the configured renderer
 func (m *messageCmp) toMarkdown(content string) string {
 	r := styles.GetMarkdownRenderer(m.textWidth())
@@ -199,6 +199,12 @@ func init() {
 	registry.register(tools.SourcegraphToolName, func() renderer { return sourcegraphRenderer{} })
 	registry.register(tools.DiagnosticsToolName, func() renderer { return diagnosticsRenderer{} })
 	registry.register(tools.TodosToolName, func() renderer { return todosRenderer{} })
+	registry.register(tools.DrillQuestionToolName, func() renderer { return drillRenderer{} })
+	registry.register(tools.DrillGradeToolName, func() renderer { return drillRenderer{} })
+	registry.register(tools.FlashcardsToolName, func() renderer { return flashcardsRenderer{} })
+	registry.register(tools.QuizToolName, func() renderer { return quizRenderer{} })
+	registry.register(tools.ReviewExerciseToolName, func() renderer { return reviewExerciseRenderer{} })
+	registry.register(tools.SQLExerciseToolName, func() renderer { return sqlExerciseRenderer{} })
 	registry.register(agent.AgentToolName, func() renderer { return agentRenderer{} })
 }
 
@@ -1302,6 +1308,18 @@ func prettifyToolName(name string) string {
 		return "Sourcegraph"
 	case tools.TodosToolName:
 		return "To-Do"
+	case tools.DrillQuestionToolName:
+		return "Drill: Question"
+	case tools.DrillGradeToolName:
+		return "Drill: Grade"
+	case tools.FlashcardsToolName:
+		return "Flashcards"
+	case tools.QuizToolName:
+		return "Quiz"
+	case tools.ReviewExerciseToolName:
+		return "Review Exercise"
+	case tools.SQLExerciseToolName:
+		return "SQL Exercise"
 	case tools.ViewToolName:
 		return "View"
 	case tools.WriteToolName:
@@ -1311,6 +1329,158 @@ func prettifyToolName(name string) string {
 	}
 }
 
+// -----------------------------------------------------------------------------
+//  Drill renderer
+// -----------------------------------------------------------------------------
+
+// drillRenderer shows drill bookkeeping without the parameters, which would
+// reveal the hints and answer before the user asks for them.
+type drillRenderer struct {
+	baseRenderer
+}
+
+func (dr drillRenderer) Render(v *toolCallCmp) string {
+	var meta tools.DrillResponseMetadata
+	var args []string
+	if v.result.Metadata != "" && dr.unmarshalParams(v.result.Metadata, &meta) == nil {
+		if v.call.Name == tools.DrillGradeToolName {
+			args = append(args, fmt.Sprintf("%d/100", meta.Score))
+		}
+		args = append(args, meta.Topic)
+	}
+
+	pb := newParamBuilder().addMain(strings.Join(args, " · ")).addKeyValue("rubric", meta.Rubric)
+	if meta.Agreement != nil {
+		agreement := fmt.Sprintf("%.0f%% of %d", 100*(*meta.Agreement), meta.Graders)
+		if meta.Flagged {
+			agreement += ", flagged"
+		}
+		pb.addKeyValue("agreement", agreement)
+	}
+	params := pb.build()
+	return dr.renderWithParams(v, prettifyToolName(v.call.Name), params, func() string {
+		if v.call.Name != tools.DrillGradeToolName {
+			return ""
+		}
+		return renderPlainContent(v, v.result.Content)
+	})
+}
+
+// -----------------------------------------------------------------------------
+//  Flashcards renderer
+// -----------------------------------------------------------------------------
+
+// flashcardsRenderer lists the fronts of the saved cards, leaving the backs
+// for the review page.
+type flashcardsRenderer struct {
+	baseRenderer
+}
+
+func (fr flashcardsRenderer) Render(v *toolCallCmp) string {
+	var params tools.FlashcardsParams
+	var args []string
+	if err := fr.unmarshalParams(v.call.Input, &params); err == nil {
+		args = newParamBuilder().
+			addMain(params.Topic).
+			addKeyValue("cards", fmt.Sprint(len(params.Cards))).
+			build()
+	}
+
+	return fr.renderWithParams(v, prettifyToolName(v.call.Name), args, func() string {
+		var meta tools.FlashcardsResponseMetadata
+		if v.result.Metadata == "" || fr.unmarshalParams(v.result.Metadata, &meta) != nil {
+			return renderPlainContent(v, v.result.Content)
+		}
+		return renderPlainContent(v, "- "+strings.Join(meta.Fronts, "\n- "))
+	})
+}
+
+// -----------------------------------------------------------------------------
+//  Quiz renderer
+// -----------------------------------------------------------------------------
+
+// quizRenderer shows the saved quiz without its questions, so the answers
+// aren't spoiled before the user takes it.
+type quizRenderer struct {
+	baseRenderer
+}
+
+func (qr quizRenderer) Render(v *toolCallCmp) string {
+	var params tools.QuizParams
+	var args []string
+	if err := qr.unmarshalParams(v.call.Input, &params); err == nil {
+		args = newParamBuilder().
+			addMain(params.Title).
+			addKeyValue("questions", fmt.Sprint(len(params.Questions))).
+			build()
+	}
+
+	return qr.renderWithParams(v, prettifyToolName(v.call.Name), args, func() string {
+		var meta tools.QuizResponseMetadata
+		if v.result.Metadata == "" || qr.unmarshalParams(v.result.Metadata, &meta) != nil {
+			return renderPlainContent(v, v.result.Content)
+		}
+		return renderPlainContent(v, "Start it with /quiz "+meta.Name)
+	})
+}
+
+// -----------------------------------------------------------------------------
+//  Review exercise renderer
+// -----------------------------------------------------------------------------
+
+// reviewExerciseRenderer shows the saved exercise without the change or its
+// seeded issues, so they aren't spoiled before the user reviews it.
+type reviewExerciseRenderer struct {
+	baseRenderer
+}
+
+func (rr reviewExerciseRenderer) Render(v *toolCallCmp) string {
+	var params tools.ReviewExerciseParams
+	var args []string
+	if err := rr.unmarshalParams(v.call.Input, &params); err == nil {
+		args = newParamBuilder().
+			addMain(params.Title).
+			addKeyValue("file", params.File).
+			build()
+	}
+
+	return rr.renderWithParams(v, prettifyToolName(v.call.Name), args, func() string {
+		var meta tools.ReviewExerciseResponseMetadata
+		if v.result.Metadata == "" || rr.unmarshalParams(v.result.Metadata, &meta) != nil {
+			return renderPlainContent(v, v.result.Content)
+		}
+		return renderPlainContent(v, "Start it with /review "+meta.Name)
+	})
+}
+
+// -----------------------------------------------------------------------------
+//  SQL exercise renderer
+// -----------------------------------------------------------------------------
+
+// sqlExerciseRenderer shows the saved exercise without its reference queries.
+type sqlExerciseRenderer struct {
+	baseRenderer
+}
+
+func (sr sqlExerciseRenderer) Render(v *toolCallCmp) string {
+	var params tools.SQLExerciseParams
+	var args []string
+	if err := sr.unmarshalParams(v.call.Input, &params); err == nil {
+		args = newParamBuilder().
+			addMain(params.Title).
+			addKeyValue("tasks", fmt.Sprint(len(params.Tasks))).
+			build()
+	}
+
+	return sr.renderWithParams(v, prettifyToolName(v.call.Name), args, func() string {
+		var meta tools.SQLExerciseResponseMetadata
+		if v.result.Metadata == "" || sr.unmarshalParams(v.result.Metadata, &meta) != nil {
+			return renderPlainContent(v, v.result.Content)
+		}
+		return renderPlainContent(v, "Start it with /sql "+meta.Name)
+	})
+}
+
 // -----------------------------------------------------------------------------
 //  Todos renderer
 // -----------------------------------------------------------------------------
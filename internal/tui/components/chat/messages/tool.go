@@ -168,6 +168,9 @@ func (m *toolCallCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 		if key.Matches(msg, CopyKey) {
 			return m, m.copyTool()
 		}
+		if key.Matches(msg, RevertKey) && m.changesFiles() {
+			return m, util.CmdHandler(RevertMsg{ToolCallID: m.call.ID})
+		}
 	}
 	return m, nil
 }
@@ -196,6 +199,16 @@ func (m *toolCallCmp) SetCancelled() {
 	m.cancelled = true
 }
 
+// changesFiles reports whether the tool call wrote files the history can
+// revert.
+func (m *toolCallCmp) changesFiles() bool {
+	switch m.call.Name {
+	case tools.EditToolName, tools.MultiEditToolName, tools.WriteToolName:
+		return m.call.Finished && !m.result.IsError
+	}
+	return false
+}
+
 func (m *toolCallCmp) copyTool() tea.Cmd {
 	content := m.formatToolForCopy()
 	return tea.Sequence(
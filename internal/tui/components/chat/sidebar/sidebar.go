@@ -162,6 +162,9 @@ func (m *sidebarCmp) View() string {
 		if m.session.ID != "" {
 			parts = append(parts, "", m.filesBlock())
 		}
+		if len(m.session.Skills) > 0 {
+			parts = append(parts, "", m.skillsBlock())
+		}
 		parts = append(parts,
 			"",
 			m.lspBlock(),
@@ -505,6 +508,22 @@ func (m *sidebarCmp) mcpBlock() string {
 	}, true)
 }
 
+// skillsBlock lists the skills activated for the session.
+func (m *sidebarCmp) skillsBlock() string {
+	t := styles.CurrentTheme()
+	maxWidth := m.getMaxWidth()
+	lines := []string{t.S().Subtle.Render(core.Section("Skills", maxWidth)), ""}
+	for _, name := range m.session.SkillNames() {
+		lines = append(lines, core.Status(core.StatusOpts{
+			Icon:  t.ItemOnlineIcon.String(),
+			Title: name,
+		}, maxWidth))
+	}
+	return lipgloss.NewStyle().Width(maxWidth).Render(
+		lipgloss.JoinVertical(lipgloss.Left, lines...),
+	)
+}
+
 func formatTokensAndCost(tokens, contextWindow int64, cost float64) string {
 	t := styles.CurrentTheme()
 	// Format tokens in human-readable format (e.g., 110K, 1.2M)
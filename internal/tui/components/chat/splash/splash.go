@@ -10,10 +10,13 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/catwalk/pkg/catwalk"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/trankhanh040147/prepf/internal/agent"
 	hyperp "github.com/trankhanh040147/prepf/internal/agent/hyper"
 	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/daily"
 	"github.com/trankhanh040147/prepf/internal/home"
+	"github.com/trankhanh040147/prepf/internal/plan"
 	"github.com/trankhanh040147/prepf/internal/tui/components/chat"
 	"github.com/trankhanh040147/prepf/internal/tui/components/core"
 	"github.com/trankhanh040147/prepf/internal/tui/components/core/layout"
@@ -38,6 +41,11 @@ type Splash interface {
 	SetOnboarding(bool)
 	// SetProjectInit controls whether the splash shows project initialization prompt
 	SetProjectInit(bool)
+	// SetDaily shows today's challenge and the streak.
+	SetDaily(daily.Challenge, daily.Streak)
+	// SetPlan shows today's practice plan items and how many items of
+	// past days were missed.
+	SetPlan(today []plan.Item, missed int)
 
 	// Showing API key input
 	IsShowingAPIKey() bool
@@ -89,6 +97,13 @@ type splashCmp struct {
 	// Copilot device flow state
 	copilotDeviceFlow     *copilot.DeviceFlow
 	showCopilotDeviceFlow bool
+
+	// Today's challenge, nil until loaded
+	daily  *daily.Challenge
+	streak daily.Streak
+	// plan holds today's practice plan items.
+	plan   []plan.Item
+	missed int
 }
 
 func New() Splash {
@@ -125,6 +140,16 @@ func (s *splashCmp) SetProjectInit(needsInit bool) {
 	s.needsProjectInit = needsInit
 }
 
+func (s *splashCmp) SetDaily(c daily.Challenge, streak daily.Streak) {
+	s.daily = &c
+	s.streak = streak
+}
+
+func (s *splashCmp) SetPlan(today []plan.Item, missed int) {
+	s.plan = today
+	s.missed = missed
+}
+
 // GetSize implements SplashPage.
 func (s *splashCmp) GetSize() (int, int) {
 	return s.width, s.height
@@ -688,17 +713,85 @@ func (s *splashCmp) infoSection() string {
 	if s.isSmallScreen() {
 		infoStyle = infoStyle.MarginTop(1)
 	}
-	return infoStyle.Render(
-		lipgloss.JoinVertical(
-			lipgloss.Left,
-			s.cwdPart(),
-			"",
-			s.currentModelBlock(),
-			"",
-			lipgloss.JoinHorizontal(lipgloss.Left, s.lspBlock(), s.mcpBlock()),
-			"",
-		),
+	parts := []string{
+		s.cwdPart(),
+		"",
+		s.currentModelBlock(),
+		"",
+	}
+	if block := s.dailyBlock(); block != "" {
+		parts = append(parts, block, "")
+	}
+	if block := s.planBlock(); block != "" {
+		parts = append(parts, block, "")
+	}
+	parts = append(parts,
+		lipgloss.JoinHorizontal(lipgloss.Left, s.lspBlock(), s.mcpBlock()),
+		"",
 	)
+	return infoStyle.Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
+
+func (s *splashCmp) dailyBlock() string {
+	if s.daily == nil {
+		return ""
+	}
+	t := styles.CurrentTheme()
+	maxWidth := s.getMaxInfoWidth()
+	c := s.daily
+
+	question, _, _ := strings.Cut(c.Question, "\n")
+	switch {
+	case question != "":
+	case c.Topic != "":
+		question = "A new question on " + c.Topic
+	default:
+		question = "A new question on a topic of the interviewer's choosing"
+	}
+
+	status := t.S().Muted.Render("/daily to answer")
+	if c.Done() {
+		status = t.S().Base.Foreground(t.Success).Render(fmt.Sprintf("Done, scored %d", *c.Score))
+	}
+	streak := fmt.Sprintf("%d day streak", s.streak.Current)
+	if s.streak.FreezesLeft > 0 {
+		streak += fmt.Sprintf(", %d freezes left", s.streak.FreezesLeft)
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		t.S().Subtle.Render("Daily Challenge"),
+		"",
+		t.S().Text.Render(ansi.Truncate(question, maxWidth, "…")),
+		status+t.S().Muted.Render(" · "+streak),
+	)
+}
+
+func (s *splashCmp) planBlock() string {
+	if len(s.plan) == 0 && s.missed == 0 {
+		return ""
+	}
+	t := styles.CurrentTheme()
+	maxWidth := s.getMaxInfoWidth()
+
+	lines := []string{t.S().Subtle.Render("Today's Plan"), ""}
+	for i, item := range s.plan {
+		status := t.S().Muted.Render(fmt.Sprintf(" · %d min · %d/%d", item.Minutes, item.Graded, item.Questions))
+		if item.Done() {
+			status = t.S().Muted.Render(fmt.Sprintf(" · %d min · ", item.Minutes)) + t.S().Base.Foreground(t.Success).Render("✓")
+		}
+		title := ansi.Truncate(fmt.Sprintf("%d. %s", i+1, item.Title()), maxWidth-lipgloss.Width(status), "…")
+		lines = append(lines, t.S().Text.Render(title)+status)
+	}
+	if len(s.plan) == 0 {
+		lines = append(lines, t.S().Text.Render("Nothing planned for today"))
+	}
+	hint := "/plan or /plan N to start"
+	if s.missed > 0 {
+		hint = fmt.Sprintf("%d missed, run prepf plan generate to reschedule", s.missed)
+	}
+	lines = append(lines, t.S().Muted.Render(ansi.Truncate(hint, maxWidth, "…")))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
 func (s *splashCmp) logoBlock() string {
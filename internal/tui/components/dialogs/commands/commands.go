@@ -48,6 +48,13 @@ type (
 	Command                         = uicmd.Command
 	CommandRunCustomMsg             = uicmd.CommandRunCustomMsg
 	ShowMCPPromptArgumentsDialogMsg = uicmd.ShowMCPPromptArgumentsDialogMsg
+	ActivateSkillMsg                = uicmd.ActivateSkillMsg
+	StartQuizMsg                    = uicmd.StartQuizMsg
+	StartReviewMsg                  = uicmd.StartReviewMsg
+	StartSQLMsg                     = uicmd.StartSQLMsg
+	StartDebugMsg                   = uicmd.StartDebugMsg
+	StartDailyMsg                   = uicmd.StartDailyMsg
+	StartPlanMsg                    = uicmd.StartPlanMsg
 )
 
 // CommandsDialog represents the commands dialog.
@@ -81,9 +88,24 @@ type (
 	OpenReasoningDialogMsg struct{}
 	OpenExternalEditorMsg  struct{}
 	ToggleYoloModeMsg      struct{}
+	ReviewFlashcardsMsg    struct{}
+	OpenWeaknessMapMsg     struct{}
+	OpenRubricsMsg         struct{}
+	SwitchThemeMsg         struct{}
 	CompactMsg             struct {
 		SessionID string
 	}
+	// ToggleReviewedMsg marks a session reviewed, or no longer reviewed if
+	// it already is.
+	ToggleReviewedMsg struct {
+		SessionID string
+	}
+	// StartGymMsg starts a gym session with the given title, opening it with
+	// Text.
+	StartGymMsg struct {
+		Title string
+		Text  string
+	}
 )
 
 func NewCommandDialog(sessionID string) CommandsDialog {
@@ -353,8 +375,30 @@ func (c *commandDialogCmp) defaultCommands() []Command {
 				})
 			},
 		})
+		commands = append(commands, Command{
+			ID:          "toggle_reviewed",
+			Title:       "Toggle Session Reviewed",
+			Description: "Mark the current session reviewed, signed with your name, or clear it",
+			Handler: func(cmd Command) tea.Cmd {
+				return util.CmdHandler(ToggleReviewedMsg{
+					SessionID: c.sessionID,
+				})
+			},
+		})
+	}
+
+	// Skills can only be activated for an existing session
+	if c.sessionID != "" {
+		commands = append(commands, uicmd.SkillCommands(config.Get())...)
 	}
 
+	commands = append(commands, uicmd.DailyCommands()...)
+	commands = append(commands, uicmd.PlanCommands()...)
+	commands = append(commands, uicmd.QuizCommands(config.Get())...)
+	commands = append(commands, uicmd.ReviewCommands(config.Get())...)
+	commands = append(commands, uicmd.SQLCommands(config.Get())...)
+	commands = append(commands, uicmd.DebugCommands(config.Get())...)
+
 	// Add reasoning toggle for models that support it
 	cfg := config.Get()
 	if agentCfg, ok := cfg.Agents[config.AgentCoder]; ok {
@@ -433,6 +477,38 @@ func (c *commandDialogCmp) defaultCommands() []Command {
 	}
 
 	return append(commands, []Command{
+		{
+			ID:          "review_flashcards",
+			Title:       "Review Flashcards",
+			Description: "Review the flashcards due today",
+			Handler: func(cmd Command) tea.Cmd {
+				return util.CmdHandler(ReviewFlashcardsMsg{})
+			},
+		},
+		{
+			ID:          "weakness_map",
+			Title:       "Weakness Map",
+			Description: "Show how well each topic went across all sessions",
+			Handler: func(cmd Command) tea.Cmd {
+				return util.CmdHandler(OpenWeaknessMapMsg{})
+			},
+		},
+		{
+			ID:          "edit_rubrics",
+			Title:       "Edit Rubrics",
+			Description: "View and edit the rubrics answers are graded against",
+			Handler: func(cmd Command) tea.Cmd {
+				return util.CmdHandler(OpenRubricsMsg{})
+			},
+		},
+		{
+			ID:          "switch_theme",
+			Title:       "Switch Theme",
+			Description: "Choose the TUI theme, previewing each one",
+			Handler: func(cmd Command) tea.Cmd {
+				return util.CmdHandler(SwitchThemeMsg{})
+			},
+		},
 		{
 			ID:          "toggle_yolo",
 			Title:       "Toggle Yolo Mode",
@@ -0,0 +1,140 @@
+// Package confirm provides a dialog asking the user to confirm an action
+// before it is carried out.
+package confirm
+
+import (
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs"
+	"github.com/trankhanh040147/prepf/internal/tui/styles"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+const ConfirmDialogID dialogs.DialogID = "confirm"
+
+// ConfirmDialog represents a yes/no dialog for an action.
+type ConfirmDialog interface {
+	dialogs.DialogModel
+}
+
+type confirmDialogCmp struct {
+	wWidth  int
+	wHeight int
+
+	question  string
+	details   string
+	onConfirm tea.Cmd
+
+	selectedNo bool // true if "No" button is selected
+	keymap     KeyMap
+}
+
+// NewConfirmDialog creates a dialog asking the question, with details on
+// what the action does below it. onConfirm runs when the user says yes.
+func NewConfirmDialog(question, details string, onConfirm tea.Cmd) ConfirmDialog {
+	return &confirmDialogCmp{
+		question:   question,
+		details:    details,
+		onConfirm:  onConfirm,
+		selectedNo: true, // Default to "No" for safety
+		keymap:     DefaultKeymap(),
+	}
+}
+
+func (c *confirmDialogCmp) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles keyboard input for the confirmation dialog.
+func (c *confirmDialogCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.wWidth = msg.Width
+		c.wHeight = msg.Height
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, c.keymap.LeftRight, c.keymap.Tab):
+			c.selectedNo = !c.selectedNo
+			return c, nil
+		case key.Matches(msg, c.keymap.EnterSpace):
+			if !c.selectedNo {
+				return c, c.confirm()
+			}
+			return c, util.CmdHandler(dialogs.CloseDialogMsg{})
+		case key.Matches(msg, c.keymap.Yes):
+			return c, c.confirm()
+		case key.Matches(msg, c.keymap.No, c.keymap.Close):
+			return c, util.CmdHandler(dialogs.CloseDialogMsg{})
+		}
+	}
+	return c, nil
+}
+
+func (c *confirmDialogCmp) confirm() tea.Cmd {
+	return tea.Sequence(util.CmdHandler(dialogs.CloseDialogMsg{}), c.onConfirm)
+}
+
+// width is the width of the dialog content: the widest line, within the
+// window.
+func (c *confirmDialogCmp) width() int {
+	width := max(lipgloss.Width(c.question), lipgloss.Width(c.details))
+	if c.wWidth > 0 {
+		width = min(width, c.wWidth-8)
+	}
+	return width
+}
+
+// View renders the question and details with Yes/No buttons.
+func (c *confirmDialogCmp) View() string {
+	t := styles.CurrentTheme()
+	baseStyle := t.S().Base
+	yesStyle := t.S().Text
+	noStyle := yesStyle
+
+	if c.selectedNo {
+		noStyle = noStyle.Foreground(t.White).Background(t.Secondary)
+		yesStyle = yesStyle.Background(t.BgSubtle)
+	} else {
+		yesStyle = yesStyle.Foreground(t.White).Background(t.Secondary)
+		noStyle = noStyle.Background(t.BgSubtle)
+	}
+
+	const horizontalPadding = 3
+	yesButton := yesStyle.PaddingLeft(horizontalPadding).Underline(true).Render("Y") +
+		yesStyle.PaddingRight(horizontalPadding).Render("es")
+	noButton := noStyle.PaddingLeft(horizontalPadding).Underline(true).Render("N") +
+		noStyle.PaddingRight(horizontalPadding).Render("o")
+
+	width := c.width()
+	buttons := baseStyle.Width(width).Align(lipgloss.Right).Render(
+		lipgloss.JoinHorizontal(lipgloss.Center, yesButton, "  ", noButton),
+	)
+
+	parts := []string{baseStyle.Width(width).Render(c.question)}
+	if c.details != "" {
+		parts = append(parts, "", t.S().Muted.Width(width).Render(c.details))
+	}
+	parts = append(parts, "", buttons)
+	content := baseStyle.Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+
+	confirmDialogStyle := baseStyle.
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.BorderFocus)
+
+	return confirmDialogStyle.Render(content)
+}
+
+func (c *confirmDialogCmp) Position() (int, int) {
+	row := c.wHeight / 2
+	row -= lipgloss.Height(c.View()) / 2
+	col := c.wWidth / 2
+	col -= (c.width() + 6) / 2
+
+	return max(row, 0), max(col, 0)
+}
+
+func (c *confirmDialogCmp) ID() dialogs.DialogID {
+	return ConfirmDialogID
+}
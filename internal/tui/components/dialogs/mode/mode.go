@@ -1,10 +1,14 @@
 package mode
 
 import (
+	"fmt"
+	"strings"
+
 	"charm.land/bubbles/v2/help"
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/trankhanh040147/prepf/internal/pack"
 	"github.com/trankhanh040147/prepf/internal/tui/components/core"
 	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs"
 	"github.com/trankhanh040147/prepf/internal/tui/styles"
@@ -21,6 +25,10 @@ type ModeOption struct {
 	ID   string
 	Name string
 	Desc string
+	// Pack and Round name the interview pack round the option starts, if
+	// any.
+	Pack  string
+	Round int
 }
 
 var (
@@ -46,13 +54,18 @@ type modeDialogCmp struct {
 	help          help.Model
 }
 
-func NewModeDialogCmp() ModeDialog {
+// NewModeDialogCmp returns the mode selector, offering a round of each
+// interview pack after the plain modes.
+func NewModeDialogCmp(packs []*pack.Pack) ModeDialog {
 	t := styles.CurrentTheme()
 	keyMap := DefaultKeyMap()
 	help := help.New()
 	help.Styles = t.S().Help
 
 	options := []ModeOption{ModeMock, ModeGym}
+	for _, p := range packs {
+		options = append(options, packOptions(p)...)
+	}
 
 	s := &modeDialogCmp{
 		selectedIndex: 0,
@@ -82,7 +95,7 @@ func (s *modeDialogCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 				selected := s.options[s.selectedIndex]
 				return s, tea.Sequence(
 					util.CmdHandler(dialogs.CloseDialogMsg{}),
-					util.CmdHandler(ModeSelectedMsg{Mode: selected.ID}),
+					util.CmdHandler(ModeSelectedMsg{Mode: selected.ID, Pack: selected.Pack, Round: selected.Round}),
 				)
 			}
 		case key.Matches(msg, s.keyMap.Close):
@@ -150,6 +163,29 @@ func (s *modeDialogCmp) ID() dialogs.DialogID {
 	return ModeDialogID
 }
 
+// packOptions returns an option per round of the pack.
+func packOptions(p *pack.Pack) []ModeOption {
+	options := make([]ModeOption, len(p.Rounds))
+	for i, r := range p.Rounds {
+		desc := fmt.Sprintf("%s · %d min", r.Name, r.Minutes)
+		if len(r.Focus) > 0 {
+			desc += " · " + strings.Join(r.Focus, ", ")
+		}
+		options[i] = ModeOption{
+			ID:    r.Mode,
+			Name:  p.SessionTitle(i + 1),
+			Desc:  desc,
+			Pack:  p.Name,
+			Round: i + 1,
+		}
+	}
+	return options
+}
+
+// ModeSelectedMsg starts a session in the mode, practicing the interview
+// pack round if Pack is set.
 type ModeSelectedMsg struct {
-	Mode string
+	Mode  string
+	Pack  string
+	Round int
 }
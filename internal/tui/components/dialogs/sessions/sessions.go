@@ -48,7 +48,11 @@ func NewSessionDialogCmp(sessions []session.Session, selectedID string) SessionD
 	items := make([]list.CompletionItem[session.Session], len(sessions))
 	if len(sessions) > 0 {
 		for i, session := range sessions {
-			items[i] = list.NewCompletionItem(session.Title, session, list.WithCompletionID(session.ID))
+			opts := []list.CompletionItemOption{list.WithCompletionID(session.ID)}
+			if session.Reviewed() {
+				opts = append(opts, list.WithCompletionShortcut("✓ "+session.ReviewedBy))
+			}
+			items[i] = list.NewCompletionItem(session.Title, session, opts...)
 		}
 	}
 
@@ -0,0 +1,245 @@
+package themes
+
+import (
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/trankhanh040147/prepf/internal/tui/components/core"
+	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs"
+	"github.com/trankhanh040147/prepf/internal/tui/exp/list"
+	"github.com/trankhanh040147/prepf/internal/tui/styles"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+const (
+	ThemesDialogID dialogs.DialogID = "themes"
+
+	defaultWidth int = 40
+)
+
+type listModel = list.FilterableList[list.CompletionItem[string]]
+
+// ThemeSelectedMsg is sent when a theme is picked, to be saved as the
+// user's choice. The theme is already current by then.
+type ThemeSelectedMsg struct {
+	Name string
+}
+
+type ThemesDialog interface {
+	dialogs.DialogModel
+}
+
+type themesDialogCmp struct {
+	width   int
+	wWidth  int // Width of the terminal window
+	wHeight int // Height of the terminal window
+
+	// original is restored when the dialog is closed without picking.
+	original  string
+	previewed string
+
+	themeList listModel
+	keyMap    KeyMap
+	help      help.Model
+}
+
+type KeyMap struct {
+	Next     key.Binding
+	Previous key.Binding
+	Select   key.Binding
+	Close    key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Next: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("↓/ctrl+n", "next"),
+		),
+		Previous: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("↑/ctrl+p", "previous"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "select"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "ctrl+c"),
+			key.WithHelp("esc/ctrl+c", "cancel"),
+		),
+	}
+}
+
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Select, k.Close}
+}
+
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Next, k.Previous},
+		{k.Select, k.Close},
+	}
+}
+
+// NewThemesDialog lists the registered themes, switching to each one as it
+// is highlighted.
+func NewThemesDialog() ThemesDialog {
+	keyMap := DefaultKeyMap()
+	listKeyMap := list.DefaultKeyMap()
+	listKeyMap.Down.SetEnabled(false)
+	listKeyMap.Up.SetEnabled(false)
+	listKeyMap.DownOneItem = keyMap.Next
+	listKeyMap.UpOneItem = keyMap.Previous
+
+	t := styles.CurrentTheme()
+	items := make([]list.CompletionItem[string], 0)
+	for _, name := range styles.DefaultManager().List() {
+		opts := []list.CompletionItemOption{list.WithCompletionID(name)}
+		if name == t.Name {
+			opts = append(opts, list.WithCompletionShortcut("current"))
+		}
+		items = append(items, list.NewCompletionItem(name, name, opts...))
+	}
+
+	inputStyle := t.S().Base.PaddingLeft(1).PaddingBottom(1)
+	themeList := list.NewFilterableList(
+		items,
+		list.WithFilterPlaceholder("Enter a theme name"),
+		list.WithFilterInputStyle(inputStyle),
+		list.WithFilterListOptions(
+			list.WithKeyMap(listKeyMap),
+			list.WithWrapNavigation(),
+			list.WithResizeByList(),
+		),
+	)
+	help := help.New()
+	help.Styles = t.S().Help
+
+	return &themesDialogCmp{
+		width:     defaultWidth,
+		original:  t.Name,
+		previewed: t.Name,
+		themeList: themeList,
+		keyMap:    keyMap,
+		help:      help,
+	}
+}
+
+func (d *themesDialogCmp) Init() tea.Cmd {
+	return tea.Sequence(d.themeList.Init(), d.themeList.Focus(), d.themeList.SetSelected(d.original))
+}
+
+func (d *themesDialogCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.wWidth = msg.Width
+		d.wHeight = msg.Height
+		d.themeList.SetInputWidth(d.listWidth() - 2)
+		return d, d.themeList.SetSize(d.listWidth(), d.listHeight())
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, d.keyMap.Select):
+			selectedItem := d.themeList.SelectedItem()
+			if selectedItem == nil {
+				return d, nil
+			}
+			name := (*selectedItem).Value()
+			return d, tea.Sequence(
+				d.preview(name),
+				util.CmdHandler(dialogs.CloseDialogMsg{}),
+				util.CmdHandler(ThemeSelectedMsg{Name: name}),
+			)
+		case key.Matches(msg, d.keyMap.Close):
+			return d, tea.Sequence(
+				d.preview(d.original),
+				util.CmdHandler(dialogs.CloseDialogMsg{}),
+			)
+		default:
+			u, cmd := d.themeList.Update(msg)
+			d.themeList = u.(listModel)
+			if selectedItem := d.themeList.SelectedItem(); selectedItem != nil {
+				cmd = tea.Batch(cmd, d.preview((*selectedItem).Value()))
+			}
+			return d, cmd
+		}
+	}
+	return d, nil
+}
+
+// preview makes name the current theme and has everything redrawn with it.
+func (d *themesDialogCmp) preview(name string) tea.Cmd {
+	if name == d.previewed {
+		return nil
+	}
+	if err := styles.DefaultManager().SetTheme(name); err != nil {
+		return util.ReportError(err)
+	}
+	d.previewed = name
+	d.help.Styles = styles.CurrentTheme().S().Help
+	u, cmd := d.themeList.Update(styles.ThemeChangedMsg{})
+	d.themeList = u.(listModel)
+	return tea.Batch(cmd, util.CmdHandler(styles.ThemeChangedMsg{}))
+}
+
+func (d *themesDialogCmp) View() string {
+	t := styles.CurrentTheme()
+	header := t.S().Base.Padding(0, 1, 1, 1).Render(core.Title("Switch Theme", d.width-4))
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		d.themeList.View(),
+		"",
+		t.S().Base.Width(d.width-2).PaddingLeft(1).AlignHorizontal(lipgloss.Left).Render(d.help.View(d.keyMap)),
+	)
+	return d.style().Render(content)
+}
+
+func (d *themesDialogCmp) Cursor() *tea.Cursor {
+	if cursor, ok := d.themeList.(util.Cursor); ok {
+		cursor := cursor.Cursor()
+		if cursor != nil {
+			cursor = d.moveCursor(cursor)
+		}
+		return cursor
+	}
+	return nil
+}
+
+func (d *themesDialogCmp) listWidth() int {
+	return d.width - 2 // 2 for the border
+}
+
+func (d *themesDialogCmp) listHeight() int {
+	listHeight := len(d.themeList.Items()) + 2 + 4 // height based on items + 2 for the input + 4 for the sections
+	return min(listHeight, d.wHeight/2)
+}
+
+func (d *themesDialogCmp) moveCursor(cursor *tea.Cursor) *tea.Cursor {
+	row, col := d.Position()
+	offset := row + 3
+	cursor.Y += offset
+	cursor.X = cursor.X + col + 2
+	return cursor
+}
+
+func (d *themesDialogCmp) style() lipgloss.Style {
+	t := styles.CurrentTheme()
+	return t.S().Base.
+		Width(d.width).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.BorderFocus)
+}
+
+func (d *themesDialogCmp) Position() (int, int) {
+	row := d.wHeight/4 - 2 // just a bit above the center
+	col := d.wWidth / 2
+	col -= d.width / 2
+	return row, col
+}
+
+func (d *themesDialogCmp) ID() dialogs.DialogID {
+	return ThemesDialogID
+}
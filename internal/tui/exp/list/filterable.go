@@ -119,6 +119,8 @@ func NewFilterableList[T FilterableItem](items []T, opts ...filterableListOption
 
 func (f *filterableList[T]) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case styles.ThemeChangedMsg:
+		f.input.SetStyles(styles.CurrentTheme().S().TextInput)
 	case tea.KeyPressMsg:
 		switch {
 		// handle movements
@@ -282,6 +282,13 @@ func (l *list[T]) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 			}
 		}
 		return l, tea.Batch(cmds...)
+	case styles.ThemeChangedMsg:
+		// Rendered items hold the old colors.
+		selectedID := ""
+		if l.selectedItemIdx >= 0 && l.selectedItemIdx < len(l.items) {
+			selectedID = l.items[l.selectedItemIdx].ID()
+		}
+		return l, l.reset(selectedID)
 	case tea.KeyPressMsg:
 		if l.focused {
 			switch {
@@ -1,9 +1,14 @@
 package chat
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"charm.land/bubbles/v2/help"
@@ -11,11 +16,18 @@ import (
 	"charm.land/bubbles/v2/spinner"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/trankhanh040147/prepf/internal/agent"
+	"github.com/trankhanh040147/prepf/internal/annotation"
 	"github.com/trankhanh040147/prepf/internal/app"
 	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/daily"
+	"github.com/trankhanh040147/prepf/internal/debugging"
+	"github.com/trankhanh040147/prepf/internal/drill"
 	"github.com/trankhanh040147/prepf/internal/history"
 	"github.com/trankhanh040147/prepf/internal/message"
+	"github.com/trankhanh040147/prepf/internal/pack"
 	"github.com/trankhanh040147/prepf/internal/permission"
+	"github.com/trankhanh040147/prepf/internal/plan"
 	"github.com/trankhanh040147/prepf/internal/pubsub"
 	"github.com/trankhanh040147/prepf/internal/session"
 	"github.com/trankhanh040147/prepf/internal/tui/components/anim"
@@ -30,6 +42,7 @@ import (
 	"github.com/trankhanh040147/prepf/internal/tui/components/core/layout"
 	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs"
 	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs/commands"
+	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs/confirm"
 	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs/copilot"
 	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs/filepicker"
 	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs/hyper"
@@ -39,6 +52,7 @@ import (
 	"github.com/trankhanh040147/prepf/internal/tui/page"
 	"github.com/trankhanh040147/prepf/internal/tui/styles"
 	"github.com/trankhanh040147/prepf/internal/tui/util"
+	"github.com/trankhanh040147/prepf/internal/uicmd"
 	"github.com/trankhanh040147/prepf/internal/version"
 )
 
@@ -49,6 +63,16 @@ type (
 		Focused bool
 	}
 	CancelTimerExpiredMsg struct{}
+	// dailyLoadedMsg carries today's challenge for the splash screen.
+	dailyLoadedMsg struct {
+		challenge daily.Challenge
+		streak    daily.Streak
+	}
+	// planLoadedMsg carries today's practice plan for the splash screen.
+	planLoadedMsg struct {
+		today  []plan.Item
+		missed int
+	}
 )
 
 type PanelType string
@@ -131,6 +155,7 @@ type chatPage struct {
 	// Pending message for mode selection
 	pendingMessage      string
 	pendingAttachments  []message.Attachment
+	pendingTiming       *message.AnswerTiming
 
 	// Pills state
 	pillsExpanded      bool
@@ -188,6 +213,8 @@ func (p *chatPage) Init() tea.Cmd {
 		p.chat.Init(),
 		p.editor.Init(),
 		p.splash.Init(),
+		p.loadDaily(),
+		p.loadPlan(),
 	)
 }
 
@@ -272,7 +299,7 @@ func (p *chatPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 		p.editor = u.(editor.Editor)
 		return p, cmd
 	case chat.SendMsg:
-		return p, p.sendMessage(msg.Text, msg.Attachments)
+		return p, p.sendMessage(msg.Text, msg.Attachments, msg.Timing)
 	case chat.SessionSelectedMsg:
 		return p, p.setSession(msg)
 	case chat.SessionCreatedWithModeMsg:
@@ -280,8 +307,9 @@ func (p *chatPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 		// Clear pending message
 		p.pendingMessage = ""
 		p.pendingAttachments = nil
+		p.pendingTiming = nil
 		// Send the message after session is set
-		cmds = append(cmds, p.sendMessageAfterSession(msg.Text, msg.Attachments))
+		cmds = append(cmds, p.sendMessageAfterSession(msg.Text, msg.Attachments, msg.Timing))
 		return p, tea.Batch(cmds...)
 	case splash.SubmitAPIKeyMsg:
 		u, cmd := p.splash.Update(msg)
@@ -301,6 +329,34 @@ func (p *chatPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 		return p, tea.Batch(p.SetSize(p.width, p.height), cmd)
 	case commands.ToggleThinkingMsg:
 		return p, p.toggleThinking()
+	case commands.ActivateSkillMsg:
+		return p, p.activateSkill(msg.Name)
+	case uicmd.RevealHintMsg:
+		return p, p.sendDrillControl(msg.Args, uicmd.HintControl)
+	case uicmd.GiveUpMsg:
+		return p, p.sendDrillControl(msg.Args, uicmd.GiveUpControl)
+	case commands.StartDebugMsg:
+		return p, p.startDebugSession(msg.Name)
+	case commands.StartDailyMsg:
+		return p, p.startDaily()
+	case commands.StartGymMsg:
+		return p, p.startGym(msg.Title, msg.Text)
+	case dailyLoadedMsg:
+		p.splash.SetDaily(msg.challenge, msg.streak)
+		return p, nil
+	case commands.StartPlanMsg:
+		return p, p.startPlanItem(msg.Args)
+	case planLoadedMsg:
+		p.splash.SetPlan(msg.today, msg.missed)
+		return p, nil
+	case uicmd.RunCheckMsg:
+		return p, p.runCheck()
+	case messages.RevertMsg:
+		return p, p.revert(msg)
+	case messages.AnnotateMsg:
+		return p, p.annotate(msg)
+	case commands.ToggleReviewedMsg:
+		return p, p.toggleReviewed(msg.SessionID)
 	case commands.OpenReasoningDialogMsg:
 		return p, p.openReasoningDialog()
 	case reasoning.ReasoningEffortSelectedMsg:
@@ -330,6 +386,14 @@ func (p *chatPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 		p.sidebar = u.(sidebar.Sidebar)
 		cmds = append(cmds, cmd)
 		return p, tea.Batch(cmds...)
+	case styles.ThemeChangedMsg:
+		u, cmd := p.chat.Update(msg)
+		p.chat = u.(chat.MessageListCmp)
+		cmds = append(cmds, cmd)
+		u, cmd = p.editor.Update(msg)
+		p.editor = u.(editor.Editor)
+		cmds = append(cmds, cmd)
+		return p, tea.Batch(cmds...)
 	case chat.SessionClearedMsg:
 		u, cmd := p.header.Update(msg)
 		p.header = u.(header.Header)
@@ -382,6 +446,11 @@ func (p *chatPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 		if _, ok := msg.(pubsub.Event[message.Message]); ok && p.hasInProgressTodo() && agentBusy {
 			cmds = append(cmds, p.todoSpinner.Tick)
 		}
+		if ev, ok := msg.(pubsub.Event[message.Message]); ok && p.isReplyFinished(ev.Payload) {
+			u, cmd := p.editor.Update(editor.QuestionShownMsg{})
+			p.editor = u.(editor.Editor)
+			cmds = append(cmds, cmd)
+		}
 		if p.focusedPane == PanelTypeSplash {
 			u, cmd := p.splash.Update(msg)
 			p.splash = u.(splash.Splash)
@@ -403,7 +472,7 @@ func (p *chatPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 		p.sidebar = u.(sidebar.Sidebar)
 		cmds = append(cmds, cmd)
 		return p, tea.Batch(cmds...)
-	case pubsub.Event[permission.PermissionNotification]:
+	case pubsub.Event[permission.PermissionNotification], pubsub.Event[annotation.Annotation]:
 		u, cmd := p.chat.Update(msg)
 		p.chat = u.(chat.MessageListCmp)
 		cmds = append(cmds, cmd)
@@ -414,7 +483,7 @@ func (p *chatPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 			return p, util.ReportWarn("Agent is busy, please wait before executing a command...")
 		}
 
-		cmd := p.sendMessage(msg.Content, nil)
+		cmd := p.sendMessage(msg.Content, nil, nil)
 		if cmd != nil {
 			return p, cmd
 		}
@@ -439,7 +508,7 @@ func (p *chatPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 		}
 		return p, p.newSession()
 	case mode.ModeSelectedMsg:
-		return p, p.createSessionWithModeAndSend(msg.Mode)
+		return p, p.createSessionWithModeAndSend(msg)
 	case tea.KeyPressMsg:
 		switch {
 		case key.Matches(msg, p.keyMap.NewSession):
@@ -482,6 +551,11 @@ func (p *chatPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 		case key.Matches(msg, p.keyMap.Details):
 			p.toggleDetails()
 			return p, nil
+		case key.Matches(msg, p.keyMap.Hint):
+			// Only gym sessions take over the editor's transpose binding.
+			if p.session.ID != "" && p.session.Mode == "gym" {
+				return p, p.sendDrillControl("", uicmd.HintControl)
+			}
 		case key.Matches(msg, p.keyMap.TogglePills):
 			if p.session.ID != "" {
 				return p, p.togglePillsExpanded()
@@ -861,12 +935,37 @@ func (p *chatPage) newSession() tea.Cmd {
 	return tea.Batch(
 		util.CmdHandler(chat.SessionClearedMsg{}),
 		p.SetSize(p.width, p.height),
+		p.loadDaily(),
+		p.loadPlan(),
 	)
 }
 
-func (p *chatPage) createSessionWithModeAndSend(selectedMode string) tea.Cmd {
+// createSessionWithModeAndSend starts a session in the selected mode, tied
+// to the selected interview pack round if any, and sends the pending
+// message.
+func (p *chatPage) createSessionWithModeAndSend(selected mode.ModeSelectedMsg) tea.Cmd {
 	return func() tea.Msg {
-		newSession, err := p.app.Sessions.CreateWithMode(context.Background(), "", selectedMode)
+		ctx := context.Background()
+		var selectedPack *pack.Pack
+		title := ""
+		if selected.Pack != "" {
+			available, _ := pack.DiscoverConfigured(p.app.Config())
+			found, ok := pack.Find(available, selected.Pack)
+			if !ok {
+				return util.InfoMsg{
+					Type: util.InfoTypeError,
+					Msg:  fmt.Sprintf("Unknown interview pack %q", selected.Pack),
+				}
+			}
+			selectedPack = found
+			title = found.SessionTitle(selected.Round)
+		}
+		newSession, err := p.app.Sessions.CreateWithMode(ctx, title, selected.Mode)
+		if err == nil && selectedPack != nil {
+			if _, err = p.app.Packs.Begin(ctx, newSession.ID, selectedPack, selected.Round); err != nil {
+				_ = p.app.Sessions.Delete(ctx, newSession.ID)
+			}
+		}
 		if err != nil {
 			return util.InfoMsg{
 				Type: util.InfoTypeError,
@@ -878,6 +977,7 @@ func (p *chatPage) createSessionWithModeAndSend(selectedMode string) tea.Cmd {
 			Session:   newSession,
 			Text:      p.pendingMessage,
 			Attachments: p.pendingAttachments,
+			Timing:    p.pendingTiming,
 		}
 	}
 }
@@ -987,19 +1087,465 @@ func (p *chatPage) toggleDetails() {
 	p.setShowDetails(!p.showingDetails)
 }
 
-func (p *chatPage) sendMessage(text string, attachments []message.Attachment) tea.Cmd {
+func (p *chatPage) sendMessage(text string, attachments []message.Attachment, timing *message.AnswerTiming) tea.Cmd {
 	if p.session.ID == "" {
 		// Store pending message and show mode selector
 		p.pendingMessage = text
 		p.pendingAttachments = attachments
+		p.pendingTiming = timing
+		packs, _ := pack.DiscoverConfigured(p.app.Config())
 		return util.CmdHandler(dialogs.OpenDialogMsg{
-			Model: mode.NewModeDialogCmp(),
+			Model: mode.NewModeDialogCmp(packs),
 		})
 	}
-	return p.sendMessageAfterSession(text, attachments)
+	return p.sendMessageAfterSession(text, attachments, timing)
+}
+
+// isReplyFinished reports whether msg is a reply in the current session that
+// has finished and is waiting for the user's answer.
+func (p *chatPage) isReplyFinished(msg message.Message) bool {
+	return msg.SessionID == p.session.ID &&
+		msg.Role == message.Assistant &&
+		msg.FinishReason() == message.FinishReasonEndTurn
+}
+
+func (p *chatPage) activateSkill(name string) tea.Cmd {
+	if p.session.ID == "" {
+		return util.ReportWarn("Start a session before activating a skill")
+	}
+	sessionID := p.session.ID
+	return func() tea.Msg {
+		if _, err := p.app.Sessions.ActivateSkill(context.Background(), sessionID, name); err != nil {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  err.Error(),
+			}
+		}
+		return util.InfoMsg{
+			Type: util.InfoTypeInfo,
+			Msg:  fmt.Sprintf("Activated skill %s; it applies from the next message", name),
+		}
+	}
+}
+
+// sendDrillControl records a hint or give up against the session's open
+// drill question and sends the resulting control message.
+func (p *chatPage) sendDrillControl(args string, control func(context.Context, drill.Service, string, string) (agent.Control, error)) tea.Cmd {
+	if p.session.ID == "" {
+		return util.ReportWarn("Start a session before asking for hints")
+	}
+	if p.app.AgentCoordinator != nil && p.app.AgentCoordinator.IsBusy() {
+		return util.ReportWarn("Agent is busy, please wait...")
+	}
+	sessionID := p.session.ID
+	return func() tea.Msg {
+		c, err := control(context.Background(), p.app.Drills, sessionID, args)
+		if err != nil {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  err.Error(),
+			}
+		}
+		return chat.SendMsg{Text: c.String()}
+	}
+}
+
+// startDebugSession starts a debugging session: the exercise is copied into
+// a scratch workspace and the interviewer is asked to open the round.
+func (p *chatPage) startDebugSession(name string) tea.Cmd {
+	if p.app.AgentCoordinator != nil && p.app.AgentCoordinator.IsBusy() {
+		return util.ReportWarn("Agent is busy, please wait...")
+	}
+	return func() tea.Msg {
+		ctx := context.Background()
+		available, _ := debugging.Exercises.Discover(debugging.Exercises.Dirs(p.app.Config()))
+		e, ok := debugging.Exercises.Find(available, name)
+		if !ok {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  fmt.Sprintf("Unknown debugging exercise %q", name),
+			}
+		}
+		sess, err := p.app.Sessions.CreateWithMode(ctx, "Debug: "+cmp.Or(e.Title, e.Name), "debug")
+		if err == nil {
+			if _, err = p.app.Debugging.Start(ctx, sess.ID, e); err != nil {
+				_ = p.app.Sessions.Delete(ctx, sess.ID)
+			}
+		}
+		if err != nil {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  err.Error(),
+			}
+		}
+		return chat.SessionCreatedWithModeMsg{
+			Session: sess,
+			Text:    "I'm ready for the debugging exercise.",
+		}
+	}
+}
+
+// loadDaily picks today's challenge, if needed, for the splash screen.
+func (p *chatPage) loadDaily() tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		c, err := p.app.Daily.Today(ctx)
+		if err != nil {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  err.Error(),
+			}
+		}
+		streak, err := p.app.Daily.Streak(ctx)
+		if err != nil {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  err.Error(),
+			}
+		}
+		return dailyLoadedMsg{challenge: c, streak: streak}
+	}
+}
+
+// startDaily opens the gym session answering today's challenge, starting
+// one if it hasn't been started yet.
+func (p *chatPage) startDaily() tea.Cmd {
+	if p.app.AgentCoordinator == nil {
+		return util.ReportWarn("Pick a model before starting the daily challenge")
+	}
+	if p.app.AgentCoordinator.IsBusy() {
+		return util.ReportWarn("Agent is busy, please wait...")
+	}
+	return func() tea.Msg {
+		ctx := context.Background()
+		c, err := p.app.Daily.Today(ctx)
+		if err != nil {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  err.Error(),
+			}
+		}
+		if c.SessionID != "" {
+			if sess, err := p.app.Sessions.Get(ctx, c.SessionID); err == nil {
+				return chat.SessionSelectedMsg(sess)
+			}
+		}
+		sess, err := p.app.Sessions.CreateWithMode(ctx, "Daily: "+c.Day, "gym")
+		if err == nil {
+			if c, err = p.app.Daily.Begin(ctx, sess.ID); err != nil {
+				_ = p.app.Sessions.Delete(ctx, sess.ID)
+			}
+		}
+		if err != nil {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  err.Error(),
+			}
+		}
+		return chat.SessionCreatedWithModeMsg{
+			Session: sess,
+			Text:    daily.Prompt(c),
+		}
+	}
+}
+
+// loadPlan lists today's practice plan items for the splash screen.
+func (p *chatPage) loadPlan() tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		today, err := p.app.Plan.Today(ctx)
+		if err != nil {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  err.Error(),
+			}
+		}
+		missed, err := p.app.Plan.Missed(ctx)
+		if err != nil {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  err.Error(),
+			}
+		}
+		return planLoadedMsg{today: today, missed: len(missed)}
+	}
+}
+
+// startPlanItem opens the session practicing one of today's plan items, the
+// numbered one or else the first not done, starting one if it hasn't been
+// started yet.
+func (p *chatPage) startPlanItem(args string) tea.Cmd {
+	if p.app.AgentCoordinator == nil {
+		return util.ReportWarn("Pick a model before starting a plan item")
+	}
+	if p.app.AgentCoordinator.IsBusy() {
+		return util.ReportWarn("Agent is busy, please wait...")
+	}
+	n := 0
+	if args != "" {
+		var err error
+		if n, err = strconv.Atoi(args); err != nil || n < 1 {
+			return util.ReportWarn("Usage: /plan [item number]")
+		}
+	}
+	return func() tea.Msg {
+		ctx := context.Background()
+		today, err := p.app.Plan.Today(ctx)
+		if err != nil {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  err.Error(),
+			}
+		}
+		if len(today) == 0 {
+			return util.InfoMsg{
+				Type: util.InfoTypeWarn,
+				Msg:  "Nothing planned for today; add an interview with prepf plan target add and run prepf plan generate",
+			}
+		}
+		i := n - 1
+		if n == 0 {
+			i = slices.IndexFunc(today, func(item plan.Item) bool { return !item.Done() })
+			if i < 0 {
+				return util.InfoMsg{
+					Type: util.InfoTypeInfo,
+					Msg:  "Today's plan is done",
+				}
+			}
+		}
+		if i >= len(today) {
+			return util.InfoMsg{
+				Type: util.InfoTypeWarn,
+				Msg:  fmt.Sprintf("Today's plan has %d item(s)", len(today)),
+			}
+		}
+		item := today[i]
+		if item.SessionID != "" {
+			if sess, err := p.app.Sessions.Get(ctx, item.SessionID); err == nil {
+				return chat.SessionSelectedMsg(sess)
+			}
+		}
+		sess, err := p.app.Sessions.CreateWithMode(ctx, "Plan: "+item.Title(), item.Mode)
+		if err == nil {
+			if item, err = p.app.Plan.Begin(ctx, item, sess.ID); err != nil {
+				_ = p.app.Sessions.Delete(ctx, sess.ID)
+			}
+		}
+		if err != nil {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  err.Error(),
+			}
+		}
+		return chat.SessionCreatedWithModeMsg{
+			Session: sess,
+			Text:    plan.Prompt(item),
+		}
+	}
+}
+
+// startGym starts a gym session opened with text.
+func (p *chatPage) startGym(title, text string) tea.Cmd {
+	if p.app.AgentCoordinator == nil {
+		return util.ReportWarn("Pick a model before starting a gym session")
+	}
+	if p.app.AgentCoordinator.IsBusy() {
+		return util.ReportWarn("Agent is busy, please wait...")
+	}
+	return func() tea.Msg {
+		sess, err := p.app.Sessions.CreateWithMode(context.Background(), title, "gym")
+		if err != nil {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  err.Error(),
+			}
+		}
+		return chat.SessionCreatedWithModeMsg{
+			Session: sess,
+			Text:    text,
+		}
+	}
 }
 
-func (p *chatPage) sendMessageAfterSession(text string, attachments []message.Attachment) tea.Cmd {
+// runCheck runs the check of the session's debugging exercise and sends
+// the result to the interviewer.
+func (p *chatPage) runCheck() tea.Cmd {
+	if p.session.ID == "" {
+		return util.ReportWarn("Start a debugging exercise with /debug first")
+	}
+	if p.app.AgentCoordinator != nil && p.app.AgentCoordinator.IsBusy() {
+		return util.ReportWarn("Agent is busy, please wait...")
+	}
+	sessionID := p.session.ID
+	return tea.Sequence(
+		util.ReportInfo("Running the check..."),
+		func() tea.Msg {
+			c, err := uicmd.CheckControl(context.Background(), p.app.Debugging, sessionID)
+			if err != nil {
+				return util.InfoMsg{
+					Type: util.InfoTypeError,
+					Msg:  err.Error(),
+				}
+			}
+			return chat.SendMsg{Text: c.String()}
+		},
+	)
+}
+
+// revert undoes the file changes of a tool call, or of a message and every
+// message after it, once the user confirmed the changes. Nothing is reverted
+// when a file changed since the agent wrote it; prepf session revert --force
+// overwrites those.
+func (p *chatPage) revert(msg messages.RevertMsg) tea.Cmd {
+	if p.session.ID == "" {
+		return nil
+	}
+	if p.app.AgentCoordinator != nil && p.app.AgentCoordinator.IsBusy() {
+		return util.ReportWarn("Agent is busy, please wait...")
+	}
+	sessionID := p.session.ID
+	workingDir := p.app.Config().WorkingDir()
+	return func() tea.Msg {
+		ctx := context.Background()
+		calls := []string{msg.ToolCallID}
+		if msg.ToolCallID == "" {
+			all, err := p.app.Messages.List(ctx, sessionID)
+			if err != nil {
+				return util.InfoMsg{Type: util.InfoTypeError, Msg: err.Error()}
+			}
+			if calls, err = history.ToolCallsFrom(all, msg.MessageID); err != nil {
+				return util.InfoMsg{Type: util.InfoTypeError, Msg: err.Error()}
+			}
+		}
+		changes, err := p.app.History.PlanRevert(ctx, sessionID, calls)
+		if err != nil {
+			return util.InfoMsg{Type: util.InfoTypeError, Msg: err.Error()}
+		}
+		if len(changes) == 0 {
+			return util.InfoMsg{Type: util.InfoTypeInfo, Msg: "No file changes to revert"}
+		}
+		question := "Revert the file changes of this message and every message after it?"
+		if msg.ToolCallID != "" {
+			question = "Revert the file changes of this tool call?"
+		}
+		return dialogs.OpenDialogMsg{
+			Model: confirm.NewConfirmDialog(question, describeRevert(changes, workingDir), func() tea.Msg {
+				return p.applyRevert(sessionID, changes)
+			}),
+		}
+	}
+}
+
+// maxRevertLines bounds the files listed in the revert confirmation.
+const maxRevertLines = 10
+
+// describeRevert lists what reverting the changes does to each file.
+func describeRevert(changes []history.Change, workingDir string) string {
+	var lines []string
+	for i, c := range changes {
+		if i == maxRevertLines {
+			lines = append(lines, fmt.Sprintf("and %d more file(s)", len(changes)-i))
+			break
+		}
+		path := c.Path
+		if rel, err := filepath.Rel(workingDir, c.Path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+		line := fmt.Sprintf("restore %s to version %d", path, c.Target.Version)
+		if c.Remove {
+			line = "remove " + path
+		}
+		if len(c.Discards) > 0 {
+			line += fmt.Sprintf(", also undoing %d later tool call(s)", len(c.Discards))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// applyRevert reverts the confirmed changes and reports the outcome.
+func (p *chatPage) applyRevert(sessionID string, changes []history.Change) tea.Msg {
+	if err := p.app.History.Revert(context.Background(), sessionID, changes, false); err != nil {
+		if errors.Is(err, history.ErrConflict) {
+			return util.InfoMsg{
+				Type: util.InfoTypeWarn,
+				Msg:  "Nothing reverted: " + strings.ReplaceAll(err.Error(), "\n", "; ") + ". Use prepf session revert --force to overwrite.",
+			}
+		}
+		return util.InfoMsg{Type: util.InfoTypeError, Msg: err.Error()}
+	}
+	return util.InfoMsg{Type: util.InfoTypeSuccess, Msg: fmt.Sprintf("Reverted %d file(s)", len(changes))}
+}
+
+// annotate asks for a comment on a message and stores it, anchored to the
+// quoted part of the message if one is given.
+func (p *chatPage) annotate(msg messages.AnnotateMsg) tea.Cmd {
+	author := annotation.Author(p.app.Config())
+	dialog := commands.NewCommandArgumentsDialog(
+		"annotate",
+		"Annotate Message",
+		"annotate",
+		"Leave a review comment, signed as "+author,
+		[]commands.Argument{
+			{Name: "comment", Title: "Comment", Required: true},
+			{Name: "quote", Title: "Quote", Description: "Part of the message the comment is about (optional)"},
+		},
+		func(args map[string]string) tea.Cmd {
+			body := strings.TrimSpace(args["comment"])
+			if body == "" {
+				return util.ReportWarn("Annotation is empty")
+			}
+			a := annotation.Annotation{
+				SessionID: msg.SessionID,
+				MessageID: msg.MessageID,
+				Author:    author,
+				Body:      body,
+			}
+			if quote := strings.TrimSpace(args["quote"]); quote != "" {
+				r, ok := annotation.FindRange(msg.Text, quote)
+				if !ok {
+					return util.ReportWarn("Quote not found in the message")
+				}
+				a.Range = &r
+			}
+			return func() tea.Msg {
+				if _, err := p.app.Annotations.Create(context.Background(), a); err != nil {
+					return util.InfoMsg{Type: util.InfoTypeError, Msg: err.Error()}
+				}
+				return util.InfoMsg{Type: util.InfoTypeSuccess, Msg: "Annotation added"}
+			}
+		},
+	)
+	return util.CmdHandler(dialogs.OpenDialogMsg{Model: dialog})
+}
+
+// toggleReviewed marks the session reviewed by the configured author, or
+// clears the review if there is one.
+func (p *chatPage) toggleReviewed(sessionID string) tea.Cmd {
+	if sessionID == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx := context.Background()
+		s, err := p.app.Sessions.Get(ctx, sessionID)
+		if err != nil {
+			return util.InfoMsg{Type: util.InfoTypeError, Msg: err.Error()}
+		}
+		reviewer := ""
+		if !s.Reviewed() {
+			reviewer = annotation.Author(p.app.Config())
+		}
+		if _, err := p.app.Sessions.SetReviewed(ctx, sessionID, reviewer); err != nil {
+			return util.InfoMsg{Type: util.InfoTypeError, Msg: err.Error()}
+		}
+		if reviewer == "" {
+			return util.InfoMsg{Type: util.InfoTypeInfo, Msg: "Session no longer marked reviewed"}
+		}
+		return util.InfoMsg{Type: util.InfoTypeSuccess, Msg: "Session marked reviewed by " + reviewer}
+	}
+}
+
+func (p *chatPage) sendMessageAfterSession(text string, attachments []message.Attachment, timing *message.AnswerTiming) tea.Cmd {
 	session := p.session
 	var cmds []tea.Cmd
 	if p.app.AgentCoordinator == nil {
@@ -1007,7 +1553,7 @@ func (p *chatPage) sendMessageAfterSession(text string, attachments []message.At
 	}
 	cmds = append(cmds, p.chat.GoToBottom())
 	cmds = append(cmds, func() tea.Msg {
-		_, err := p.app.AgentCoordinator.Run(context.Background(), session.ID, text, attachments...)
+		_, err := p.app.AgentCoordinator.Run(context.Background(), session.ID, text, agent.RunOptions{Timing: timing}, attachments...)
 		if err != nil {
 			isCancelErr := errors.Is(err, context.Canceled)
 			isPermissionErr := errors.Is(err, permission.ErrorPermissionDenied)
@@ -1029,6 +1575,9 @@ func (p *chatPage) Bindings() []key.Binding {
 		p.keyMap.NewSession,
 		p.keyMap.AddAttachment,
 	}
+	if p.session.Mode == "gym" {
+		bindings = append(bindings, p.keyMap.Hint)
+	}
 	if p.app.AgentCoordinator != nil && p.app.AgentCoordinator.IsBusy() {
 		cancelBinding := p.keyMap.Cancel
 		if p.isCanceling {
@@ -1286,6 +1835,8 @@ func (p *chatPage) Help() help.KeyMap {
 					key.WithHelp("↑↓", "scroll"),
 				),
 				messages.CopyKey,
+				messages.RevertKey,
+				messages.AnnotateKey,
 			)
 			fullList = append(fullList,
 				[]key.Binding{
@@ -1326,6 +1877,8 @@ func (p *chatPage) Help() help.KeyMap {
 				},
 				[]key.Binding{
 					messages.CopyKey,
+					messages.RevertKey,
+					messages.AnnotateKey,
 					messages.ClearSelectionKey,
 				},
 			)
@@ -13,6 +13,7 @@ type KeyMap struct {
 	TogglePills   key.Binding
 	PillLeft      key.Binding
 	PillRight     key.Binding
+	Hint          key.Binding
 }
 
 func DefaultKeyMap() KeyMap {
@@ -49,5 +50,9 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("right"),
 			key.WithHelp("←/→", "switch section"),
 		),
+		Hint: key.NewBinding(
+			key.WithKeys("ctrl+t"),
+			key.WithHelp("ctrl+t", "next hint"),
+		),
 	}
 }
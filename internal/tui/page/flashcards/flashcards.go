@@ -0,0 +1,221 @@
+// Package flashcards implements the page for reviewing the flashcards due
+// today.
+package flashcards
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/trankhanh040147/prepf/internal/app"
+	"github.com/trankhanh040147/prepf/internal/flashcard"
+	"github.com/trankhanh040147/prepf/internal/tui/components/core"
+	"github.com/trankhanh040147/prepf/internal/tui/components/core/layout"
+	"github.com/trankhanh040147/prepf/internal/tui/page"
+	"github.com/trankhanh040147/prepf/internal/tui/page/chat"
+	"github.com/trankhanh040147/prepf/internal/tui/styles"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+var FlashcardsPageID page.PageID = "flashcards"
+
+// LoadMsg reloads the cards due for review. It is sent whenever the page is
+// opened.
+type LoadMsg struct{}
+
+type loadedMsg struct {
+	cards []flashcard.Card
+	err   error
+}
+
+type reviewFailedMsg struct {
+	err error
+}
+
+type FlashcardsPage interface {
+	util.Model
+	layout.Sizeable
+	core.KeyMapHelp
+}
+
+type flashcardsPage struct {
+	app    *app.App
+	keyMap KeyMap
+
+	width, height int
+
+	loaded   bool
+	cards    []flashcard.Card
+	current  int
+	revealed bool
+	reviewed int
+	again    int
+}
+
+func New(app *app.App) FlashcardsPage {
+	return &flashcardsPage{
+		app:    app,
+		keyMap: DefaultKeyMap(),
+	}
+}
+
+func (p *flashcardsPage) Init() tea.Cmd {
+	return p.load
+}
+
+func (p *flashcardsPage) load() tea.Msg {
+	cards, err := p.app.Flashcards.Due(context.Background(), time.Now())
+	return loadedMsg{cards: cards, err: err}
+}
+
+func (p *flashcardsPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case LoadMsg:
+		return p, p.load
+	case loadedMsg:
+		if msg.err != nil {
+			return p, util.ReportError(msg.err)
+		}
+		p.loaded = true
+		p.cards = msg.cards
+		p.current = 0
+		p.revealed = false
+		p.reviewed = 0
+		p.again = 0
+		return p, nil
+	case reviewFailedMsg:
+		return p, util.ReportError(msg.err)
+	case tea.KeyPressMsg:
+		return p, p.handleKey(msg)
+	}
+	return p, nil
+}
+
+func (p *flashcardsPage) handleKey(msg tea.KeyPressMsg) tea.Cmd {
+	if key.Matches(msg, p.keyMap.Back) {
+		return util.CmdHandler(page.PageChangeMsg{ID: chat.ChatPageID})
+	}
+	card, ok := p.currentCard()
+	if !ok {
+		return nil
+	}
+	if !p.revealed {
+		if key.Matches(msg, p.keyMap.Reveal) {
+			p.revealed = true
+		}
+		return nil
+	}
+
+	var grade flashcard.Grade
+	switch {
+	case key.Matches(msg, p.keyMap.Again):
+		grade = flashcard.GradeAgain
+	case key.Matches(msg, p.keyMap.Hard):
+		grade = flashcard.GradeHard
+	case key.Matches(msg, p.keyMap.Good):
+		grade = flashcard.GradeGood
+	case key.Matches(msg, p.keyMap.Easy):
+		grade = flashcard.GradeEasy
+	default:
+		return nil
+	}
+	if grade == flashcard.GradeAgain {
+		// Forgotten cards come back at the end of this review.
+		p.again++
+		p.cards = append(p.cards, card)
+	}
+	p.reviewed++
+	p.current++
+	p.revealed = false
+	return func() tea.Msg {
+		if _, err := p.app.Flashcards.Review(context.Background(), card.ID, grade); err != nil {
+			return reviewFailedMsg{err: err}
+		}
+		return nil
+	}
+}
+
+func (p *flashcardsPage) currentCard() (flashcard.Card, bool) {
+	if p.current >= len(p.cards) {
+		return flashcard.Card{}, false
+	}
+	return p.cards[p.current], true
+}
+
+func (p *flashcardsPage) View() string {
+	t := styles.CurrentTheme()
+	width := max(min(p.width-4, 80), 10)
+
+	var content []string
+	card, ok := p.currentCard()
+	switch {
+	case !p.loaded:
+		content = append(content, t.S().Muted.Render("Loading cards..."))
+	case !ok && p.reviewed == 0:
+		content = append(content,
+			t.S().Title.Render("No cards due"),
+			"",
+			t.S().Muted.Width(width).Render("Cards are saved from the corrections in mock interviews and gym sessions. Come back once you've practiced."),
+		)
+	case !ok:
+		content = append(content,
+			t.S().Title.Render("Review done"),
+			"",
+			t.S().Text.Render(fmt.Sprintf("Reviewed %d card(s); %d needed another pass.", len(p.cards)-p.again, p.again)),
+		)
+	default:
+		header := fmt.Sprintf("Card %d of %d", p.current+1, len(p.cards))
+		if card.Topic != "" {
+			header += " · " + card.Topic
+		}
+		content = append(content,
+			core.Section(header, width),
+			"",
+			t.S().Text.Bold(true).Width(width).Render(card.Front),
+			"",
+		)
+		if p.revealed {
+			content = append(content,
+				t.S().Base.Foreground(t.Border).Render(strings.Repeat("─", width)),
+				"",
+				t.S().Text.Width(width).Render(card.Back),
+				"",
+				t.S().Muted.Render("How well did you remember it? 1 again · 2 hard · 3 good · 4 easy"),
+			)
+		} else {
+			content = append(content, t.S().Muted.Render("Recall the answer, then press space to reveal it."))
+		}
+	}
+
+	return lipgloss.Place(p.width, p.height, lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Left, content...),
+	)
+}
+
+func (p *flashcardsPage) SetSize(width, height int) tea.Cmd {
+	p.width = width
+	p.height = height
+	return nil
+}
+
+func (p *flashcardsPage) GetSize() (int, int) {
+	return p.width, p.height
+}
+
+func (p *flashcardsPage) Help() help.KeyMap {
+	var bindings []key.Binding
+	switch {
+	case p.current >= len(p.cards):
+	case p.revealed:
+		bindings = append(bindings, p.keyMap.Again, p.keyMap.Hard, p.keyMap.Good, p.keyMap.Easy)
+	default:
+		bindings = append(bindings, p.keyMap.Reveal)
+	}
+	bindings = append(bindings, p.keyMap.Back)
+	return core.NewSimpleHelp(bindings, [][]key.Binding{bindings})
+}
@@ -0,0 +1,43 @@
+package flashcards
+
+import (
+	"charm.land/bubbles/v2/key"
+)
+
+type KeyMap struct {
+	Reveal key.Binding
+	Again  key.Binding
+	Hard   key.Binding
+	Good   key.Binding
+	Easy   key.Binding
+	Back   key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Reveal: key.NewBinding(
+			key.WithKeys("space", "enter"),
+			key.WithHelp("space", "reveal"),
+		),
+		Again: key.NewBinding(
+			key.WithKeys("1"),
+			key.WithHelp("1", "again"),
+		),
+		Hard: key.NewBinding(
+			key.WithKeys("2"),
+			key.WithHelp("2", "hard"),
+		),
+		Good: key.NewBinding(
+			key.WithKeys("3", "space", "enter"),
+			key.WithHelp("3", "good"),
+		),
+		Easy: key.NewBinding(
+			key.WithKeys("4"),
+			key.WithHelp("4", "easy"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc", "back to chat"),
+		),
+	}
+}
@@ -0,0 +1,105 @@
+package quizzes
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/trankhanh040147/prepf/internal/tui/styles"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+// choiceMark is how a choice is marked once the question is graded.
+type choiceMark int
+
+const (
+	markNone choiceMark = iota
+	// markCorrect is a correct choice the user selected.
+	markCorrect
+	// markWrong is an incorrect choice the user selected.
+	markWrong
+	// markMissed is a correct choice the user didn't select.
+	markMissed
+)
+
+// choiceItem is one option of a choice question in the quiz list.
+type choiceItem struct {
+	index   int
+	text    string
+	multi   bool
+	checked bool
+	mark    choiceMark
+	focused bool
+	width   int
+}
+
+func newChoiceItem(index int, text string, multi bool) *choiceItem {
+	return &choiceItem{index: index, text: text, multi: multi}
+}
+
+func (c *choiceItem) ID() string {
+	return fmt.Sprint(c.index)
+}
+
+func (c *choiceItem) Init() tea.Cmd {
+	return nil
+}
+
+func (c *choiceItem) Update(tea.Msg) (util.Model, tea.Cmd) {
+	return c, nil
+}
+
+func (c *choiceItem) View() string {
+	t := styles.CurrentTheme()
+
+	box := "( )"
+	if c.checked {
+		box = "(•)"
+	}
+	if c.multi {
+		box = "[ ]"
+		if c.checked {
+			box = "[x]"
+		}
+	}
+
+	style := t.S().Text
+	suffix := ""
+	switch c.mark {
+	case markCorrect:
+		style = style.Foreground(t.Success)
+		suffix = " ✓"
+	case markWrong:
+		style = style.Foreground(t.Error)
+		suffix = " ✗"
+	case markMissed:
+		style = style.Foreground(t.Success)
+		suffix = " ← correct"
+	}
+	if c.focused && c.mark == markNone {
+		style = t.S().TextSelected
+	}
+	return style.Padding(0, 1).Width(c.width).Render(box + " " + c.text + suffix)
+}
+
+func (c *choiceItem) SetSize(width, height int) tea.Cmd {
+	c.width = width
+	return nil
+}
+
+func (c *choiceItem) GetSize() (int, int) {
+	return c.width, 1
+}
+
+func (c *choiceItem) Focus() tea.Cmd {
+	c.focused = true
+	return nil
+}
+
+func (c *choiceItem) Blur() tea.Cmd {
+	c.focused = false
+	return nil
+}
+
+func (c *choiceItem) IsFocused() bool {
+	return c.focused
+}
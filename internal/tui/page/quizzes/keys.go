@@ -0,0 +1,48 @@
+package quizzes
+
+import (
+	"charm.land/bubbles/v2/key"
+)
+
+type KeyMap struct {
+	Next     key.Binding
+	Previous key.Binding
+	Toggle   key.Binding
+	Submit   key.Binding
+	Continue key.Binding
+	Explain  key.Binding
+	Back     key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Next: key.NewBinding(
+			key.WithKeys("down", "j", "ctrl+n", "tab"),
+			key.WithHelp("↓", "next choice"),
+		),
+		Previous: key.NewBinding(
+			key.WithKeys("up", "k", "ctrl+p", "shift+tab"),
+			key.WithHelp("↑", "previous choice"),
+		),
+		Toggle: key.NewBinding(
+			key.WithKeys("space", "x"),
+			key.WithHelp("space", "toggle"),
+		),
+		Submit: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "submit"),
+		),
+		Continue: key.NewBinding(
+			key.WithKeys("enter", "space", "n"),
+			key.WithHelp("enter", "continue"),
+		),
+		Explain: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "explain"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back to chat"),
+		),
+	}
+}
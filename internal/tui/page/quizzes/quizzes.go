@@ -0,0 +1,399 @@
+// Package quizzes implements the page for taking a quiz. Answers are graded
+// locally; the model is only asked to explain a wrong answer on demand.
+package quizzes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/google/uuid"
+	"github.com/trankhanh040147/prepf/internal/app"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/quiz"
+	"github.com/trankhanh040147/prepf/internal/tui/components/core"
+	"github.com/trankhanh040147/prepf/internal/tui/components/core/layout"
+	"github.com/trankhanh040147/prepf/internal/tui/exp/list"
+	"github.com/trankhanh040147/prepf/internal/tui/page"
+	"github.com/trankhanh040147/prepf/internal/tui/page/chat"
+	"github.com/trankhanh040147/prepf/internal/tui/styles"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+var QuizPageID page.PageID = "quiz"
+
+// StartMsg loads the named quiz and starts it from the first question.
+type StartMsg struct {
+	Name string
+}
+
+// ExplainMsg asks the model to explain a wrong answer. Prompt stands on its
+// own and is meant to be sent to the chat.
+type ExplainMsg struct {
+	Prompt string
+}
+
+type loadedMsg struct {
+	quiz *quiz.Quiz
+	err  error
+}
+
+type recordFailedMsg struct {
+	err error
+}
+
+type QuizPage interface {
+	util.Model
+	layout.Sizeable
+	core.KeyMapHelp
+}
+
+type quizPage struct {
+	app    *app.App
+	keyMap KeyMap
+
+	width, height int
+
+	quiz *quiz.Quiz
+	// runID groups the recorded answers of one run of the quiz.
+	runID    string
+	current  int
+	choices  list.List[*choiceItem]
+	input    textinput.Model
+	answered bool
+	response quiz.Response
+	result   quiz.Result
+	scores   []int
+}
+
+func New(app *app.App) QuizPage {
+	input := textinput.New()
+	input.Placeholder = "Type your answer"
+	input.Prompt = "> "
+	input.SetStyles(styles.CurrentTheme().S().TextInput)
+	return &quizPage{
+		app:    app,
+		keyMap: DefaultKeyMap(),
+		input:  input,
+	}
+}
+
+func (p *quizPage) Init() tea.Cmd {
+	return nil
+}
+
+func (p *quizPage) load(name string) tea.Cmd {
+	return func() tea.Msg {
+		available, _ := quiz.Quizzes.Discover(quiz.Quizzes.Dirs(p.app.Config()))
+		q, ok := quiz.Quizzes.Find(available, name)
+		if !ok {
+			return loadedMsg{err: fmt.Errorf("unknown quiz %q", name)}
+		}
+		return loadedMsg{quiz: q}
+	}
+}
+
+func (p *quizPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case styles.ThemeChangedMsg:
+		p.input.SetStyles(styles.CurrentTheme().S().TextInput)
+		return p, nil
+	case StartMsg:
+		p.quiz = nil
+		return p, p.load(msg.Name)
+	case loadedMsg:
+		if msg.err != nil {
+			return p, tea.Batch(
+				util.ReportError(msg.err),
+				util.CmdHandler(page.PageChangeMsg{ID: chat.ChatPageID}),
+			)
+		}
+		p.quiz = msg.quiz
+		p.runID = "quiz-" + uuid.NewString()
+		p.current = 0
+		p.scores = nil
+		return p, p.showQuestion()
+	case recordFailedMsg:
+		return p, util.ReportError(msg.err)
+	case tea.KeyPressMsg:
+		return p, p.handleKey(msg)
+	}
+	return p, nil
+}
+
+func (p *quizPage) handleKey(msg tea.KeyPressMsg) tea.Cmd {
+	if key.Matches(msg, p.keyMap.Back) {
+		return util.CmdHandler(page.PageChangeMsg{ID: chat.ChatPageID})
+	}
+	question, ok := p.currentQuestion()
+	if !ok {
+		return nil
+	}
+
+	if p.answered {
+		switch {
+		case key.Matches(msg, p.keyMap.Explain) && !p.result.Correct:
+			return util.CmdHandler(ExplainMsg{Prompt: quiz.ExplainPrompt(question, p.response, p.result)})
+		case key.Matches(msg, p.keyMap.Continue):
+			p.current++
+			return p.showQuestion()
+		}
+		return nil
+	}
+
+	if question.Type == quiz.TypeShort {
+		if key.Matches(msg, p.keyMap.Submit) {
+			return p.submit(question, quiz.Response{Text: p.input.Value()})
+		}
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		return cmd
+	}
+
+	switch {
+	case key.Matches(msg, p.keyMap.Next):
+		return p.choices.SelectItemBelow()
+	case key.Matches(msg, p.keyMap.Previous):
+		return p.choices.SelectItemAbove()
+	case key.Matches(msg, p.keyMap.Toggle) && question.Type == quiz.TypeMulti:
+		if item := p.choices.SelectedItem(); item != nil {
+			(*item).checked = !(*item).checked
+			return p.choices.UpdateItem((*item).ID(), *item)
+		}
+	case key.Matches(msg, p.keyMap.Submit):
+		var response quiz.Response
+		selected := p.choices.SelectedItem()
+		for _, item := range p.choices.Items() {
+			if question.Type != quiz.TypeMulti {
+				item.checked = selected != nil && *selected == item
+			}
+			if item.checked {
+				response.Selected = append(response.Selected, item.index)
+			}
+		}
+		if len(response.Selected) == 0 {
+			return util.ReportWarn("Select at least one choice")
+		}
+		return p.submit(question, response)
+	}
+	return nil
+}
+
+// submit grades the response, marks the choices and records the result so
+// it shows up in the progress stats.
+func (p *quizPage) submit(question quiz.Question, response quiz.Response) tea.Cmd {
+	p.answered = true
+	p.response = response
+	p.result = quiz.Grade(question, response)
+	p.scores = append(p.scores, p.result.Score)
+	p.input.Blur()
+
+	var cmds []tea.Cmd
+	if question.Type != quiz.TypeShort {
+		options := question.Options()
+		for _, item := range p.choices.Items() {
+			expected := false
+			for _, e := range p.result.Expected {
+				expected = expected || e == options[item.index]
+			}
+			switch {
+			case item.checked && expected:
+				item.mark = markCorrect
+			case item.checked:
+				item.mark = markWrong
+			case expected:
+				item.mark = markMissed
+			}
+			cmds = append(cmds, p.choices.UpdateItem(item.ID(), item))
+		}
+	}
+
+	record := drill.Question{
+		SessionID: p.runID,
+		Topic:     p.quiz.TopicOf(question),
+		Text:      question.Prompt,
+		Answer:    strings.Join(p.result.Expected, "; "),
+	}
+	score := p.result.Score
+	cmds = append(cmds, func() tea.Msg {
+		if _, err := p.app.Drills.Record(context.Background(), record, score); err != nil {
+			return recordFailedMsg{err: err}
+		}
+		return nil
+	})
+	return tea.Batch(cmds...)
+}
+
+// showQuestion resets the page for the current question.
+func (p *quizPage) showQuestion() tea.Cmd {
+	p.answered = false
+	p.response = quiz.Response{}
+	p.result = quiz.Result{}
+	question, ok := p.currentQuestion()
+	if !ok {
+		return nil
+	}
+	if question.Type == quiz.TypeShort {
+		p.input.Reset()
+		return p.input.Focus()
+	}
+
+	options := question.Options()
+	items := make([]*choiceItem, len(options))
+	for i, option := range options {
+		items[i] = newChoiceItem(i, option, question.Type == quiz.TypeMulti)
+	}
+	p.choices = list.New(items,
+		list.WithFocus(true),
+		list.WithGap(1),
+		list.WithWrapNavigation(),
+		list.WithSelectedItem(items[0].ID()),
+	)
+	return tea.Batch(p.choices.Init(), p.choices.SetSize(p.contentWidth(), p.listHeight()))
+}
+
+func (p *quizPage) currentQuestion() (quiz.Question, bool) {
+	if p.quiz == nil || p.current >= len(p.quiz.Questions) {
+		return quiz.Question{}, false
+	}
+	return p.quiz.Questions[p.current], true
+}
+
+func (p *quizPage) contentWidth() int {
+	return max(min(p.width-4, 80), 10)
+}
+
+func (p *quizPage) View() string {
+	t := styles.CurrentTheme()
+	width := p.contentWidth()
+
+	var content []string
+	question, ok := p.currentQuestion()
+	switch {
+	case p.quiz == nil:
+		content = append(content, t.S().Muted.Render("Loading quiz..."))
+	case !ok:
+		total := 0
+		correct := 0
+		for _, score := range p.scores {
+			total += score
+			if score == 100 {
+				correct++
+			}
+		}
+		content = append(content,
+			t.S().Title.Render("Quiz done"),
+			"",
+			t.S().Text.Render(fmt.Sprintf("%d of %d correct, %d%% overall.", correct, len(p.scores), total/max(len(p.scores), 1))),
+			"",
+			t.S().Muted.Width(width).Render("The results count towards your progress; see them with prepf progress."),
+		)
+	default:
+		header := fmt.Sprintf("%s · Question %d of %d", p.quiz.Title, p.current+1, len(p.quiz.Questions))
+		if p.quiz.Title == "" {
+			header = fmt.Sprintf("Question %d of %d", p.current+1, len(p.quiz.Questions))
+		}
+		content = append(content,
+			core.Section(header, width),
+			"",
+			t.S().Text.Bold(true).Width(width).Render(question.Prompt),
+			"",
+		)
+		if question.Type == quiz.TypeShort {
+			p.input.SetWidth(width - 2)
+			content = append(content, p.input.View())
+		} else {
+			content = append(content, p.choices.View())
+		}
+		content = append(content, "")
+		if p.answered {
+			content = append(content, p.feedback(question, width)...)
+		} else {
+			content = append(content, t.S().Muted.Render(p.instructions(question)))
+		}
+	}
+
+	return lipgloss.Place(p.width, p.height, lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Left, content...),
+	)
+}
+
+func (p *quizPage) feedback(question quiz.Question, width int) []string {
+	t := styles.CurrentTheme()
+	var lines []string
+	switch {
+	case p.result.Correct:
+		lines = append(lines, t.S().Base.Foreground(t.Success).Render("✓ Correct"))
+	case p.result.Score > 0:
+		lines = append(lines, t.S().Base.Foreground(t.Warning).Render(fmt.Sprintf("◐ Partly correct (%d%%)", p.result.Score)))
+	default:
+		lines = append(lines, t.S().Base.Foreground(t.Error).Render("✗ Incorrect"))
+	}
+	if question.Type == quiz.TypeShort && !p.result.Correct && len(p.result.Expected) > 0 {
+		lines = append(lines, t.S().Text.Width(width).Render("Answer: "+strings.Join(p.result.Expected, "; ")))
+	}
+	if question.Explanation != "" {
+		lines = append(lines, "", t.S().Muted.Width(width).Render(question.Explanation))
+	}
+	hint := "Press enter to continue."
+	if !p.result.Correct {
+		hint = "Press enter to continue, or e to ask the model why."
+	}
+	return append(lines, "", t.S().Muted.Render(hint))
+}
+
+func (p *quizPage) instructions(question quiz.Question) string {
+	switch question.Type {
+	case quiz.TypeShort:
+		return "Type your answer and press enter."
+	case quiz.TypeMulti:
+		return "Select every correct choice with space, then press enter."
+	default:
+		return "Pick a choice and press enter."
+	}
+}
+
+func (p *quizPage) SetSize(width, height int) tea.Cmd {
+	p.width = width
+	p.height = height
+	if _, ok := p.currentQuestion(); ok && p.choices != nil {
+		return p.choices.SetSize(p.contentWidth(), p.listHeight())
+	}
+	return nil
+}
+
+// listHeight fits the choices with a line between them, leaving it to the
+// list to scroll when long choices wrap.
+func (p *quizPage) listHeight() int {
+	return max(len(p.choices.Items())*2-1, 1)
+}
+
+func (p *quizPage) GetSize() (int, int) {
+	return p.width, p.height
+}
+
+func (p *quizPage) Help() help.KeyMap {
+	var bindings []key.Binding
+	question, ok := p.currentQuestion()
+	switch {
+	case !ok:
+	case p.answered:
+		bindings = append(bindings, p.keyMap.Continue)
+		if !p.result.Correct {
+			bindings = append(bindings, p.keyMap.Explain)
+		}
+	case question.Type == quiz.TypeShort:
+		bindings = append(bindings, p.keyMap.Submit)
+	case question.Type == quiz.TypeMulti:
+		bindings = append(bindings, p.keyMap.Previous, p.keyMap.Next, p.keyMap.Toggle, p.keyMap.Submit)
+	default:
+		bindings = append(bindings, p.keyMap.Previous, p.keyMap.Next, p.keyMap.Submit)
+	}
+	bindings = append(bindings, p.keyMap.Back)
+	return core.NewSimpleHelp(bindings, [][]key.Binding{bindings})
+}
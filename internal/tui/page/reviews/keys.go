@@ -0,0 +1,73 @@
+package reviews
+
+import (
+	"charm.land/bubbles/v2/key"
+)
+
+type KeyMap struct {
+	Down     key.Binding
+	Up       key.Binding
+	PageDown key.Binding
+	PageUp   key.Binding
+	Layout   key.Binding
+	Comment  key.Binding
+	Undo     key.Binding
+	Submit   key.Binding
+	Discuss  key.Binding
+	Save     key.Binding
+	Cancel   key.Binding
+	Back     key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓", "scroll down"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑", "scroll up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", "f", "space"),
+			key.WithHelp("pgdn", "page down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "b"),
+			key.WithHelp("pgup", "page up"),
+		),
+		Layout: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "split/unified"),
+		),
+		Comment: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "comment"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "remove last comment"),
+		),
+		Submit: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "submit review"),
+		),
+		Discuss: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "discuss with interviewer"),
+		),
+		Save: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "add comment"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc", "back to chat"),
+		),
+	}
+}
@@ -0,0 +1,345 @@
+// Package reviews implements the code review page: the user reads a change
+// with seeded issues and leaves comments on its lines, which are matched
+// against the issues when the review is submitted.
+package reviews
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/google/uuid"
+	"github.com/trankhanh040147/prepf/internal/app"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/review"
+	"github.com/trankhanh040147/prepf/internal/tui/components/core"
+	"github.com/trankhanh040147/prepf/internal/tui/components/core/layout"
+	"github.com/trankhanh040147/prepf/internal/tui/exp/diffview"
+	"github.com/trankhanh040147/prepf/internal/tui/page"
+	"github.com/trankhanh040147/prepf/internal/tui/page/chat"
+	"github.com/trankhanh040147/prepf/internal/tui/styles"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+var ReviewPageID page.PageID = "review"
+
+// StartMsg loads the named exercise and starts a new review of it.
+type StartMsg struct {
+	Name string
+}
+
+// DiscussMsg asks the model to go over a submitted review. Prompt stands on
+// its own and is meant to be sent to the chat.
+type DiscussMsg struct {
+	Prompt string
+}
+
+type loadedMsg struct {
+	exercise *review.Exercise
+	err      error
+}
+
+type recordFailedMsg struct {
+	err error
+}
+
+type ReviewPage interface {
+	util.Model
+	layout.Sizeable
+	core.KeyMapHelp
+}
+
+type reviewPage struct {
+	app    *app.App
+	keyMap KeyMap
+
+	width, height int
+
+	exercise *review.Exercise
+	split    bool
+	yOffset  int
+
+	commenting bool
+	input      textinput.Model
+	comments   []review.Comment
+	report     *review.Report
+}
+
+func New(app *app.App) ReviewPage {
+	input := textinput.New()
+	input.Placeholder = "12: this read races with the writer"
+	input.Prompt = "> "
+	input.SetStyles(styles.CurrentTheme().S().TextInput)
+	return &reviewPage{
+		app:    app,
+		keyMap: DefaultKeyMap(),
+		input:  input,
+	}
+}
+
+func (p *reviewPage) Init() tea.Cmd {
+	return nil
+}
+
+func (p *reviewPage) load(name string) tea.Cmd {
+	return func() tea.Msg {
+		available, _ := review.Exercises.Discover(review.Exercises.Dirs(p.app.Config()))
+		e, ok := review.Exercises.Find(available, name)
+		if !ok {
+			return loadedMsg{err: fmt.Errorf("unknown review exercise %q", name)}
+		}
+		return loadedMsg{exercise: e}
+	}
+}
+
+func (p *reviewPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case styles.ThemeChangedMsg:
+		p.input.SetStyles(styles.CurrentTheme().S().TextInput)
+		return p, nil
+	case StartMsg:
+		p.exercise = nil
+		return p, p.load(msg.Name)
+	case loadedMsg:
+		if msg.err != nil {
+			return p, tea.Batch(
+				util.ReportError(msg.err),
+				util.CmdHandler(page.PageChangeMsg{ID: chat.ChatPageID}),
+			)
+		}
+		p.exercise = msg.exercise
+		p.split = p.width > 120
+		p.yOffset = 0
+		p.comments = nil
+		p.report = nil
+		p.commenting = false
+		return p, nil
+	case recordFailedMsg:
+		return p, util.ReportError(msg.err)
+	case tea.KeyPressMsg:
+		return p, p.handleKey(msg)
+	}
+	return p, nil
+}
+
+func (p *reviewPage) handleKey(msg tea.KeyPressMsg) tea.Cmd {
+	if p.commenting {
+		switch {
+		case key.Matches(msg, p.keyMap.Cancel):
+			p.commenting = false
+			p.input.Blur()
+			return nil
+		case key.Matches(msg, p.keyMap.Save):
+			return p.addComment()
+		}
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		return cmd
+	}
+
+	switch {
+	case key.Matches(msg, p.keyMap.Back):
+		return util.CmdHandler(page.PageChangeMsg{ID: chat.ChatPageID})
+	case p.exercise == nil:
+		return nil
+	case key.Matches(msg, p.keyMap.Down):
+		p.scroll(1)
+	case key.Matches(msg, p.keyMap.Up):
+		p.scroll(-1)
+	case key.Matches(msg, p.keyMap.PageDown):
+		p.scroll(p.diffHeight())
+	case key.Matches(msg, p.keyMap.PageUp):
+		p.scroll(-p.diffHeight())
+	case key.Matches(msg, p.keyMap.Layout):
+		p.split = !p.split
+		p.scroll(0)
+	case p.report != nil:
+		if key.Matches(msg, p.keyMap.Discuss) {
+			return util.CmdHandler(DiscussMsg{Prompt: review.DiscussPrompt(p.exercise, p.comments, *p.report)})
+		}
+	case key.Matches(msg, p.keyMap.Comment):
+		p.commenting = true
+		p.input.Reset()
+		return p.input.Focus()
+	case key.Matches(msg, p.keyMap.Undo):
+		if len(p.comments) > 0 {
+			p.comments = p.comments[:len(p.comments)-1]
+		}
+	case key.Matches(msg, p.keyMap.Submit):
+		return p.submit()
+	}
+	return nil
+}
+
+func (p *reviewPage) addComment() tea.Cmd {
+	comment, err := review.ParseComment(p.input.Value())
+	if err != nil {
+		return util.ReportWarn(err.Error())
+	}
+	lines := strings.Count(strings.TrimSuffix(p.exercise.After, "\n"), "\n") + 1
+	if comment.Line < 1 || comment.Line > lines {
+		return util.ReportWarn(fmt.Sprintf("Line %d is outside the changed file (1-%d)", comment.Line, lines))
+	}
+	p.comments = append(p.comments, comment)
+	p.commenting = false
+	p.input.Blur()
+	return nil
+}
+
+// submit grades the review and records the result so it shows up in the
+// progress stats.
+func (p *reviewPage) submit() tea.Cmd {
+	if len(p.comments) == 0 {
+		return util.ReportWarn("Leave at least one comment before submitting")
+	}
+	report := review.Grade(p.exercise, p.comments)
+	p.report = &report
+
+	var missed []string
+	for _, issue := range report.Missed {
+		missed = append(missed, fmt.Sprintf("line %d: %s", issue.Line, issue.Description))
+	}
+	record := drill.Question{
+		SessionID: "review-" + uuid.NewString(),
+		Topic:     p.exercise.TopicOf(),
+		Text:      "Review " + p.exercise.File + ": " + p.exercise.Title,
+		Answer:    strings.Join(missed, "\n"),
+	}
+	return func() tea.Msg {
+		if _, err := p.app.Drills.Record(context.Background(), record, report.Score); err != nil {
+			return recordFailedMsg{err: err}
+		}
+		return nil
+	}
+}
+
+// scroll moves the diff by delta lines, keeping the last line on screen.
+func (p *reviewPage) scroll(delta int) {
+	total := lipgloss.Height(p.formatter().String())
+	p.yOffset = max(min(p.yOffset+delta, total-p.diffHeight()), 0)
+}
+
+func (p *reviewPage) formatter() *diffview.DiffView {
+	e := p.exercise
+	context := strings.Count(e.Before, "\n") + strings.Count(e.After, "\n") + 1
+	formatter := core.DiffFormatter().
+		Before(e.File, e.Before).
+		After(e.File, e.After).
+		ContextLines(context).
+		Width(p.width - 2)
+	if p.split {
+		formatter = formatter.Split()
+	}
+	return formatter
+}
+
+func (p *reviewPage) View() string {
+	t := styles.CurrentTheme()
+	if p.exercise == nil {
+		return lipgloss.Place(p.width, p.height, lipgloss.Center, lipgloss.Center,
+			t.S().Muted.Render("Loading exercise..."))
+	}
+
+	header := p.header()
+	footer := p.footer()
+	// Pad the diff so the comments stay at the bottom of the page.
+	height := max(p.height-lipgloss.Height(header)-lipgloss.Height(footer), 1)
+	diff := t.S().Base.Height(height).Render(
+		p.formatter().Height(height).YOffset(p.yOffset).String(),
+	)
+
+	return t.S().Base.Width(p.width).Height(p.height).PaddingLeft(1).Render(
+		lipgloss.JoinVertical(lipgloss.Left, header, diff, footer),
+	)
+}
+
+func (p *reviewPage) header() string {
+	t := styles.CurrentTheme()
+	width := p.width - 2
+	title := p.exercise.Title
+	if title == "" {
+		title = p.exercise.Name
+	}
+	lines := []string{core.Section(title+" · "+p.exercise.File, width)}
+	if p.exercise.Description != "" {
+		lines = append(lines, t.S().Muted.Width(width).Render(p.exercise.Description))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, append(lines, "")...)
+}
+
+// footer shows the comments and the comment input while reviewing, and the
+// report once the review is submitted.
+func (p *reviewPage) footer() string {
+	t := styles.CurrentTheme()
+	width := p.width - 2
+	var lines []string
+	if p.report != nil {
+		lines = append(lines, "", core.Section(fmt.Sprintf("Caught %d of %d issues (%d%%)", len(p.report.Caught), len(p.exercise.Issues), p.report.Score), width))
+		for _, f := range p.report.Caught {
+			lines = append(lines, t.S().Base.Foreground(t.Success).Width(width).Render(
+				fmt.Sprintf("✓ L%d %s: %s — you said: %s", f.Issue.Line, f.Issue.Kind, f.Issue.Description, f.Comment.Text)))
+		}
+		for _, issue := range p.report.Missed {
+			lines = append(lines, t.S().Base.Foreground(t.Error).Width(width).Render(
+				fmt.Sprintf("✗ L%d %s: %s", issue.Line, issue.Kind, issue.Description)))
+		}
+		for _, c := range p.report.Unmatched {
+			lines = append(lines, t.S().Muted.Width(width).Render(fmt.Sprintf("· L%d %s", c.Line, c.Text)))
+		}
+		lines = append(lines, "", t.S().Muted.Render("Press d to go over the review with the interviewer."))
+		return lipgloss.JoinVertical(lipgloss.Left, lines...)
+	}
+
+	lines = append(lines, "", core.Section(fmt.Sprintf("Comments (%d)", len(p.comments)), width))
+	for _, c := range p.comments {
+		lines = append(lines, t.S().Text.Width(width).Render(fmt.Sprintf("L%d %s", c.Line, c.Text)))
+	}
+	if p.commenting {
+		p.input.SetWidth(width - 2)
+		lines = append(lines, p.input.View())
+	} else {
+		lines = append(lines, t.S().Muted.Render("Press c to comment on a line of the new file, then s to submit the review."))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func (p *reviewPage) diffHeight() int {
+	if p.exercise == nil {
+		return 0
+	}
+	return max(p.height-lipgloss.Height(p.header())-lipgloss.Height(p.footer()), 1)
+}
+
+func (p *reviewPage) SetSize(width, height int) tea.Cmd {
+	p.width = width
+	p.height = height
+	if p.exercise != nil {
+		p.scroll(0)
+	}
+	return nil
+}
+
+func (p *reviewPage) GetSize() (int, int) {
+	return p.width, p.height
+}
+
+func (p *reviewPage) Help() help.KeyMap {
+	var bindings []key.Binding
+	switch {
+	case p.commenting:
+		bindings = append(bindings, p.keyMap.Save, p.keyMap.Cancel)
+		return core.NewSimpleHelp(bindings, [][]key.Binding{bindings})
+	case p.exercise == nil:
+	case p.report != nil:
+		bindings = append(bindings, p.keyMap.Down, p.keyMap.Up, p.keyMap.Layout, p.keyMap.Discuss)
+	default:
+		bindings = append(bindings, p.keyMap.Down, p.keyMap.Up, p.keyMap.Layout, p.keyMap.Comment, p.keyMap.Undo, p.keyMap.Submit)
+	}
+	bindings = append(bindings, p.keyMap.Back)
+	return core.NewSimpleHelp(bindings, [][]key.Binding{bindings})
+}
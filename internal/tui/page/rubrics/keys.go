@@ -0,0 +1,71 @@
+package rubrics
+
+import (
+	"charm.land/bubbles/v2/key"
+)
+
+type KeyMap struct {
+	// List
+	Down key.Binding
+	Up   key.Binding
+	Edit key.Binding
+	New  key.Binding
+	Back key.Binding
+
+	// Form
+	Next            key.Binding
+	Previous        key.Binding
+	AddDimension    key.Binding
+	RemoveDimension key.Binding
+	Save            key.Binding
+	Cancel          key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓", "next"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑", "previous"),
+		),
+		Edit: key.NewBinding(
+			key.WithKeys("enter", "e"),
+			key.WithHelp("enter", "edit"),
+		),
+		New: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "new rubric"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc", "back to chat"),
+		),
+		Next: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next field"),
+		),
+		Previous: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "previous field"),
+		),
+		AddDimension: key.NewBinding(
+			key.WithKeys("alt+n"),
+			key.WithHelp("alt+n", "add dimension"),
+		),
+		RemoveDimension: key.NewBinding(
+			key.WithKeys("alt+x"),
+			key.WithHelp("alt+x", "remove dimension"),
+		),
+		Save: key.NewBinding(
+			key.WithKeys("ctrl+o"),
+			key.WithHelp("ctrl+o", "save"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "discard changes"),
+		),
+	}
+}
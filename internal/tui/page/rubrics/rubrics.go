@@ -0,0 +1,531 @@
+// Package rubrics implements the page listing the grading rubrics and the
+// form they are edited in.
+package rubrics
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textarea"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/trankhanh040147/prepf/internal/app"
+	"github.com/trankhanh040147/prepf/internal/rubric"
+	"github.com/trankhanh040147/prepf/internal/tui/components/core"
+	"github.com/trankhanh040147/prepf/internal/tui/components/core/layout"
+	"github.com/trankhanh040147/prepf/internal/tui/page"
+	"github.com/trankhanh040147/prepf/internal/tui/page/chat"
+	"github.com/trankhanh040147/prepf/internal/tui/styles"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+var RubricsPageID page.PageID = "rubrics"
+
+// LoadMsg reloads the rubrics. It is sent whenever the page is opened.
+type LoadMsg struct{}
+
+type loadedMsg struct {
+	rubrics  []*rubric.Rubric
+	problems []rubric.Problem
+}
+
+type savedMsg struct {
+	rubric *rubric.Rubric
+	path   string
+	err    error
+}
+
+type RubricsPage interface {
+	util.Model
+	layout.Sizeable
+	core.KeyMapHelp
+}
+
+// The form's inputs are addressed by slot: first the rubric's own, then
+// those of each dimension in turn.
+const (
+	slotName = iota
+	slotTitle
+	slotDescription
+	headerSlots
+)
+
+const (
+	slotDimensionName = iota
+	slotDimensionWeight
+	slotDimensionDescription
+	slotDimensionLevels
+	dimensionSlots
+)
+
+// dimensionFields are the inputs of a dimension in the form.
+type dimensionFields struct {
+	name        textinput.Model
+	weight      textinput.Model
+	description textinput.Model
+	levels      textarea.Model
+}
+
+type rubricsPage struct {
+	app    *app.App
+	keyMap KeyMap
+
+	width, height int
+
+	loaded  bool
+	rubrics []*rubric.Rubric
+	cursor  int
+	// selected is the name of the rubric to select once the rubrics are
+	// reloaded, such as the one just saved.
+	selected string
+
+	// editing is set while the form is shown. original is the revision of
+	// the rubric the form started from, or nil for a new rubric.
+	editing     bool
+	original    *rubric.Rubric
+	name        textinput.Model
+	title       textinput.Model
+	description textinput.Model
+	dimensions  []dimensionFields
+	focus       int
+}
+
+func New(app *app.App) RubricsPage {
+	return &rubricsPage{
+		app:    app,
+		keyMap: DefaultKeyMap(),
+	}
+}
+
+func (p *rubricsPage) Init() tea.Cmd {
+	return p.load
+}
+
+func (p *rubricsPage) load() tea.Msg {
+	rubrics, problems := rubric.Discover(rubric.Dirs(p.app.Config()))
+	return loadedMsg{rubrics: rubrics, problems: problems}
+}
+
+func (p *rubricsPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case styles.ThemeChangedMsg:
+		p.restyle()
+		return p, nil
+	case LoadMsg:
+		return p, p.load
+	case loadedMsg:
+		p.loaded = true
+		p.rubrics = msg.rubrics
+		for i, r := range p.rubrics {
+			if r.Name == p.selected {
+				p.cursor = i
+			}
+		}
+		p.selected = ""
+		p.cursor = min(p.cursor, max(len(p.rubrics)-1, 0))
+		var cmds []tea.Cmd
+		for _, problem := range msg.problems {
+			cmds = append(cmds, util.ReportError(problem))
+		}
+		return p, tea.Batch(cmds...)
+	case savedMsg:
+		if msg.err != nil {
+			return p, util.ReportError(msg.err)
+		}
+		p.editing = false
+		p.selected = msg.rubric.Name
+		return p, tea.Batch(
+			util.ReportInfo(fmt.Sprintf("Saved %s to %s", msg.rubric.Ref(), msg.path)),
+			p.load,
+		)
+	case tea.PasteMsg:
+		if p.editing {
+			return p, p.updateFocused(msg)
+		}
+	case tea.KeyPressMsg:
+		if p.editing {
+			return p, p.handleFormKey(msg)
+		}
+		return p, p.handleListKey(msg)
+	}
+	return p, nil
+}
+
+func (p *rubricsPage) handleListKey(msg tea.KeyPressMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, p.keyMap.Back):
+		return util.CmdHandler(page.PageChangeMsg{ID: chat.ChatPageID})
+	case key.Matches(msg, p.keyMap.New):
+		return p.edit(nil)
+	case len(p.rubrics) == 0:
+		return nil
+	case key.Matches(msg, p.keyMap.Down):
+		p.cursor = min(p.cursor+1, len(p.rubrics)-1)
+	case key.Matches(msg, p.keyMap.Up):
+		p.cursor = max(p.cursor-1, 0)
+	case key.Matches(msg, p.keyMap.Edit):
+		return p.edit(p.rubrics[p.cursor])
+	}
+	return nil
+}
+
+func (p *rubricsPage) handleFormKey(msg tea.KeyPressMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, p.keyMap.Cancel):
+		p.editing = false
+		return nil
+	case key.Matches(msg, p.keyMap.Save):
+		return p.save()
+	case key.Matches(msg, p.keyMap.Next):
+		return p.move(1)
+	case key.Matches(msg, p.keyMap.Previous):
+		return p.move(-1)
+	case key.Matches(msg, p.keyMap.AddDimension):
+		i := len(p.dimensions)
+		if d, ok := p.focusedDimension(); ok {
+			i = d + 1
+		}
+		p.dimensions = slices.Insert(p.dimensions, i, p.newDimension(rubric.Dimension{}))
+		return p.setFocus(headerSlots + i*dimensionSlots)
+	case key.Matches(msg, p.keyMap.RemoveDimension):
+		d, ok := p.focusedDimension()
+		if !ok {
+			return nil
+		}
+		if len(p.dimensions) == 1 {
+			return util.ReportWarn("A rubric needs at least one dimension")
+		}
+		p.dimensions = slices.Delete(p.dimensions, d, d+1)
+		return p.setFocus(headerSlots + min(d, len(p.dimensions)-1)*dimensionSlots)
+	}
+	return p.updateFocused(msg)
+}
+
+// edit opens the form on a rubric, or on an empty one if r is nil.
+func (p *rubricsPage) edit(r *rubric.Rubric) tea.Cmd {
+	p.editing = true
+	p.original = r
+	p.name = p.newInput("interview", "")
+	p.title = p.newInput("Technical interview answer", "")
+	p.description = p.newInput("What the rubric grades", "")
+	p.dimensions = nil
+	if r == nil {
+		p.dimensions = append(p.dimensions, p.newDimension(rubric.Dimension{}))
+		return p.setFocus(slotName)
+	}
+	p.name.SetValue(r.Name)
+	p.title.SetValue(r.Title)
+	p.description.SetValue(r.Description)
+	for _, d := range r.Dimensions {
+		p.dimensions = append(p.dimensions, p.newDimension(d))
+	}
+	return p.setFocus(slotTitle)
+}
+
+func (p *rubricsPage) newInput(placeholder, value string) textinput.Model {
+	input := textinput.New()
+	input.SetStyles(styles.CurrentTheme().S().TextInput)
+	input.Prompt = "> "
+	input.Placeholder = placeholder
+	input.SetValue(value)
+	input.SetWidth(max(p.width-8, 10))
+	return input
+}
+
+func (p *rubricsPage) newDimension(d rubric.Dimension) dimensionFields {
+	weight := ""
+	if d.Weight != 0 {
+		weight = strconv.FormatFloat(d.Weight, 'g', -1, 64)
+	}
+	levels := textarea.New()
+	levels.SetStyles(styles.CurrentTheme().S().TextArea)
+	levels.ShowLineNumbers = false
+	levels.CharLimit = -1
+	levels.Placeholder = "0 | Vague | Hand-waving with no example\n  - We would just scale it\n1 | Concrete | Backs its claims with numbers or code"
+	levels.SetWidth(max(p.width-6, 10))
+	levels.SetHeight(6)
+	levels.SetValue(rubric.FormatLevels(d.Levels))
+	return dimensionFields{
+		name:        p.newInput("correctness", d.Name),
+		weight:      p.newInput("1", weight),
+		description: p.newInput("What the dimension grades", d.Description),
+		levels:      levels,
+	}
+}
+
+func (p *rubricsPage) restyle() {
+	t := styles.CurrentTheme()
+	p.name.SetStyles(t.S().TextInput)
+	p.title.SetStyles(t.S().TextInput)
+	p.description.SetStyles(t.S().TextInput)
+	for i := range p.dimensions {
+		p.dimensions[i].name.SetStyles(t.S().TextInput)
+		p.dimensions[i].weight.SetStyles(t.S().TextInput)
+		p.dimensions[i].description.SetStyles(t.S().TextInput)
+		p.dimensions[i].levels.SetStyles(t.S().TextArea)
+	}
+}
+
+func (p *rubricsPage) slots() int {
+	return headerSlots + len(p.dimensions)*dimensionSlots
+}
+
+// focusedDimension returns the index of the dimension the focused input
+// belongs to.
+func (p *rubricsPage) focusedDimension() (int, bool) {
+	if p.focus < headerSlots {
+		return 0, false
+	}
+	return (p.focus - headerSlots) / dimensionSlots, true
+}
+
+// move focuses the next or previous input, skipping the name of a rubric
+// that already exists since the name is its file name.
+func (p *rubricsPage) move(delta int) tea.Cmd {
+	slot := (p.focus + delta + p.slots()) % p.slots()
+	if slot == slotName && p.original != nil {
+		slot = (slot + delta + p.slots()) % p.slots()
+	}
+	return p.setFocus(slot)
+}
+
+func (p *rubricsPage) setFocus(slot int) tea.Cmd {
+	p.name.Blur()
+	p.title.Blur()
+	p.description.Blur()
+	for i := range p.dimensions {
+		p.dimensions[i].name.Blur()
+		p.dimensions[i].weight.Blur()
+		p.dimensions[i].description.Blur()
+		p.dimensions[i].levels.Blur()
+	}
+	p.focus = slot
+	if input := p.focusedInput(); input != nil {
+		return input.Focus()
+	}
+	if d, ok := p.focusedDimension(); ok {
+		return p.dimensions[d].levels.Focus()
+	}
+	return nil
+}
+
+// focusedInput returns the focused single line input, or nil if the
+// focused input is the levels of a dimension.
+func (p *rubricsPage) focusedInput() *textinput.Model {
+	switch p.focus {
+	case slotName:
+		return &p.name
+	case slotTitle:
+		return &p.title
+	case slotDescription:
+		return &p.description
+	}
+	d, _ := p.focusedDimension()
+	if d >= len(p.dimensions) {
+		return nil
+	}
+	switch (p.focus - headerSlots) % dimensionSlots {
+	case slotDimensionName:
+		return &p.dimensions[d].name
+	case slotDimensionWeight:
+		return &p.dimensions[d].weight
+	case slotDimensionDescription:
+		return &p.dimensions[d].description
+	}
+	return nil
+}
+
+func (p *rubricsPage) updateFocused(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	if input := p.focusedInput(); input != nil {
+		*input, cmd = input.Update(msg)
+		return cmd
+	}
+	if d, ok := p.focusedDimension(); ok && d < len(p.dimensions) {
+		p.dimensions[d].levels, cmd = p.dimensions[d].levels.Update(msg)
+	}
+	return cmd
+}
+
+// save builds the rubric from the form and writes it, raising its version
+// if the dimensions changed. A built-in rubric is saved to the project's
+// rubrics directory, where it takes precedence.
+func (p *rubricsPage) save() tea.Cmd {
+	r := &rubric.Rubric{
+		Name:        strings.TrimSpace(p.name.Value()),
+		Title:       strings.TrimSpace(p.title.Value()),
+		Description: strings.TrimSpace(p.description.Value()),
+	}
+	if p.original != nil {
+		r.Version = p.original.Version
+		r.Path = p.original.Path
+	} else if _, ok := rubric.Find(p.rubrics, r.Name); ok {
+		return util.ReportWarn(fmt.Sprintf("A rubric named %s already exists; edit it instead", r.Name))
+	}
+	for i, fields := range p.dimensions {
+		d := rubric.Dimension{
+			Name:        strings.TrimSpace(fields.name.Value()),
+			Description: strings.TrimSpace(fields.description.Value()),
+		}
+		label := cmp.Or(d.Name, fmt.Sprintf("dimension %d", i+1))
+		if weight := strings.TrimSpace(fields.weight.Value()); weight != "" {
+			w, err := strconv.ParseFloat(weight, 64)
+			if err != nil {
+				return util.ReportWarn(fmt.Sprintf("%s: weight must be a number", label))
+			}
+			d.Weight = w
+		}
+		levels, err := rubric.ParseLevels(fields.levels.Value())
+		if err != nil {
+			return util.ReportWarn(fmt.Sprintf("%s: %v", label, err))
+		}
+		d.Levels = levels
+		r.Dimensions = append(r.Dimensions, d)
+	}
+	r.Bump(p.original)
+
+	dir := rubric.Dirs(p.app.Config())[0]
+	return func() tea.Msg {
+		path, err := rubric.Save(dir, r)
+		return savedMsg{rubric: r, path: path, err: err}
+	}
+}
+
+func (p *rubricsPage) View() string {
+	t := styles.CurrentTheme()
+	width := max(p.width-4, 10)
+	var content string
+	switch {
+	case p.editing:
+		content = p.renderForm(width)
+	case !p.loaded:
+		content = lipgloss.JoinVertical(lipgloss.Left, core.Section("Rubrics", width), "", t.S().Muted.Render("Loading rubrics..."))
+	default:
+		content = p.renderList(width)
+	}
+	return t.S().Base.Padding(0, 2).Render(content)
+}
+
+func (p *rubricsPage) renderList(width int) string {
+	t := styles.CurrentTheme()
+	lines := []string{core.Section("Rubrics", width), ""}
+	for i, r := range p.rubrics {
+		source := "built-in"
+		if r.Path != "" {
+			source = r.Path
+		}
+		name := fmt.Sprintf("%-16s v%-3d", r.Name, r.Version)
+		line := ansi.Truncate(name+" "+t.S().Subtle.Render(source), width-2, "…")
+		if i == p.cursor {
+			lines = append(lines, t.S().Text.Bold(true).Background(t.BgSubtle).Render("› "+line))
+		} else {
+			lines = append(lines, t.S().Text.Render("  "+line))
+		}
+	}
+	if len(p.rubrics) > 0 {
+		lines = append(lines, "", p.renderDetails(p.rubrics[p.cursor], width))
+	}
+	// The details are cut rather than the list.
+	lines = strings.Split(strings.Join(lines, "\n"), "\n")
+	return strings.Join(lines[:min(len(lines), max(p.height, 1))], "\n")
+}
+
+func (p *rubricsPage) renderDetails(r *rubric.Rubric, width int) string {
+	t := styles.CurrentTheme()
+	lines := []string{core.Section(cmp.Or(r.Title, r.Name), width)}
+	if r.Description != "" {
+		lines = append(lines, t.S().Muted.Width(width).Render(r.Description))
+	}
+	for _, d := range r.Dimensions {
+		weight := d.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		lines = append(lines, "", t.S().Text.Bold(true).Render(d.Name)+t.S().Subtle.Render(fmt.Sprintf(" ×%g", weight)))
+		if d.Description != "" {
+			lines = append(lines, t.S().Muted.Width(width).Render(d.Description))
+		}
+		for _, l := range d.Levels {
+			prefix := t.S().Base.Foreground(t.Primary).Render(fmt.Sprintf("%d %s", l.Points, l.Label)) + " "
+			lines = append(lines, prefix+t.S().Text.Render(ansi.Truncate(l.Descriptor, width-lipgloss.Width(prefix), "…")))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (p *rubricsPage) renderForm(width int) string {
+	t := styles.CurrentTheme()
+	title := "New Rubric"
+	if p.original != nil {
+		title = "Edit " + p.original.Ref()
+	}
+	lines := []string{core.Section(title, width), ""}
+	// focusLine is where the focused input starts, to scroll it into view.
+	focusLine := 0
+	field := func(slot int, label, view string) {
+		style := t.S().Subtle
+		if slot == p.focus {
+			style = t.S().Base.Foreground(t.Primary).Bold(true)
+			focusLine = len(lines)
+		}
+		lines = append(lines, style.Render(label))
+		lines = append(lines, strings.Split(view, "\n")...)
+	}
+	if p.original == nil {
+		field(slotName, "Name (file name: lowercase letters, digits and hyphens)", p.name.View())
+	} else {
+		lines = append(lines, t.S().Subtle.Render("Name"), t.S().Text.Render(p.original.Name))
+	}
+	field(slotTitle, "Title", p.title.View())
+	field(slotDescription, "Description", p.description.View())
+	for i, d := range p.dimensions {
+		slot := headerSlots + i*dimensionSlots
+		lines = append(lines, "", core.Section(fmt.Sprintf("Dimension %d", i+1), width))
+		field(slot+slotDimensionName, "Name", d.name.View())
+		field(slot+slotDimensionWeight, "Weight (1 if empty)", d.weight.View())
+		field(slot+slotDimensionDescription, "Description", d.description.View())
+		field(slot+slotDimensionLevels, "Levels: one per line as points | label | descriptor, examples on indented lines", d.levels.View())
+	}
+
+	height := max(p.height, 1)
+	if len(lines) <= height {
+		return strings.Join(lines, "\n")
+	}
+	start := min(max(focusLine-height/3, 0), len(lines)-height)
+	return strings.Join(lines[start:start+height], "\n")
+}
+
+func (p *rubricsPage) SetSize(width, height int) tea.Cmd {
+	p.width = width
+	p.height = height
+	p.name.SetWidth(max(width-8, 10))
+	p.title.SetWidth(max(width-8, 10))
+	p.description.SetWidth(max(width-8, 10))
+	for i := range p.dimensions {
+		p.dimensions[i].name.SetWidth(max(width-8, 10))
+		p.dimensions[i].weight.SetWidth(max(width-8, 10))
+		p.dimensions[i].description.SetWidth(max(width-8, 10))
+		p.dimensions[i].levels.SetWidth(max(width-6, 10))
+	}
+	return nil
+}
+
+func (p *rubricsPage) GetSize() (int, int) {
+	return p.width, p.height
+}
+
+func (p *rubricsPage) Help() help.KeyMap {
+	if p.editing {
+		bindings := []key.Binding{p.keyMap.Next, p.keyMap.Previous, p.keyMap.AddDimension, p.keyMap.RemoveDimension, p.keyMap.Save, p.keyMap.Cancel}
+		return core.NewSimpleHelp(bindings, [][]key.Binding{bindings})
+	}
+	bindings := []key.Binding{p.keyMap.Up, p.keyMap.Down, p.keyMap.Edit, p.keyMap.New, p.keyMap.Back}
+	return core.NewSimpleHelp(bindings, [][]key.Binding{bindings})
+}
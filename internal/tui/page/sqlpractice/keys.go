@@ -0,0 +1,38 @@
+package sqlpractice
+
+import (
+	"charm.land/bubbles/v2/key"
+)
+
+type KeyMap struct {
+	Run     key.Binding
+	Revise  key.Binding
+	Next    key.Binding
+	Explain key.Binding
+	Back    key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Run: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "run query"),
+		),
+		Revise: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "revise query"),
+		),
+		Next: key.NewBinding(
+			key.WithKeys("enter", "n"),
+			key.WithHelp("enter", "next task"),
+		),
+		Explain: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "explain plan"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back to chat"),
+		),
+	}
+}
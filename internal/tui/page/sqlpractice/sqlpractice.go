@@ -0,0 +1,487 @@
+// Package sqlpractice implements the SQL practice page: the user answers
+// tasks with queries run against a scratch database, and their result set is
+// compared with the one of the reference query.
+package sqlpractice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textarea"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/table"
+	"github.com/google/uuid"
+	"github.com/trankhanh040147/prepf/internal/app"
+	"github.com/trankhanh040147/prepf/internal/drill"
+	"github.com/trankhanh040147/prepf/internal/sqlgym"
+	"github.com/trankhanh040147/prepf/internal/tui/components/core"
+	"github.com/trankhanh040147/prepf/internal/tui/components/core/layout"
+	"github.com/trankhanh040147/prepf/internal/tui/page"
+	"github.com/trankhanh040147/prepf/internal/tui/page/chat"
+	"github.com/trankhanh040147/prepf/internal/tui/styles"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+var SQLPageID page.PageID = "sql"
+
+// maxTableRows caps the rows shown in a result table, and in each half of
+// a diff table.
+const maxTableRows = 10
+
+// StartMsg loads the named exercise and starts it from the first task.
+type StartMsg struct {
+	Name string
+}
+
+// ExplainMsg asks the model to go over the query plan of an answer. Prompt
+// stands on its own and is meant to be sent to the chat.
+type ExplainMsg struct {
+	Prompt string
+}
+
+type loadedMsg struct {
+	exercise *sqlgym.Exercise
+	sandbox  *sqlgym.Sandbox
+	err      error
+}
+
+type ranMsg struct {
+	task       int
+	got        sqlgym.Result
+	comparison sqlgym.Comparison
+	err        error
+}
+
+type recordFailedMsg struct {
+	err error
+}
+
+type SQLPage interface {
+	util.Model
+	layout.Sizeable
+	core.KeyMapHelp
+}
+
+type sqlPage struct {
+	app    *app.App
+	keyMap KeyMap
+
+	width, height int
+
+	exercise *sqlgym.Exercise
+	sandbox  *sqlgym.Sandbox
+	// runID groups the recorded answers of one run of the exercise.
+	runID   string
+	current int
+	editor  textarea.Model
+	running bool
+	// ran is set once the query in the editor has been run; result holds
+	// the outcome until the query is revised.
+	ran    bool
+	result ranMsg
+	// graded is set once the current task has counted towards the progress
+	// stats: only the first run of a task is recorded.
+	graded bool
+	scores []int
+}
+
+func New(app *app.App) SQLPage {
+	editor := textarea.New()
+	editor.SetStyles(styles.CurrentTheme().S().TextArea)
+	editor.ShowLineNumbers = false
+	editor.CharLimit = -1
+	editor.Placeholder = "SELECT ..."
+	editor.SetHeight(6)
+	return &sqlPage{
+		app:    app,
+		keyMap: DefaultKeyMap(),
+		editor: editor,
+	}
+}
+
+func (p *sqlPage) Init() tea.Cmd {
+	return nil
+}
+
+func (p *sqlPage) load(name string) tea.Cmd {
+	return func() tea.Msg {
+		available, _ := sqlgym.Exercises.Discover(sqlgym.Exercises.Dirs(p.app.Config()))
+		e, ok := sqlgym.Exercises.Find(available, name)
+		if !ok {
+			return loadedMsg{err: fmt.Errorf("unknown SQL exercise %q", name)}
+		}
+		sandbox, err := sqlgym.Open(context.Background(), e)
+		if err != nil {
+			return loadedMsg{err: fmt.Errorf("setting up %s: %w", name, err)}
+		}
+		return loadedMsg{exercise: e, sandbox: sandbox}
+	}
+}
+
+func (p *sqlPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case styles.ThemeChangedMsg:
+		p.editor.SetStyles(styles.CurrentTheme().S().TextArea)
+		return p, nil
+	case StartMsg:
+		p.close()
+		p.exercise = nil
+		return p, p.load(msg.Name)
+	case loadedMsg:
+		if msg.err != nil {
+			return p, tea.Batch(
+				util.ReportError(msg.err),
+				util.CmdHandler(page.PageChangeMsg{ID: chat.ChatPageID}),
+			)
+		}
+		p.exercise = msg.exercise
+		p.sandbox = msg.sandbox
+		p.runID = "sql-" + uuid.NewString()
+		p.current = 0
+		p.scores = nil
+		return p, p.showTask()
+	case ranMsg:
+		p.running = false
+		if msg.task != p.current || p.sandbox == nil {
+			return p, nil
+		}
+		p.ran = true
+		p.result = msg
+		p.editor.Blur()
+		if msg.err != nil || p.graded {
+			return p, nil
+		}
+		return p, p.record(msg.comparison)
+	case recordFailedMsg:
+		return p, util.ReportError(msg.err)
+	case tea.PasteMsg:
+		if p.editor.Focused() {
+			var cmd tea.Cmd
+			p.editor, cmd = p.editor.Update(msg)
+			return p, cmd
+		}
+	case tea.KeyPressMsg:
+		return p, p.handleKey(msg)
+	}
+	return p, nil
+}
+
+func (p *sqlPage) handleKey(msg tea.KeyPressMsg) tea.Cmd {
+	if key.Matches(msg, p.keyMap.Back) {
+		p.close()
+		return util.CmdHandler(page.PageChangeMsg{ID: chat.ChatPageID})
+	}
+	task, ok := p.currentTask()
+	if !ok || p.running {
+		return nil
+	}
+
+	if p.ran {
+		switch {
+		case key.Matches(msg, p.keyMap.Revise):
+			p.ran = false
+			return p.editor.Focus()
+		case key.Matches(msg, p.keyMap.Explain) && p.result.err == nil:
+			return p.explain(task)
+		case key.Matches(msg, p.keyMap.Next):
+			p.current++
+			return p.showTask()
+		}
+		return nil
+	}
+
+	if key.Matches(msg, p.keyMap.Run) {
+		return p.run(task)
+	}
+	var cmd tea.Cmd
+	p.editor, cmd = p.editor.Update(msg)
+	return cmd
+}
+
+// run runs the reference query and the user's one and compares them. The
+// reference is run each time; the fixtures are small enough for it not to
+// matter.
+func (p *sqlPage) run(task sqlgym.Task) tea.Cmd {
+	query := p.editor.Value()
+	if strings.TrimSpace(query) == "" {
+		return util.ReportWarn("Write a query first")
+	}
+	p.running = true
+	sandbox, current := p.sandbox, p.current
+	return func() tea.Msg {
+		ctx := context.Background()
+		expected, err := sandbox.Query(ctx, task.Reference)
+		if err != nil {
+			return ranMsg{task: current, err: fmt.Errorf("reference query: %w", err)}
+		}
+		got, err := sandbox.Query(ctx, query)
+		if err != nil {
+			return ranMsg{task: current, err: err}
+		}
+		return ranMsg{
+			task:       current,
+			got:        got,
+			comparison: sqlgym.Compare(expected, got, task.Ordered),
+		}
+	}
+}
+
+// record saves the result of the first run of the task so it shows up in
+// the progress stats. Queries that don't run aren't graded.
+func (p *sqlPage) record(comparison sqlgym.Comparison) tea.Cmd {
+	task, _ := p.currentTask()
+	score := 0
+	if comparison.Match {
+		score = 100
+	}
+	p.graded = true
+	p.scores = append(p.scores, score)
+
+	record := drill.Question{
+		SessionID: p.runID,
+		Topic:     p.exercise.TopicOf(),
+		Text:      task.Prompt,
+		Answer:    task.Reference,
+	}
+	return func() tea.Msg {
+		if _, err := p.app.Drills.Record(context.Background(), record, score); err != nil {
+			return recordFailedMsg{err: err}
+		}
+		return nil
+	}
+}
+
+// explain gets the plan of the user's query and hands it to the chat. The
+// page is left for the chat, so the sandbox is closed once it's done.
+func (p *sqlPage) explain(task sqlgym.Task) tea.Cmd {
+	e, sandbox, query, comparison := p.exercise, p.sandbox, p.editor.Value(), p.result.comparison
+	p.sandbox = nil
+	return func() tea.Msg {
+		defer sandbox.Close()
+		plan, err := sandbox.Plan(context.Background(), query)
+		if err != nil {
+			return util.ReportError(fmt.Errorf("explaining the query: %w", err))()
+		}
+		return ExplainMsg{Prompt: sqlgym.ExplainPrompt(e, task, query, comparison, plan)}
+	}
+}
+
+// showTask resets the page for the current task, closing the sandbox when
+// the exercise is done.
+func (p *sqlPage) showTask() tea.Cmd {
+	p.ran = false
+	p.running = false
+	p.graded = false
+	p.result = ranMsg{}
+	p.editor.Reset()
+	if _, ok := p.currentTask(); !ok {
+		p.close()
+		p.editor.Blur()
+		return nil
+	}
+	return p.editor.Focus()
+}
+
+func (p *sqlPage) close() {
+	if p.sandbox != nil {
+		p.sandbox.Close()
+		p.sandbox = nil
+	}
+}
+
+func (p *sqlPage) currentTask() (sqlgym.Task, bool) {
+	if p.exercise == nil || p.sandbox == nil || p.current >= len(p.exercise.Tasks) {
+		return sqlgym.Task{}, false
+	}
+	return p.exercise.Tasks[p.current], true
+}
+
+func (p *sqlPage) contentWidth() int {
+	return max(min(p.width-4, 100), 10)
+}
+
+func (p *sqlPage) View() string {
+	t := styles.CurrentTheme()
+	width := p.contentWidth()
+
+	var content []string
+	task, ok := p.currentTask()
+	switch {
+	case p.exercise == nil:
+		content = append(content, t.S().Muted.Render("Setting up the database..."))
+	case !ok && p.current < len(p.exercise.Tasks):
+		content = append(content, t.S().Muted.Render("The exercise was closed; start it again with /sql "+p.exercise.Name+"."))
+	case !ok:
+		correct := 0
+		for _, score := range p.scores {
+			if score == 100 {
+				correct++
+			}
+		}
+		content = append(content,
+			t.S().Title.Render("SQL practice done"),
+			"",
+			t.S().Text.Render(fmt.Sprintf("%d of %d tasks right on the first run.", correct, len(p.exercise.Tasks))),
+			"",
+			t.S().Muted.Width(width).Render("The results count towards your progress; see them with prepf progress."),
+		)
+	default:
+		title := p.exercise.Title
+		if title == "" {
+			title = p.exercise.Name
+		}
+		p.editor.SetWidth(width)
+		content = append(content,
+			core.Section(fmt.Sprintf("%s · Task %d of %d", title, p.current+1, len(p.exercise.Tasks)), width),
+			"",
+			t.S().Muted.Width(width).Render(strings.TrimSpace(p.exercise.Schema)),
+			"",
+			t.S().Text.Bold(true).Width(width).Render(task.Prompt),
+			"",
+			p.editor.View(),
+			"",
+		)
+		switch {
+		case p.running:
+			content = append(content, t.S().Muted.Render("Running..."))
+		case p.ran:
+			content = append(content, p.feedback(width)...)
+		default:
+			content = append(content, t.S().Muted.Render("Write a query and press ctrl+r to run it."))
+		}
+	}
+
+	return lipgloss.Place(p.width, p.height, lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Left, content...),
+	)
+}
+
+// feedback shows the outcome of the last run: the error, or how the result
+// set differs from the expected one.
+func (p *sqlPage) feedback(width int) []string {
+	t := styles.CurrentTheme()
+	r := p.result
+	var lines []string
+	switch {
+	case r.err != nil:
+		lines = append(lines, t.S().Base.Foreground(t.Error).Width(width).Render("✗ "+r.err.Error()))
+	case r.comparison.Match:
+		lines = append(lines,
+			t.S().Base.Foreground(t.Success).Render(fmt.Sprintf("✓ Correct, %d row(s)", len(r.got.Rows))),
+			previewTable(r.got),
+		)
+	case r.comparison.Reason != "":
+		lines = append(lines,
+			t.S().Base.Foreground(t.Error).Width(width).Render("✗ Not quite: "+r.comparison.Reason),
+			previewTable(r.got),
+		)
+	default:
+		lines = append(lines,
+			t.S().Base.Foreground(t.Error).Width(width).Render(fmt.Sprintf("✗ %d expected row(s) missing (-), %d unexpected row(s) (+)", len(r.comparison.Missing), len(r.comparison.Extra))),
+			p.diffTable(),
+		)
+	}
+
+	hint := "Press enter for the next task, r to revise the query or e to go over its query plan."
+	if r.err != nil {
+		hint = "Press r to revise the query or enter to skip the task."
+	}
+	return append(lines, "", t.S().Muted.Width(width).Render(hint))
+}
+
+// diffTable lists the missing and the extra rows, marked - and + like a
+// diff.
+func (p *sqlPage) diffTable() string {
+	c := p.result.comparison
+	var rows [][]string
+	var marks []string
+	for _, row := range truncateRows(c.Missing) {
+		rows = append(rows, row)
+		marks = append(marks, "-")
+	}
+	for _, row := range truncateRows(c.Extra) {
+		rows = append(rows, row)
+		marks = append(marks, "+")
+	}
+	return resultTable(p.result.got.Columns, rows, marks)
+}
+
+// previewTable shows the first rows of a result.
+func previewTable(result sqlgym.Result) string {
+	shown := truncateRows(result.Rows)
+	tbl := resultTable(result.Columns, shown, nil)
+	if more := len(result.Rows) - len(shown); more > 0 {
+		t := styles.CurrentTheme()
+		return lipgloss.JoinVertical(lipgloss.Left, tbl, t.S().Muted.Render(fmt.Sprintf("… and %d more row(s)", more)))
+	}
+	return tbl
+}
+
+// resultTable renders rows under the columns, with marks in a first column
+// when set.
+func resultTable(columns []string, rows [][]string, marks []string) string {
+	t := styles.CurrentTheme()
+	headers := columns
+	if marks != nil {
+		headers = append([]string{""}, columns...)
+	}
+	tbl := table.New().
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(t.S().Muted).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := t.S().Text.Padding(0, 1)
+			switch {
+			case row == table.HeaderRow:
+				return style.Bold(true)
+			case marks == nil:
+			case marks[row] == "-":
+				return style.Foreground(t.Error)
+			default:
+				return style.Foreground(t.Success)
+			}
+			return style
+		}).
+		Headers(headers...)
+	for i, row := range rows {
+		if marks != nil {
+			row = append([]string{marks[i]}, row...)
+		}
+		tbl.Row(row...)
+	}
+	return tbl.String()
+}
+
+// truncateRows keeps the rows that fit in a table; the counts shown above
+// it say how many there are in all.
+func truncateRows(rows [][]string) [][]string {
+	return rows[:min(len(rows), maxTableRows)]
+}
+
+func (p *sqlPage) SetSize(width, height int) tea.Cmd {
+	p.width = width
+	p.height = height
+	return nil
+}
+
+func (p *sqlPage) GetSize() (int, int) {
+	return p.width, p.height
+}
+
+func (p *sqlPage) Help() help.KeyMap {
+	var bindings []key.Binding
+	switch _, ok := p.currentTask(); {
+	case !ok || p.running:
+	case p.ran && p.result.err != nil:
+		bindings = append(bindings, p.keyMap.Revise, p.keyMap.Next)
+	case p.ran:
+		bindings = append(bindings, p.keyMap.Next, p.keyMap.Revise, p.keyMap.Explain)
+	default:
+		bindings = append(bindings, p.keyMap.Run)
+	}
+	bindings = append(bindings, p.keyMap.Back)
+	return core.NewSimpleHelp(bindings, [][]key.Binding{bindings})
+}
@@ -0,0 +1,43 @@
+package weakness
+
+import (
+	"charm.land/bubbles/v2/key"
+)
+
+type KeyMap struct {
+	Down     key.Binding
+	Up       key.Binding
+	Expand   key.Binding
+	Collapse key.Binding
+	Drill    key.Binding
+	Back     key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓", "next"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑", "previous"),
+		),
+		Expand: key.NewBinding(
+			key.WithKeys("right", "l", "enter"),
+			key.WithHelp("→", "expand"),
+		),
+		Collapse: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←", "collapse"),
+		),
+		Drill: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "drill this topic"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc", "back to chat"),
+		),
+	}
+}
@@ -0,0 +1,329 @@
+// Package weakness implements the page showing how well each topic of the
+// taxonomy went across all sessions.
+package weakness
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/trankhanh040147/prepf/internal/app"
+	"github.com/trankhanh040147/prepf/internal/taxonomy"
+	"github.com/trankhanh040147/prepf/internal/tui/components/core"
+	"github.com/trankhanh040147/prepf/internal/tui/components/core/layout"
+	"github.com/trankhanh040147/prepf/internal/tui/page"
+	"github.com/trankhanh040147/prepf/internal/tui/page/chat"
+	"github.com/trankhanh040147/prepf/internal/tui/styles"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+var WeaknessPageID page.PageID = "weakness"
+
+// Width from which the questions are shown next to the tree rather than
+// below it.
+const sideBySideWidth = 100
+
+// LoadMsg reloads the classified questions. It is sent whenever the page is
+// opened.
+type LoadMsg struct{}
+
+// DrillMsg starts a gym session focused on a topic. Prompt stands on its
+// own and is meant to open the session.
+type DrillMsg struct {
+	Title  string
+	Prompt string
+}
+
+type loadedMsg struct {
+	masteries []*taxonomy.Mastery
+	errs      []error
+}
+
+type WeaknessPage interface {
+	util.Model
+	layout.Sizeable
+	core.KeyMapHelp
+}
+
+// row is a visible line of the tree.
+type row struct {
+	mastery *taxonomy.Mastery
+	depth   int
+}
+
+type weaknessPage struct {
+	app    *app.App
+	keyMap KeyMap
+
+	width, height int
+
+	loaded    bool
+	masteries []*taxonomy.Mastery
+	// expanded holds the paths of the expanded nodes, so they stay
+	// expanded across reloads.
+	expanded map[string]bool
+	cursor   int
+	offset   int
+}
+
+func New(app *app.App) WeaknessPage {
+	return &weaknessPage{
+		app:      app,
+		keyMap:   DefaultKeyMap(),
+		expanded: make(map[string]bool),
+	}
+}
+
+func (p *weaknessPage) Init() tea.Cmd {
+	return p.load
+}
+
+func (p *weaknessPage) load() tea.Msg {
+	tx, errs := taxonomy.Load(p.app.Config())
+	questions, err := p.app.Drills.Classified(context.Background())
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return loadedMsg{masteries: tx.Mastery(questions), errs: errs}
+}
+
+func (p *weaknessPage) Update(msg tea.Msg) (util.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case LoadMsg:
+		return p, p.load
+	case loadedMsg:
+		p.loaded = true
+		p.masteries = msg.masteries
+		p.cursor = min(p.cursor, max(len(p.rows())-1, 0))
+		p.scroll()
+		var cmds []tea.Cmd
+		for _, err := range msg.errs {
+			cmds = append(cmds, util.ReportError(err))
+		}
+		return p, tea.Batch(cmds...)
+	case tea.KeyPressMsg:
+		return p, p.handleKey(msg)
+	}
+	return p, nil
+}
+
+func (p *weaknessPage) handleKey(msg tea.KeyPressMsg) tea.Cmd {
+	if key.Matches(msg, p.keyMap.Back) {
+		return util.CmdHandler(page.PageChangeMsg{ID: chat.ChatPageID})
+	}
+	rows := p.rows()
+	if len(rows) == 0 {
+		return nil
+	}
+	selected := rows[p.cursor]
+	switch {
+	case key.Matches(msg, p.keyMap.Down):
+		p.cursor = min(p.cursor+1, len(rows)-1)
+	case key.Matches(msg, p.keyMap.Up):
+		p.cursor = max(p.cursor-1, 0)
+	case key.Matches(msg, p.keyMap.Expand):
+		if len(selected.mastery.Children) > 0 {
+			p.expanded[selected.mastery.Node.Path] = true
+		}
+	case key.Matches(msg, p.keyMap.Collapse):
+		if p.expanded[selected.mastery.Node.Path] {
+			delete(p.expanded, selected.mastery.Node.Path)
+			break
+		}
+		// Move up to the parent, which is the closest row above that is
+		// less indented.
+		for i := p.cursor - 1; i >= 0; i-- {
+			if rows[i].depth < selected.depth {
+				p.cursor = i
+				break
+			}
+		}
+	case key.Matches(msg, p.keyMap.Drill):
+		return util.CmdHandler(DrillMsg{
+			Title:  "Drill: " + selected.mastery.Node.Path,
+			Prompt: taxonomy.DrillPrompt(selected.mastery),
+		})
+	}
+	p.scroll()
+	return nil
+}
+
+// rows flattens the expanded part of the tree.
+func (p *weaknessPage) rows() []row {
+	var rows []row
+	var walk func(masteries []*taxonomy.Mastery, depth int)
+	walk = func(masteries []*taxonomy.Mastery, depth int) {
+		for _, m := range masteries {
+			rows = append(rows, row{mastery: m, depth: depth})
+			if p.expanded[m.Node.Path] {
+				walk(m.Children, depth+1)
+			}
+		}
+	}
+	walk(p.masteries, 0)
+	return rows
+}
+
+// scroll keeps the cursor within the visible part of the tree.
+func (p *weaknessPage) scroll() {
+	height := p.treeHeight()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+height {
+		p.offset = p.cursor - height + 1
+	}
+	p.offset = max(p.offset, 0)
+}
+
+func (p *weaknessPage) sideBySide() bool {
+	return p.width >= sideBySideWidth
+}
+
+// treeHeight is the number of tree rows that fit, below the title and the
+// legend.
+func (p *weaknessPage) treeHeight() int {
+	height := p.height - 4
+	if !p.sideBySide() {
+		height /= 2
+	}
+	return max(height, 1)
+}
+
+func levelColor(t *styles.Theme, l taxonomy.Level) color.Color {
+	switch l {
+	case taxonomy.LevelWeak:
+		return t.Error
+	case taxonomy.LevelShaky:
+		return t.Warning
+	case taxonomy.LevelStrong:
+		return t.Success
+	default:
+		return t.FgSubtle
+	}
+}
+
+func (p *weaknessPage) View() string {
+	t := styles.CurrentTheme()
+	width := max(p.width-4, 10)
+
+	title := core.Section("Weakness Map", width)
+	if !p.loaded {
+		return lipgloss.JoinVertical(lipgloss.Left, title, "", t.S().Muted.Render("Loading topics..."))
+	}
+
+	rows := p.rows()
+	var legend []string
+	for _, l := range []taxonomy.Level{taxonomy.LevelStrong, taxonomy.LevelShaky, taxonomy.LevelWeak, taxonomy.LevelUntested} {
+		legend = append(legend, t.S().Base.Foreground(levelColor(t, l)).Render("● "+l.String()))
+	}
+	header := lipgloss.JoinVertical(lipgloss.Left, title, strings.Join(legend, "  "), "")
+
+	treeWidth := width
+	if p.sideBySide() {
+		treeWidth = width / 2
+	}
+	tree := p.renderTree(rows, treeWidth)
+	var body string
+	switch {
+	case len(rows) == 0:
+		body = t.S().Muted.Render("The taxonomy is empty.")
+	case p.sideBySide():
+		details := p.renderDetails(rows[p.cursor].mastery, width-treeWidth-2)
+		body = lipgloss.JoinHorizontal(lipgloss.Top, tree, "  ", details)
+	default:
+		details := p.renderDetails(rows[p.cursor].mastery, width)
+		body = lipgloss.JoinVertical(lipgloss.Left, tree, "", details)
+	}
+	return t.S().Base.Padding(0, 2).Render(lipgloss.JoinVertical(lipgloss.Left, header, body))
+}
+
+func (p *weaknessPage) renderTree(rows []row, width int) string {
+	t := styles.CurrentTheme()
+	end := min(p.offset+p.treeHeight(), len(rows))
+	var lines []string
+	for i := p.offset; i < end; i++ {
+		r := rows[i]
+		m := r.mastery
+
+		marker := "  "
+		if len(m.Children) > 0 {
+			marker = "▸ "
+			if p.expanded[m.Node.Path] {
+				marker = "▾ "
+			}
+		}
+		stats := "-"
+		if len(m.Questions) > 0 {
+			stats = fmt.Sprintf("%3.0f · %d", m.Score, len(m.Questions))
+		}
+		name := ansi.Truncate(strings.Repeat("  ", r.depth)+marker+m.Node.Name, width-lipgloss.Width(stats)-3, "…")
+		gap := max(width-2-lipgloss.Width(name)-lipgloss.Width(stats), 1)
+		line := name + strings.Repeat(" ", gap) + stats
+
+		style := t.S().Base.Foreground(levelColor(t, m.Level()))
+		if i == p.cursor {
+			lines = append(lines, style.Bold(true).Background(t.BgSubtle).Render("› "+line))
+		} else {
+			lines = append(lines, style.Render("  "+line))
+		}
+	}
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(lines, "\n"))
+}
+
+func (p *weaknessPage) renderDetails(m *taxonomy.Mastery, width int) string {
+	t := styles.CurrentTheme()
+	width = max(width, 10)
+
+	lines := []string{core.Section(m.Node.Path, width), ""}
+	if len(m.Questions) == 0 {
+		lines = append(lines, t.S().Muted.Width(width).Render("No graded questions filed here yet. Press d to start drilling it."))
+		return strings.Join(lines, "\n")
+	}
+	lines = append(lines,
+		t.S().Base.Foreground(levelColor(t, m.Level())).Render(fmt.Sprintf("%s · %.0f over %d question(s)", m.Level(), m.Score, len(m.Questions))),
+		"",
+	)
+	height := p.height - 8
+	if !p.sideBySide() {
+		height = p.height - p.treeHeight() - 9
+	}
+	for i, q := range m.Questions {
+		if i >= max(height, 1) {
+			lines = append(lines, t.S().Subtle.Render(fmt.Sprintf("… %d more", len(m.Questions)-i)))
+			break
+		}
+		score, level := "  -", taxonomy.LevelUntested
+		if q.Score != nil {
+			score, level = fmt.Sprintf("%3d", *q.Score), taxonomy.LevelOf(float64(*q.Score))
+		}
+		date := time.Unix(q.CreatedAt, 0).Format(time.DateOnly)
+		text, _, _ := strings.Cut(q.Text, "\n")
+		prefix := t.S().Base.Foreground(levelColor(t, level)).Render(score) + " " + t.S().Subtle.Render(date) + " "
+		lines = append(lines, prefix+t.S().Text.Render(ansi.Truncate(text, width-lipgloss.Width(prefix), "…")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (p *weaknessPage) SetSize(width, height int) tea.Cmd {
+	p.width = width
+	p.height = height
+	p.scroll()
+	return nil
+}
+
+func (p *weaknessPage) GetSize() (int, int) {
+	return p.width, p.height
+}
+
+func (p *weaknessPage) Help() help.KeyMap {
+	bindings := []key.Binding{p.keyMap.Up, p.keyMap.Down, p.keyMap.Expand, p.keyMap.Collapse, p.keyMap.Drill, p.keyMap.Back}
+	return core.NewSimpleHelp(bindings, [][]key.Binding{bindings})
+}
@@ -3,6 +3,7 @@ package styles
 import (
 	"charm.land/glamour/v2/ansi"
 	"github.com/alecthomas/chroma/v2"
+	chromaStyles "github.com/alecthomas/chroma/v2/styles"
 )
 
 func chromaStyle(style ansi.StylePrimitive) string {
@@ -41,6 +42,14 @@ func chromaStyle(style ansi.StylePrimitive) string {
 
 func GetChromaTheme() chroma.StyleEntries {
 	t := CurrentTheme()
+	if t.Syntax != "" {
+		style := chromaStyles.Get(t.Syntax)
+		entries := make(chroma.StyleEntries)
+		for _, ttype := range style.Types() {
+			entries[ttype] = style.Get(ttype).String()
+		}
+		return entries
+	}
 	rules := t.S().Markdown.CodeBlock
 
 	return chroma.StyleEntries{
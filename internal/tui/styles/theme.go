@@ -3,6 +3,8 @@ package styles
 import (
 	"fmt"
 	"image/color"
+	"maps"
+	"slices"
 	"strings"
 
 	"charm.land/bubbles/v2/filepicker"
@@ -28,6 +30,10 @@ type Theme struct {
 	Name   string
 	IsDark bool
 
+	// Syntax names the chroma style for code. Empty uses the charmtone
+	// palette built into the markdown styles.
+	Syntax string
+
 	Primary   color.Color
 	Secondary color.Color
 	Tertiary  color.Color
@@ -76,6 +82,10 @@ type Theme struct {
 	RedLight color.Color
 	Cherry   color.Color
 
+	// Markdown links and images.
+	Link  color.Color
+	Image color.Color
+
 	// Text selection.
 	TextSelection lipgloss.Style
 
@@ -143,7 +153,7 @@ func (t *Theme) S() *Styles {
 func (t *Theme) buildStyles() *Styles {
 	base := lipgloss.NewStyle().
 		Foreground(t.FgBase)
-	return &Styles{
+	s := &Styles{
 		Base: base,
 
 		SelectedBase: base.Background(t.Primary),
@@ -221,7 +231,7 @@ func (t *Theme) buildStyles() *Styles {
 				StylePrimitive: ansi.StylePrimitive{
 					// BlockPrefix: "\n",
 					// BlockSuffix: "\n",
-					Color: stringPtr(charmtone.Smoke.Hex()),
+					Color: stringPtr(lipglossColorToHex(t.FgHalfMuted)),
 				},
 				// Margin: uintPtr(defaultMargin),
 			},
@@ -236,7 +246,7 @@ func (t *Theme) buildStyles() *Styles {
 			Heading: ansi.StyleBlock{
 				StylePrimitive: ansi.StylePrimitive{
 					BlockSuffix: "\n",
-					Color:       stringPtr(charmtone.Malibu.Hex()),
+					Color:       stringPtr(lipglossColorToHex(t.Info)),
 					Bold:        boolPtr(true),
 				},
 			},
@@ -244,8 +254,8 @@ func (t *Theme) buildStyles() *Styles {
 				StylePrimitive: ansi.StylePrimitive{
 					Prefix:          " ",
 					Suffix:          " ",
-					Color:           stringPtr(charmtone.Zest.Hex()),
-					BackgroundColor: stringPtr(charmtone.Charple.Hex()),
+					Color:           stringPtr(lipglossColorToHex(t.Accent)),
+					BackgroundColor: stringPtr(lipglossColorToHex(t.Primary)),
 					Bold:            boolPtr(true),
 				},
 			},
@@ -272,7 +282,7 @@ func (t *Theme) buildStyles() *Styles {
 			H6: ansi.StyleBlock{
 				StylePrimitive: ansi.StylePrimitive{
 					Prefix: "###### ",
-					Color:  stringPtr(charmtone.Guac.Hex()),
+					Color:  stringPtr(lipglossColorToHex(t.Success)),
 					Bold:   boolPtr(false),
 				},
 			},
@@ -286,7 +296,7 @@ func (t *Theme) buildStyles() *Styles {
 				Bold: boolPtr(true),
 			},
 			HorizontalRule: ansi.StylePrimitive{
-				Color:  stringPtr(charmtone.Charcoal.Hex()),
+				Color:  stringPtr(lipglossColorToHex(t.Border)),
 				Format: "\n--------\n",
 			},
 			Item: ansi.StylePrimitive{
@@ -301,33 +311,33 @@ func (t *Theme) buildStyles() *Styles {
 				Unticked:       "[ ] ",
 			},
 			Link: ansi.StylePrimitive{
-				Color:     stringPtr(charmtone.Zinc.Hex()),
+				Color:     stringPtr(lipglossColorToHex(t.Link)),
 				Underline: boolPtr(true),
 			},
 			LinkText: ansi.StylePrimitive{
-				Color: stringPtr(charmtone.Guac.Hex()),
+				Color: stringPtr(lipglossColorToHex(t.Success)),
 				Bold:  boolPtr(true),
 			},
 			Image: ansi.StylePrimitive{
-				Color:     stringPtr(charmtone.Cheeky.Hex()),
+				Color:     stringPtr(lipglossColorToHex(t.Image)),
 				Underline: boolPtr(true),
 			},
 			ImageText: ansi.StylePrimitive{
-				Color:  stringPtr(charmtone.Squid.Hex()),
+				Color:  stringPtr(lipglossColorToHex(t.FgMuted)),
 				Format: "Image: {{.text}} →",
 			},
 			Code: ansi.StyleBlock{
 				StylePrimitive: ansi.StylePrimitive{
 					Prefix:          " ",
 					Suffix:          " ",
-					Color:           stringPtr(charmtone.Coral.Hex()),
-					BackgroundColor: stringPtr(charmtone.Charcoal.Hex()),
+					Color:           stringPtr(lipglossColorToHex(t.Red)),
+					BackgroundColor: stringPtr(lipglossColorToHex(t.BgSubtle)),
 				},
 			},
 			CodeBlock: ansi.StyleCodeBlock{
 				StyleBlock: ansi.StyleBlock{
 					StylePrimitive: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Charcoal.Hex()),
+						Color: stringPtr(lipglossColorToHex(t.Border)),
 					},
 					Margin: uintPtr(defaultMargin),
 				},
@@ -493,6 +503,26 @@ func (t *Theme) buildStyles() *Styles {
 			EmptyDirectory:   base.Foreground(t.FgMuted).PaddingLeft(2).SetString("Empty directory"),
 		},
 	}
+
+	if t.Syntax != "" {
+		s.Markdown.CodeBlock.Chroma = nil
+		s.Markdown.CodeBlock.Theme = t.Syntax
+	}
+	if !t.IsDark {
+		s.Diff.InsertLine = diffLineStyle(lipgloss.Color("#1a7f37"), lipgloss.Color("#ccffd8"), lipgloss.Color("#e6ffec"))
+		s.Diff.DeleteLine = diffLineStyle(lipgloss.Color("#cf222e"), lipgloss.Color("#ffd7d5"), lipgloss.Color("#ffebe9"))
+	}
+	return s
+}
+
+// diffLineStyle styles added or removed lines, with the gutter in a
+// stronger shade than the code.
+func diffLineStyle(fg, gutter, code color.Color) diffview.LineStyle {
+	return diffview.LineStyle{
+		LineNumber: lipgloss.NewStyle().Foreground(fg).Background(gutter),
+		Symbol:     lipgloss.NewStyle().Foreground(fg).Background(code),
+		Code:       lipgloss.NewStyle().Background(code),
+	}
 }
 
 type Manager struct {
@@ -528,6 +558,9 @@ func NewManager() *Manager {
 	t := NewCharmtoneTheme() // default theme
 	m.Register(t)
 	m.current = m.themes[t.Name]
+	for _, theme := range builtinThemes() {
+		m.Register(theme)
+	}
 
 	return m
 }
@@ -549,11 +582,7 @@ func (m *Manager) SetTheme(name string) error {
 }
 
 func (m *Manager) List() []string {
-	names := make([]string, 0, len(m.themes))
-	for name := range m.themes {
-		names = append(names, name)
-	}
-	return names
+	return slices.Sorted(maps.Keys(m.themes))
 }
 
 // ParseHex converts hex string to color
@@ -0,0 +1,171 @@
+package styles
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	chromaStyles "github.com/alecthomas/chroma/v2/styles"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+//go:embed themes/*.json
+var builtinThemeFiles embed.FS
+
+// ThemeChangedMsg is sent after the current theme changes, so components
+// that cache rendered output can redraw it.
+type ThemeChangedMsg struct{}
+
+// colorSlots maps the keys of a theme file to the colors they set.
+func (t *Theme) colorSlots() map[string]*color.Color {
+	return map[string]*color.Color{
+		"primary":         &t.Primary,
+		"secondary":       &t.Secondary,
+		"tertiary":        &t.Tertiary,
+		"accent":          &t.Accent,
+		"bg_base":         &t.BgBase,
+		"bg_base_lighter": &t.BgBaseLighter,
+		"bg_subtle":       &t.BgSubtle,
+		"bg_overlay":      &t.BgOverlay,
+		"fg_base":         &t.FgBase,
+		"fg_muted":        &t.FgMuted,
+		"fg_half_muted":   &t.FgHalfMuted,
+		"fg_subtle":       &t.FgSubtle,
+		"fg_selected":     &t.FgSelected,
+		"border":          &t.Border,
+		"border_focus":    &t.BorderFocus,
+		"success":         &t.Success,
+		"error":           &t.Error,
+		"warning":         &t.Warning,
+		"info":            &t.Info,
+		"white":           &t.White,
+		"blue_light":      &t.BlueLight,
+		"blue_dark":       &t.BlueDark,
+		"blue":            &t.Blue,
+		"yellow":          &t.Yellow,
+		"citron":          &t.Citron,
+		"green":           &t.Green,
+		"green_dark":      &t.GreenDark,
+		"green_light":     &t.GreenLight,
+		"red":             &t.Red,
+		"red_dark":        &t.RedDark,
+		"red_light":       &t.RedLight,
+		"cherry":          &t.Cherry,
+		"link":            &t.Link,
+		"image":           &t.Image,
+	}
+}
+
+// ParseTheme reads a theme from a JSON object holding every color slot as a
+// hex color, plus optional "dark" (default true) and "syntax", the name of
+// a chroma style. Errors name the keys that are wrong.
+func ParseTheme(name string, data []byte) (*Theme, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	t := &Theme{Name: name, IsDark: true}
+	slots := t.colorSlots()
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(raw)) {
+		value := raw[key]
+		var err error
+		switch key {
+		case "dark":
+			err = json.Unmarshal(value, &t.IsDark)
+		case "syntax":
+			if err = json.Unmarshal(value, &t.Syntax); err == nil && chromaStyles.Registry[t.Syntax] == nil {
+				err = fmt.Errorf("unknown chroma style %q", t.Syntax)
+			}
+		default:
+			slot, ok := slots[key]
+			if !ok {
+				err = errors.New("unknown key")
+				break
+			}
+			var hex string
+			if err = json.Unmarshal(value, &hex); err == nil {
+				*slot, err = parseColor(hex)
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(slots)) {
+		if _, ok := raw[key]; !ok {
+			errs = append(errs, fmt.Errorf("%s: missing", key))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	t.deriveStyles()
+	return t, nil
+}
+
+func parseColor(hex string) (color.Color, error) {
+	if _, err := colorful.Hex(hex); err != nil {
+		return nil, fmt.Errorf("%q is not a hex color like #1a2b3c", hex)
+	}
+	return lipgloss.Color(hex), nil
+}
+
+// LoadTheme reads a theme file, named after the file.
+func LoadTheme(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	t, err := ParseTheme(name, data)
+	if err != nil {
+		return nil, fmt.Errorf("theme %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Load registers the themes in dir, replacing built-in ones of the same
+// name. Files that don't parse are skipped and reported, as are TOML files,
+// since only JSON themes are supported. A missing dir is not an error.
+func (m *Manager) Load(dir string) []error {
+	var errs []error
+	tomlPaths, _ := filepath.Glob(filepath.Join(dir, "*.toml"))
+	for _, path := range tomlPaths {
+		errs = append(errs, fmt.Errorf("theme %s: only JSON theme files are supported", path))
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, path := range paths {
+		t, err := LoadTheme(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		m.Register(t)
+	}
+	return errs
+}
+
+func builtinThemes() []*Theme {
+	entries, _ := builtinThemeFiles.ReadDir("themes")
+	themes := make([]*Theme, 0, len(entries))
+	for _, entry := range entries {
+		data, _ := builtinThemeFiles.ReadFile("themes/" + entry.Name())
+		t, err := ParseTheme(strings.TrimSuffix(entry.Name(), ".json"), data)
+		if err != nil {
+			panic(fmt.Sprintf("built-in theme %s: %v", entry.Name(), err))
+		}
+		themes = append(themes, t)
+	}
+	return themes
+}
@@ -0,0 +1,84 @@
+package styles
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/exp/charmtone"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinThemes(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager()
+	require.Equal(t, []string{"charmtone", "high-contrast", "light", "solarized"}, m.List())
+	require.NoError(t, m.SetTheme("light"))
+	light := m.Current()
+	require.False(t, light.IsDark)
+	require.Equal(t, "github", light.S().Markdown.CodeBlock.Theme)
+	require.Nil(t, light.S().Markdown.CodeBlock.Chroma)
+}
+
+func TestCharmtoneMarkdown(t *testing.T) {
+	t.Parallel()
+
+	// The default theme keeps the colors it had before themes were files.
+	md := NewCharmtoneTheme().S().Markdown
+	for want, got := range map[charmtone.Key]*string{
+		charmtone.Zinc:   md.Link.Color,
+		charmtone.Cheeky: md.Image.Color,
+		charmtone.Guac:   md.LinkText.Color,
+		charmtone.Coral:  md.Code.Color,
+	} {
+		require.True(t, strings.EqualFold(want.Hex(), *got), "want %s, got %s", want.Hex(), *got)
+	}
+}
+
+func TestParseTheme(t *testing.T) {
+	t.Parallel()
+
+	data, err := builtinThemeFiles.ReadFile("themes/light.json")
+	require.NoError(t, err)
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+
+	delete(raw, "accent")
+	raw["primry"] = "#ffffff"
+	raw["border"] = "grey"
+	raw["dark"] = "no"
+	raw["syntax"] = "nope"
+	data, err = json.Marshal(raw)
+	require.NoError(t, err)
+
+	_, err = ParseTheme("broken", data)
+	require.EqualError(t, err, `border: "grey" is not a hex color like #1a2b3c
+dark: json: cannot unmarshal string into Go value of type bool
+primry: unknown key
+syntax: unknown chroma style "nope"
+accent: missing`)
+}
+
+func TestManagerLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	data, err := builtinThemeFiles.ReadFile("themes/solarized.json")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mine.json"), data, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{"primary": 1}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.toml"), []byte(`primary = "#ffffff"`), 0o644))
+
+	m := NewManager()
+	errs := m.Load(dir)
+	require.Len(t, errs, 2)
+	require.ErrorContains(t, errs[0], "other.toml: only JSON theme files are supported")
+	require.ErrorContains(t, errs[1], "bad.json: primary: json: cannot unmarshal number")
+	require.NoError(t, m.SetTheme("mine"))
+	require.Equal(t, "solarized-dark", m.Current().Syntax)
+
+	require.Empty(t, m.Load(filepath.Join(dir, "missing")))
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"regexp"
@@ -12,6 +13,7 @@ import (
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/trankhanh040147/prepf/internal/agent/prompt"
 	"github.com/trankhanh040147/prepf/internal/agent/tools/mcp"
 	"github.com/trankhanh040147/prepf/internal/app"
 	"github.com/trankhanh040147/prepf/internal/config"
@@ -32,10 +34,20 @@ import (
 	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs/permissions"
 	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs/quit"
 	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs/sessions"
+	"github.com/trankhanh040147/prepf/internal/tui/components/dialogs/themes"
 	"github.com/trankhanh040147/prepf/internal/tui/page"
 	"github.com/trankhanh040147/prepf/internal/tui/page/chat"
+	"github.com/trankhanh040147/prepf/internal/tui/page/flashcards"
+	"github.com/trankhanh040147/prepf/internal/tui/page/quizzes"
+	"github.com/trankhanh040147/prepf/internal/tui/page/reviews"
+	"github.com/trankhanh040147/prepf/internal/tui/page/rubrics"
+	"github.com/trankhanh040147/prepf/internal/tui/page/sqlpractice"
+	"github.com/trankhanh040147/prepf/internal/tui/page/weakness"
 	"github.com/trankhanh040147/prepf/internal/tui/styles"
 	"github.com/trankhanh040147/prepf/internal/tui/util"
+	"github.com/trankhanh040147/prepf/internal/uicmd"
+	"github.com/trankhanh040147/prepf/internal/usage"
+	"github.com/trankhanh040147/prepf/internal/watcher"
 	"golang.org/x/mod/semver"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -77,6 +89,9 @@ type appModel struct {
 	completions  completions.Completions
 	isConfigured bool
 
+	// themeWarning reports theme files that failed to load.
+	themeWarning string
+
 	// Chat Page Specific
 	selectedSessionID string // The ID of the currently selected session
 
@@ -87,6 +102,10 @@ type appModel struct {
 	// QueryVersion instructs the TUI to query for the terminal version when it
 	// starts.
 	QueryVersion bool
+
+	// StartDaily instructs the TUI to open today's daily challenge when it
+	// starts.
+	StartDaily bool
 }
 
 // Init initializes the application model and returns initial commands.
@@ -103,9 +122,16 @@ func (a appModel) Init() tea.Cmd {
 
 	cmd = a.status.Init()
 	cmds = append(cmds, cmd)
+	cmds = append(cmds, reportInvalidSkills)
+	if a.themeWarning != "" {
+		cmds = append(cmds, util.ReportWarn(a.themeWarning))
+	}
 	if a.QueryVersion {
 		cmds = append(cmds, tea.RequestTerminalVersion)
 	}
+	if a.StartDaily {
+		cmds = append(cmds, util.CmdHandler(commands.StartDailyMsg{}))
+	}
 
 	return tea.Batch(cmds...)
 }
@@ -167,6 +193,18 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, handleMCPToolsEvent(context.Background(), msg.Payload.Name)
 		}
 
+	case pubsub.Event[usage.Alert]:
+		if msg.Payload.Exceeded {
+			return a, util.ReportError(fmt.Errorf("%s", msg.Payload))
+		}
+		return a, util.ReportWarn(msg.Payload.String())
+
+	case pubsub.Event[watcher.Event]:
+		if slices.Contains(msg.Payload.Kinds, watcher.KindSkills) {
+			return a, tea.Sequence(util.ReportInfo(msg.Payload.String()), reportInvalidSkills)
+		}
+		return a, util.ReportInfo(msg.Payload.String())
+
 	// Completions messages
 	case completions.OpenCompletionsMsg, completions.FilterCompletionsMsg,
 		completions.CloseCompletionsMsg, completions.RepositionCompletionsMsg:
@@ -248,6 +286,43 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case commands.SwitchThemeMsg:
+		return a, util.CmdHandler(dialogs.OpenDialogMsg{Model: themes.NewThemesDialog()})
+	case themes.ThemeSelectedMsg:
+		return a, saveTheme(msg.Name)
+	case styles.ThemeChangedMsg:
+		for id, page := range a.pages {
+			m, pageCmd := page.Update(msg)
+			a.pages[id] = m
+			cmds = append(cmds, pageCmd)
+		}
+		return a, tea.Batch(cmds...)
+
+	case commands.ReviewFlashcardsMsg:
+		return a, tea.Sequence(a.moveToPage(flashcards.FlashcardsPageID), util.CmdHandler(flashcards.LoadMsg{}))
+
+	case commands.OpenWeaknessMapMsg:
+		return a, tea.Sequence(a.moveToPage(weakness.WeaknessPageID), util.CmdHandler(weakness.LoadMsg{}))
+	case commands.OpenRubricsMsg:
+		return a, tea.Sequence(a.moveToPage(rubrics.RubricsPageID), util.CmdHandler(rubrics.LoadMsg{}))
+	case weakness.DrillMsg:
+		return a, tea.Sequence(a.moveToPage(chat.ChatPageID), util.CmdHandler(commands.StartGymMsg{Title: msg.Title, Text: msg.Prompt}))
+
+	case commands.StartQuizMsg:
+		return a, tea.Sequence(a.moveToPage(quizzes.QuizPageID), util.CmdHandler(quizzes.StartMsg{Name: msg.Name}))
+	case quizzes.ExplainMsg:
+		return a, tea.Sequence(a.moveToPage(chat.ChatPageID), util.CmdHandler(cmpChat.SendMsg{Text: msg.Prompt}))
+
+	case commands.StartReviewMsg:
+		return a, tea.Sequence(a.moveToPage(reviews.ReviewPageID), util.CmdHandler(reviews.StartMsg{Name: msg.Name}))
+	case reviews.DiscussMsg:
+		return a, tea.Sequence(a.moveToPage(chat.ChatPageID), util.CmdHandler(cmpChat.SendMsg{Text: msg.Prompt}))
+
+	case commands.StartSQLMsg:
+		return a, tea.Sequence(a.moveToPage(sqlpractice.SQLPageID), util.CmdHandler(sqlpractice.StartMsg{Name: msg.Name}))
+	case sqlpractice.ExplainMsg:
+		return a, tea.Sequence(a.moveToPage(chat.ChatPageID), util.CmdHandler(cmpChat.SendMsg{Text: msg.Prompt}))
+
 	case commands.SwitchModelMsg:
 		return a, util.CmdHandler(
 			dialogs.OpenDialogMsg{
@@ -562,6 +637,42 @@ func (a *appModel) handleKeyPressMsg(msg tea.KeyPressMsg) tea.Cmd {
 	}
 }
 
+func saveTheme(name string) tea.Cmd {
+	return func() tea.Msg {
+		if err := config.Get().SetTheme(name); err != nil {
+			return util.InfoMsg{Type: util.InfoTypeError, Msg: "Failed to save theme: " + err.Error()}
+		}
+		return util.InfoMsg{Type: util.InfoTypeInfo, Msg: "Theme set to " + name}
+	}
+}
+
+// loadThemes registers the user's themes and switches to the configured
+// one, before any component picks up its styles. It returns a warning for
+// whatever went wrong.
+func loadThemes(cfg *config.Config) string {
+	manager := styles.DefaultManager()
+	errs := manager.Load(config.GlobalThemesDir())
+	if name := cfg.Options.TUI.Theme; name != "" {
+		if err := manager.SetTheme(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return ""
+	}
+	return errors.Join(errs...).Error()
+}
+
+// reportInvalidSkills warns about skill files that fail validation, which
+// are otherwise left out of the prompt without notice.
+func reportInvalidSkills() tea.Msg {
+	_, problems := prompt.DiscoverSkills(*config.Get())
+	if warning := uicmd.InvalidSkillsWarning(problems); warning != "" {
+		return util.InfoMsg{Type: util.InfoTypeWarn, Msg: warning}
+	}
+	return nil
+}
+
 // moveToPage handles navigation between different pages in the application.
 func (a *appModel) moveToPage(pageID page.PageID) tea.Cmd {
 	if a.app.AgentCoordinator.IsBusy() {
@@ -687,6 +798,7 @@ func handleMCPToolsEvent(ctx context.Context, name string) tea.Cmd {
 
 // New creates and initializes a new TUI application model.
 func New(app *app.App) *appModel {
+	themeWarning := loadThemes(app.Config())
 	chatPage := chat.New(app)
 	keyMap := DefaultKeyMap()
 	keyMap.pageBindings = chatPage.Bindings()
@@ -699,12 +811,22 @@ func New(app *app.App) *appModel {
 		keyMap:      keyMap,
 
 		pages: map[page.PageID]util.Model{
-			chat.ChatPageID: chatPage,
+			chat.ChatPageID:             chatPage,
+			flashcards.FlashcardsPageID: flashcards.New(app),
+			quizzes.QuizPageID:          quizzes.New(app),
+			reviews.ReviewPageID:        reviews.New(app),
+			rubrics.RubricsPageID:       rubrics.New(app),
+			sqlpractice.SQLPageID:       sqlpractice.New(app),
+			weakness.WeaknessPageID:     weakness.New(app),
 		},
 
-		dialog:      dialogs.NewDialogCmp(),
-		completions: completions.New(),
+		dialog:       dialogs.NewDialogCmp(),
+		completions:  completions.New(),
+		themeWarning: themeWarning,
 	}
 
+	// Custom commands are only used here, so the TUI registers them.
+	app.Watcher.Watch(watcher.KindCommands, uicmd.CommandDirs(app.Config())...)
+
 	return model
 }
@@ -0,0 +1,37 @@
+package uicmd
+
+import (
+	tea "charm.land/bubbletea/v2"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+// DailyCommandID is the slash command that starts the daily challenge.
+const DailyCommandID = "daily"
+
+// StartDailyMsg asks the chat page to open the session answering today's
+// challenge, starting one if needed.
+type StartDailyMsg struct{}
+
+// DailyCommand returns the /daily command.
+func DailyCommand() Command {
+	return Command{
+		ID:          DailyCommandID,
+		Title:       SlashPrefix + DailyCommandID,
+		Description: "Answer today's question to keep your streak",
+		Handler: func(Command) tea.Cmd {
+			return util.CmdHandler(StartDailyMsg{})
+		},
+	}
+}
+
+// DailyCommands returns the daily challenge command for the commands dialog.
+func DailyCommands() []Command {
+	return []Command{{
+		ID:          DailyCommandID,
+		Title:       "Start Daily Challenge",
+		Description: "Answer today's question to keep your streak",
+		Handler: func(Command) tea.Cmd {
+			return util.CmdHandler(StartDailyMsg{})
+		},
+	}}
+}
@@ -0,0 +1,68 @@
+package uicmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/trankhanh040147/prepf/internal/agent"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/debugging"
+)
+
+// DebugCommandID is the slash command that starts a debugging exercise.
+const DebugCommandID = "debug"
+
+// StartDebugMsg asks the chat page to start a debugging session with the
+// named exercise.
+type StartDebugMsg struct {
+	Name string
+}
+
+// RunCheckMsg asks the chat page to run the check command of the current
+// debugging exercise.
+type RunCheckMsg struct{}
+
+var debugCommand = exerciseCommand[debugging.Exercise, *debugging.Exercise]{
+	loader:      debugging.Exercises,
+	id:          DebugCommandID,
+	description: "Find and fix a bug in a scratch copy of a project",
+	title:       "Start Debugging: ",
+	listed:      "Available exercises: ",
+	missing:     "No debugging exercises found; add a project with an " + debugging.ManifestName + " to ",
+	describe: func(e *debugging.Exercise) string {
+		return cmp.Or(e.Title, e.Name)
+	},
+	start: func(name string) tea.Msg { return StartDebugMsg{Name: name} },
+}
+
+// DebugCommand returns the /debug command. Without an argument it asks for
+// the exercise name.
+func DebugCommand(cfg *config.Config) Command {
+	return debugCommand.command(cfg)
+}
+
+// DebugCommands returns a command per available debugging exercise for the
+// commands dialog.
+func DebugCommands(cfg *config.Config) []Command {
+	return debugCommand.commands(cfg)
+}
+
+// CheckControl runs the check command of the session's debugging exercise
+// and returns the control message carrying the result.
+func CheckControl(ctx context.Context, runs debugging.Service, sessionID string) (agent.Control, error) {
+	run, result, err := runs.Check(ctx, sessionID)
+	if err != nil {
+		return agent.Control{}, err
+	}
+	verdict := fmt.Sprintf("Check failed with exit code %d", result.ExitCode)
+	if result.Passed {
+		verdict = "Check passed"
+	}
+	args := fmt.Sprintf("%s (check %d): %s", verdict, run.Checks, run.CheckCommand)
+	if result.Output != "" {
+		args += "\n\n" + result.Output
+	}
+	return agent.Control{Action: agent.ControlCheck, Args: args}, nil
+}
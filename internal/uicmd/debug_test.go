@@ -0,0 +1,49 @@
+package uicmd
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/agent"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/debugging"
+	"github.com/trankhanh040147/prepf/internal/drill"
+)
+
+func TestCheckControl(t *testing.T) {
+	t.Parallel()
+
+	exerciseDir := filepath.Join(t.TempDir(), "paging")
+	require.NoError(t, os.MkdirAll(exerciseDir, 0o755))
+	manifest := "title: Off by one\ndescription: The last item of every page is missing.\ncheck: test -f fixed\n"
+	require.NoError(t, os.WriteFile(filepath.Join(exerciseDir, debugging.ManifestName), []byte(manifest), 0o644))
+
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	q := db.New(conn)
+	_, err = q.CreateSession(t.Context(), db.CreateSessionParams{ID: "s1", Mode: sql.NullString{String: "debug", Valid: true}})
+	require.NoError(t, err)
+	runs := debugging.NewService(q, drill.NewService(q, conn))
+
+	_, err = CheckControl(t.Context(), runs, "s1")
+	require.ErrorIs(t, err, debugging.ErrNoRun)
+
+	e, err := debugging.Exercises.Load(exerciseDir)
+	require.NoError(t, err)
+	run, err := runs.Start(t.Context(), "s1", e)
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(run.Workspace) })
+
+	control, err := CheckControl(t.Context(), runs, "s1")
+	require.NoError(t, err)
+	require.Equal(t, agent.Control{Action: agent.ControlCheck, Args: "Check failed with exit code 1 (check 1): test -f fixed"}, control)
+
+	require.NoError(t, os.WriteFile(filepath.Join(run.Workspace, "fixed"), nil, 0o644))
+	control, err = CheckControl(t.Context(), runs, "s1")
+	require.NoError(t, err)
+	require.Equal(t, "Check passed (check 2): test -f fixed", control.Args)
+}
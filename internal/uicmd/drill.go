@@ -0,0 +1,77 @@
+package uicmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/trankhanh040147/prepf/internal/agent"
+	"github.com/trankhanh040147/prepf/internal/drill"
+)
+
+// RevealHintMsg asks the chat page to reveal the next hint for the current
+// drill question.
+type RevealHintMsg struct {
+	Args string
+}
+
+// GiveUpMsg asks the chat page to give up on the current drill question.
+type GiveUpMsg struct {
+	Args string
+}
+
+func drillCommand(action, description string, msg func(args string) tea.Msg) Command {
+	return Command{
+		ID:          action,
+		Title:       SlashPrefix + action,
+		Description: description,
+		Handler: func(Command) tea.Cmd {
+			return func() tea.Msg { return msg("") }
+		},
+		ArgsHandler: func(_ Command, args string) tea.Cmd {
+			return func() tea.Msg { return msg(args) }
+		},
+	}
+}
+
+// HintControl climbs the hint ladder of the session's open drill question
+// and returns the control message carrying the revealed hint. Without a
+// recorded question the interviewer is asked to come up with a hint.
+func HintControl(ctx context.Context, drills drill.Service, sessionID, args string) (agent.Control, error) {
+	q, err := drills.RevealHint(ctx, sessionID)
+	if errors.Is(err, drill.ErrNoQuestion) {
+		return agent.Control{Action: agent.ControlHint, Args: args}, nil
+	}
+	if err != nil {
+		return agent.Control{}, err
+	}
+	if q.Status == drill.StatusGaveUp {
+		return giveUpControl(q), nil
+	}
+	return agent.Control{
+		Action: agent.ControlHint,
+		Args:   fmt.Sprintf("Hint %d of %d (%s): %s", q.HintsUsed, drill.MaxHintLevel, q.HintsUsed, q.Hint(q.HintsUsed)),
+	}, nil
+}
+
+// GiveUpControl gives up on the session's open drill question and returns
+// the control message carrying the model answer. Without a recorded
+// question the interviewer is asked to give the answer.
+func GiveUpControl(ctx context.Context, drills drill.Service, sessionID, args string) (agent.Control, error) {
+	q, err := drills.GiveUp(ctx, sessionID)
+	if errors.Is(err, drill.ErrNoQuestion) {
+		return agent.Control{Action: agent.ControlGiveUp, Args: args}, nil
+	}
+	if err != nil {
+		return agent.Control{}, err
+	}
+	return giveUpControl(q), nil
+}
+
+func giveUpControl(q drill.Question) agent.Control {
+	return agent.Control{
+		Action: agent.ControlGiveUp,
+		Args:   "Model answer: " + q.Answer,
+	}
+}
@@ -0,0 +1,46 @@
+package uicmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/agent"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/drill"
+)
+
+func TestDrillControls(t *testing.T) {
+	t.Parallel()
+
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	drills := drill.NewService(db.New(conn), conn)
+
+	// Without a recorded question the interviewer improvises.
+	control, err := HintControl(t.Context(), drills, "s1", "about complexity")
+	require.NoError(t, err)
+	require.Equal(t, agent.Control{Action: agent.ControlHint, Args: "about complexity"}, control)
+
+	_, err = drills.Ask(t.Context(), drill.Question{
+		SessionID: "s1",
+		Topic:     "Hash maps",
+		Text:      "Why are hash map lookups O(1) on average?",
+		Hints:     []string{"Buckets.", "Hash the key to an index.", "The hash picks a bucket directly..."},
+		Answer:    "Hashing gives the bucket index directly; collisions are rare with a good load factor.",
+	})
+	require.NoError(t, err)
+
+	control, err = HintControl(t.Context(), drills, "s1", "")
+	require.NoError(t, err)
+	require.Equal(t, agent.Control{Action: agent.ControlHint, Args: "Hint 1 of 4 (nudge): Buckets."}, control)
+
+	control, err = GiveUpControl(t.Context(), drills, "s1", "")
+	require.NoError(t, err)
+	require.Equal(t, agent.ControlGiveUp, control.Action)
+	require.Contains(t, control.Args, "Hashing gives the bucket index directly")
+
+	control, err = GiveUpControl(t.Context(), drills, "s1", "")
+	require.NoError(t, err)
+	require.Equal(t, agent.Control{Action: agent.ControlGiveUp}, control)
+}
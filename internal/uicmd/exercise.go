@@ -0,0 +1,96 @@
+package uicmd
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/exercise"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+// exerciseCommand describes the slash command that starts one kind of
+// exercise by name.
+type exerciseCommand[T any, P exercise.Ptr[T]] struct {
+	loader      exercise.Loader[T, P]
+	id          string
+	description string
+	// title prefixes the exercise name in the commands dialog.
+	title string
+	// listed prefixes the available names when asking for one.
+	listed string
+	// missing is the warning when there are no exercises, followed by the
+	// directory to add one to.
+	missing string
+	// describe returns the commands dialog description of an exercise.
+	describe func(P) string
+	// start returns the message that starts the named exercise.
+	start func(name string) tea.Msg
+}
+
+// command returns the slash command. Without an argument it asks for the
+// exercise name.
+func (c exerciseCommand[T, P]) command(cfg *config.Config) Command {
+	return Command{
+		ID:          c.id,
+		Title:       SlashPrefix + c.id,
+		Description: c.description,
+		Handler: func(Command) tea.Cmd {
+			return c.ask(cfg)
+		},
+		ArgsHandler: func(_ Command, args string) tea.Cmd {
+			if args == "" {
+				return c.ask(cfg)
+			}
+			return c.startNamed(cfg, args)
+		},
+	}
+}
+
+// commands returns a command per available exercise for the commands
+// dialog.
+func (c exerciseCommand[T, P]) commands(cfg *config.Config) []Command {
+	available, _ := c.loader.Discover(c.loader.Dirs(cfg))
+	commands := make([]Command, 0, len(available))
+	for _, e := range available {
+		name := e.ExerciseName()
+		commands = append(commands, Command{
+			ID:          c.id + ":" + name,
+			Title:       c.title + name,
+			Description: c.describe(e),
+			Handler: func(Command) tea.Cmd {
+				return util.CmdHandler(c.start(name))
+			},
+		})
+	}
+	return commands
+}
+
+func (c exerciseCommand[T, P]) ask(cfg *config.Config) tea.Cmd {
+	available, _ := c.loader.Discover(c.loader.Dirs(cfg))
+	if len(available) == 0 {
+		return util.ReportWarn(c.missing + c.loader.Dirs(cfg)[0])
+	}
+	names := make([]string, len(available))
+	for i, e := range available {
+		names[i] = e.ExerciseName()
+	}
+	return util.CmdHandler(ShowArgumentsDialogMsg{
+		CommandID:   c.id,
+		Description: c.listed + strings.Join(names, ", "),
+		ArgNames:    []string{"NAME"},
+		OnSubmit: func(args map[string]string) tea.Cmd {
+			return c.startNamed(cfg, args["NAME"])
+		},
+	})
+}
+
+func (c exerciseCommand[T, P]) startNamed(cfg *config.Config, name string) tea.Cmd {
+	name = strings.TrimSpace(name)
+	available, _ := c.loader.Discover(c.loader.Dirs(cfg))
+	if _, ok := c.loader.Find(available, name); !ok {
+		return util.ReportWarn(fmt.Sprintf("Unknown %s %q", c.loader.Kind, name))
+	}
+	return util.CmdHandler(c.start(name))
+}
@@ -0,0 +1,39 @@
+package uicmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+func TestExerciseCommand(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	cfg := &config.Config{Options: &config.Options{DataDirectory: dataDir}}
+	cmd := QuizCommand(cfg)
+	require.IsType(t, util.InfoMsg{}, RunSlash(cmd, "")(), "nothing to pick from")
+
+	quizDir := filepath.Join(dataDir, "quizzes")
+	require.NoError(t, os.MkdirAll(quizDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(quizDir, "go-maps.yaml"), []byte("title: Go maps\nquestions:\n  - type: true_false\n    prompt: Maps are safe for concurrent writes.\n    answer: false\n"), 0o644))
+
+	require.Equal(t, StartQuizMsg{Name: "go-maps"}, RunSlash(cmd, " go-maps ")())
+	require.Equal(t, util.InfoMsg{Type: util.InfoTypeWarn, Msg: `Unknown quiz "missing"`}, RunSlash(cmd, "missing")())
+
+	ask, ok := RunSlash(cmd, "")().(ShowArgumentsDialogMsg)
+	require.True(t, ok)
+	require.Equal(t, "Available quizzes: go-maps", ask.Description)
+	require.Equal(t, StartQuizMsg{Name: "go-maps"}, ask.OnSubmit(map[string]string{"NAME": "go-maps"})())
+
+	commands := QuizCommands(cfg)
+	require.Len(t, commands, 1)
+	require.Equal(t, "quiz:go-maps", commands[0].ID)
+	require.Equal(t, "Start Quiz: go-maps", commands[0].Title)
+	require.Equal(t, "Go maps (1 question(s))", commands[0].Description)
+	require.Equal(t, StartQuizMsg{Name: "go-maps"}, commands[0].Handler(commands[0])())
+}
@@ -0,0 +1,44 @@
+package uicmd
+
+import (
+	tea "charm.land/bubbletea/v2"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+// PlanCommandID is the slash command that starts an item of today's
+// practice plan.
+const PlanCommandID = "plan"
+
+// StartPlanMsg asks the chat page to open the session practicing one of
+// today's plan items, starting one if needed. Args holds the item's number,
+// counted from 1; without it the first item not done is started.
+type StartPlanMsg struct {
+	Args string
+}
+
+// PlanCommand returns the /plan command.
+func PlanCommand() Command {
+	return Command{
+		ID:          PlanCommandID,
+		Title:       SlashPrefix + PlanCommandID,
+		Description: "Start today's next practice plan item, or the numbered one",
+		Handler: func(Command) tea.Cmd {
+			return util.CmdHandler(StartPlanMsg{})
+		},
+		ArgsHandler: func(_ Command, args string) tea.Cmd {
+			return util.CmdHandler(StartPlanMsg{Args: args})
+		},
+	}
+}
+
+// PlanCommands returns the practice plan command for the commands dialog.
+func PlanCommands() []Command {
+	return []Command{{
+		ID:          PlanCommandID,
+		Title:       "Start Next Plan Item",
+		Description: "Practice the next item of today's plan",
+		Handler: func(Command) tea.Cmd {
+			return util.CmdHandler(StartPlanMsg{})
+		},
+	}}
+}
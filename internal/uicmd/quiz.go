@@ -0,0 +1,43 @@
+package uicmd
+
+import (
+	"cmp"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/quiz"
+)
+
+// QuizCommandID is the slash command that starts a quiz.
+const QuizCommandID = "quiz"
+
+// StartQuizMsg asks the UI to open the quiz page with the named quiz.
+type StartQuizMsg struct {
+	Name string
+}
+
+var quizCommand = exerciseCommand[quiz.Quiz, *quiz.Quiz]{
+	loader:      quiz.Quizzes,
+	id:          QuizCommandID,
+	description: "Take a quiz, graded locally",
+	title:       "Start Quiz: ",
+	listed:      "Available quizzes: ",
+	missing:     "No quizzes found; ask for one in the chat or add a file to ",
+	describe: func(q *quiz.Quiz) string {
+		return fmt.Sprintf("%s (%d question(s))", cmp.Or(q.Title, q.Name), len(q.Questions))
+	},
+	start: func(name string) tea.Msg { return StartQuizMsg{Name: name} },
+}
+
+// QuizCommand returns the /quiz command. Without an argument it asks for the
+// quiz name.
+func QuizCommand(cfg *config.Config) Command {
+	return quizCommand.command(cfg)
+}
+
+// QuizCommands returns a command per available quiz for the commands
+// dialog.
+func QuizCommands(cfg *config.Config) []Command {
+	return quizCommand.commands(cfg)
+}
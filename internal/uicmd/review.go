@@ -0,0 +1,44 @@
+package uicmd
+
+import (
+	"cmp"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/review"
+)
+
+// ReviewCommandID is the slash command that starts a code review exercise.
+const ReviewCommandID = "review"
+
+// StartReviewMsg asks the UI to open the code review page with the named
+// exercise.
+type StartReviewMsg struct {
+	Name string
+}
+
+var reviewCommand = exerciseCommand[review.Exercise, *review.Exercise]{
+	loader:      review.Exercises,
+	id:          ReviewCommandID,
+	description: "Review a code change with seeded issues",
+	title:       "Start Code Review: ",
+	listed:      "Available exercises: ",
+	missing:     "No review exercises found; ask for one in the chat or add a file to ",
+	describe: func(e *review.Exercise) string {
+		return fmt.Sprintf("%s (%s)", cmp.Or(e.Title, e.Name), e.File)
+	},
+	start: func(name string) tea.Msg { return StartReviewMsg{Name: name} },
+}
+
+// ReviewCommand returns the /review command. Without an argument it asks for
+// the exercise name.
+func ReviewCommand(cfg *config.Config) Command {
+	return reviewCommand.command(cfg)
+}
+
+// ReviewCommands returns a command per available review exercise for the
+// commands dialog.
+func ReviewCommands(cfg *config.Config) []Command {
+	return reviewCommand.commands(cfg)
+}
@@ -0,0 +1,99 @@
+package uicmd
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/trankhanh040147/prepf/internal/agent/prompt"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/skills"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+// SkillCommandID is the slash command that activates a skill.
+const SkillCommandID = "skill"
+
+// ActivateSkillMsg asks the chat page to activate a skill for the current
+// session.
+type ActivateSkillMsg struct {
+	Name string
+}
+
+// SkillCommand returns the /skill command. Without an argument it asks for
+// the skill name.
+func SkillCommand(cfg *config.Config) Command {
+	return Command{
+		ID:          SkillCommandID,
+		Title:       SlashPrefix + SkillCommandID,
+		Description: "Activate a skill for this session",
+		Handler: func(Command) tea.Cmd {
+			return askSkillName(cfg)
+		},
+		ArgsHandler: func(_ Command, args string) tea.Cmd {
+			if args == "" {
+				return askSkillName(cfg)
+			}
+			return activateSkill(cfg, args)
+		},
+	}
+}
+
+// SkillCommands returns a command per available skill for the commands
+// dialog.
+func SkillCommands(cfg *config.Config) []Command {
+	available, _ := prompt.DiscoverSkills(*cfg)
+	commands := make([]Command, 0, len(available))
+	for _, skill := range available {
+		commands = append(commands, Command{
+			ID:          SkillCommandID + ":" + skill.Name,
+			Title:       "Activate Skill: " + skill.Name,
+			Description: skill.Description,
+			Handler: func(Command) tea.Cmd {
+				return util.CmdHandler(ActivateSkillMsg{Name: skill.Name})
+			},
+		})
+	}
+	return commands
+}
+
+// InvalidSkillsWarning describes the skill files that failed validation, or
+// returns an empty string when there are none.
+func InvalidSkillsWarning(problems []skills.Problem) string {
+	switch len(problems) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("Invalid skill %s", problems[0])
+	default:
+		return fmt.Sprintf("Invalid skill %s (and %d more)", problems[0], len(problems)-1)
+	}
+}
+
+func askSkillName(cfg *config.Config) tea.Cmd {
+	available, _ := prompt.DiscoverSkills(*cfg)
+	if len(available) == 0 {
+		return util.ReportWarn("No skills found in the configured skills paths")
+	}
+	names := make([]string, len(available))
+	for i, skill := range available {
+		names[i] = skill.Name
+	}
+	return util.CmdHandler(ShowArgumentsDialogMsg{
+		CommandID:   SkillCommandID,
+		Description: "Available skills: " + strings.Join(names, ", "),
+		ArgNames:    []string{"NAME"},
+		OnSubmit: func(args map[string]string) tea.Cmd {
+			return activateSkill(cfg, args["NAME"])
+		},
+	})
+}
+
+func activateSkill(cfg *config.Config, name string) tea.Cmd {
+	name = strings.TrimSpace(name)
+	available, _ := prompt.DiscoverSkills(*cfg)
+	if _, ok := skills.Find(available, name); !ok {
+		return util.ReportWarn(fmt.Sprintf("Unknown skill %q", name))
+	}
+	return util.CmdHandler(ActivateSkillMsg{Name: name})
+}
@@ -0,0 +1,46 @@
+package uicmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/skills"
+)
+
+func TestInvalidSkillsWarning(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, InvalidSkillsWarning(nil))
+
+	problems := []skills.Problem{
+		{Path: "/skills/a/SKILL.md", Err: errors.New("description is required")},
+		{Path: "/skills/b/SKILL.md", Err: errors.New("no YAML frontmatter found")},
+	}
+	require.Equal(t, "Invalid skill /skills/a/SKILL.md: description is required", InvalidSkillsWarning(problems[:1]))
+	require.Equal(t, "Invalid skill /skills/a/SKILL.md: description is required (and 1 more)", InvalidSkillsWarning(problems))
+}
+
+func TestSkillCommand(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	skillDir := filepath.Join(dir, "go-concurrency")
+	require.NoError(t, os.MkdirAll(skillDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, skills.SkillFileName), []byte("---\nname: go-concurrency\ndescription: Drills on goroutines.\n---\nAsk about channels.\n"), 0o644))
+
+	cfg := &config.Config{Options: &config.Options{SkillsPaths: []string{dir}}}
+
+	cmd := SkillCommand(cfg)
+	require.Equal(t, ActivateSkillMsg{Name: "go-concurrency"}, RunSlash(cmd, "go-concurrency")())
+	require.IsType(t, ShowArgumentsDialogMsg{}, RunSlash(cmd, "")())
+	require.NotEqual(t, ActivateSkillMsg{Name: "missing"}, RunSlash(cmd, "missing")())
+
+	commands := SkillCommands(cfg)
+	require.Len(t, commands, 1)
+	require.Equal(t, "skill:go-concurrency", commands[0].ID)
+	require.Equal(t, ActivateSkillMsg{Name: "go-concurrency"}, commands[0].Handler(commands[0])())
+}
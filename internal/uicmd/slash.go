@@ -0,0 +1,235 @@
+package uicmd
+
+import (
+	"maps"
+	"strings"
+	"unicode"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/trankhanh040147/prepf/internal/agent"
+	"github.com/trankhanh040147/prepf/internal/agent/tools/mcp"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/tui/components/chat"
+	"github.com/trankhanh040147/prepf/internal/tui/util"
+)
+
+// SlashPrefix starts a command typed directly in the editor. Typing it twice
+// sends the rest of the message as plain text.
+const SlashPrefix = "/"
+
+// ParseSlash splits editor input of the form "/name args" into the command
+// name and its arguments.
+func ParseSlash(input string) (name, args string, ok bool) {
+	input = strings.TrimSpace(input)
+	if !strings.HasPrefix(input, SlashPrefix) || strings.HasPrefix(input, SlashPrefix+SlashPrefix) {
+		return "", "", false
+	}
+	name = input[len(SlashPrefix):]
+	if i := strings.IndexFunc(name, unicode.IsSpace); i >= 0 {
+		name, args = name[:i], name[i:]
+	}
+	if name == "" {
+		return "", "", false
+	}
+	return name, strings.TrimSpace(args), true
+}
+
+// SlashCommands returns every command that can be run from the editor: the
+// interview controls, skill activation, custom commands and MCP prompts.
+func SlashCommands(cfg *config.Config) []Command {
+	commands := append(InterviewCommands(), SkillCommand(cfg), QuizCommand(cfg), ReviewCommand(cfg), SQLCommand(cfg), DebugCommand(cfg), DailyCommand(), PlanCommand())
+	if custom, err := LoadCustomCommandsFromConfig(cfg); err == nil {
+		commands = append(commands, custom...)
+	}
+	return append(commands, LoadMCPPrompts()...)
+}
+
+// FindSlash looks up a command by the name typed after the slash.
+func FindSlash(commands []Command, name string) (Command, bool) {
+	for _, cmd := range commands {
+		if cmd.ID == name {
+			return cmd, true
+		}
+	}
+	return Command{}, false
+}
+
+// RunSlash runs a command with the arguments typed after its name.
+func RunSlash(cmd Command, args string) tea.Cmd {
+	if cmd.ArgsHandler != nil {
+		return cmd.ArgsHandler(cmd, args)
+	}
+	return cmd.Handler(cmd)
+}
+
+// InterviewCommands returns the built-in commands that steer a mock
+// interview or gym session. They send control messages rather than plain
+// text so the model can tell them apart from answers.
+func InterviewCommands() []Command {
+	return []Command{
+		controlCommand(agent.ControlSkip, "Skip the current question"),
+		drillCommand(agent.ControlHint, "Reveal the next hint for the current question", func(args string) tea.Msg {
+			return RevealHintMsg{Args: args}
+		}),
+		drillCommand(agent.ControlGiveUp, "Show the answer and practice the topic again later", func(args string) tea.Msg {
+			return GiveUpMsg{Args: args}
+		}),
+		controlCommand(agent.ControlHarder, "Make the next questions harder"),
+		controlCommand(agent.ControlEasier, "Make the next questions easier"),
+		controlCommand(agent.ControlReport, "Summarize the session so far"),
+		drillCommand(agent.ControlCheck, "Run the check of the current debugging exercise", func(string) tea.Msg {
+			return RunCheckMsg{}
+		}),
+	}
+}
+
+func controlCommand(action, description string) Command {
+	send := func(args string) tea.Cmd {
+		return util.CmdHandler(chat.SendMsg{
+			Text: agent.Control{Action: action, Args: args}.String(),
+		})
+	}
+	return Command{
+		ID:          action,
+		Title:       SlashPrefix + action,
+		Description: description,
+		Handler: func(Command) tea.Cmd {
+			return send("")
+		},
+		ArgsHandler: func(_ Command, args string) tea.Cmd {
+			return send(args)
+		},
+	}
+}
+
+// createCommandArgsHandler fills a custom command's named arguments from the
+// inline arguments and only asks for the ones still missing. Commands
+// without arguments get the inline text appended to their content.
+func createCommandArgsHandler(id, desc, content string) func(Command, string) tea.Cmd {
+	return func(cmd Command, input string) tea.Cmd {
+		names := extractArgNames(content)
+		if len(names) == 0 {
+			prompt := content
+			if input != "" {
+				prompt += "\n\n" + input
+			}
+			return util.CmdHandler(CommandRunCustomMsg{Content: prompt})
+		}
+
+		values := parseInlineArgs(names, input)
+		missing := missingArgs(names, values)
+		if len(missing) == 0 {
+			return execUserPrompt(content, values)
+		}
+		return util.CmdHandler(ShowArgumentsDialogMsg{
+			CommandID:   id,
+			Description: desc,
+			ArgNames:    missing,
+			OnSubmit: func(args map[string]string) tea.Cmd {
+				maps.Copy(values, args)
+				return execUserPrompt(content, values)
+			},
+		})
+	}
+}
+
+func createMCPPromptArgsHandler(mcpName, promptName string, prompt *mcp.Prompt) func(Command, string) tea.Cmd {
+	return func(cmd Command, input string) tea.Cmd {
+		if len(prompt.Arguments) == 0 {
+			return execMCPPrompt(mcpName, promptName, nil)
+		}
+		names := make([]string, 0, len(prompt.Arguments))
+		for _, arg := range prompt.Arguments {
+			names = append(names, arg.Name)
+		}
+		values := parseInlineArgs(names, input)
+		for _, arg := range prompt.Arguments {
+			if arg.Required && values[arg.Name] == "" {
+				return createMCPPromptHandler(mcpName, promptName, prompt)(cmd)
+			}
+		}
+		return execMCPPrompt(mcpName, promptName, values)
+	}
+}
+
+// parseInlineArgs maps inline arguments onto names. NAME=value pairs are
+// assigned by name (case-insensitively), the remaining words are assigned in
+// order, and the last unassigned name receives all the leftover text.
+func parseInlineArgs(names []string, input string) map[string]string {
+	values := make(map[string]string, len(names))
+	var positional []string
+	for _, field := range splitArgs(input) {
+		key, value, ok := strings.Cut(field, "=")
+		if ok {
+			if name, found := lookupArg(names, key); found {
+				values[name] = value
+				continue
+			}
+		}
+		positional = append(positional, field)
+	}
+
+	remaining := missingArgs(names, values)
+	for i, name := range remaining {
+		if len(positional) == 0 {
+			break
+		}
+		if i == len(remaining)-1 {
+			values[name] = strings.Join(positional, " ")
+			break
+		}
+		values[name] = positional[0]
+		positional = positional[1:]
+	}
+	return values
+}
+
+func lookupArg(names []string, key string) (string, bool) {
+	for _, name := range names {
+		if strings.EqualFold(name, key) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func missingArgs(names []string, values map[string]string) []string {
+	var missing []string
+	for _, name := range names {
+		if values[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// splitArgs splits input on whitespace, keeping double-quoted sections
+// together.
+func splitArgs(input string) []string {
+	var (
+		fields  []string
+		current strings.Builder
+		quoted  bool
+		started bool
+	)
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if started {
+				fields = append(fields, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
@@ -0,0 +1,62 @@
+package uicmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSlash(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		name  string
+		args  string
+		ok    bool
+	}{
+		{input: "/hint", name: "hint", ok: true},
+		{input: "  /user:review main.go  ", name: "user:review", args: "main.go", ok: true},
+		{input: "/report\nfocus on concurrency", name: "report", args: "focus on concurrency", ok: true},
+		{input: "//etc/hosts is a file", ok: false},
+		{input: "/", ok: false},
+		{input: "hint", ok: false},
+	}
+	for _, tt := range tests {
+		name, args, ok := ParseSlash(tt.input)
+		require.Equal(t, tt.ok, ok, tt.input)
+		require.Equal(t, tt.name, name, tt.input)
+		require.Equal(t, tt.args, args, tt.input)
+	}
+}
+
+func TestParseInlineArgs(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"LANGUAGE", "TOPIC"}
+
+	require.Equal(t, map[string]string{
+		"LANGUAGE": "go",
+		"TOPIC":    "channels and select",
+	}, parseInlineArgs(names, "go channels and select"))
+
+	require.Equal(t, map[string]string{
+		"LANGUAGE": "rust",
+		"TOPIC":    "error handling",
+	}, parseInlineArgs(names, `topic="error handling" rust`))
+
+	require.Equal(t, map[string]string{
+		"LANGUAGE": "go",
+	}, parseInlineArgs(names, "LANGUAGE=go"))
+	require.Equal(t, []string{"TOPIC"}, missingArgs(names, parseInlineArgs(names, "LANGUAGE=go")))
+
+	require.Empty(t, parseInlineArgs(names, ""))
+}
+
+func TestSplitArgs(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"a", "b c", "d=e f"}, splitArgs(`a  "b c" d="e f"`))
+	require.Equal(t, []string{""}, splitArgs(`""`))
+	require.Empty(t, splitArgs("   "))
+}
@@ -0,0 +1,44 @@
+package uicmd
+
+import (
+	"cmp"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/sqlgym"
+)
+
+// SQLCommandID is the slash command that starts a SQL exercise.
+const SQLCommandID = "sql"
+
+// StartSQLMsg asks the UI to open the SQL practice page with the named
+// exercise.
+type StartSQLMsg struct {
+	Name string
+}
+
+var sqlCommand = exerciseCommand[sqlgym.Exercise, *sqlgym.Exercise]{
+	loader:      sqlgym.Exercises,
+	id:          SQLCommandID,
+	description: "Answer questions about a database with SQL queries",
+	title:       "Start SQL Practice: ",
+	listed:      "Available exercises: ",
+	missing:     "No SQL exercises found; ask for one in the chat or add a file to ",
+	describe: func(e *sqlgym.Exercise) string {
+		return fmt.Sprintf("%s (%d task(s))", cmp.Or(e.Title, e.Name), len(e.Tasks))
+	},
+	start: func(name string) tea.Msg { return StartSQLMsg{Name: name} },
+}
+
+// SQLCommand returns the /sql command. Without an argument it asks for
+// the exercise name.
+func SQLCommand(cfg *config.Config) Command {
+	return sqlCommand.command(cfg)
+}
+
+// SQLCommands returns a command per available SQL exercise for the
+// commands dialog.
+func SQLCommands(cfg *config.Config) []Command {
+	return sqlCommand.commands(cfg)
+}
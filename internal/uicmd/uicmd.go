@@ -38,6 +38,10 @@ type Command struct {
 	Description string
 	Shortcut    string // Optional shortcut for the command
 	Handler     func(cmd Command) tea.Cmd
+	// ArgsHandler runs the command with the arguments typed after it in the
+	// editor, e.g. "/user:review main.go". Commands without one ignore
+	// inline arguments.
+	ArgsHandler func(cmd Command, args string) tea.Cmd
 }
 
 // ShowArgumentsDialogMsg is a message that is sent to show the arguments dialog.
@@ -88,6 +92,16 @@ func LoadCustomCommandsFromConfig(cfg *config.Config) ([]Command, error) {
 	return loader.loadAll()
 }
 
+// CommandDirs returns the directories custom commands are loaded from.
+func CommandDirs(cfg *config.Config) []string {
+	sources := buildCommandSources(cfg)
+	dirs := make([]string, 0, len(sources))
+	for _, source := range sources {
+		dirs = append(dirs, source.path)
+	}
+	return dirs
+}
+
 func buildCommandSources(cfg *config.Config) []commandSource {
 	var sources []commandSource
 
@@ -179,6 +193,7 @@ func (l *commandLoader) loadCommand(path, baseDir, prefix string) (Command, erro
 		Title:       id,
 		Description: desc,
 		Handler:     createCommandHandler(id, desc, string(content)),
+		ArgsHandler: createCommandArgsHandler(id, desc, string(content)),
 	}, nil
 }
 
@@ -272,6 +287,7 @@ func LoadMCPPrompts() []Command {
 				Title:       cmp.Or(prompt.Title, prompt.Name),
 				Description: prompt.Description,
 				Handler:     createMCPPromptHandler(mcpName, prompt.Name, prompt),
+				ArgsHandler: createMCPPromptArgsHandler(mcpName, prompt.Name, prompt),
 			})
 		}
 	}
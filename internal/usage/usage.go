@@ -0,0 +1,249 @@
+// Package usage keeps a ledger of model spend and enforces the local budgets
+// configured in [config.Budget].
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/csync"
+	"github.com/trankhanh040147/prepf/internal/db"
+	"github.com/trankhanh040147/prepf/internal/pubsub"
+)
+
+// Record is a single entry in the usage ledger.
+type Record struct {
+	SessionID        string
+	Mode             string
+	Provider         string
+	Model            string
+	PromptTokens     int64
+	CompletionTokens int64
+	Cost             float64
+}
+
+type Period string
+
+const (
+	PeriodDaily   Period = "daily"
+	PeriodMonthly Period = "monthly"
+	PeriodSession Period = "session"
+)
+
+// Spend is the amount spent in each budget period.
+type Spend struct {
+	Daily   float64
+	Monthly float64
+	Session float64
+}
+
+// Alert is published when spend approaches or exceeds a budget limit.
+type Alert struct {
+	Period   Period
+	Limit    float64
+	Spent    float64
+	Exceeded bool
+}
+
+func (a Alert) String() string {
+	if a.Exceeded {
+		return fmt.Sprintf("%s budget of $%.2f exceeded ($%.2f spent)", periodTitle(a.Period), a.Limit, a.Spent)
+	}
+	return fmt.Sprintf("%s budget %.0f%% used ($%.2f of $%.2f)", periodTitle(a.Period), a.Spent/a.Limit*100, a.Spent, a.Limit)
+}
+
+func periodTitle(p Period) string {
+	switch p {
+	case PeriodDaily:
+		return "Daily"
+	case PeriodMonthly:
+		return "Monthly"
+	default:
+		return "Session"
+	}
+}
+
+// Evaluate compares spend against the budget and returns one alert per
+// limit that is either exceeded or past the warning threshold. Exceeded
+// limits are returned first.
+func Evaluate(b config.Budget, s Spend) []Alert {
+	limits := []struct {
+		period Period
+		limit  float64
+		spent  float64
+	}{
+		{PeriodSession, b.Session, s.Session},
+		{PeriodDaily, b.Daily, s.Daily},
+		{PeriodMonthly, b.Monthly, s.Monthly},
+	}
+
+	var exceeded, warnings []Alert
+	for _, l := range limits {
+		if l.limit <= 0 {
+			continue
+		}
+		alert := Alert{Period: l.period, Limit: l.limit, Spent: l.spent}
+		switch {
+		case l.spent >= l.limit:
+			alert.Exceeded = true
+			exceeded = append(exceeded, alert)
+		case l.spent >= l.limit*b.WarnThreshold():
+			warnings = append(warnings, alert)
+		}
+	}
+	return append(exceeded, warnings...)
+}
+
+// Row is an aggregated line of a usage report.
+type Row struct {
+	Key              string  `json:"key"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// Report aggregates spend by day, mode and model.
+type Report struct {
+	ByDay   []Row `json:"by_day"`
+	ByMode  []Row `json:"by_mode"`
+	ByModel []Row `json:"by_model"`
+}
+
+type Service interface {
+	pubsub.Subscriber[Alert]
+	Record(ctx context.Context, record Record) error
+	SpentSince(ctx context.Context, since time.Time) (float64, error)
+	Report(ctx context.Context, since time.Time) (Report, error)
+	// Check evaluates the budget, publishing an alert the first time each
+	// limit is approached or exceeded within its period. It returns the first
+	// exceeded limit, if any.
+	Check(ctx context.Context, budget config.Budget, sessionID string, sessionCost float64) (*Alert, error)
+}
+
+type service struct {
+	*pubsub.Broker[Alert]
+	q   db.Querier
+	now func() time.Time
+
+	alerted *csync.Map[string, bool]
+}
+
+func NewService(q db.Querier) Service {
+	return &service{
+		Broker:  pubsub.NewBroker(pubsub.WithName[Alert]("usage")),
+		q:       q,
+		now:     time.Now,
+		alerted: csync.NewMap[string, bool](),
+	}
+}
+
+func (s *service) Record(ctx context.Context, record Record) error {
+	if record.Cost == 0 && record.PromptTokens == 0 && record.CompletionTokens == 0 {
+		return nil
+	}
+	_, err := s.q.CreateUsageRecord(ctx, db.CreateUsageRecordParams{
+		ID:        uuid.New().String(),
+		SessionID: record.SessionID,
+		Mode: sql.NullString{
+			String: record.Mode,
+			Valid:  record.Mode != "",
+		},
+		Provider:         record.Provider,
+		Model:            record.Model,
+		PromptTokens:     record.PromptTokens,
+		CompletionTokens: record.CompletionTokens,
+		Cost:             record.Cost,
+	})
+	return err
+}
+
+func (s *service) SpentSince(ctx context.Context, since time.Time) (float64, error) {
+	return s.q.GetUsageCostSince(ctx, since.Unix())
+}
+
+func (s *service) Report(ctx context.Context, since time.Time) (Report, error) {
+	var report Report
+	days, err := s.q.ListUsageByDay(ctx, since.Unix())
+	if err != nil {
+		return Report{}, err
+	}
+	for _, d := range days {
+		report.ByDay = append(report.ByDay, Row{d.Day, d.PromptTokens, d.CompletionTokens, d.Cost})
+	}
+	modes, err := s.q.ListUsageByMode(ctx, since.Unix())
+	if err != nil {
+		return Report{}, err
+	}
+	for _, m := range modes {
+		report.ByMode = append(report.ByMode, Row{m.Mode, m.PromptTokens, m.CompletionTokens, m.Cost})
+	}
+	models, err := s.q.ListUsageByModel(ctx, since.Unix())
+	if err != nil {
+		return Report{}, err
+	}
+	for _, m := range models {
+		report.ByModel = append(report.ByModel, Row{m.Provider + "/" + m.Model, m.PromptTokens, m.CompletionTokens, m.Cost})
+	}
+	return report, nil
+}
+
+func (s *service) Check(ctx context.Context, budget config.Budget, sessionID string, sessionCost float64) (*Alert, error) {
+	now := s.now()
+	spend := Spend{Session: sessionCost}
+	var err error
+	if budget.Daily > 0 {
+		if spend.Daily, err = s.SpentSince(ctx, StartOfDay(now)); err != nil {
+			return nil, err
+		}
+	}
+	if budget.Monthly > 0 {
+		if spend.Monthly, err = s.SpentSince(ctx, StartOfMonth(now)); err != nil {
+			return nil, err
+		}
+	}
+
+	var exceeded *Alert
+	for _, alert := range Evaluate(budget, spend) {
+		if alert.Exceeded && exceeded == nil {
+			exceeded = &alert
+		}
+		key := alertKey(alert, sessionID, now)
+		if _, seen := s.alerted.Get(key); seen {
+			continue
+		}
+		s.alerted.Set(key, true)
+		s.Publish(pubsub.CreatedEvent, alert)
+	}
+	return exceeded, nil
+}
+
+// alertKey identifies an alert within its period so each one is only
+// published once.
+func alertKey(a Alert, sessionID string, now time.Time) string {
+	var scope string
+	switch a.Period {
+	case PeriodDaily:
+		scope = now.Format(time.DateOnly)
+	case PeriodMonthly:
+		scope = now.Format("2006-01")
+	default:
+		scope = sessionID
+	}
+	return fmt.Sprintf("%s:%s:%t", a.Period, scope, a.Exceeded)
+}
+
+// StartOfDay returns local midnight for the given time.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfMonth returns local midnight of the first day of the given month.
+func StartOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,115 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trankhanh040147/prepf/internal/config"
+	"github.com/trankhanh040147/prepf/internal/db"
+)
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	half := 0.5
+	tests := []struct {
+		name   string
+		budget config.Budget
+		spend  Spend
+		want   []Alert
+	}{
+		{
+			name:   "no limits",
+			budget: config.Budget{},
+			spend:  Spend{Daily: 100, Monthly: 100, Session: 100},
+			want:   nil,
+		},
+		{
+			name:   "under warning threshold",
+			budget: config.Budget{Daily: 10},
+			spend:  Spend{Daily: 5},
+			want:   nil,
+		},
+		{
+			name:   "warning at default threshold",
+			budget: config.Budget{Daily: 10},
+			spend:  Spend{Daily: 8},
+			want:   []Alert{{Period: PeriodDaily, Limit: 10, Spent: 8}},
+		},
+		{
+			name:   "custom warning threshold",
+			budget: config.Budget{Session: 2, WarnAt: &half},
+			spend:  Spend{Session: 1},
+			want:   []Alert{{Period: PeriodSession, Limit: 2, Spent: 1}},
+		},
+		{
+			name:   "exceeded limits come first",
+			budget: config.Budget{Daily: 10, Monthly: 20},
+			spend:  Spend{Daily: 9, Monthly: 25},
+			want: []Alert{
+				{Period: PeriodMonthly, Limit: 20, Spent: 25, Exceeded: true},
+				{Period: PeriodDaily, Limit: 10, Spent: 9},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, Evaluate(tt.budget, tt.spend))
+		})
+	}
+}
+
+func TestServiceCheck(t *testing.T) {
+	t.Parallel()
+
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	svc := NewService(db.New(conn))
+	events := svc.Subscribe(t.Context())
+
+	for range 3 {
+		require.NoError(t, svc.Record(t.Context(), Record{
+			SessionID: "s1",
+			Mode:      "mock",
+			Provider:  "openai",
+			Model:     "gpt-4o",
+			Cost:      1,
+		}))
+	}
+
+	spent, err := svc.SpentSince(t.Context(), StartOfDay(time.Now()))
+	require.NoError(t, err)
+	require.InDelta(t, 3.0, spent, 0.0001)
+
+	budget := config.Budget{Daily: 3}
+	alert, err := svc.Check(t.Context(), budget, "s1", 0)
+	require.NoError(t, err)
+	require.NotNil(t, alert)
+	require.Equal(t, PeriodDaily, alert.Period)
+	require.True(t, alert.Exceeded)
+
+	event := <-events
+	require.Equal(t, *alert, event.Payload)
+
+	// A repeated check still blocks but does not publish again.
+	alert, err = svc.Check(t.Context(), budget, "s1", 0)
+	require.NoError(t, err)
+	require.NotNil(t, alert)
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected alert: %v", event.Payload)
+	default:
+	}
+
+	report, err := svc.Report(t.Context(), StartOfDay(time.Now()))
+	require.NoError(t, err)
+	require.Len(t, report.ByMode, 1)
+	require.Equal(t, "mock", report.ByMode[0].Key)
+	require.Len(t, report.ByModel, 1)
+	require.Equal(t, "openai/gpt-4o", report.ByModel[0].Key)
+}
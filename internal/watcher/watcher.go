@@ -0,0 +1,265 @@
+// Package watcher reports changes to the files that shape a session without
+// being part of it, such as context files, skills, custom commands, mode
+// templates and config files, so they can be reloaded without a restart.
+package watcher
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/trankhanh040147/prepf/internal/pubsub"
+)
+
+// Kind groups watched paths by what they affect.
+type Kind string
+
+const (
+	KindConfig   Kind = "config"
+	KindContext  Kind = "context"
+	KindSkills   Kind = "skills"
+	KindModes    Kind = "modes"
+	KindCommands Kind = "commands"
+)
+
+// Event lists the files that changed since the last event.
+type Event struct {
+	Kinds []Kind
+	Paths []string
+}
+
+// AffectsPrompt reports whether the change requires rebuilding system
+// prompts. Custom commands are read when they are run, so they don't.
+func (e Event) AffectsPrompt() bool {
+	return slices.ContainsFunc(e.Kinds, func(k Kind) bool {
+		return k != KindCommands
+	})
+}
+
+var kindNames = map[Kind]string{
+	KindConfig:   "config",
+	KindContext:  "context files",
+	KindSkills:   "skills",
+	KindModes:    "mode templates",
+	KindCommands: "custom commands",
+}
+
+// String describes the change for status messages.
+func (e Event) String() string {
+	names := make([]string, 0, len(e.Kinds))
+	for _, kind := range e.Kinds {
+		names = append(names, kindNames[kind])
+	}
+	msg := "Reloaded " + strings.Join(names, ", ")
+	if e.AffectsPrompt() {
+		msg += "; changes apply from the next message"
+	}
+	return msg
+}
+
+// DefaultDebounce is how long the watcher waits for more changes before
+// publishing, since editors often write a file several times in a row.
+const DefaultDebounce = 300 * time.Millisecond
+
+type target struct {
+	kind Kind
+	path string
+}
+
+// Watcher watches files and directories and publishes an [Event] once
+// changes settle. Directories are watched recursively. Paths that don't
+// exist yet are picked up when they are created inside an existing parent.
+type Watcher struct {
+	*pubsub.Broker[Event]
+
+	debounce time.Duration
+
+	mu      sync.Mutex
+	fs      *fsnotify.Watcher
+	targets []target
+	dirs    map[string]bool
+}
+
+// New creates a watcher. Nothing is watched until [Watcher.Start] is called.
+func New() *Watcher {
+	return &Watcher{
+		Broker:   pubsub.NewBroker(pubsub.WithName[Event]("watcher")),
+		debounce: DefaultDebounce,
+		dirs:     make(map[string]bool),
+	}
+}
+
+// Watch adds paths of the given kind. It can be called before or after
+// [Watcher.Start].
+func (w *Watcher) Watch(kind Kind, paths ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		path = filepath.Clean(path)
+		t := target{kind: kind, path: path}
+		if slices.Contains(w.targets, t) {
+			continue
+		}
+		w.targets = append(w.targets, t)
+		if w.fs != nil {
+			w.addTarget(t)
+		}
+	}
+}
+
+// Start watches the registered paths until ctx is done.
+func (w *Watcher) Start(ctx context.Context) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.fs = fsw
+	for _, t := range w.targets {
+		w.addTarget(t)
+	}
+	w.mu.Unlock()
+
+	go w.loop(ctx, fsw)
+	return nil
+}
+
+func (w *Watcher) loop(ctx context.Context, fsw *fsnotify.Watcher) {
+	defer func() {
+		w.mu.Lock()
+		w.fs = nil
+		w.dirs = make(map[string]bool)
+		w.mu.Unlock()
+		fsw.Close()
+	}()
+
+	var (
+		pending = make(map[string]Kind)
+		timer   = time.NewTimer(0)
+	)
+	<-timer.C
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("File watcher error", "error", err)
+		case ev, ok := <-fsw.Events:
+			if !ok {
+				return
+			}
+			if ev.Op == fsnotify.Chmod {
+				continue
+			}
+			kind, matched := w.handle(ev)
+			if !matched {
+				continue
+			}
+			pending[ev.Name] = kind
+			timer.Reset(w.debounce)
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+			w.Publish(pubsub.UpdatedEvent, newEvent(pending))
+			pending = make(map[string]Kind)
+		}
+	}
+}
+
+// handle matches an fsnotify event against the targets and starts watching
+// directories created inside a watched tree.
+func (w *Watcher) handle(ev fsnotify.Event) (Kind, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	t, ok := w.match(ev.Name)
+	if !ok {
+		return "", false
+	}
+	if ev.Has(fsnotify.Create) {
+		if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+			w.addTree(ev.Name)
+		}
+	}
+	return t.kind, true
+}
+
+func (w *Watcher) match(path string) (target, bool) {
+	for _, t := range w.targets {
+		if path == t.path || strings.HasPrefix(path, t.path+string(filepath.Separator)) {
+			return t, true
+		}
+	}
+	return target{}, false
+}
+
+// addTarget must be called with w.mu held.
+func (w *Watcher) addTarget(t target) {
+	info, err := os.Stat(t.path)
+	if err == nil && info.IsDir() {
+		w.addTree(t.path)
+		return
+	}
+	// Watch the parent so the file, or a directory that doesn't exist yet,
+	// is noticed when it is created or replaced.
+	parent := filepath.Dir(t.path)
+	if info, err := os.Stat(parent); err == nil && info.IsDir() {
+		w.addDir(parent)
+	}
+}
+
+func (w *Watcher) addTree(root string) {
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			w.addDir(path)
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) addDir(dir string) {
+	if w.dirs[dir] {
+		return
+	}
+	if err := w.fs.Add(dir); err != nil {
+		slog.Warn("Failed to watch directory", "path", dir, "error", err)
+		return
+	}
+	w.dirs[dir] = true
+}
+
+func newEvent(pending map[string]Kind) Event {
+	var ev Event
+	for path, kind := range pending {
+		ev.Paths = append(ev.Paths, path)
+		if !slices.Contains(ev.Kinds, kind) {
+			ev.Kinds = append(ev.Kinds, kind)
+		}
+	}
+	slices.Sort(ev.Paths)
+	slices.Sort(ev.Kinds)
+	return ev
+}
@@ -0,0 +1,62 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	contextFile := filepath.Join(dir, "PREPF.md")
+	skillsDir := filepath.Join(dir, "skills")
+	require.NoError(t, os.MkdirAll(filepath.Join(skillsDir, "go"), 0o755))
+
+	w := New()
+	w.debounce = 20 * time.Millisecond
+	w.Watch(KindContext, contextFile)
+	w.Watch(KindSkills, skillsDir)
+	events := w.Subscribe(t.Context())
+	require.NoError(t, w.Start(t.Context()))
+
+	next := func() Event {
+		t.Helper()
+		select {
+		case ev := <-events:
+			return ev.Payload
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for watcher event")
+			return Event{}
+		}
+	}
+
+	// The context file doesn't exist yet; its parent is watched.
+	require.NoError(t, os.WriteFile(contextFile, []byte("persona"), 0o644))
+	ev := next()
+	require.Equal(t, []Kind{KindContext}, ev.Kinds)
+	require.Equal(t, []string{contextFile}, ev.Paths)
+	require.True(t, ev.AffectsPrompt())
+	require.Equal(t, "Reloaded context files; changes apply from the next message", ev.String())
+
+	// Nested skill directories are watched recursively.
+	skill := filepath.Join(skillsDir, "go", "SKILL.md")
+	require.NoError(t, os.WriteFile(skill, []byte("---\nname: go\n---\n"), 0o644))
+	ev = next()
+	require.Equal(t, []Kind{KindSkills}, ev.Kinds)
+	require.Contains(t, ev.Paths, skill)
+
+	// Unrelated files next to watched ones are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o644))
+	commands := filepath.Join(dir, "commands")
+	w.Watch(KindCommands, commands)
+	require.NoError(t, os.MkdirAll(commands, 0o755))
+	ev = next()
+	require.Equal(t, []Kind{KindCommands}, ev.Kinds)
+	require.False(t, ev.AffectsPrompt())
+	require.Equal(t, "Reloaded custom commands", ev.String())
+}